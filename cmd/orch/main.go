@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,6 +23,7 @@ import (
 	"github.com/wilhg/orch/pkg/runtime"
 	"github.com/wilhg/orch/pkg/store"
 	"github.com/wilhg/orch/pkg/store/entstore"
+	"github.com/wilhg/orch/pkg/store/envelope"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -34,10 +37,22 @@ func main() {
 	var showVersion bool
 	var addr string
 	var databaseURL string
+	var autoMigrate bool
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		if err := runMigrate(ctx, os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	flag.BoolVar(&showVersion, "version", false, "print version and exit")
 	flag.StringVar(&addr, "addr", getEnv("ORCH_ADDR", ":8080"), "http listen address")
-	flag.StringVar(&databaseURL, "database", getEnv("DATABASE_URL", "sqlite:file:orch.sqlite?_fk=1&cache=shared&_pragma=busy_timeout(5000)"), "database url (sqlite or postgres)")
+	flag.StringVar(&databaseURL, "database", getEnv("DATABASE_URL", defaultDatabaseURL), "database url (sqlite or postgres)")
+	flag.BoolVar(&autoMigrate, "auto-migrate", os.Getenv("ORCH_AUTO_MIGRATE") == "1", "apply pending schema migrations at startup")
 	flag.Parse()
 
 	if showVersion {
@@ -53,18 +68,31 @@ func main() {
 		defer func() { _ = shutdown(context.Background()) }()
 	}
 
-	st, err := entstore.Open(ctx, databaseURL)
+	var opts []entstore.Option
+	keys, err := keyringFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encryption keys error: %v\n", err)
+		os.Exit(1)
+	}
+	if keys != nil {
+		opts = append(opts, entstore.WithEncryption(keys))
+	}
+	st, err := entstore.Open(ctx, databaseURL, opts...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "store open error: %v\n", err)
 		os.Exit(1)
 	}
 	defer func() { _ = st.Close() }()
-	if err := st.Migrate(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "migrate error: %v\n", err)
+	if err := ensureSchema(ctx, st, autoMigrate); err != nil {
+		fmt.Fprintf(os.Stderr, "schema error: %v\n", err)
 		os.Exit(1)
 	}
 
-	mux := buildMux(st)
+	if keys != nil && os.Getenv("ORCH_ENCRYPTION_REENCRYPT") == "1" {
+		go reencrypt(ctx, st)
+	}
+
+	mux := buildMux(ctx, st)
 
 	server := &http.Server{Addr: addr, Handler: otelhttp.NewHandler(mux, "http.server")}
 	go func() { _ = server.ListenAndServe() }()
@@ -72,8 +100,66 @@ func main() {
 	_ = server.Shutdown(context.Background())
 }
 
-func buildMux(st store.Store) *http.ServeMux {
+// orchStore is the storage the HTTP surface depends on.
+type orchStore interface {
+	store.Store
+	store.EventSubscriber
+	store.DeadLetterStore
+	store.OutboxStore
+	store.TimerStore
+	store.RunStore
+	store.EventQuerier
+	store.RunEraser
+	store.ShredKeyStore
+	store.AuditStore
+}
+
+// buildMux wires the HTTP API. Intents are executed asynchronously by outbox workers, and timers
+// are delivered by schedulers; both run until ctx is done.
+func buildMux(ctx context.Context, st orchStore) *http.ServeMux {
 	mux := http.NewServeMux()
+	// Runners are shared across requests so that calls for the same run are serialized.
+	newTodoState := func(runID string) agent.State { return todo.State{Run: runID} }
+	events := agent.NewEventRegistry()
+	if err := todo.RegisterEvents(events); err != nil {
+		panic(err)
+	}
+	te := agent.ToolEffectHandler{AllowedPermissions: map[string]bool{"network:outbound": true, "fs:read": true}, Validate: agent.JSONSchemaValidator}
+	toolRunner := runtime.NewRunner(st, todo.Reducer{}, []agent.EffectHandler{te, todo.LoggerEffect{}}, newTodoState,
+		runtime.WithDeadLetters(st), runtime.WithOutbox(st, "tool"), runtime.WithTimers(st, "tool"),
+		runtime.WithRunCatalog(st, "tool"), runtime.WithEventRegistry(events),
+		runtime.WithShredding(st), runtime.WithRunEraser(st), runtime.WithAudit(st))
+	todoRunner := runtime.NewRunner(st, todo.Reducer{}, []agent.EffectHandler{todo.LoggerEffect{}}, newTodoState,
+		runtime.WithDeadLetters(st), runtime.WithOutbox(st, "todo"), runtime.WithTimers(st, "todo"),
+		runtime.WithRunCatalog(st, "todo"), runtime.WithEventRegistry(events),
+		runtime.WithShredding(st), runtime.WithRunEraser(st), runtime.WithAudit(st))
+	// runnerFor returns the runner of the agent type the catalog records for the run; runs it
+	// does not know are handled by the tool runner.
+	runnerFor := func(ctx context.Context, runID string) (*runtime.Runner, error) {
+		run, err := st.GetRun(ctx, runID)
+		switch {
+		case err == sql.ErrNoRows:
+			return toolRunner, nil
+		case err != nil:
+			return nil, errmodel.System("store_error", "failed to read run", map[string]any{"run_id": runID}, err)
+		case run.AgentType == "todo":
+			return todoRunner, nil
+		}
+		return toolRunner, nil
+	}
+	for _, rn := range []*runtime.Runner{toolRunner, todoRunner} {
+		w := runtime.NewWorker(rn, runtime.WithWorkerErrorHandler(func(item store.OutboxRecord, err error) {
+			fmt.Fprintf(os.Stderr, "outbox item %s (%s, run %s) failed: %v\n", item.ItemID, item.IntentName, item.RunID, err)
+		}))
+		sc := runtime.NewScheduler(rn, runtime.WithSchedulerErrorHandler(func(t store.TimerRecord, err error) {
+			fmt.Fprintf(os.Stderr, "timer %s (run %s) failed: %v\n", t.TimerID, t.RunID, err)
+		}))
+		go func() { _ = w.Run(ctx) }()
+		go func() { _ = sc.Run(ctx) }()
+	}
+	if p, ok := retentionFromEnv(); ok {
+		go applyRetention(ctx, toolRunner, p)
+	}
 	// Example: trigger a tool via ToolEffectHandler
 	mux.HandleFunc("/api/examples/tool", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -89,10 +175,8 @@ func buildMux(st store.Store) *http.ServeMux {
 			errmodel.WriteHTTP(w, r, errmodel.Validation("missing_fields", "run_id and name required", map[string]any{"fields": []string{"run_id", "name"}}))
 			return
 		}
-		te := agent.ToolEffectHandler{AllowedPermissions: map[string]bool{"network:outbound": true, "fs:read": true}, Validate: agent.JSONSchemaValidator}
-		runner := runtime.NewRunner(st, todo.Reducer{}, []agent.EffectHandler{te, todo.LoggerEffect{}}, func(runID string) agent.State { return todo.State{Run: runID} })
 		ev := agent.Event{ID: uuid.NewString(), Type: "tool", Timestamp: time.Now().UTC(), Payload: map[string]any{"name": body.Name, "args": body.Args}}
-		s, err := runner.HandleEvent(r.Context(), body.RunID, ev)
+		s, err := toolRunner.HandleEvent(r.Context(), body.RunID, ev)
 		if err != nil {
 			errmodel.WriteHTTP(w, r, err)
 			return
@@ -120,13 +204,12 @@ func buildMux(st store.Store) *http.ServeMux {
 			errmodel.WriteHTTP(w, r, errmodel.Validation("missing_fields", "run_id and type required", map[string]any{"fields": []string{"run_id", "type"}}))
 			return
 		}
-		runner := runtime.NewRunner(st, todo.Reducer{}, []agent.EffectHandler{todo.LoggerEffect{}}, func(runID string) agent.State { return todo.State{Run: runID} })
 		ev := agent.Event{ID: uuid.NewString(), Type: strings.ToLower(body.Type), Timestamp: time.Now().UTC()}
 		// decode payload into generic map
 		var p any
 		_ = json.Unmarshal(body.Payload, &p)
 		ev.Payload = p
-		s, err := runner.HandleEvent(r.Context(), body.RunID, ev)
+		s, err := todoRunner.HandleEvent(r.Context(), body.RunID, ev)
 		if err != nil {
 			errmodel.WriteHTTP(w, r, err)
 			return
@@ -138,44 +221,68 @@ func buildMux(st store.Store) *http.ServeMux {
 		_, _ = w.Write([]byte("ok"))
 	})
 
-	// Control plane: create run
+	// Control plane: create, inspect and list runs
 	mux.HandleFunc("/api/runs", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
 			var body struct {
-				RunID string `json:"run_id"`
+				RunID       string            `json:"run_id"`
+				Agent       string            `json:"agent"`
+				Labels      map[string]string `json:"labels"`
+				ParentRunID string            `json:"parent_run_id"`
 			}
 			_ = json.NewDecoder(r.Body).Decode(&body)
 			if body.RunID == "" {
 				body.RunID = uuid.NewString()
 			}
-			// Creating a run is implicit; we persist an initial event for audit.
+			run := store.RunRecord{RunID: body.RunID, AgentType: body.Agent, Labels: body.Labels, ParentRunID: body.ParentRunID}
+			if _, err := st.CreateRun(r.Context(), run); err != nil {
+				errmodel.WriteHTTP(w, r, err)
+				return
+			}
+			// Persist an initial event for audit.
 			rec := store.EventRecord{EventID: uuid.NewString(), RunID: body.RunID, Type: "run_created", CreatedAt: time.Now()}
-			if _, err := st.AppendEvent(r.Context(), rec); err != nil {
+			rec, err := st.AppendEvent(r.Context(), rec)
+			if err != nil {
+				errmodel.WriteHTTP(w, r, err)
+				return
+			}
+			if err := st.RecordRunProgress(r.Context(), store.RunRecord{RunID: body.RunID, LastSeq: rec.Seq}); err != nil {
 				errmodel.WriteHTTP(w, r, err)
 				return
 			}
 			writeJSON(w, map[string]any{"run_id": body.RunID})
 		case http.MethodGet:
-			// get state: return events and latest snapshot meta
-			runID := r.URL.Query().Get("run")
+			q := r.URL.Query()
+			runID := q.Get("run")
 			if runID == "" {
-				errmodel.WriteHTTP(w, r, errmodel.Validation("missing_run", "missing run", nil))
+				listRuns(w, r, st)
 				return
 			}
+			// get state: return the catalog entry, events and latest snapshot meta
 			events, err := st.ListEvents(r.Context(), runID, 0, 200)
 			if err != nil {
 				errmodel.WriteHTTP(w, r, err)
 				return
 			}
 			sn, _ := st.LoadLatestSnapshot(r.Context(), runID)
-			writeJSON(w, map[string]any{"events": events, "snapshot": sn})
+			resp := map[string]any{"events": events, "snapshot": sn}
+			if run, err := st.GetRun(r.Context(), runID); err == nil {
+				resp["run"] = run
+			}
+			writeJSON(w, resp)
 		default:
 			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
 		}
 	})
 
-	// Control plane: pause/resume via event types
+	// Live updates: SSE for watching a run, WebSocket sessions for interacting with it.
+	mux.HandleFunc("/api/runs/{id}/stream", streamEvents(st))
+	mux.HandleFunc("/api/runs/{id}/session", runSession(st, runnerFor))
+
+	// Control plane: pause/resume/cancel. The runner of the run's agent type drives the
+	// transitions, so that a resume drains the queued events under that runner's lock and with
+	// its handlers and outbox queue.
 	mux.HandleFunc("/api/runs/pause", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
@@ -188,8 +295,12 @@ func buildMux(st store.Store) *http.ServeMux {
 			errmodel.WriteHTTP(w, r, errmodel.Validation("missing_fields", "run_id required", map[string]any{"fields": []string{"run_id"}}))
 			return
 		}
-		rec := store.EventRecord{EventID: uuid.NewString(), RunID: body.RunID, Type: "run_paused", CreatedAt: time.Now()}
-		if _, err := st.AppendEvent(r.Context(), rec); err != nil {
+		rn, err := runnerFor(r.Context(), body.RunID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		if err := rn.Pause(r.Context(), body.RunID); err != nil {
 			errmodel.WriteHTTP(w, r, err)
 			return
 		}
@@ -207,13 +318,191 @@ func buildMux(st store.Store) *http.ServeMux {
 			errmodel.WriteHTTP(w, r, errmodel.Validation("missing_fields", "run_id required", map[string]any{"fields": []string{"run_id"}}))
 			return
 		}
-		rec := store.EventRecord{EventID: uuid.NewString(), RunID: body.RunID, Type: "run_resumed", CreatedAt: time.Now()}
-		if _, err := st.AppendEvent(r.Context(), rec); err != nil {
+		rn, err := runnerFor(r.Context(), body.RunID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		s, err := rn.Resume(r.Context(), body.RunID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, map[string]any{"ok": true, "state": s})
+	})
+	mux.HandleFunc("/api/runs/cancel", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		var body struct {
+			RunID  string `json:"run_id"`
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RunID == "" {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("missing_fields", "run_id required", map[string]any{"fields": []string{"run_id"}}))
+			return
+		}
+		// Each runner interrupts its own in-flight effects; only the first records run_cancelled.
+		for _, rn := range []*runtime.Runner{toolRunner, todoRunner} {
+			if err := rn.Cancel(r.Context(), body.RunID, body.Reason); err != nil {
+				errmodel.WriteHTTP(w, r, err)
+				return
+			}
+		}
+		writeJSON(w, map[string]any{"ok": true})
+	})
+
+	// Time travel: the state of a run at any sequence, and forks branching from one.
+	mux.HandleFunc("/api/runs/{id}/state", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		runID := r.PathValue("id")
+		rn, err := runnerFor(r.Context(), runID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		v := r.URL.Query().Get("seq")
+		if v == "" {
+			s, seq, err := rn.State(r.Context(), runID)
+			if err != nil {
+				errmodel.WriteHTTP(w, r, err)
+				return
+			}
+			writeJSON(w, map[string]any{"run_id": runID, "seq": seq, "state": s})
+			return
+		}
+		seq, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("bad_seq", "seq must be an integer", map[string]any{"seq": v}))
+			return
+		}
+		s, err := rn.StateAt(r.Context(), runID, seq)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, map[string]any{"run_id": runID, "seq": seq, "state": s})
+	})
+	mux.HandleFunc("/api/runs/fork", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		var body struct {
+			RunID    string `json:"run_id"`
+			Seq      int64  `json:"seq"`
+			NewRunID string `json:"new_run_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RunID == "" {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("missing_fields", "run_id required", map[string]any{"fields": []string{"run_id"}}))
+			return
+		}
+		if body.NewRunID == "" {
+			body.NewRunID = uuid.NewString()
+		}
+		// The fork is handled, and registered in the catalog, by the runner of its source.
+		rn, err := runnerFor(r.Context(), body.RunID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		s, err := rn.Fork(r.Context(), body.RunID, body.Seq, body.NewRunID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, map[string]any{"run_id": body.NewRunID, "state": s})
+	})
+
+	// Data lifecycle: deletion, crypto-shredding and export of runs, and the audit trail.
+	mux.HandleFunc("/api/runs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		runID := r.PathValue("id")
+		e := runtime.Erasure{Actor: r.URL.Query().Get("actor"), Reason: r.URL.Query().Get("reason")}
+		// The runner of the run interrupts its effects in flight and deletes the records.
+		rn, err := runnerFor(r.Context(), runID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		d, err := rn.DeleteRun(r.Context(), runID, e)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, map[string]any{"ok": true, "deleted": d})
+	})
+	mux.HandleFunc("/api/runs/{id}/shred", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		var body struct {
+			Actor  string `json:"actor"`
+			Reason string `json:"reason"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		runID := r.PathValue("id")
+		rn, err := runnerFor(r.Context(), runID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		if err := rn.ShredRun(r.Context(), runID, runtime.Erasure{Actor: body.Actor, Reason: body.Reason}); err != nil {
 			errmodel.WriteHTTP(w, r, err)
 			return
 		}
 		writeJSON(w, map[string]any{"ok": true})
 	})
+	mux.HandleFunc("/api/runs/{id}/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		runID := r.PathValue("id")
+		rn, err := runnerFor(r.Context(), runID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", runID+".jsonl.gz"))
+		if _, err := rn.ExportRun(r.Context(), w, runID); err != nil {
+			// Nothing was written yet unless the export failed halfway, which the client sees
+			// as a truncated archive.
+			w.Header().Del("Content-Disposition")
+			errmodel.WriteHTTP(w, r, err)
+		}
+	})
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		q := r.URL.Query()
+		f := store.AuditFilter{RunID: q.Get("run"), Cursor: q.Get("cursor"), Limit: 100}
+		if v := q.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 || n > 1000 {
+				errmodel.WriteHTTP(w, r, errmodel.Validation("bad_limit", "limit must be an integer between 1 and 1000", map[string]any{"limit": v}))
+				return
+			}
+			f.Limit = n
+		}
+		page, err := st.ListAudit(r.Context(), f)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, map[string]any{"entries": page.Entries, "next_cursor": page.NextCursor})
+	})
 
 	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -257,6 +546,14 @@ func buildMux(st store.Store) *http.ServeMux {
 		}
 	})
 
+	mux.HandleFunc("/api/events/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		searchEvents(w, r, st)
+	})
+
 	mux.HandleFunc("/api/snapshots", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -297,9 +594,247 @@ func buildMux(st store.Store) *http.ServeMux {
 		}
 	})
 
+	// Dead letters: intents that exhausted their retry policy.
+	mux.HandleFunc("/api/deadletters", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		limit := 100
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				errmodel.WriteHTTP(w, r, errmodel.Validation("bad_limit", "limit must be a positive integer", map[string]any{"limit": v}))
+				return
+			}
+			limit = n
+		}
+		items, err := st.ListDeadLetters(r.Context(), r.URL.Query().Get("run"), limit)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, items)
+	})
+	mux.HandleFunc("/api/deadletters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		switch r.Method {
+		case http.MethodGet:
+			d, err := st.GetDeadLetter(r.Context(), id)
+			if err != nil {
+				errmodel.WriteHTTP(w, r, deadLetterErr(id, err))
+				return
+			}
+			writeJSON(w, d)
+		case http.MethodDelete:
+			// Discard the dead letter without running the intent.
+			if err := st.DeleteDeadLetter(r.Context(), id); err != nil {
+				errmodel.WriteHTTP(w, r, deadLetterErr(id, err))
+				return
+			}
+			writeJSON(w, map[string]any{"ok": true})
+		default:
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+		}
+	})
+	mux.HandleFunc("/api/deadletters/{id}/requeue", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		// The dead letter is requeued by the runner of its run, with that runner's handlers and
+		// outbox queue.
+		id := r.PathValue("id")
+		d, err := st.GetDeadLetter(r.Context(), id)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, deadLetterErr(id, err))
+			return
+		}
+		rn, err := runnerFor(r.Context(), d.RunID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		s, err := rn.Requeue(r.Context(), id)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		writeJSON(w, s)
+	})
+
 	return mux
 }
 
+// listRuns serves GET /api/runs without a run: the catalog filtered by status, agent,
+// parent and label=key:value parameters, newest first, in pages of limit runs.
+func listRuns(w http.ResponseWriter, r *http.Request, st store.RunStore) {
+	q := r.URL.Query()
+	f := store.RunFilter{
+		Status:      q.Get("status"),
+		AgentType:   q.Get("agent"),
+		ParentRunID: q.Get("parent"),
+		Cursor:      q.Get("cursor"),
+		Limit:       100,
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("bad_limit", "limit must be an integer between 1 and 1000", map[string]any{"limit": v}))
+			return
+		}
+		f.Limit = n
+	}
+	for _, l := range q["label"] {
+		k, v, ok := strings.Cut(l, ":")
+		if !ok || k == "" {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("bad_label", "label must be key:value", map[string]any{"label": l}))
+			return
+		}
+		if f.Labels == nil {
+			f.Labels = map[string]string{}
+		}
+		f.Labels[k] = v
+	}
+	page, err := st.ListRuns(r.Context(), f)
+	if err != nil {
+		errmodel.WriteHTTP(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]any{"runs": page.Runs, "next_cursor": page.NextCursor})
+}
+
+// searchEvents serves GET /api/events/search. Filters are the run, repeated type and
+// label=key:value parameters, RFC 3339 since and until times, q for text in the payload, and
+// repeated where=path:op[:value] payload predicates, whose value is JSON or else a string.
+func searchEvents(w http.ResponseWriter, r *http.Request, st store.EventQuerier) {
+	q := r.URL.Query()
+	eq := store.EventQuery{
+		RunID:  q.Get("run"),
+		Types:  q["type"],
+		Text:   q.Get("q"),
+		Cursor: q.Get("cursor"),
+		Limit:  100,
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("bad_limit", "limit must be an integer between 1 and 1000", map[string]any{"limit": v}))
+			return
+		}
+		eq.Limit = n
+	}
+	for name, t := range map[string]*time.Time{"since": &eq.Since, "until": &eq.Until} {
+		if v := q.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				errmodel.WriteHTTP(w, r, errmodel.Validation("bad_time", name+" must be an RFC 3339 time", map[string]any{name: v}))
+				return
+			}
+			*t = parsed
+		}
+	}
+	for _, l := range q["label"] {
+		k, v, ok := strings.Cut(l, ":")
+		if !ok || k == "" {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("bad_label", "label must be key:value", map[string]any{"label": l}))
+			return
+		}
+		if eq.Labels == nil {
+			eq.Labels = map[string]string{}
+		}
+		eq.Labels[k] = v
+	}
+	for _, p := range q["where"] {
+		path, rest, _ := strings.Cut(p, ":")
+		op, value, hasValue := strings.Cut(rest, ":")
+		pred := store.PayloadPredicate{Path: path, Op: store.PayloadOp(op)}
+		if hasValue {
+			pred.Value = json.RawMessage(value)
+			if !json.Valid(pred.Value) {
+				pred.Value, _ = json.Marshal(value)
+			}
+		} else if pred.Op != store.PayloadExists {
+			errmodel.WriteHTTP(w, r, errmodel.Validation("bad_where", "where must be path:op:value, or path:exists", map[string]any{"where": p}))
+			return
+		}
+		eq.Where = append(eq.Where, pred)
+	}
+	page, err := st.QueryEvents(r.Context(), eq)
+	if err != nil {
+		errmodel.WriteHTTP(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]any{"events": page.Events, "next_cursor": page.NextCursor})
+}
+
+// retentionFromEnv reads the retention policy of finished runs: ORCH_RETENTION_MAX_AGE is the
+// age after which they are deleted, and ORCH_EXPORT_DIR, if set, where they are exported first.
+func retentionFromEnv() (runtime.RunRetention, bool) {
+	v := os.Getenv("ORCH_RETENTION_MAX_AGE")
+	if v == "" {
+		return runtime.RunRetention{}, false
+	}
+	age, err := time.ParseDuration(v)
+	if err != nil || age <= 0 {
+		fmt.Fprintf(os.Stderr, "ignoring ORCH_RETENTION_MAX_AGE=%q: not a positive duration\n", v)
+		return runtime.RunRetention{}, false
+	}
+	return runtime.RunRetention{MaxAge: age, ExportDir: os.Getenv("ORCH_EXPORT_DIR")}, true
+}
+
+// applyRetention applies p every hour, and once at startup, until ctx is done.
+func applyRetention(ctx context.Context, rn *runtime.Runner, p runtime.RunRetention) {
+	t := time.NewTicker(time.Hour)
+	defer t.Stop()
+	for {
+		if deleted, err := rn.ApplyRetention(ctx, p); err != nil {
+			fmt.Fprintf(os.Stderr, "retention failed after deleting %d runs: %v\n", len(deleted), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// keyringFromEnv reads the master keys that encrypt payloads at rest from ORCH_ENCRYPTION_KEYS,
+// or from the file named by ORCH_ENCRYPTION_KEYFILE. It returns nil when neither is set.
+func keyringFromEnv() (*envelope.Keyring, error) {
+	if path := os.Getenv("ORCH_ENCRYPTION_KEYFILE"); path != "" {
+		return envelope.LoadKeyFile(path)
+	}
+	if os.Getenv("ORCH_ENCRYPTION_KEYS") == "" {
+		return nil, nil
+	}
+	return envelope.KeyringFromEnv("ORCH_ENCRYPTION_KEYS")
+}
+
+// reencrypt moves rows that are in plain text or under a retired key to the active key, in
+// batches, until none are left or ctx is done.
+func reencrypt(ctx context.Context, st store.KeyRotator) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := st.Reencrypt(ctx, 100)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "re-encryption failed after %d rows: %v\n", total, err)
+			return
+		}
+		if n == 0 {
+			return
+		}
+		total += n
+	}
+}
+
+func deadLetterErr(id string, err error) error {
+	if err == sql.ErrNoRows {
+		return errmodel.Validation("not_found", "dead letter not found", map[string]any{"dead_letter_id": id})
+	}
+	return err
+}
+
 func getEnv(key string, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
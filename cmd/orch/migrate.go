@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wilhg/orch/pkg/store/entstore"
+)
+
+// defaultDatabaseURL is the database used when neither -database nor DATABASE_URL is set.
+const defaultDatabaseURL = "sqlite:file:orch.sqlite?_fk=1&cache=shared&_pragma=busy_timeout(5000)"
+
+const migrateUsage = "usage: orch migrate status|up|down|diff|hash [flags] [args]"
+
+// runMigrate runs `orch migrate`. status, up and down work on the database; diff and hash
+// maintain the migration files of the source tree.
+func runMigrate(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("orch migrate "+cmd, flag.ContinueOnError)
+	var databaseURL *string
+	if cmd == "status" || cmd == "up" || cmd == "down" {
+		databaseURL = fs.String("database", getEnv("DATABASE_URL", defaultDatabaseURL), "database url (sqlite or postgres)")
+	}
+	switch cmd {
+	case "status":
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		return withStore(ctx, *databaseURL, func(st *entstore.Store) error {
+			status, err := st.MigrationStatus(ctx)
+			if err != nil {
+				return err
+			}
+			for _, m := range status.Applied {
+				fmt.Fprintf(out, "%s %s applied %s\n", m.Version, m.Description, m.AppliedAt.Format(time.RFC3339))
+			}
+			for _, m := range status.Pending {
+				fmt.Fprintf(out, "%s %s pending\n", m.Version, m.Description)
+			}
+			current := status.Current()
+			if current == "" {
+				current = "none"
+			}
+			fmt.Fprintf(out, "current: %s, pending: %d\n", current, len(status.Pending))
+			return nil
+		})
+	case "up":
+		n := fs.Int("n", 0, "number of migrations to apply (0 for all)")
+		baseline := fs.String("baseline", "", "record the migrations up to this version as applied without running them")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		return withStore(ctx, *databaseURL, func(st *entstore.Store) error {
+			if *baseline != "" {
+				if err := st.BaselineMigrations(ctx, *baseline); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "baselined at %s\n", *baseline)
+			}
+			done, err := st.MigrateUp(ctx, *n)
+			printMigrations(out, "applied", done)
+			return err
+		})
+	case "down":
+		n := fs.Int("n", 1, "number of migrations to revert (0 for all)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		return withStore(ctx, *databaseURL, func(st *entstore.Store) error {
+			done, err := st.MigrateDown(ctx, *n)
+			printMigrations(out, "reverted", done)
+			return err
+		})
+	case "diff":
+		dev := fs.String("dev", "sqlite:file:dev?mode=memory&_fk=1", "empty database of the directory's dialect to replay the migrations on")
+		dir := fs.String("dir", "", "migration directory (default pkg/store/entstore/migrations/<dialect of -dev>)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return errors.New("usage: orch migrate diff [-dev url] [-dir path] NAME")
+		}
+		if *dir == "" {
+			*dir = filepath.Join("pkg", "store", "entstore", "migrations", "postgres")
+			if strings.HasPrefix(strings.ToLower(*dev), "sqlite:") {
+				*dir = filepath.Join("pkg", "store", "entstore", "migrations", "sqlite")
+			}
+		}
+		changed, err := entstore.DiffMigrations(ctx, *dev, *dir, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Fprintln(out, "the migrations match the ent schema")
+			return nil
+		}
+		fmt.Fprintf(out, "wrote migration %q to %s\n", fs.Arg(0), *dir)
+		return nil
+	case "hash":
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return errors.New("usage: orch migrate hash DIR")
+		}
+		return entstore.HashMigrations(fs.Arg(0))
+	}
+	return errors.New(migrateUsage)
+}
+
+func withStore(ctx context.Context, databaseURL string, f func(*entstore.Store) error) error {
+	st, err := entstore.Open(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = st.Close() }()
+	return f(st)
+}
+
+func printMigrations(out io.Writer, verb string, ms []entstore.Migration) {
+	if len(ms) == 0 {
+		fmt.Fprintf(out, "no migrations %s\n", verb)
+	}
+	for _, m := range ms {
+		fmt.Fprintf(out, "%s %s %s\n", verb, m.Version, m.Description)
+	}
+}
+
+// ensureSchema applies the pending migrations when auto is set. Otherwise it refuses a database
+// that is behind, which `orch migrate up` brings up to date.
+func ensureSchema(ctx context.Context, st *entstore.Store, auto bool) error {
+	if auto {
+		if err := st.Migrate(ctx); err != nil {
+			return err
+		}
+	}
+	if err := st.CheckMigrations(ctx); err != nil {
+		return fmt.Errorf("%w; run `orch migrate up` or start with -auto-migrate", err)
+	}
+	return nil
+}
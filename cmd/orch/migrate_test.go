@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store/entstore"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	ctx := t.Context()
+	dsn := "sqlite:file:" + filepath.Join(t.TempDir(), "orch.sqlite") + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1"
+	t.Setenv("DATABASE_URL", dsn)
+	run := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := runMigrate(ctx, args, &out); err != nil {
+			t.Fatalf("migrate %v: %v", args, err)
+		}
+		return out.String()
+	}
+	st, err := entstore.Open(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	if out := run("status"); !strings.Contains(out, " pending\n") || !strings.Contains(out, "current: none") {
+		t.Fatalf("status:\n%s", out)
+	}
+	// The server refuses a database that is behind.
+	if err := ensureSchema(ctx, st, false); !errmodel.HasCode(err, "schema_behind") {
+		t.Fatalf("err=%v want schema_behind", err)
+	}
+	if out := run("up"); !strings.HasPrefix(out, "applied ") {
+		t.Fatalf("up:\n%s", out)
+	}
+	if out := run("status"); strings.Contains(out, " pending\n") || !strings.Contains(out, "pending: 0") {
+		t.Fatalf("status:\n%s", out)
+	}
+	if err := ensureSchema(ctx, st, false); err != nil {
+		t.Fatal(err)
+	}
+	if out := run("down", "-n", "1"); !strings.HasPrefix(out, "reverted ") {
+		t.Fatalf("down:\n%s", out)
+	}
+	// -auto-migrate brings it up to date instead.
+	if err := ensureSchema(ctx, st, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMigrate(ctx, []string{"sideways"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("unknown subcommand accepted")
+	}
+	if err := runMigrate(ctx, []string{"up", "-baseline", "19700101000000"}, &bytes.Buffer{}); !errmodel.HasCode(err, "unknown_migration") {
+		t.Fatalf("err=%v want unknown_migration", err)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/runtime"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// keepAlive is how often idle streams send a heartbeat so proxies keep them open.
+const keepAlive = 15 * time.Second
+
+// streamItem is an event or the error that ended a subscription.
+type streamItem struct {
+	ev  store.EventRecord
+	err error
+}
+
+// subscribe relays a store subscription to a channel, which is closed when it ends.
+func subscribe(ctx context.Context, st store.EventSubscriber, runID string, afterSeq int64) <-chan streamItem {
+	ch := make(chan streamItem, 16)
+	go func() {
+		defer close(ch)
+		for ev, err := range st.Subscribe(ctx, runID, afterSeq) {
+			select {
+			case ch <- streamItem{ev: ev, err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// afterSeq reads the sequence to resume from: the Last-Event-ID header sent by reconnecting
+// EventSource clients, or the after query parameter.
+func afterSeq(r *http.Request) (int64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("after")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errmodel.Validation("bad_after", "resume position must be a non-negative sequence", map[string]any{"after": v})
+	}
+	return seq, nil
+}
+
+// streamEvents serves GET /api/runs/{id}/stream: the run's events as Server-Sent Events, each
+// with its sequence as the event ID so clients resume where they left off.
+func streamEvents(st store.EventSubscriber) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			errmodel.WriteHTTP(w, r, errmodel.Policy("method_not_allowed", "method not allowed", nil))
+			return
+		}
+		after, err := afterSeq(r)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		events := subscribe(ctx, st, r.PathValue("id"), after)
+		ping := time.NewTicker(keepAlive)
+		defer ping.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ping.C:
+				_, err = fmt.Fprint(w, ": ping\n\n")
+			case it, ok := <-events:
+				if !ok {
+					return
+				}
+				if it.err != nil {
+					b, _ := json.Marshal(errmodel.From(it.err))
+					_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", b)
+					_ = rc.Flush()
+					return
+				}
+				b, _ := json.Marshal(it.ev)
+				_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", it.ev.Seq, b)
+			}
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sessionMessage is sent by session clients to post an event to the run.
+type sessionMessage struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+var upgrader = websocket.Upgrader{}
+
+// runSession serves GET /api/runs/{id}/session over WebSocket. Clients post events to the run
+// as {"id","type","payload"} messages, each answered with an "ack" carrying the resulting state
+// or an "error". The server pushes every committed event of the run as an "event" message and,
+// whenever it has caught up with the log, the run's "state" with the sequence it reflects. The
+// after query parameter skips events already seen. Each connection is served by the runner
+// runnerFor returns for the run when it opens.
+func runSession(st store.EventSubscriber, runnerFor func(ctx context.Context, runID string) (*runtime.Runner, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		after, err := afterSeq(r)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		runID := r.PathValue("id")
+		rn, err := runnerFor(r.Context(), runID)
+		if err != nil {
+			errmodel.WriteHTTP(w, r, err)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already replied.
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		conn.SetReadLimit(1 << 20)
+
+		// Detached from the request, which ends with the upgrade on some servers.
+		ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+		defer cancel()
+		var mu sync.Mutex
+		send := func(v any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			return conn.WriteJSON(v)
+		}
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		wg.Go(func() {
+			// Closing the connection also stops the read loop below.
+			defer func() { cancel(); _ = conn.Close() }()
+			pushRun(ctx, st, rn, runID, after, send)
+		})
+
+		for {
+			var msg sessionMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				cancel()
+				return
+			}
+			if msg.ID == "" {
+				msg.ID = uuid.NewString()
+			}
+			if msg.Type == "" {
+				err = errmodel.Validation("missing_fields", "type required", map[string]any{"fields": []string{"type"}})
+				_ = send(map[string]any{"type": "error", "id": msg.ID, "error": errmodel.From(err)})
+				continue
+			}
+			ev := agent.Event{ID: msg.ID, Type: strings.ToLower(msg.Type), Timestamp: time.Now().UTC()}
+			if len(msg.Payload) > 0 {
+				var p any
+				_ = json.Unmarshal(msg.Payload, &p)
+				ev.Payload = p
+			}
+			s, err := rn.HandleEvent(ctx, runID, ev)
+			if err != nil {
+				err = send(map[string]any{"type": "error", "id": msg.ID, "error": errmodel.From(err)})
+			} else {
+				err = send(map[string]any{"type": "ack", "id": msg.ID, "state": s})
+			}
+			if err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// pushRun sends the run's events and, once caught up with them, its state until ctx is done.
+func pushRun(ctx context.Context, st store.EventSubscriber, rn *runtime.Runner, runID string, after int64, send func(any) error) {
+	events := subscribe(ctx, st, runID, after)
+	ping := time.NewTicker(keepAlive)
+	defer ping.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			err = send(map[string]any{"type": "ping"})
+		case it, ok := <-events:
+			if !ok {
+				return
+			}
+			if it.err != nil {
+				_ = send(map[string]any{"type": "error", "error": errmodel.From(it.err)})
+				return
+			}
+			err = send(map[string]any{"type": "event", "event": it.ev})
+			if err == nil && len(events) == 0 {
+				// Coalesce bursts of events into one state update.
+				var (
+					s   agent.State
+					seq int64
+				)
+				if s, seq, err = rn.State(ctx, runID); err == nil {
+					err = send(map[string]any{"type": "state", "seq": seq, "state": s})
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
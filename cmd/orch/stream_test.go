@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/wilhg/orch/pkg/store/entstore"
+)
+
+func newStreamServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	st, err := entstore.Open(t.Context(), "sqlite:file:"+name+"?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(buildMux(t.Context(), st))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStream_SSEResumesFromLastEventID(t *testing.T) {
+	srv := newStreamServer(t, "httptest-sse")
+	for _, typ := range []string{"add_task", "add_task", "add_task"} {
+		res, err := http.Post(srv.URL+"/api/examples/todo", "application/json", bytes.NewBufferString(`{"RunID":"run-sse","Type":"`+typ+`","Payload":{"title":"x"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/runs/run-sse/stream", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type=%q", ct)
+	}
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(res.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+	nextID := func() string {
+		for {
+			select {
+			case l := <-lines:
+				if id, ok := strings.CutPrefix(l, "id: "); ok {
+					return id
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no event received")
+			}
+		}
+	}
+	if got := []string{nextID(), nextID()}; got[0] != "2" || got[1] != "3" {
+		t.Fatalf("ids=%v want [2 3]", got)
+	}
+	// Events appended while connected are streamed too.
+	res2, err := http.Post(srv.URL+"/api/examples/todo", "application/json", bytes.NewBufferString(`{"RunID":"run-sse","Type":"add_task","Payload":{"title":"y"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res2.Body.Close()
+	if id := nextID(); id != "4" {
+		t.Fatalf("id=%s want 4", id)
+	}
+}
+
+func TestStream_WebSocketSession(t *testing.T) {
+	srv := newStreamServer(t, "httptest-ws")
+	conn, _, err := websocket.DefaultDialer.DialContext(t.Context(), "ws"+strings.TrimPrefix(srv.URL, "http")+"/api/runs/run-ws/session", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.WriteJSON(map[string]any{"id": "c1", "type": "complete_task", "payload": map[string]any{"title": "demo"}}); err != nil {
+		t.Fatal(err)
+	}
+	// The logged event is produced asynchronously by the outbox worker and pushed when it commits.
+	var acked, logged bool
+	var done int
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for !acked || !logged || done != 1 {
+		var msg struct {
+			Type  string `json:"type"`
+			ID    string `json:"id"`
+			Event struct{ Type string }
+			State json.RawMessage `json:"state"`
+			Error json.RawMessage `json:"error"`
+		}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("read: %v (acked=%v logged=%v done=%d)", err, acked, logged, done)
+		}
+		switch msg.Type {
+		case "ack":
+			acked = msg.ID == "c1"
+		case "event":
+			logged = logged || msg.Event.Type == "logged"
+		case "state":
+			var s struct {
+				Done int `json:"done"`
+			}
+			_ = json.Unmarshal(msg.State, &s)
+			if logged {
+				done = s.Done
+			}
+		case "error":
+			t.Fatalf("error message: %s", msg.Error)
+		}
+	}
+}
@@ -16,23 +16,51 @@ type State struct {
 func (s State) RunID() string      { return s.Run }
 func (s State) Clone() agent.State { return State{Run: s.Run, Done: s.Done} }
 
-type Reducer struct{}
+// Task is the payload of add_task and complete_task.
+type Task struct {
+	Title string `json:"title"`
+}
+
+// taskSchema is the payload of add_task and complete_task.
+var taskSchema = []byte(`{
+  "type": "object",
+  "properties": {"title": {"type": "string", "minLength": 1}},
+  "required": ["title"]
+}`)
+
+// RegisterEvents declares the schemas of the todo events in reg.
+func RegisterEvents(reg *agent.EventRegistry) error {
+	for _, typ := range []string{"add_task", "complete_task"} {
+		if err := reg.Register(agent.EventSchema{Type: typ, Version: 1, Schema: taskSchema}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var reducer = newReducer()
 
 // Events:
 // - add_task {title}
 // - complete_task {title}
 // For demo, completing a task emits an intent to log.
-func (Reducer) Reduce(ctx context.Context, current agent.State, ev agent.Event) (agent.State, []agent.Intent, error) {
-	st := current.(State)
-	switch ev.Type {
-	case "add_task":
+func newReducer() *agent.TypedReducer[State] {
+	r := agent.NewTypedReducer[State]()
+	agent.On(r, "add_task", func(ctx context.Context, st State, ev agent.Event, t Task) (State, []agent.Intent, error) {
 		return st, nil, nil
-	case "complete_task":
+	})
+	agent.On(r, "complete_task", func(ctx context.Context, st State, ev agent.Event, t Task) (State, []agent.Intent, error) {
 		st.Done++
 		return st, []agent.Intent{{Name: "log", Args: map[string]any{"msg": "task completed"}, IdempotencyKey: ev.ID + "-log"}}, nil
-	default:
-		return st, nil, nil
-	}
+	})
+	return r
+}
+
+// Reducer is the todo reducer as an agent.Reducer.
+type Reducer struct{}
+
+func (Reducer) Reduce(ctx context.Context, current agent.State, ev agent.Event) (agent.State, []agent.Intent, error) {
+	return reducer.Reduce(ctx, current, ev)
 }
 
 type LoggerEffect struct{}
@@ -0,0 +1,373 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+)
+
+// ArchivedEventCreate is the builder for creating a ArchivedEvent entity.
+type ArchivedEventCreate struct {
+	config
+	mutation *ArchivedEventMutation
+	hooks    []Hook
+}
+
+// SetPosition sets the "position" field.
+func (_c *ArchivedEventCreate) SetPosition(v int64) *ArchivedEventCreate {
+	_c.mutation.SetPosition(v)
+	return _c
+}
+
+// SetEventID sets the "event_id" field.
+func (_c *ArchivedEventCreate) SetEventID(v string) *ArchivedEventCreate {
+	_c.mutation.SetEventID(v)
+	return _c
+}
+
+// SetRunID sets the "run_id" field.
+func (_c *ArchivedEventCreate) SetRunID(v string) *ArchivedEventCreate {
+	_c.mutation.SetRunID(v)
+	return _c
+}
+
+// SetSeq sets the "seq" field.
+func (_c *ArchivedEventCreate) SetSeq(v int64) *ArchivedEventCreate {
+	_c.mutation.SetSeq(v)
+	return _c
+}
+
+// SetType sets the "type" field.
+func (_c *ArchivedEventCreate) SetType(v string) *ArchivedEventCreate {
+	_c.mutation.SetType(v)
+	return _c
+}
+
+// SetVersion sets the "version" field.
+func (_c *ArchivedEventCreate) SetVersion(v int) *ArchivedEventCreate {
+	_c.mutation.SetVersion(v)
+	return _c
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_c *ArchivedEventCreate) SetNillableVersion(v *int) *ArchivedEventCreate {
+	if v != nil {
+		_c.SetVersion(*v)
+	}
+	return _c
+}
+
+// SetPayload sets the "payload" field.
+func (_c *ArchivedEventCreate) SetPayload(v string) *ArchivedEventCreate {
+	_c.mutation.SetPayload(v)
+	return _c
+}
+
+// SetNillablePayload sets the "payload" field if the given value is not nil.
+func (_c *ArchivedEventCreate) SetNillablePayload(v *string) *ArchivedEventCreate {
+	if v != nil {
+		_c.SetPayload(*v)
+	}
+	return _c
+}
+
+// SetKeyID sets the "key_id" field.
+func (_c *ArchivedEventCreate) SetKeyID(v string) *ArchivedEventCreate {
+	_c.mutation.SetKeyID(v)
+	return _c
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_c *ArchivedEventCreate) SetNillableKeyID(v *string) *ArchivedEventCreate {
+	if v != nil {
+		_c.SetKeyID(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *ArchivedEventCreate) SetCreatedAt(v time.Time) *ArchivedEventCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetArchivedAt sets the "archived_at" field.
+func (_c *ArchivedEventCreate) SetArchivedAt(v time.Time) *ArchivedEventCreate {
+	_c.mutation.SetArchivedAt(v)
+	return _c
+}
+
+// SetNillableArchivedAt sets the "archived_at" field if the given value is not nil.
+func (_c *ArchivedEventCreate) SetNillableArchivedAt(v *time.Time) *ArchivedEventCreate {
+	if v != nil {
+		_c.SetArchivedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the ArchivedEventMutation object of the builder.
+func (_c *ArchivedEventCreate) Mutation() *ArchivedEventMutation {
+	return _c.mutation
+}
+
+// Save creates the ArchivedEvent in the database.
+func (_c *ArchivedEventCreate) Save(ctx context.Context) (*ArchivedEvent, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ArchivedEventCreate) SaveX(ctx context.Context) *ArchivedEvent {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ArchivedEventCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ArchivedEventCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ArchivedEventCreate) defaults() {
+	if _, ok := _c.mutation.Version(); !ok {
+		v := archivedevent.DefaultVersion
+		_c.mutation.SetVersion(v)
+	}
+	if _, ok := _c.mutation.KeyID(); !ok {
+		v := archivedevent.DefaultKeyID
+		_c.mutation.SetKeyID(v)
+	}
+	if _, ok := _c.mutation.ArchivedAt(); !ok {
+		v := archivedevent.DefaultArchivedAt()
+		_c.mutation.SetArchivedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ArchivedEventCreate) check() error {
+	if _, ok := _c.mutation.Position(); !ok {
+		return &ValidationError{Name: "position", err: errors.New(`ent: missing required field "ArchivedEvent.position"`)}
+	}
+	if _, ok := _c.mutation.EventID(); !ok {
+		return &ValidationError{Name: "event_id", err: errors.New(`ent: missing required field "ArchivedEvent.event_id"`)}
+	}
+	if v, ok := _c.mutation.EventID(); ok {
+		if err := archivedevent.EventIDValidator(v); err != nil {
+			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.event_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.RunID(); !ok {
+		return &ValidationError{Name: "run_id", err: errors.New(`ent: missing required field "ArchivedEvent.run_id"`)}
+	}
+	if v, ok := _c.mutation.RunID(); ok {
+		if err := archivedevent.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.run_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Seq(); !ok {
+		return &ValidationError{Name: "seq", err: errors.New(`ent: missing required field "ArchivedEvent.seq"`)}
+	}
+	if v, ok := _c.mutation.Seq(); ok {
+		if err := archivedevent.SeqValidator(v); err != nil {
+			return &ValidationError{Name: "seq", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.seq": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.GetType(); !ok {
+		return &ValidationError{Name: "type", err: errors.New(`ent: missing required field "ArchivedEvent.type"`)}
+	}
+	if v, ok := _c.mutation.GetType(); ok {
+		if err := archivedevent.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.type": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Version(); !ok {
+		return &ValidationError{Name: "version", err: errors.New(`ent: missing required field "ArchivedEvent.version"`)}
+	}
+	if v, ok := _c.mutation.Version(); ok {
+		if err := archivedevent.VersionValidator(v); err != nil {
+			return &ValidationError{Name: "version", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.version": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.KeyID(); !ok {
+		return &ValidationError{Name: "key_id", err: errors.New(`ent: missing required field "ArchivedEvent.key_id"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ArchivedEvent.created_at"`)}
+	}
+	if _, ok := _c.mutation.ArchivedAt(); !ok {
+		return &ValidationError{Name: "archived_at", err: errors.New(`ent: missing required field "ArchivedEvent.archived_at"`)}
+	}
+	return nil
+}
+
+func (_c *ArchivedEventCreate) sqlSave(ctx context.Context) (*ArchivedEvent, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ArchivedEventCreate) createSpec() (*ArchivedEvent, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ArchivedEvent{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(archivedevent.Table, sqlgraph.NewFieldSpec(archivedevent.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.Position(); ok {
+		_spec.SetField(archivedevent.FieldPosition, field.TypeInt64, value)
+		_node.Position = value
+	}
+	if value, ok := _c.mutation.EventID(); ok {
+		_spec.SetField(archivedevent.FieldEventID, field.TypeString, value)
+		_node.EventID = value
+	}
+	if value, ok := _c.mutation.RunID(); ok {
+		_spec.SetField(archivedevent.FieldRunID, field.TypeString, value)
+		_node.RunID = value
+	}
+	if value, ok := _c.mutation.Seq(); ok {
+		_spec.SetField(archivedevent.FieldSeq, field.TypeInt64, value)
+		_node.Seq = value
+	}
+	if value, ok := _c.mutation.GetType(); ok {
+		_spec.SetField(archivedevent.FieldType, field.TypeString, value)
+		_node.Type = value
+	}
+	if value, ok := _c.mutation.Version(); ok {
+		_spec.SetField(archivedevent.FieldVersion, field.TypeInt, value)
+		_node.Version = value
+	}
+	if value, ok := _c.mutation.Payload(); ok {
+		_spec.SetField(archivedevent.FieldPayload, field.TypeString, value)
+		_node.Payload = value
+	}
+	if value, ok := _c.mutation.KeyID(); ok {
+		_spec.SetField(archivedevent.FieldKeyID, field.TypeString, value)
+		_node.KeyID = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(archivedevent.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.ArchivedAt(); ok {
+		_spec.SetField(archivedevent.FieldArchivedAt, field.TypeTime, value)
+		_node.ArchivedAt = value
+	}
+	return _node, _spec
+}
+
+// ArchivedEventCreateBulk is the builder for creating many ArchivedEvent entities in bulk.
+type ArchivedEventCreateBulk struct {
+	config
+	err      error
+	builders []*ArchivedEventCreate
+}
+
+// Save creates the ArchivedEvent entities in the database.
+func (_c *ArchivedEventCreateBulk) Save(ctx context.Context) ([]*ArchivedEvent, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ArchivedEvent, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ArchivedEventMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ArchivedEventCreateBulk) SaveX(ctx context.Context) []*ArchivedEvent {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ArchivedEventCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ArchivedEventCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
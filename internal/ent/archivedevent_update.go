@@ -0,0 +1,591 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// ArchivedEventUpdate is the builder for updating ArchivedEvent entities.
+type ArchivedEventUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ArchivedEventMutation
+}
+
+// Where appends a list predicates to the ArchivedEventUpdate builder.
+func (_u *ArchivedEventUpdate) Where(ps ...predicate.ArchivedEvent) *ArchivedEventUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetPosition sets the "position" field.
+func (_u *ArchivedEventUpdate) SetPosition(v int64) *ArchivedEventUpdate {
+	_u.mutation.ResetPosition()
+	_u.mutation.SetPosition(v)
+	return _u
+}
+
+// SetNillablePosition sets the "position" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillablePosition(v *int64) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetPosition(*v)
+	}
+	return _u
+}
+
+// AddPosition adds value to the "position" field.
+func (_u *ArchivedEventUpdate) AddPosition(v int64) *ArchivedEventUpdate {
+	_u.mutation.AddPosition(v)
+	return _u
+}
+
+// SetEventID sets the "event_id" field.
+func (_u *ArchivedEventUpdate) SetEventID(v string) *ArchivedEventUpdate {
+	_u.mutation.SetEventID(v)
+	return _u
+}
+
+// SetNillableEventID sets the "event_id" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillableEventID(v *string) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetEventID(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *ArchivedEventUpdate) SetRunID(v string) *ArchivedEventUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillableRunID(v *string) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetSeq sets the "seq" field.
+func (_u *ArchivedEventUpdate) SetSeq(v int64) *ArchivedEventUpdate {
+	_u.mutation.ResetSeq()
+	_u.mutation.SetSeq(v)
+	return _u
+}
+
+// SetNillableSeq sets the "seq" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillableSeq(v *int64) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetSeq(*v)
+	}
+	return _u
+}
+
+// AddSeq adds value to the "seq" field.
+func (_u *ArchivedEventUpdate) AddSeq(v int64) *ArchivedEventUpdate {
+	_u.mutation.AddSeq(v)
+	return _u
+}
+
+// SetType sets the "type" field.
+func (_u *ArchivedEventUpdate) SetType(v string) *ArchivedEventUpdate {
+	_u.mutation.SetType(v)
+	return _u
+}
+
+// SetNillableType sets the "type" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillableType(v *string) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetType(*v)
+	}
+	return _u
+}
+
+// SetVersion sets the "version" field.
+func (_u *ArchivedEventUpdate) SetVersion(v int) *ArchivedEventUpdate {
+	_u.mutation.ResetVersion()
+	_u.mutation.SetVersion(v)
+	return _u
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillableVersion(v *int) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetVersion(*v)
+	}
+	return _u
+}
+
+// AddVersion adds value to the "version" field.
+func (_u *ArchivedEventUpdate) AddVersion(v int) *ArchivedEventUpdate {
+	_u.mutation.AddVersion(v)
+	return _u
+}
+
+// SetPayload sets the "payload" field.
+func (_u *ArchivedEventUpdate) SetPayload(v string) *ArchivedEventUpdate {
+	_u.mutation.SetPayload(v)
+	return _u
+}
+
+// SetNillablePayload sets the "payload" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillablePayload(v *string) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetPayload(*v)
+	}
+	return _u
+}
+
+// ClearPayload clears the value of the "payload" field.
+func (_u *ArchivedEventUpdate) ClearPayload() *ArchivedEventUpdate {
+	_u.mutation.ClearPayload()
+	return _u
+}
+
+// SetKeyID sets the "key_id" field.
+func (_u *ArchivedEventUpdate) SetKeyID(v string) *ArchivedEventUpdate {
+	_u.mutation.SetKeyID(v)
+	return _u
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_u *ArchivedEventUpdate) SetNillableKeyID(v *string) *ArchivedEventUpdate {
+	if v != nil {
+		_u.SetKeyID(*v)
+	}
+	return _u
+}
+
+// Mutation returns the ArchivedEventMutation object of the builder.
+func (_u *ArchivedEventUpdate) Mutation() *ArchivedEventMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ArchivedEventUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ArchivedEventUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ArchivedEventUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ArchivedEventUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ArchivedEventUpdate) check() error {
+	if v, ok := _u.mutation.EventID(); ok {
+		if err := archivedevent.EventIDValidator(v); err != nil {
+			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.event_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := archivedevent.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Seq(); ok {
+		if err := archivedevent.SeqValidator(v); err != nil {
+			return &ValidationError{Name: "seq", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.seq": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.GetType(); ok {
+		if err := archivedevent.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.type": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Version(); ok {
+		if err := archivedevent.VersionValidator(v); err != nil {
+			return &ValidationError{Name: "version", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.version": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ArchivedEventUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(archivedevent.Table, archivedevent.Columns, sqlgraph.NewFieldSpec(archivedevent.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Position(); ok {
+		_spec.SetField(archivedevent.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedPosition(); ok {
+		_spec.AddField(archivedevent.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.EventID(); ok {
+		_spec.SetField(archivedevent.FieldEventID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(archivedevent.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Seq(); ok {
+		_spec.SetField(archivedevent.FieldSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedSeq(); ok {
+		_spec.AddField(archivedevent.FieldSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.GetType(); ok {
+		_spec.SetField(archivedevent.FieldType, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Version(); ok {
+		_spec.SetField(archivedevent.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedVersion(); ok {
+		_spec.AddField(archivedevent.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Payload(); ok {
+		_spec.SetField(archivedevent.FieldPayload, field.TypeString, value)
+	}
+	if _u.mutation.PayloadCleared() {
+		_spec.ClearField(archivedevent.FieldPayload, field.TypeString)
+	}
+	if value, ok := _u.mutation.KeyID(); ok {
+		_spec.SetField(archivedevent.FieldKeyID, field.TypeString, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{archivedevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ArchivedEventUpdateOne is the builder for updating a single ArchivedEvent entity.
+type ArchivedEventUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ArchivedEventMutation
+}
+
+// SetPosition sets the "position" field.
+func (_u *ArchivedEventUpdateOne) SetPosition(v int64) *ArchivedEventUpdateOne {
+	_u.mutation.ResetPosition()
+	_u.mutation.SetPosition(v)
+	return _u
+}
+
+// SetNillablePosition sets the "position" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillablePosition(v *int64) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetPosition(*v)
+	}
+	return _u
+}
+
+// AddPosition adds value to the "position" field.
+func (_u *ArchivedEventUpdateOne) AddPosition(v int64) *ArchivedEventUpdateOne {
+	_u.mutation.AddPosition(v)
+	return _u
+}
+
+// SetEventID sets the "event_id" field.
+func (_u *ArchivedEventUpdateOne) SetEventID(v string) *ArchivedEventUpdateOne {
+	_u.mutation.SetEventID(v)
+	return _u
+}
+
+// SetNillableEventID sets the "event_id" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillableEventID(v *string) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetEventID(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *ArchivedEventUpdateOne) SetRunID(v string) *ArchivedEventUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillableRunID(v *string) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetSeq sets the "seq" field.
+func (_u *ArchivedEventUpdateOne) SetSeq(v int64) *ArchivedEventUpdateOne {
+	_u.mutation.ResetSeq()
+	_u.mutation.SetSeq(v)
+	return _u
+}
+
+// SetNillableSeq sets the "seq" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillableSeq(v *int64) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetSeq(*v)
+	}
+	return _u
+}
+
+// AddSeq adds value to the "seq" field.
+func (_u *ArchivedEventUpdateOne) AddSeq(v int64) *ArchivedEventUpdateOne {
+	_u.mutation.AddSeq(v)
+	return _u
+}
+
+// SetType sets the "type" field.
+func (_u *ArchivedEventUpdateOne) SetType(v string) *ArchivedEventUpdateOne {
+	_u.mutation.SetType(v)
+	return _u
+}
+
+// SetNillableType sets the "type" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillableType(v *string) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetType(*v)
+	}
+	return _u
+}
+
+// SetVersion sets the "version" field.
+func (_u *ArchivedEventUpdateOne) SetVersion(v int) *ArchivedEventUpdateOne {
+	_u.mutation.ResetVersion()
+	_u.mutation.SetVersion(v)
+	return _u
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillableVersion(v *int) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetVersion(*v)
+	}
+	return _u
+}
+
+// AddVersion adds value to the "version" field.
+func (_u *ArchivedEventUpdateOne) AddVersion(v int) *ArchivedEventUpdateOne {
+	_u.mutation.AddVersion(v)
+	return _u
+}
+
+// SetPayload sets the "payload" field.
+func (_u *ArchivedEventUpdateOne) SetPayload(v string) *ArchivedEventUpdateOne {
+	_u.mutation.SetPayload(v)
+	return _u
+}
+
+// SetNillablePayload sets the "payload" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillablePayload(v *string) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetPayload(*v)
+	}
+	return _u
+}
+
+// ClearPayload clears the value of the "payload" field.
+func (_u *ArchivedEventUpdateOne) ClearPayload() *ArchivedEventUpdateOne {
+	_u.mutation.ClearPayload()
+	return _u
+}
+
+// SetKeyID sets the "key_id" field.
+func (_u *ArchivedEventUpdateOne) SetKeyID(v string) *ArchivedEventUpdateOne {
+	_u.mutation.SetKeyID(v)
+	return _u
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_u *ArchivedEventUpdateOne) SetNillableKeyID(v *string) *ArchivedEventUpdateOne {
+	if v != nil {
+		_u.SetKeyID(*v)
+	}
+	return _u
+}
+
+// Mutation returns the ArchivedEventMutation object of the builder.
+func (_u *ArchivedEventUpdateOne) Mutation() *ArchivedEventMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ArchivedEventUpdate builder.
+func (_u *ArchivedEventUpdateOne) Where(ps ...predicate.ArchivedEvent) *ArchivedEventUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ArchivedEventUpdateOne) Select(field string, fields ...string) *ArchivedEventUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ArchivedEvent entity.
+func (_u *ArchivedEventUpdateOne) Save(ctx context.Context) (*ArchivedEvent, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ArchivedEventUpdateOne) SaveX(ctx context.Context) *ArchivedEvent {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ArchivedEventUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ArchivedEventUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ArchivedEventUpdateOne) check() error {
+	if v, ok := _u.mutation.EventID(); ok {
+		if err := archivedevent.EventIDValidator(v); err != nil {
+			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.event_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := archivedevent.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Seq(); ok {
+		if err := archivedevent.SeqValidator(v); err != nil {
+			return &ValidationError{Name: "seq", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.seq": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.GetType(); ok {
+		if err := archivedevent.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.type": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Version(); ok {
+		if err := archivedevent.VersionValidator(v); err != nil {
+			return &ValidationError{Name: "version", err: fmt.Errorf(`ent: validator failed for field "ArchivedEvent.version": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ArchivedEventUpdateOne) sqlSave(ctx context.Context) (_node *ArchivedEvent, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(archivedevent.Table, archivedevent.Columns, sqlgraph.NewFieldSpec(archivedevent.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ArchivedEvent.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, archivedevent.FieldID)
+		for _, f := range fields {
+			if !archivedevent.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != archivedevent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Position(); ok {
+		_spec.SetField(archivedevent.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedPosition(); ok {
+		_spec.AddField(archivedevent.FieldPosition, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.EventID(); ok {
+		_spec.SetField(archivedevent.FieldEventID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(archivedevent.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Seq(); ok {
+		_spec.SetField(archivedevent.FieldSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedSeq(); ok {
+		_spec.AddField(archivedevent.FieldSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.GetType(); ok {
+		_spec.SetField(archivedevent.FieldType, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Version(); ok {
+		_spec.SetField(archivedevent.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedVersion(); ok {
+		_spec.AddField(archivedevent.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Payload(); ok {
+		_spec.SetField(archivedevent.FieldPayload, field.TypeString, value)
+	}
+	if _u.mutation.PayloadCleared() {
+		_spec.ClearField(archivedevent.FieldPayload, field.TypeString)
+	}
+	if value, ok := _u.mutation.KeyID(); ok {
+		_spec.SetField(archivedevent.FieldKeyID, field.TypeString, value)
+	}
+	_node = &ArchivedEvent{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{archivedevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,191 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/deadletter"
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// DeadLetter is the model entity for the DeadLetter schema.
+type DeadLetter struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// DeadLetterID holds the value of the "dead_letter_id" field.
+	DeadLetterID string `json:"dead_letter_id,omitempty"`
+	// RunID holds the value of the "run_id" field.
+	RunID string `json:"run_id,omitempty"`
+	// IntentName holds the value of the "intent_name" field.
+	IntentName string `json:"intent_name,omitempty"`
+	// IdempotencyKey holds the value of the "idempotency_key" field.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// Args holds the value of the "args" field.
+	Args map[string]interface{} `json:"args,omitempty"`
+	// Attempts holds the value of the "attempts" field.
+	Attempts int `json:"attempts,omitempty"`
+	// Error holds the value of the "error" field.
+	Error errmodel.Error `json:"error,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*DeadLetter) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case deadletter.FieldArgs, deadletter.FieldError:
+			values[i] = new([]byte)
+		case deadletter.FieldID, deadletter.FieldAttempts:
+			values[i] = new(sql.NullInt64)
+		case deadletter.FieldDeadLetterID, deadletter.FieldRunID, deadletter.FieldIntentName, deadletter.FieldIdempotencyKey:
+			values[i] = new(sql.NullString)
+		case deadletter.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the DeadLetter fields.
+func (_m *DeadLetter) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case deadletter.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int(value.Int64)
+		case deadletter.FieldDeadLetterID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field dead_letter_id", values[i])
+			} else if value.Valid {
+				_m.DeadLetterID = value.String
+			}
+		case deadletter.FieldRunID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field run_id", values[i])
+			} else if value.Valid {
+				_m.RunID = value.String
+			}
+		case deadletter.FieldIntentName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field intent_name", values[i])
+			} else if value.Valid {
+				_m.IntentName = value.String
+			}
+		case deadletter.FieldIdempotencyKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field idempotency_key", values[i])
+			} else if value.Valid {
+				_m.IdempotencyKey = value.String
+			}
+		case deadletter.FieldArgs:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field args", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Args); err != nil {
+					return fmt.Errorf("unmarshal field args: %w", err)
+				}
+			}
+		case deadletter.FieldAttempts:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field attempts", values[i])
+			} else if value.Valid {
+				_m.Attempts = int(value.Int64)
+			}
+		case deadletter.FieldError:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field error", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Error); err != nil {
+					return fmt.Errorf("unmarshal field error: %w", err)
+				}
+			}
+		case deadletter.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the DeadLetter.
+// This includes values selected through modifiers, order, etc.
+func (_m *DeadLetter) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this DeadLetter.
+// Note that you need to call DeadLetter.Unwrap() before calling this method if this DeadLetter
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *DeadLetter) Update() *DeadLetterUpdateOne {
+	return NewDeadLetterClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the DeadLetter entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *DeadLetter) Unwrap() *DeadLetter {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: DeadLetter is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *DeadLetter) String() string {
+	var builder strings.Builder
+	builder.WriteString("DeadLetter(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("dead_letter_id=")
+	builder.WriteString(_m.DeadLetterID)
+	builder.WriteString(", ")
+	builder.WriteString("run_id=")
+	builder.WriteString(_m.RunID)
+	builder.WriteString(", ")
+	builder.WriteString("intent_name=")
+	builder.WriteString(_m.IntentName)
+	builder.WriteString(", ")
+	builder.WriteString("idempotency_key=")
+	builder.WriteString(_m.IdempotencyKey)
+	builder.WriteString(", ")
+	builder.WriteString("args=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Args))
+	builder.WriteString(", ")
+	builder.WriteString("attempts=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Attempts))
+	builder.WriteString(", ")
+	builder.WriteString("error=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Error))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// DeadLetters is a parsable slice of DeadLetter.
+type DeadLetters []*DeadLetter
@@ -0,0 +1,108 @@
+// Code generated by ent, DO NOT EDIT.
+
+package deadletter
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the deadletter type in the database.
+	Label = "dead_letter"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldDeadLetterID holds the string denoting the dead_letter_id field in the database.
+	FieldDeadLetterID = "dead_letter_id"
+	// FieldRunID holds the string denoting the run_id field in the database.
+	FieldRunID = "run_id"
+	// FieldIntentName holds the string denoting the intent_name field in the database.
+	FieldIntentName = "intent_name"
+	// FieldIdempotencyKey holds the string denoting the idempotency_key field in the database.
+	FieldIdempotencyKey = "idempotency_key"
+	// FieldArgs holds the string denoting the args field in the database.
+	FieldArgs = "args"
+	// FieldAttempts holds the string denoting the attempts field in the database.
+	FieldAttempts = "attempts"
+	// FieldError holds the string denoting the error field in the database.
+	FieldError = "error"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// Table holds the table name of the deadletter in the database.
+	Table = "dead_letters"
+)
+
+// Columns holds all SQL columns for deadletter fields.
+var Columns = []string{
+	FieldID,
+	FieldDeadLetterID,
+	FieldRunID,
+	FieldIntentName,
+	FieldIdempotencyKey,
+	FieldArgs,
+	FieldAttempts,
+	FieldError,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DeadLetterIDValidator is a validator for the "dead_letter_id" field. It is called by the builders before save.
+	DeadLetterIDValidator func(string) error
+	// RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	RunIDValidator func(string) error
+	// IntentNameValidator is a validator for the "intent_name" field. It is called by the builders before save.
+	IntentNameValidator func(string) error
+	// AttemptsValidator is a validator for the "attempts" field. It is called by the builders before save.
+	AttemptsValidator func(int) error
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// OrderOption defines the ordering options for the DeadLetter queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByDeadLetterID orders the results by the dead_letter_id field.
+func ByDeadLetterID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDeadLetterID, opts...).ToFunc()
+}
+
+// ByRunID orders the results by the run_id field.
+func ByRunID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRunID, opts...).ToFunc()
+}
+
+// ByIntentName orders the results by the intent_name field.
+func ByIntentName(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIntentName, opts...).ToFunc()
+}
+
+// ByIdempotencyKey orders the results by the idempotency_key field.
+func ByIdempotencyKey(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIdempotencyKey, opts...).ToFunc()
+}
+
+// ByAttempts orders the results by the attempts field.
+func ByAttempts(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAttempts, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
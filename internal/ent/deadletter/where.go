@@ -0,0 +1,460 @@
+// Code generated by ent, DO NOT EDIT.
+
+package deadletter
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldID, id))
+}
+
+// DeadLetterID applies equality check predicate on the "dead_letter_id" field. It's identical to DeadLetterIDEQ.
+func DeadLetterID(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldDeadLetterID, v))
+}
+
+// RunID applies equality check predicate on the "run_id" field. It's identical to RunIDEQ.
+func RunID(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldRunID, v))
+}
+
+// IntentName applies equality check predicate on the "intent_name" field. It's identical to IntentNameEQ.
+func IntentName(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldIntentName, v))
+}
+
+// IdempotencyKey applies equality check predicate on the "idempotency_key" field. It's identical to IdempotencyKeyEQ.
+func IdempotencyKey(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldIdempotencyKey, v))
+}
+
+// Attempts applies equality check predicate on the "attempts" field. It's identical to AttemptsEQ.
+func Attempts(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldAttempts, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// DeadLetterIDEQ applies the EQ predicate on the "dead_letter_id" field.
+func DeadLetterIDEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDNEQ applies the NEQ predicate on the "dead_letter_id" field.
+func DeadLetterIDNEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDIn applies the In predicate on the "dead_letter_id" field.
+func DeadLetterIDIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldDeadLetterID, vs...))
+}
+
+// DeadLetterIDNotIn applies the NotIn predicate on the "dead_letter_id" field.
+func DeadLetterIDNotIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldDeadLetterID, vs...))
+}
+
+// DeadLetterIDGT applies the GT predicate on the "dead_letter_id" field.
+func DeadLetterIDGT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDGTE applies the GTE predicate on the "dead_letter_id" field.
+func DeadLetterIDGTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDLT applies the LT predicate on the "dead_letter_id" field.
+func DeadLetterIDLT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDLTE applies the LTE predicate on the "dead_letter_id" field.
+func DeadLetterIDLTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDContains applies the Contains predicate on the "dead_letter_id" field.
+func DeadLetterIDContains(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContains(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDHasPrefix applies the HasPrefix predicate on the "dead_letter_id" field.
+func DeadLetterIDHasPrefix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasPrefix(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDHasSuffix applies the HasSuffix predicate on the "dead_letter_id" field.
+func DeadLetterIDHasSuffix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasSuffix(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDEqualFold applies the EqualFold predicate on the "dead_letter_id" field.
+func DeadLetterIDEqualFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEqualFold(FieldDeadLetterID, v))
+}
+
+// DeadLetterIDContainsFold applies the ContainsFold predicate on the "dead_letter_id" field.
+func DeadLetterIDContainsFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContainsFold(FieldDeadLetterID, v))
+}
+
+// RunIDEQ applies the EQ predicate on the "run_id" field.
+func RunIDEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldRunID, v))
+}
+
+// RunIDNEQ applies the NEQ predicate on the "run_id" field.
+func RunIDNEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldRunID, v))
+}
+
+// RunIDIn applies the In predicate on the "run_id" field.
+func RunIDIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldRunID, vs...))
+}
+
+// RunIDNotIn applies the NotIn predicate on the "run_id" field.
+func RunIDNotIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldRunID, vs...))
+}
+
+// RunIDGT applies the GT predicate on the "run_id" field.
+func RunIDGT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldRunID, v))
+}
+
+// RunIDGTE applies the GTE predicate on the "run_id" field.
+func RunIDGTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldRunID, v))
+}
+
+// RunIDLT applies the LT predicate on the "run_id" field.
+func RunIDLT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldRunID, v))
+}
+
+// RunIDLTE applies the LTE predicate on the "run_id" field.
+func RunIDLTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldRunID, v))
+}
+
+// RunIDContains applies the Contains predicate on the "run_id" field.
+func RunIDContains(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContains(FieldRunID, v))
+}
+
+// RunIDHasPrefix applies the HasPrefix predicate on the "run_id" field.
+func RunIDHasPrefix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasPrefix(FieldRunID, v))
+}
+
+// RunIDHasSuffix applies the HasSuffix predicate on the "run_id" field.
+func RunIDHasSuffix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasSuffix(FieldRunID, v))
+}
+
+// RunIDEqualFold applies the EqualFold predicate on the "run_id" field.
+func RunIDEqualFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEqualFold(FieldRunID, v))
+}
+
+// RunIDContainsFold applies the ContainsFold predicate on the "run_id" field.
+func RunIDContainsFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContainsFold(FieldRunID, v))
+}
+
+// IntentNameEQ applies the EQ predicate on the "intent_name" field.
+func IntentNameEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldIntentName, v))
+}
+
+// IntentNameNEQ applies the NEQ predicate on the "intent_name" field.
+func IntentNameNEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldIntentName, v))
+}
+
+// IntentNameIn applies the In predicate on the "intent_name" field.
+func IntentNameIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldIntentName, vs...))
+}
+
+// IntentNameNotIn applies the NotIn predicate on the "intent_name" field.
+func IntentNameNotIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldIntentName, vs...))
+}
+
+// IntentNameGT applies the GT predicate on the "intent_name" field.
+func IntentNameGT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldIntentName, v))
+}
+
+// IntentNameGTE applies the GTE predicate on the "intent_name" field.
+func IntentNameGTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldIntentName, v))
+}
+
+// IntentNameLT applies the LT predicate on the "intent_name" field.
+func IntentNameLT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldIntentName, v))
+}
+
+// IntentNameLTE applies the LTE predicate on the "intent_name" field.
+func IntentNameLTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldIntentName, v))
+}
+
+// IntentNameContains applies the Contains predicate on the "intent_name" field.
+func IntentNameContains(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContains(FieldIntentName, v))
+}
+
+// IntentNameHasPrefix applies the HasPrefix predicate on the "intent_name" field.
+func IntentNameHasPrefix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasPrefix(FieldIntentName, v))
+}
+
+// IntentNameHasSuffix applies the HasSuffix predicate on the "intent_name" field.
+func IntentNameHasSuffix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasSuffix(FieldIntentName, v))
+}
+
+// IntentNameEqualFold applies the EqualFold predicate on the "intent_name" field.
+func IntentNameEqualFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEqualFold(FieldIntentName, v))
+}
+
+// IntentNameContainsFold applies the ContainsFold predicate on the "intent_name" field.
+func IntentNameContainsFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContainsFold(FieldIntentName, v))
+}
+
+// IdempotencyKeyEQ applies the EQ predicate on the "idempotency_key" field.
+func IdempotencyKeyEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyNEQ applies the NEQ predicate on the "idempotency_key" field.
+func IdempotencyKeyNEQ(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyIn applies the In predicate on the "idempotency_key" field.
+func IdempotencyKeyIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldIdempotencyKey, vs...))
+}
+
+// IdempotencyKeyNotIn applies the NotIn predicate on the "idempotency_key" field.
+func IdempotencyKeyNotIn(vs ...string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldIdempotencyKey, vs...))
+}
+
+// IdempotencyKeyGT applies the GT predicate on the "idempotency_key" field.
+func IdempotencyKeyGT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyGTE applies the GTE predicate on the "idempotency_key" field.
+func IdempotencyKeyGTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyLT applies the LT predicate on the "idempotency_key" field.
+func IdempotencyKeyLT(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyLTE applies the LTE predicate on the "idempotency_key" field.
+func IdempotencyKeyLTE(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyContains applies the Contains predicate on the "idempotency_key" field.
+func IdempotencyKeyContains(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContains(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyHasPrefix applies the HasPrefix predicate on the "idempotency_key" field.
+func IdempotencyKeyHasPrefix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasPrefix(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyHasSuffix applies the HasSuffix predicate on the "idempotency_key" field.
+func IdempotencyKeyHasSuffix(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldHasSuffix(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyIsNil applies the IsNil predicate on the "idempotency_key" field.
+func IdempotencyKeyIsNil() predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIsNull(FieldIdempotencyKey))
+}
+
+// IdempotencyKeyNotNil applies the NotNil predicate on the "idempotency_key" field.
+func IdempotencyKeyNotNil() predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotNull(FieldIdempotencyKey))
+}
+
+// IdempotencyKeyEqualFold applies the EqualFold predicate on the "idempotency_key" field.
+func IdempotencyKeyEqualFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEqualFold(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyContainsFold applies the ContainsFold predicate on the "idempotency_key" field.
+func IdempotencyKeyContainsFold(v string) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldContainsFold(FieldIdempotencyKey, v))
+}
+
+// ArgsIsNil applies the IsNil predicate on the "args" field.
+func ArgsIsNil() predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIsNull(FieldArgs))
+}
+
+// ArgsNotNil applies the NotNil predicate on the "args" field.
+func ArgsNotNil() predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotNull(FieldArgs))
+}
+
+// AttemptsEQ applies the EQ predicate on the "attempts" field.
+func AttemptsEQ(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldAttempts, v))
+}
+
+// AttemptsNEQ applies the NEQ predicate on the "attempts" field.
+func AttemptsNEQ(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldAttempts, v))
+}
+
+// AttemptsIn applies the In predicate on the "attempts" field.
+func AttemptsIn(vs ...int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldAttempts, vs...))
+}
+
+// AttemptsNotIn applies the NotIn predicate on the "attempts" field.
+func AttemptsNotIn(vs ...int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldAttempts, vs...))
+}
+
+// AttemptsGT applies the GT predicate on the "attempts" field.
+func AttemptsGT(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldAttempts, v))
+}
+
+// AttemptsGTE applies the GTE predicate on the "attempts" field.
+func AttemptsGTE(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldAttempts, v))
+}
+
+// AttemptsLT applies the LT predicate on the "attempts" field.
+func AttemptsLT(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldAttempts, v))
+}
+
+// AttemptsLTE applies the LTE predicate on the "attempts" field.
+func AttemptsLTE(v int) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldAttempts, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.DeadLetter) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.DeadLetter) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.DeadLetter) predicate.DeadLetter {
+	return predicate.DeadLetter(sql.NotPredicates(p))
+}
@@ -0,0 +1,510 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/deadletter"
+	"github.com/wilhg/orch/internal/ent/predicate"
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// DeadLetterUpdate is the builder for updating DeadLetter entities.
+type DeadLetterUpdate struct {
+	config
+	hooks    []Hook
+	mutation *DeadLetterMutation
+}
+
+// Where appends a list predicates to the DeadLetterUpdate builder.
+func (_u *DeadLetterUpdate) Where(ps ...predicate.DeadLetter) *DeadLetterUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetDeadLetterID sets the "dead_letter_id" field.
+func (_u *DeadLetterUpdate) SetDeadLetterID(v string) *DeadLetterUpdate {
+	_u.mutation.SetDeadLetterID(v)
+	return _u
+}
+
+// SetNillableDeadLetterID sets the "dead_letter_id" field if the given value is not nil.
+func (_u *DeadLetterUpdate) SetNillableDeadLetterID(v *string) *DeadLetterUpdate {
+	if v != nil {
+		_u.SetDeadLetterID(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *DeadLetterUpdate) SetRunID(v string) *DeadLetterUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *DeadLetterUpdate) SetNillableRunID(v *string) *DeadLetterUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetIntentName sets the "intent_name" field.
+func (_u *DeadLetterUpdate) SetIntentName(v string) *DeadLetterUpdate {
+	_u.mutation.SetIntentName(v)
+	return _u
+}
+
+// SetNillableIntentName sets the "intent_name" field if the given value is not nil.
+func (_u *DeadLetterUpdate) SetNillableIntentName(v *string) *DeadLetterUpdate {
+	if v != nil {
+		_u.SetIntentName(*v)
+	}
+	return _u
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (_u *DeadLetterUpdate) SetIdempotencyKey(v string) *DeadLetterUpdate {
+	_u.mutation.SetIdempotencyKey(v)
+	return _u
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (_u *DeadLetterUpdate) SetNillableIdempotencyKey(v *string) *DeadLetterUpdate {
+	if v != nil {
+		_u.SetIdempotencyKey(*v)
+	}
+	return _u
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (_u *DeadLetterUpdate) ClearIdempotencyKey() *DeadLetterUpdate {
+	_u.mutation.ClearIdempotencyKey()
+	return _u
+}
+
+// SetArgs sets the "args" field.
+func (_u *DeadLetterUpdate) SetArgs(v map[string]interface{}) *DeadLetterUpdate {
+	_u.mutation.SetArgs(v)
+	return _u
+}
+
+// ClearArgs clears the value of the "args" field.
+func (_u *DeadLetterUpdate) ClearArgs() *DeadLetterUpdate {
+	_u.mutation.ClearArgs()
+	return _u
+}
+
+// SetAttempts sets the "attempts" field.
+func (_u *DeadLetterUpdate) SetAttempts(v int) *DeadLetterUpdate {
+	_u.mutation.ResetAttempts()
+	_u.mutation.SetAttempts(v)
+	return _u
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_u *DeadLetterUpdate) SetNillableAttempts(v *int) *DeadLetterUpdate {
+	if v != nil {
+		_u.SetAttempts(*v)
+	}
+	return _u
+}
+
+// AddAttempts adds value to the "attempts" field.
+func (_u *DeadLetterUpdate) AddAttempts(v int) *DeadLetterUpdate {
+	_u.mutation.AddAttempts(v)
+	return _u
+}
+
+// SetError sets the "error" field.
+func (_u *DeadLetterUpdate) SetError(v errmodel.Error) *DeadLetterUpdate {
+	_u.mutation.SetError(v)
+	return _u
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_u *DeadLetterUpdate) SetNillableError(v *errmodel.Error) *DeadLetterUpdate {
+	if v != nil {
+		_u.SetError(*v)
+	}
+	return _u
+}
+
+// Mutation returns the DeadLetterMutation object of the builder.
+func (_u *DeadLetterUpdate) Mutation() *DeadLetterMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *DeadLetterUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *DeadLetterUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *DeadLetterUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *DeadLetterUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *DeadLetterUpdate) check() error {
+	if v, ok := _u.mutation.DeadLetterID(); ok {
+		if err := deadletter.DeadLetterIDValidator(v); err != nil {
+			return &ValidationError{Name: "dead_letter_id", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.dead_letter_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := deadletter.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.IntentName(); ok {
+		if err := deadletter.IntentNameValidator(v); err != nil {
+			return &ValidationError{Name: "intent_name", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.intent_name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Attempts(); ok {
+		if err := deadletter.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.attempts": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *DeadLetterUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(deadletter.Table, deadletter.Columns, sqlgraph.NewFieldSpec(deadletter.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.DeadLetterID(); ok {
+		_spec.SetField(deadletter.FieldDeadLetterID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(deadletter.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IntentName(); ok {
+		_spec.SetField(deadletter.FieldIntentName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IdempotencyKey(); ok {
+		_spec.SetField(deadletter.FieldIdempotencyKey, field.TypeString, value)
+	}
+	if _u.mutation.IdempotencyKeyCleared() {
+		_spec.ClearField(deadletter.FieldIdempotencyKey, field.TypeString)
+	}
+	if value, ok := _u.mutation.Args(); ok {
+		_spec.SetField(deadletter.FieldArgs, field.TypeJSON, value)
+	}
+	if _u.mutation.ArgsCleared() {
+		_spec.ClearField(deadletter.FieldArgs, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Attempts(); ok {
+		_spec.SetField(deadletter.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAttempts(); ok {
+		_spec.AddField(deadletter.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Error(); ok {
+		_spec.SetField(deadletter.FieldError, field.TypeJSON, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{deadletter.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// DeadLetterUpdateOne is the builder for updating a single DeadLetter entity.
+type DeadLetterUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *DeadLetterMutation
+}
+
+// SetDeadLetterID sets the "dead_letter_id" field.
+func (_u *DeadLetterUpdateOne) SetDeadLetterID(v string) *DeadLetterUpdateOne {
+	_u.mutation.SetDeadLetterID(v)
+	return _u
+}
+
+// SetNillableDeadLetterID sets the "dead_letter_id" field if the given value is not nil.
+func (_u *DeadLetterUpdateOne) SetNillableDeadLetterID(v *string) *DeadLetterUpdateOne {
+	if v != nil {
+		_u.SetDeadLetterID(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *DeadLetterUpdateOne) SetRunID(v string) *DeadLetterUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *DeadLetterUpdateOne) SetNillableRunID(v *string) *DeadLetterUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetIntentName sets the "intent_name" field.
+func (_u *DeadLetterUpdateOne) SetIntentName(v string) *DeadLetterUpdateOne {
+	_u.mutation.SetIntentName(v)
+	return _u
+}
+
+// SetNillableIntentName sets the "intent_name" field if the given value is not nil.
+func (_u *DeadLetterUpdateOne) SetNillableIntentName(v *string) *DeadLetterUpdateOne {
+	if v != nil {
+		_u.SetIntentName(*v)
+	}
+	return _u
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (_u *DeadLetterUpdateOne) SetIdempotencyKey(v string) *DeadLetterUpdateOne {
+	_u.mutation.SetIdempotencyKey(v)
+	return _u
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (_u *DeadLetterUpdateOne) SetNillableIdempotencyKey(v *string) *DeadLetterUpdateOne {
+	if v != nil {
+		_u.SetIdempotencyKey(*v)
+	}
+	return _u
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (_u *DeadLetterUpdateOne) ClearIdempotencyKey() *DeadLetterUpdateOne {
+	_u.mutation.ClearIdempotencyKey()
+	return _u
+}
+
+// SetArgs sets the "args" field.
+func (_u *DeadLetterUpdateOne) SetArgs(v map[string]interface{}) *DeadLetterUpdateOne {
+	_u.mutation.SetArgs(v)
+	return _u
+}
+
+// ClearArgs clears the value of the "args" field.
+func (_u *DeadLetterUpdateOne) ClearArgs() *DeadLetterUpdateOne {
+	_u.mutation.ClearArgs()
+	return _u
+}
+
+// SetAttempts sets the "attempts" field.
+func (_u *DeadLetterUpdateOne) SetAttempts(v int) *DeadLetterUpdateOne {
+	_u.mutation.ResetAttempts()
+	_u.mutation.SetAttempts(v)
+	return _u
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_u *DeadLetterUpdateOne) SetNillableAttempts(v *int) *DeadLetterUpdateOne {
+	if v != nil {
+		_u.SetAttempts(*v)
+	}
+	return _u
+}
+
+// AddAttempts adds value to the "attempts" field.
+func (_u *DeadLetterUpdateOne) AddAttempts(v int) *DeadLetterUpdateOne {
+	_u.mutation.AddAttempts(v)
+	return _u
+}
+
+// SetError sets the "error" field.
+func (_u *DeadLetterUpdateOne) SetError(v errmodel.Error) *DeadLetterUpdateOne {
+	_u.mutation.SetError(v)
+	return _u
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_u *DeadLetterUpdateOne) SetNillableError(v *errmodel.Error) *DeadLetterUpdateOne {
+	if v != nil {
+		_u.SetError(*v)
+	}
+	return _u
+}
+
+// Mutation returns the DeadLetterMutation object of the builder.
+func (_u *DeadLetterUpdateOne) Mutation() *DeadLetterMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the DeadLetterUpdate builder.
+func (_u *DeadLetterUpdateOne) Where(ps ...predicate.DeadLetter) *DeadLetterUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *DeadLetterUpdateOne) Select(field string, fields ...string) *DeadLetterUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated DeadLetter entity.
+func (_u *DeadLetterUpdateOne) Save(ctx context.Context) (*DeadLetter, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *DeadLetterUpdateOne) SaveX(ctx context.Context) *DeadLetter {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *DeadLetterUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *DeadLetterUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *DeadLetterUpdateOne) check() error {
+	if v, ok := _u.mutation.DeadLetterID(); ok {
+		if err := deadletter.DeadLetterIDValidator(v); err != nil {
+			return &ValidationError{Name: "dead_letter_id", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.dead_letter_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := deadletter.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.IntentName(); ok {
+		if err := deadletter.IntentNameValidator(v); err != nil {
+			return &ValidationError{Name: "intent_name", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.intent_name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Attempts(); ok {
+		if err := deadletter.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "DeadLetter.attempts": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *DeadLetterUpdateOne) sqlSave(ctx context.Context) (_node *DeadLetter, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(deadletter.Table, deadletter.Columns, sqlgraph.NewFieldSpec(deadletter.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "DeadLetter.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, deadletter.FieldID)
+		for _, f := range fields {
+			if !deadletter.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != deadletter.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.DeadLetterID(); ok {
+		_spec.SetField(deadletter.FieldDeadLetterID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(deadletter.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IntentName(); ok {
+		_spec.SetField(deadletter.FieldIntentName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IdempotencyKey(); ok {
+		_spec.SetField(deadletter.FieldIdempotencyKey, field.TypeString, value)
+	}
+	if _u.mutation.IdempotencyKeyCleared() {
+		_spec.ClearField(deadletter.FieldIdempotencyKey, field.TypeString)
+	}
+	if value, ok := _u.mutation.Args(); ok {
+		_spec.SetField(deadletter.FieldArgs, field.TypeJSON, value)
+	}
+	if _u.mutation.ArgsCleared() {
+		_spec.ClearField(deadletter.FieldArgs, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Attempts(); ok {
+		_spec.SetField(deadletter.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAttempts(); ok {
+		_spec.AddField(deadletter.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Error(); ok {
+		_spec.SetField(deadletter.FieldError, field.TypeJSON, value)
+	}
+	_node = &DeadLetter{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{deadletter.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
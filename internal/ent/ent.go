@@ -12,8 +12,16 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/auditentry"
+	"github.com/wilhg/orch/internal/ent/checkpoint"
+	"github.com/wilhg/orch/internal/ent/deadletter"
 	"github.com/wilhg/orch/internal/ent/event"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+	"github.com/wilhg/orch/internal/ent/run"
+	"github.com/wilhg/orch/internal/ent/shredkey"
 	"github.com/wilhg/orch/internal/ent/snapshot"
+	"github.com/wilhg/orch/internal/ent/timer"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -70,15 +78,23 @@ var (
 	columnCheck sql.ColumnCheck
 )
 
-// columnChecker checks if the column exists in the given table.
-func checkColumn(table, column string) error {
+// checkColumn checks if the column exists in the given table.
+func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			event.Table:    event.ValidColumn,
-			snapshot.Table: snapshot.ValidColumn,
+			archivedevent.Table: archivedevent.ValidColumn,
+			auditentry.Table:    auditentry.ValidColumn,
+			checkpoint.Table:    checkpoint.ValidColumn,
+			deadletter.Table:    deadletter.ValidColumn,
+			event.Table:         event.ValidColumn,
+			outboxitem.Table:    outboxitem.ValidColumn,
+			run.Table:           run.ValidColumn,
+			shredkey.Table:      shredkey.ValidColumn,
+			snapshot.Table:      snapshot.ValidColumn,
+			timer.Table:         timer.ValidColumn,
 		})
 	})
-	return columnCheck(table, column)
+	return columnCheck(t, c)
 }
 
 // Asc applies the given fields in ASC order.
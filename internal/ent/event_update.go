@@ -22,99 +22,142 @@ type EventUpdate struct {
 }
 
 // Where appends a list predicates to the EventUpdate builder.
-func (eu *EventUpdate) Where(ps ...predicate.Event) *EventUpdate {
-	eu.mutation.Where(ps...)
-	return eu
+func (_u *EventUpdate) Where(ps ...predicate.Event) *EventUpdate {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // SetEventID sets the "event_id" field.
-func (eu *EventUpdate) SetEventID(s string) *EventUpdate {
-	eu.mutation.SetEventID(s)
-	return eu
+func (_u *EventUpdate) SetEventID(v string) *EventUpdate {
+	_u.mutation.SetEventID(v)
+	return _u
 }
 
 // SetNillableEventID sets the "event_id" field if the given value is not nil.
-func (eu *EventUpdate) SetNillableEventID(s *string) *EventUpdate {
-	if s != nil {
-		eu.SetEventID(*s)
+func (_u *EventUpdate) SetNillableEventID(v *string) *EventUpdate {
+	if v != nil {
+		_u.SetEventID(*v)
 	}
-	return eu
+	return _u
 }
 
 // SetRunID sets the "run_id" field.
-func (eu *EventUpdate) SetRunID(s string) *EventUpdate {
-	eu.mutation.SetRunID(s)
-	return eu
+func (_u *EventUpdate) SetRunID(v string) *EventUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
 }
 
 // SetNillableRunID sets the "run_id" field if the given value is not nil.
-func (eu *EventUpdate) SetNillableRunID(s *string) *EventUpdate {
-	if s != nil {
-		eu.SetRunID(*s)
+func (_u *EventUpdate) SetNillableRunID(v *string) *EventUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
 	}
-	return eu
+	return _u
 }
 
 // SetSeq sets the "seq" field.
-func (eu *EventUpdate) SetSeq(i int64) *EventUpdate {
-	eu.mutation.ResetSeq()
-	eu.mutation.SetSeq(i)
-	return eu
+func (_u *EventUpdate) SetSeq(v int64) *EventUpdate {
+	_u.mutation.ResetSeq()
+	_u.mutation.SetSeq(v)
+	return _u
 }
 
 // SetNillableSeq sets the "seq" field if the given value is not nil.
-func (eu *EventUpdate) SetNillableSeq(i *int64) *EventUpdate {
-	if i != nil {
-		eu.SetSeq(*i)
+func (_u *EventUpdate) SetNillableSeq(v *int64) *EventUpdate {
+	if v != nil {
+		_u.SetSeq(*v)
 	}
-	return eu
+	return _u
 }
 
-// AddSeq adds i to the "seq" field.
-func (eu *EventUpdate) AddSeq(i int64) *EventUpdate {
-	eu.mutation.AddSeq(i)
-	return eu
+// AddSeq adds value to the "seq" field.
+func (_u *EventUpdate) AddSeq(v int64) *EventUpdate {
+	_u.mutation.AddSeq(v)
+	return _u
 }
 
 // SetType sets the "type" field.
-func (eu *EventUpdate) SetType(s string) *EventUpdate {
-	eu.mutation.SetType(s)
-	return eu
+func (_u *EventUpdate) SetType(v string) *EventUpdate {
+	_u.mutation.SetType(v)
+	return _u
 }
 
 // SetNillableType sets the "type" field if the given value is not nil.
-func (eu *EventUpdate) SetNillableType(s *string) *EventUpdate {
-	if s != nil {
-		eu.SetType(*s)
+func (_u *EventUpdate) SetNillableType(v *string) *EventUpdate {
+	if v != nil {
+		_u.SetType(*v)
 	}
-	return eu
+	return _u
+}
+
+// SetVersion sets the "version" field.
+func (_u *EventUpdate) SetVersion(v int) *EventUpdate {
+	_u.mutation.ResetVersion()
+	_u.mutation.SetVersion(v)
+	return _u
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_u *EventUpdate) SetNillableVersion(v *int) *EventUpdate {
+	if v != nil {
+		_u.SetVersion(*v)
+	}
+	return _u
+}
+
+// AddVersion adds value to the "version" field.
+func (_u *EventUpdate) AddVersion(v int) *EventUpdate {
+	_u.mutation.AddVersion(v)
+	return _u
 }
 
 // SetPayload sets the "payload" field.
-func (eu *EventUpdate) SetPayload(m map[string]interface{}) *EventUpdate {
-	eu.mutation.SetPayload(m)
-	return eu
+func (_u *EventUpdate) SetPayload(v string) *EventUpdate {
+	_u.mutation.SetPayload(v)
+	return _u
+}
+
+// SetNillablePayload sets the "payload" field if the given value is not nil.
+func (_u *EventUpdate) SetNillablePayload(v *string) *EventUpdate {
+	if v != nil {
+		_u.SetPayload(*v)
+	}
+	return _u
 }
 
 // ClearPayload clears the value of the "payload" field.
-func (eu *EventUpdate) ClearPayload() *EventUpdate {
-	eu.mutation.ClearPayload()
-	return eu
+func (_u *EventUpdate) ClearPayload() *EventUpdate {
+	_u.mutation.ClearPayload()
+	return _u
+}
+
+// SetKeyID sets the "key_id" field.
+func (_u *EventUpdate) SetKeyID(v string) *EventUpdate {
+	_u.mutation.SetKeyID(v)
+	return _u
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_u *EventUpdate) SetNillableKeyID(v *string) *EventUpdate {
+	if v != nil {
+		_u.SetKeyID(*v)
+	}
+	return _u
 }
 
 // Mutation returns the EventMutation object of the builder.
-func (eu *EventUpdate) Mutation() *EventMutation {
-	return eu.mutation
+func (_u *EventUpdate) Mutation() *EventMutation {
+	return _u.mutation
 }
 
 // Save executes the query and returns the number of nodes affected by the update operation.
-func (eu *EventUpdate) Save(ctx context.Context) (int, error) {
-	return withHooks(ctx, eu.sqlSave, eu.mutation, eu.hooks)
+func (_u *EventUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (eu *EventUpdate) SaveX(ctx context.Context) int {
-	affected, err := eu.Save(ctx)
+func (_u *EventUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -122,77 +165,91 @@ func (eu *EventUpdate) SaveX(ctx context.Context) int {
 }
 
 // Exec executes the query.
-func (eu *EventUpdate) Exec(ctx context.Context) error {
-	_, err := eu.Save(ctx)
+func (_u *EventUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (eu *EventUpdate) ExecX(ctx context.Context) {
-	if err := eu.Exec(ctx); err != nil {
+func (_u *EventUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (eu *EventUpdate) check() error {
-	if v, ok := eu.mutation.EventID(); ok {
+func (_u *EventUpdate) check() error {
+	if v, ok := _u.mutation.EventID(); ok {
 		if err := event.EventIDValidator(v); err != nil {
 			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "Event.event_id": %w`, err)}
 		}
 	}
-	if v, ok := eu.mutation.RunID(); ok {
+	if v, ok := _u.mutation.RunID(); ok {
 		if err := event.RunIDValidator(v); err != nil {
 			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Event.run_id": %w`, err)}
 		}
 	}
-	if v, ok := eu.mutation.Seq(); ok {
+	if v, ok := _u.mutation.Seq(); ok {
 		if err := event.SeqValidator(v); err != nil {
 			return &ValidationError{Name: "seq", err: fmt.Errorf(`ent: validator failed for field "Event.seq": %w`, err)}
 		}
 	}
-	if v, ok := eu.mutation.GetType(); ok {
+	if v, ok := _u.mutation.GetType(); ok {
 		if err := event.TypeValidator(v); err != nil {
 			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "Event.type": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.Version(); ok {
+		if err := event.VersionValidator(v); err != nil {
+			return &ValidationError{Name: "version", err: fmt.Errorf(`ent: validator failed for field "Event.version": %w`, err)}
+		}
+	}
 	return nil
 }
 
-func (eu *EventUpdate) sqlSave(ctx context.Context) (n int, err error) {
-	if err := eu.check(); err != nil {
-		return n, err
+func (_u *EventUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
 	}
 	_spec := sqlgraph.NewUpdateSpec(event.Table, event.Columns, sqlgraph.NewFieldSpec(event.FieldID, field.TypeInt))
-	if ps := eu.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if value, ok := eu.mutation.EventID(); ok {
+	if value, ok := _u.mutation.EventID(); ok {
 		_spec.SetField(event.FieldEventID, field.TypeString, value)
 	}
-	if value, ok := eu.mutation.RunID(); ok {
+	if value, ok := _u.mutation.RunID(); ok {
 		_spec.SetField(event.FieldRunID, field.TypeString, value)
 	}
-	if value, ok := eu.mutation.Seq(); ok {
+	if value, ok := _u.mutation.Seq(); ok {
 		_spec.SetField(event.FieldSeq, field.TypeInt64, value)
 	}
-	if value, ok := eu.mutation.AddedSeq(); ok {
+	if value, ok := _u.mutation.AddedSeq(); ok {
 		_spec.AddField(event.FieldSeq, field.TypeInt64, value)
 	}
-	if value, ok := eu.mutation.GetType(); ok {
+	if value, ok := _u.mutation.GetType(); ok {
 		_spec.SetField(event.FieldType, field.TypeString, value)
 	}
-	if value, ok := eu.mutation.Payload(); ok {
-		_spec.SetField(event.FieldPayload, field.TypeJSON, value)
+	if value, ok := _u.mutation.Version(); ok {
+		_spec.SetField(event.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedVersion(); ok {
+		_spec.AddField(event.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Payload(); ok {
+		_spec.SetField(event.FieldPayload, field.TypeString, value)
+	}
+	if _u.mutation.PayloadCleared() {
+		_spec.ClearField(event.FieldPayload, field.TypeString)
 	}
-	if eu.mutation.PayloadCleared() {
-		_spec.ClearField(event.FieldPayload, field.TypeJSON)
+	if value, ok := _u.mutation.KeyID(); ok {
+		_spec.SetField(event.FieldKeyID, field.TypeString, value)
 	}
-	if n, err = sqlgraph.UpdateNodes(ctx, eu.driver, _spec); err != nil {
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{event.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -200,8 +257,8 @@ func (eu *EventUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		return 0, err
 	}
-	eu.mutation.done = true
-	return n, nil
+	_u.mutation.done = true
+	return _node, nil
 }
 
 // EventUpdateOne is the builder for updating a single Event entity.
@@ -213,106 +270,149 @@ type EventUpdateOne struct {
 }
 
 // SetEventID sets the "event_id" field.
-func (euo *EventUpdateOne) SetEventID(s string) *EventUpdateOne {
-	euo.mutation.SetEventID(s)
-	return euo
+func (_u *EventUpdateOne) SetEventID(v string) *EventUpdateOne {
+	_u.mutation.SetEventID(v)
+	return _u
 }
 
 // SetNillableEventID sets the "event_id" field if the given value is not nil.
-func (euo *EventUpdateOne) SetNillableEventID(s *string) *EventUpdateOne {
-	if s != nil {
-		euo.SetEventID(*s)
+func (_u *EventUpdateOne) SetNillableEventID(v *string) *EventUpdateOne {
+	if v != nil {
+		_u.SetEventID(*v)
 	}
-	return euo
+	return _u
 }
 
 // SetRunID sets the "run_id" field.
-func (euo *EventUpdateOne) SetRunID(s string) *EventUpdateOne {
-	euo.mutation.SetRunID(s)
-	return euo
+func (_u *EventUpdateOne) SetRunID(v string) *EventUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
 }
 
 // SetNillableRunID sets the "run_id" field if the given value is not nil.
-func (euo *EventUpdateOne) SetNillableRunID(s *string) *EventUpdateOne {
-	if s != nil {
-		euo.SetRunID(*s)
+func (_u *EventUpdateOne) SetNillableRunID(v *string) *EventUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
 	}
-	return euo
+	return _u
 }
 
 // SetSeq sets the "seq" field.
-func (euo *EventUpdateOne) SetSeq(i int64) *EventUpdateOne {
-	euo.mutation.ResetSeq()
-	euo.mutation.SetSeq(i)
-	return euo
+func (_u *EventUpdateOne) SetSeq(v int64) *EventUpdateOne {
+	_u.mutation.ResetSeq()
+	_u.mutation.SetSeq(v)
+	return _u
 }
 
 // SetNillableSeq sets the "seq" field if the given value is not nil.
-func (euo *EventUpdateOne) SetNillableSeq(i *int64) *EventUpdateOne {
-	if i != nil {
-		euo.SetSeq(*i)
+func (_u *EventUpdateOne) SetNillableSeq(v *int64) *EventUpdateOne {
+	if v != nil {
+		_u.SetSeq(*v)
 	}
-	return euo
+	return _u
 }
 
-// AddSeq adds i to the "seq" field.
-func (euo *EventUpdateOne) AddSeq(i int64) *EventUpdateOne {
-	euo.mutation.AddSeq(i)
-	return euo
+// AddSeq adds value to the "seq" field.
+func (_u *EventUpdateOne) AddSeq(v int64) *EventUpdateOne {
+	_u.mutation.AddSeq(v)
+	return _u
 }
 
 // SetType sets the "type" field.
-func (euo *EventUpdateOne) SetType(s string) *EventUpdateOne {
-	euo.mutation.SetType(s)
-	return euo
+func (_u *EventUpdateOne) SetType(v string) *EventUpdateOne {
+	_u.mutation.SetType(v)
+	return _u
 }
 
 // SetNillableType sets the "type" field if the given value is not nil.
-func (euo *EventUpdateOne) SetNillableType(s *string) *EventUpdateOne {
-	if s != nil {
-		euo.SetType(*s)
+func (_u *EventUpdateOne) SetNillableType(v *string) *EventUpdateOne {
+	if v != nil {
+		_u.SetType(*v)
+	}
+	return _u
+}
+
+// SetVersion sets the "version" field.
+func (_u *EventUpdateOne) SetVersion(v int) *EventUpdateOne {
+	_u.mutation.ResetVersion()
+	_u.mutation.SetVersion(v)
+	return _u
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_u *EventUpdateOne) SetNillableVersion(v *int) *EventUpdateOne {
+	if v != nil {
+		_u.SetVersion(*v)
 	}
-	return euo
+	return _u
+}
+
+// AddVersion adds value to the "version" field.
+func (_u *EventUpdateOne) AddVersion(v int) *EventUpdateOne {
+	_u.mutation.AddVersion(v)
+	return _u
 }
 
 // SetPayload sets the "payload" field.
-func (euo *EventUpdateOne) SetPayload(m map[string]interface{}) *EventUpdateOne {
-	euo.mutation.SetPayload(m)
-	return euo
+func (_u *EventUpdateOne) SetPayload(v string) *EventUpdateOne {
+	_u.mutation.SetPayload(v)
+	return _u
+}
+
+// SetNillablePayload sets the "payload" field if the given value is not nil.
+func (_u *EventUpdateOne) SetNillablePayload(v *string) *EventUpdateOne {
+	if v != nil {
+		_u.SetPayload(*v)
+	}
+	return _u
 }
 
 // ClearPayload clears the value of the "payload" field.
-func (euo *EventUpdateOne) ClearPayload() *EventUpdateOne {
-	euo.mutation.ClearPayload()
-	return euo
+func (_u *EventUpdateOne) ClearPayload() *EventUpdateOne {
+	_u.mutation.ClearPayload()
+	return _u
+}
+
+// SetKeyID sets the "key_id" field.
+func (_u *EventUpdateOne) SetKeyID(v string) *EventUpdateOne {
+	_u.mutation.SetKeyID(v)
+	return _u
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_u *EventUpdateOne) SetNillableKeyID(v *string) *EventUpdateOne {
+	if v != nil {
+		_u.SetKeyID(*v)
+	}
+	return _u
 }
 
 // Mutation returns the EventMutation object of the builder.
-func (euo *EventUpdateOne) Mutation() *EventMutation {
-	return euo.mutation
+func (_u *EventUpdateOne) Mutation() *EventMutation {
+	return _u.mutation
 }
 
 // Where appends a list predicates to the EventUpdate builder.
-func (euo *EventUpdateOne) Where(ps ...predicate.Event) *EventUpdateOne {
-	euo.mutation.Where(ps...)
-	return euo
+func (_u *EventUpdateOne) Where(ps ...predicate.Event) *EventUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // Select allows selecting one or more fields (columns) of the returned entity.
 // The default is selecting all fields defined in the entity schema.
-func (euo *EventUpdateOne) Select(field string, fields ...string) *EventUpdateOne {
-	euo.fields = append([]string{field}, fields...)
-	return euo
+func (_u *EventUpdateOne) Select(field string, fields ...string) *EventUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
 }
 
 // Save executes the query and returns the updated Event entity.
-func (euo *EventUpdateOne) Save(ctx context.Context) (*Event, error) {
-	return withHooks(ctx, euo.sqlSave, euo.mutation, euo.hooks)
+func (_u *EventUpdateOne) Save(ctx context.Context) (*Event, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (euo *EventUpdateOne) SaveX(ctx context.Context) *Event {
-	node, err := euo.Save(ctx)
+func (_u *EventUpdateOne) SaveX(ctx context.Context) *Event {
+	node, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -320,54 +420,59 @@ func (euo *EventUpdateOne) SaveX(ctx context.Context) *Event {
 }
 
 // Exec executes the query on the entity.
-func (euo *EventUpdateOne) Exec(ctx context.Context) error {
-	_, err := euo.Save(ctx)
+func (_u *EventUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (euo *EventUpdateOne) ExecX(ctx context.Context) {
-	if err := euo.Exec(ctx); err != nil {
+func (_u *EventUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (euo *EventUpdateOne) check() error {
-	if v, ok := euo.mutation.EventID(); ok {
+func (_u *EventUpdateOne) check() error {
+	if v, ok := _u.mutation.EventID(); ok {
 		if err := event.EventIDValidator(v); err != nil {
 			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "Event.event_id": %w`, err)}
 		}
 	}
-	if v, ok := euo.mutation.RunID(); ok {
+	if v, ok := _u.mutation.RunID(); ok {
 		if err := event.RunIDValidator(v); err != nil {
 			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Event.run_id": %w`, err)}
 		}
 	}
-	if v, ok := euo.mutation.Seq(); ok {
+	if v, ok := _u.mutation.Seq(); ok {
 		if err := event.SeqValidator(v); err != nil {
 			return &ValidationError{Name: "seq", err: fmt.Errorf(`ent: validator failed for field "Event.seq": %w`, err)}
 		}
 	}
-	if v, ok := euo.mutation.GetType(); ok {
+	if v, ok := _u.mutation.GetType(); ok {
 		if err := event.TypeValidator(v); err != nil {
 			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "Event.type": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.Version(); ok {
+		if err := event.VersionValidator(v); err != nil {
+			return &ValidationError{Name: "version", err: fmt.Errorf(`ent: validator failed for field "Event.version": %w`, err)}
+		}
+	}
 	return nil
 }
 
-func (euo *EventUpdateOne) sqlSave(ctx context.Context) (_node *Event, err error) {
-	if err := euo.check(); err != nil {
+func (_u *EventUpdateOne) sqlSave(ctx context.Context) (_node *Event, err error) {
+	if err := _u.check(); err != nil {
 		return _node, err
 	}
 	_spec := sqlgraph.NewUpdateSpec(event.Table, event.Columns, sqlgraph.NewFieldSpec(event.FieldID, field.TypeInt))
-	id, ok := euo.mutation.ID()
+	id, ok := _u.mutation.ID()
 	if !ok {
 		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Event.id" for update`)}
 	}
 	_spec.Node.ID.Value = id
-	if fields := euo.fields; len(fields) > 0 {
+	if fields := _u.fields; len(fields) > 0 {
 		_spec.Node.Columns = make([]string, 0, len(fields))
 		_spec.Node.Columns = append(_spec.Node.Columns, event.FieldID)
 		for _, f := range fields {
@@ -379,38 +484,47 @@ func (euo *EventUpdateOne) sqlSave(ctx context.Context) (_node *Event, err error
 			}
 		}
 	}
-	if ps := euo.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if value, ok := euo.mutation.EventID(); ok {
+	if value, ok := _u.mutation.EventID(); ok {
 		_spec.SetField(event.FieldEventID, field.TypeString, value)
 	}
-	if value, ok := euo.mutation.RunID(); ok {
+	if value, ok := _u.mutation.RunID(); ok {
 		_spec.SetField(event.FieldRunID, field.TypeString, value)
 	}
-	if value, ok := euo.mutation.Seq(); ok {
+	if value, ok := _u.mutation.Seq(); ok {
 		_spec.SetField(event.FieldSeq, field.TypeInt64, value)
 	}
-	if value, ok := euo.mutation.AddedSeq(); ok {
+	if value, ok := _u.mutation.AddedSeq(); ok {
 		_spec.AddField(event.FieldSeq, field.TypeInt64, value)
 	}
-	if value, ok := euo.mutation.GetType(); ok {
+	if value, ok := _u.mutation.GetType(); ok {
 		_spec.SetField(event.FieldType, field.TypeString, value)
 	}
-	if value, ok := euo.mutation.Payload(); ok {
-		_spec.SetField(event.FieldPayload, field.TypeJSON, value)
+	if value, ok := _u.mutation.Version(); ok {
+		_spec.SetField(event.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedVersion(); ok {
+		_spec.AddField(event.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Payload(); ok {
+		_spec.SetField(event.FieldPayload, field.TypeString, value)
+	}
+	if _u.mutation.PayloadCleared() {
+		_spec.ClearField(event.FieldPayload, field.TypeString)
 	}
-	if euo.mutation.PayloadCleared() {
-		_spec.ClearField(event.FieldPayload, field.TypeJSON)
+	if value, ok := _u.mutation.KeyID(); ok {
+		_spec.SetField(event.FieldKeyID, field.TypeString, value)
 	}
-	_node = &Event{config: euo.config}
+	_node = &Event{config: _u.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
-	if err = sqlgraph.UpdateNode(ctx, euo.driver, _spec); err != nil {
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{event.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -418,6 +532,6 @@ func (euo *EventUpdateOne) sqlSave(ctx context.Context) (_node *Event, err error
 		}
 		return nil, err
 	}
-	euo.mutation.done = true
+	_u.mutation.done = true
 	return _node, nil
 }
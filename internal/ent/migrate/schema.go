@@ -8,6 +8,94 @@ import (
 )
 
 var (
+	// ArchivedEventsColumns holds the columns for the "archived_events" table.
+	ArchivedEventsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "position", Type: field.TypeInt64, Unique: true},
+		{Name: "event_id", Type: field.TypeString, Unique: true},
+		{Name: "run_id", Type: field.TypeString},
+		{Name: "seq", Type: field.TypeInt64},
+		{Name: "type", Type: field.TypeString},
+		{Name: "version", Type: field.TypeInt, Default: 1},
+		{Name: "payload", Type: field.TypeString, Nullable: true, Size: 2147483647, SchemaType: map[string]string{"postgres": "json"}},
+		{Name: "key_id", Type: field.TypeString, Default: ""},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+		{Name: "archived_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// ArchivedEventsTable holds the schema information for the "archived_events" table.
+	ArchivedEventsTable = &schema.Table{
+		Name:       "archived_events",
+		Columns:    ArchivedEventsColumns,
+		PrimaryKey: []*schema.Column{ArchivedEventsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "archivedevent_run_id_seq",
+				Unique:  true,
+				Columns: []*schema.Column{ArchivedEventsColumns[3], ArchivedEventsColumns[4]},
+			},
+		},
+	}
+	// AuditEntriesColumns holds the columns for the "audit_entries" table.
+	AuditEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "audit_id", Type: field.TypeString, Unique: true},
+		{Name: "action", Type: field.TypeString},
+		{Name: "run_id", Type: field.TypeString, Nullable: true},
+		{Name: "actor", Type: field.TypeString, Nullable: true},
+		{Name: "details", Type: field.TypeJSON, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// AuditEntriesTable holds the schema information for the "audit_entries" table.
+	AuditEntriesTable = &schema.Table{
+		Name:       "audit_entries",
+		Columns:    AuditEntriesColumns,
+		PrimaryKey: []*schema.Column{AuditEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "auditentry_run_id",
+				Unique:  false,
+				Columns: []*schema.Column{AuditEntriesColumns[3]},
+			},
+		},
+	}
+	// CheckpointsColumns holds the columns for the "checkpoints" table.
+	CheckpointsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "name", Type: field.TypeString, Unique: true},
+		{Name: "position", Type: field.TypeInt64, Default: 0},
+		{Name: "updated_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// CheckpointsTable holds the schema information for the "checkpoints" table.
+	CheckpointsTable = &schema.Table{
+		Name:       "checkpoints",
+		Columns:    CheckpointsColumns,
+		PrimaryKey: []*schema.Column{CheckpointsColumns[0]},
+	}
+	// DeadLettersColumns holds the columns for the "dead_letters" table.
+	DeadLettersColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "dead_letter_id", Type: field.TypeString, Unique: true},
+		{Name: "run_id", Type: field.TypeString},
+		{Name: "intent_name", Type: field.TypeString},
+		{Name: "idempotency_key", Type: field.TypeString, Nullable: true},
+		{Name: "args", Type: field.TypeJSON, Nullable: true},
+		{Name: "attempts", Type: field.TypeInt},
+		{Name: "error", Type: field.TypeJSON},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// DeadLettersTable holds the schema information for the "dead_letters" table.
+	DeadLettersTable = &schema.Table{
+		Name:       "dead_letters",
+		Columns:    DeadLettersColumns,
+		PrimaryKey: []*schema.Column{DeadLettersColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "deadletter_run_id",
+				Unique:  false,
+				Columns: []*schema.Column{DeadLettersColumns[2]},
+			},
+		},
+	}
 	// EventsColumns holds the columns for the "events" table.
 	EventsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
@@ -15,7 +103,9 @@ var (
 		{Name: "run_id", Type: field.TypeString},
 		{Name: "seq", Type: field.TypeInt64},
 		{Name: "type", Type: field.TypeString},
-		{Name: "payload", Type: field.TypeJSON, Nullable: true},
+		{Name: "version", Type: field.TypeInt, Default: 1},
+		{Name: "payload", Type: field.TypeString, Nullable: true, Size: 2147483647, SchemaType: map[string]string{"postgres": "json"}},
+		{Name: "key_id", Type: field.TypeString, Default: ""},
 		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
 	}
 	// EventsTable holds the schema information for the "events" table.
@@ -36,13 +126,99 @@ var (
 			},
 		},
 	}
+	// OutboxItemsColumns holds the columns for the "outbox_items" table.
+	OutboxItemsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "item_id", Type: field.TypeString, Unique: true},
+		{Name: "queue", Type: field.TypeString},
+		{Name: "run_id", Type: field.TypeString},
+		{Name: "intent_name", Type: field.TypeString},
+		{Name: "idempotency_key", Type: field.TypeString, Nullable: true},
+		{Name: "args", Type: field.TypeJSON, Nullable: true},
+		{Name: "depth", Type: field.TypeInt, Default: 0},
+		{Name: "claims", Type: field.TypeInt, Default: 0},
+		{Name: "lease_owner", Type: field.TypeString, Nullable: true},
+		{Name: "lease_expires_at", Type: field.TypeInt64, Default: 0},
+		{Name: "available_at", Type: field.TypeInt64},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// OutboxItemsTable holds the schema information for the "outbox_items" table.
+	OutboxItemsTable = &schema.Table{
+		Name:       "outbox_items",
+		Columns:    OutboxItemsColumns,
+		PrimaryKey: []*schema.Column{OutboxItemsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "outboxitem_queue_available_at",
+				Unique:  false,
+				Columns: []*schema.Column{OutboxItemsColumns[2], OutboxItemsColumns[11]},
+			},
+			{
+				Name:    "outboxitem_run_id",
+				Unique:  false,
+				Columns: []*schema.Column{OutboxItemsColumns[3]},
+			},
+		},
+	}
+	// RunsColumns holds the columns for the "runs" table.
+	RunsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "run_id", Type: field.TypeString, Unique: true},
+		{Name: "status", Type: field.TypeString, Default: "active"},
+		{Name: "agent_type", Type: field.TypeString, Nullable: true},
+		{Name: "labels", Type: field.TypeJSON, Nullable: true},
+		{Name: "parent_run_id", Type: field.TypeString, Nullable: true},
+		{Name: "last_seq", Type: field.TypeInt64, Default: 0},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+		{Name: "updated_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// RunsTable holds the schema information for the "runs" table.
+	RunsTable = &schema.Table{
+		Name:       "runs",
+		Columns:    RunsColumns,
+		PrimaryKey: []*schema.Column{RunsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "run_status",
+				Unique:  false,
+				Columns: []*schema.Column{RunsColumns[2]},
+			},
+			{
+				Name:    "run_agent_type",
+				Unique:  false,
+				Columns: []*schema.Column{RunsColumns[3]},
+			},
+			{
+				Name:    "run_parent_run_id",
+				Unique:  false,
+				Columns: []*schema.Column{RunsColumns[5]},
+			},
+		},
+	}
+	// ShredKeysColumns holds the columns for the "shred_keys" table.
+	ShredKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "run_id", Type: field.TypeString, Unique: true},
+		{Name: "key", Type: field.TypeBytes, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+		{Name: "shredded_at", Type: field.TypeTime, Nullable: true, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// ShredKeysTable holds the schema information for the "shred_keys" table.
+	ShredKeysTable = &schema.Table{
+		Name:       "shred_keys",
+		Columns:    ShredKeysColumns,
+		PrimaryKey: []*schema.Column{ShredKeysColumns[0]},
+	}
 	// SnapshotsColumns holds the columns for the "snapshots" table.
 	SnapshotsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "snapshot_id", Type: field.TypeString, Unique: true},
 		{Name: "run_id", Type: field.TypeString},
 		{Name: "upto_seq", Type: field.TypeInt64},
-		{Name: "state", Type: field.TypeJSON, Nullable: true},
+		{Name: "state", Type: field.TypeString, Nullable: true, Size: 2147483647, SchemaType: map[string]string{"postgres": "json"}},
+		{Name: "key_id", Type: field.TypeString, Default: ""},
+		{Name: "version", Type: field.TypeString, Default: ""},
+		{Name: "hash", Type: field.TypeString, Default: ""},
 		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
 	}
 	// SnapshotsTable holds the schema information for the "snapshots" table.
@@ -61,12 +237,56 @@ var (
 				Unique:  true,
 				Columns: []*schema.Column{SnapshotsColumns[2], SnapshotsColumns[3]},
 			},
+			{
+				Name:    "snapshot_version",
+				Unique:  false,
+				Columns: []*schema.Column{SnapshotsColumns[6]},
+			},
+		},
+	}
+	// TimersColumns holds the columns for the "timers" table.
+	TimersColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "timer_id", Type: field.TypeString, Unique: true},
+		{Name: "queue", Type: field.TypeString},
+		{Name: "run_id", Type: field.TypeString},
+		{Name: "name", Type: field.TypeString, Nullable: true},
+		{Name: "payload", Type: field.TypeJSON, Nullable: true},
+		{Name: "due_at", Type: field.TypeInt64},
+		{Name: "attempts", Type: field.TypeInt, Default: 0},
+		{Name: "available_at", Type: field.TypeInt64, Default: 0},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "TIMESTAMPTZ", "sqlite3": "DATETIME"}},
+	}
+	// TimersTable holds the schema information for the "timers" table.
+	TimersTable = &schema.Table{
+		Name:       "timers",
+		Columns:    TimersColumns,
+		PrimaryKey: []*schema.Column{TimersColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "timer_queue_due_at",
+				Unique:  false,
+				Columns: []*schema.Column{TimersColumns[2], TimersColumns[6]},
+			},
+			{
+				Name:    "timer_run_id",
+				Unique:  false,
+				Columns: []*schema.Column{TimersColumns[3]},
+			},
 		},
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		ArchivedEventsTable,
+		AuditEntriesTable,
+		CheckpointsTable,
+		DeadLettersTable,
 		EventsTable,
+		OutboxItemsTable,
+		RunsTable,
+		ShredKeysTable,
 		SnapshotsTable,
+		TimersTable,
 	}
 )
 
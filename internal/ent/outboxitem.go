@@ -0,0 +1,232 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+)
+
+// OutboxItem is the model entity for the OutboxItem schema.
+type OutboxItem struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// ItemID holds the value of the "item_id" field.
+	ItemID string `json:"item_id,omitempty"`
+	// Queue holds the value of the "queue" field.
+	Queue string `json:"queue,omitempty"`
+	// RunID holds the value of the "run_id" field.
+	RunID string `json:"run_id,omitempty"`
+	// IntentName holds the value of the "intent_name" field.
+	IntentName string `json:"intent_name,omitempty"`
+	// IdempotencyKey holds the value of the "idempotency_key" field.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// Args holds the value of the "args" field.
+	Args map[string]interface{} `json:"args,omitempty"`
+	// Depth holds the value of the "depth" field.
+	Depth int `json:"depth,omitempty"`
+	// Claims holds the value of the "claims" field.
+	Claims int `json:"claims,omitempty"`
+	// LeaseOwner holds the value of the "lease_owner" field.
+	LeaseOwner string `json:"lease_owner,omitempty"`
+	// LeaseExpiresAt holds the value of the "lease_expires_at" field.
+	LeaseExpiresAt int64 `json:"lease_expires_at,omitempty"`
+	// AvailableAt holds the value of the "available_at" field.
+	AvailableAt int64 `json:"available_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*OutboxItem) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case outboxitem.FieldArgs:
+			values[i] = new([]byte)
+		case outboxitem.FieldID, outboxitem.FieldDepth, outboxitem.FieldClaims, outboxitem.FieldLeaseExpiresAt, outboxitem.FieldAvailableAt:
+			values[i] = new(sql.NullInt64)
+		case outboxitem.FieldItemID, outboxitem.FieldQueue, outboxitem.FieldRunID, outboxitem.FieldIntentName, outboxitem.FieldIdempotencyKey, outboxitem.FieldLeaseOwner:
+			values[i] = new(sql.NullString)
+		case outboxitem.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the OutboxItem fields.
+func (_m *OutboxItem) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case outboxitem.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int(value.Int64)
+		case outboxitem.FieldItemID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field item_id", values[i])
+			} else if value.Valid {
+				_m.ItemID = value.String
+			}
+		case outboxitem.FieldQueue:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field queue", values[i])
+			} else if value.Valid {
+				_m.Queue = value.String
+			}
+		case outboxitem.FieldRunID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field run_id", values[i])
+			} else if value.Valid {
+				_m.RunID = value.String
+			}
+		case outboxitem.FieldIntentName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field intent_name", values[i])
+			} else if value.Valid {
+				_m.IntentName = value.String
+			}
+		case outboxitem.FieldIdempotencyKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field idempotency_key", values[i])
+			} else if value.Valid {
+				_m.IdempotencyKey = value.String
+			}
+		case outboxitem.FieldArgs:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field args", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Args); err != nil {
+					return fmt.Errorf("unmarshal field args: %w", err)
+				}
+			}
+		case outboxitem.FieldDepth:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field depth", values[i])
+			} else if value.Valid {
+				_m.Depth = int(value.Int64)
+			}
+		case outboxitem.FieldClaims:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field claims", values[i])
+			} else if value.Valid {
+				_m.Claims = int(value.Int64)
+			}
+		case outboxitem.FieldLeaseOwner:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field lease_owner", values[i])
+			} else if value.Valid {
+				_m.LeaseOwner = value.String
+			}
+		case outboxitem.FieldLeaseExpiresAt:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field lease_expires_at", values[i])
+			} else if value.Valid {
+				_m.LeaseExpiresAt = value.Int64
+			}
+		case outboxitem.FieldAvailableAt:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field available_at", values[i])
+			} else if value.Valid {
+				_m.AvailableAt = value.Int64
+			}
+		case outboxitem.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the OutboxItem.
+// This includes values selected through modifiers, order, etc.
+func (_m *OutboxItem) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this OutboxItem.
+// Note that you need to call OutboxItem.Unwrap() before calling this method if this OutboxItem
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *OutboxItem) Update() *OutboxItemUpdateOne {
+	return NewOutboxItemClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the OutboxItem entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *OutboxItem) Unwrap() *OutboxItem {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: OutboxItem is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *OutboxItem) String() string {
+	var builder strings.Builder
+	builder.WriteString("OutboxItem(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("item_id=")
+	builder.WriteString(_m.ItemID)
+	builder.WriteString(", ")
+	builder.WriteString("queue=")
+	builder.WriteString(_m.Queue)
+	builder.WriteString(", ")
+	builder.WriteString("run_id=")
+	builder.WriteString(_m.RunID)
+	builder.WriteString(", ")
+	builder.WriteString("intent_name=")
+	builder.WriteString(_m.IntentName)
+	builder.WriteString(", ")
+	builder.WriteString("idempotency_key=")
+	builder.WriteString(_m.IdempotencyKey)
+	builder.WriteString(", ")
+	builder.WriteString("args=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Args))
+	builder.WriteString(", ")
+	builder.WriteString("depth=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Depth))
+	builder.WriteString(", ")
+	builder.WriteString("claims=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Claims))
+	builder.WriteString(", ")
+	builder.WriteString("lease_owner=")
+	builder.WriteString(_m.LeaseOwner)
+	builder.WriteString(", ")
+	builder.WriteString("lease_expires_at=")
+	builder.WriteString(fmt.Sprintf("%v", _m.LeaseExpiresAt))
+	builder.WriteString(", ")
+	builder.WriteString("available_at=")
+	builder.WriteString(fmt.Sprintf("%v", _m.AvailableAt))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// OutboxItems is a parsable slice of OutboxItem.
+type OutboxItems []*OutboxItem
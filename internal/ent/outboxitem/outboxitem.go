@@ -0,0 +1,155 @@
+// Code generated by ent, DO NOT EDIT.
+
+package outboxitem
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the outboxitem type in the database.
+	Label = "outbox_item"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldItemID holds the string denoting the item_id field in the database.
+	FieldItemID = "item_id"
+	// FieldQueue holds the string denoting the queue field in the database.
+	FieldQueue = "queue"
+	// FieldRunID holds the string denoting the run_id field in the database.
+	FieldRunID = "run_id"
+	// FieldIntentName holds the string denoting the intent_name field in the database.
+	FieldIntentName = "intent_name"
+	// FieldIdempotencyKey holds the string denoting the idempotency_key field in the database.
+	FieldIdempotencyKey = "idempotency_key"
+	// FieldArgs holds the string denoting the args field in the database.
+	FieldArgs = "args"
+	// FieldDepth holds the string denoting the depth field in the database.
+	FieldDepth = "depth"
+	// FieldClaims holds the string denoting the claims field in the database.
+	FieldClaims = "claims"
+	// FieldLeaseOwner holds the string denoting the lease_owner field in the database.
+	FieldLeaseOwner = "lease_owner"
+	// FieldLeaseExpiresAt holds the string denoting the lease_expires_at field in the database.
+	FieldLeaseExpiresAt = "lease_expires_at"
+	// FieldAvailableAt holds the string denoting the available_at field in the database.
+	FieldAvailableAt = "available_at"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// Table holds the table name of the outboxitem in the database.
+	Table = "outbox_items"
+)
+
+// Columns holds all SQL columns for outboxitem fields.
+var Columns = []string{
+	FieldID,
+	FieldItemID,
+	FieldQueue,
+	FieldRunID,
+	FieldIntentName,
+	FieldIdempotencyKey,
+	FieldArgs,
+	FieldDepth,
+	FieldClaims,
+	FieldLeaseOwner,
+	FieldLeaseExpiresAt,
+	FieldAvailableAt,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ItemIDValidator is a validator for the "item_id" field. It is called by the builders before save.
+	ItemIDValidator func(string) error
+	// QueueValidator is a validator for the "queue" field. It is called by the builders before save.
+	QueueValidator func(string) error
+	// RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	RunIDValidator func(string) error
+	// IntentNameValidator is a validator for the "intent_name" field. It is called by the builders before save.
+	IntentNameValidator func(string) error
+	// DefaultDepth holds the default value on creation for the "depth" field.
+	DefaultDepth int
+	// DepthValidator is a validator for the "depth" field. It is called by the builders before save.
+	DepthValidator func(int) error
+	// DefaultClaims holds the default value on creation for the "claims" field.
+	DefaultClaims int
+	// ClaimsValidator is a validator for the "claims" field. It is called by the builders before save.
+	ClaimsValidator func(int) error
+	// DefaultLeaseExpiresAt holds the default value on creation for the "lease_expires_at" field.
+	DefaultLeaseExpiresAt int64
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// OrderOption defines the ordering options for the OutboxItem queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByItemID orders the results by the item_id field.
+func ByItemID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldItemID, opts...).ToFunc()
+}
+
+// ByQueue orders the results by the queue field.
+func ByQueue(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldQueue, opts...).ToFunc()
+}
+
+// ByRunID orders the results by the run_id field.
+func ByRunID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRunID, opts...).ToFunc()
+}
+
+// ByIntentName orders the results by the intent_name field.
+func ByIntentName(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIntentName, opts...).ToFunc()
+}
+
+// ByIdempotencyKey orders the results by the idempotency_key field.
+func ByIdempotencyKey(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIdempotencyKey, opts...).ToFunc()
+}
+
+// ByDepth orders the results by the depth field.
+func ByDepth(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDepth, opts...).ToFunc()
+}
+
+// ByClaims orders the results by the claims field.
+func ByClaims(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldClaims, opts...).ToFunc()
+}
+
+// ByLeaseOwner orders the results by the lease_owner field.
+func ByLeaseOwner(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLeaseOwner, opts...).ToFunc()
+}
+
+// ByLeaseExpiresAt orders the results by the lease_expires_at field.
+func ByLeaseExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLeaseExpiresAt, opts...).ToFunc()
+}
+
+// ByAvailableAt orders the results by the available_at field.
+func ByAvailableAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAvailableAt, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
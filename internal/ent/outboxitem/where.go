@@ -0,0 +1,745 @@
+// Code generated by ent, DO NOT EDIT.
+
+package outboxitem
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldID, id))
+}
+
+// ItemID applies equality check predicate on the "item_id" field. It's identical to ItemIDEQ.
+func ItemID(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldItemID, v))
+}
+
+// Queue applies equality check predicate on the "queue" field. It's identical to QueueEQ.
+func Queue(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldQueue, v))
+}
+
+// RunID applies equality check predicate on the "run_id" field. It's identical to RunIDEQ.
+func RunID(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldRunID, v))
+}
+
+// IntentName applies equality check predicate on the "intent_name" field. It's identical to IntentNameEQ.
+func IntentName(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldIntentName, v))
+}
+
+// IdempotencyKey applies equality check predicate on the "idempotency_key" field. It's identical to IdempotencyKeyEQ.
+func IdempotencyKey(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldIdempotencyKey, v))
+}
+
+// Depth applies equality check predicate on the "depth" field. It's identical to DepthEQ.
+func Depth(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldDepth, v))
+}
+
+// Claims applies equality check predicate on the "claims" field. It's identical to ClaimsEQ.
+func Claims(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldClaims, v))
+}
+
+// LeaseOwner applies equality check predicate on the "lease_owner" field. It's identical to LeaseOwnerEQ.
+func LeaseOwner(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldLeaseOwner, v))
+}
+
+// LeaseExpiresAt applies equality check predicate on the "lease_expires_at" field. It's identical to LeaseExpiresAtEQ.
+func LeaseExpiresAt(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldLeaseExpiresAt, v))
+}
+
+// AvailableAt applies equality check predicate on the "available_at" field. It's identical to AvailableAtEQ.
+func AvailableAt(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldAvailableAt, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// ItemIDEQ applies the EQ predicate on the "item_id" field.
+func ItemIDEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldItemID, v))
+}
+
+// ItemIDNEQ applies the NEQ predicate on the "item_id" field.
+func ItemIDNEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldItemID, v))
+}
+
+// ItemIDIn applies the In predicate on the "item_id" field.
+func ItemIDIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldItemID, vs...))
+}
+
+// ItemIDNotIn applies the NotIn predicate on the "item_id" field.
+func ItemIDNotIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldItemID, vs...))
+}
+
+// ItemIDGT applies the GT predicate on the "item_id" field.
+func ItemIDGT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldItemID, v))
+}
+
+// ItemIDGTE applies the GTE predicate on the "item_id" field.
+func ItemIDGTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldItemID, v))
+}
+
+// ItemIDLT applies the LT predicate on the "item_id" field.
+func ItemIDLT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldItemID, v))
+}
+
+// ItemIDLTE applies the LTE predicate on the "item_id" field.
+func ItemIDLTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldItemID, v))
+}
+
+// ItemIDContains applies the Contains predicate on the "item_id" field.
+func ItemIDContains(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContains(FieldItemID, v))
+}
+
+// ItemIDHasPrefix applies the HasPrefix predicate on the "item_id" field.
+func ItemIDHasPrefix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasPrefix(FieldItemID, v))
+}
+
+// ItemIDHasSuffix applies the HasSuffix predicate on the "item_id" field.
+func ItemIDHasSuffix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasSuffix(FieldItemID, v))
+}
+
+// ItemIDEqualFold applies the EqualFold predicate on the "item_id" field.
+func ItemIDEqualFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEqualFold(FieldItemID, v))
+}
+
+// ItemIDContainsFold applies the ContainsFold predicate on the "item_id" field.
+func ItemIDContainsFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContainsFold(FieldItemID, v))
+}
+
+// QueueEQ applies the EQ predicate on the "queue" field.
+func QueueEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldQueue, v))
+}
+
+// QueueNEQ applies the NEQ predicate on the "queue" field.
+func QueueNEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldQueue, v))
+}
+
+// QueueIn applies the In predicate on the "queue" field.
+func QueueIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldQueue, vs...))
+}
+
+// QueueNotIn applies the NotIn predicate on the "queue" field.
+func QueueNotIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldQueue, vs...))
+}
+
+// QueueGT applies the GT predicate on the "queue" field.
+func QueueGT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldQueue, v))
+}
+
+// QueueGTE applies the GTE predicate on the "queue" field.
+func QueueGTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldQueue, v))
+}
+
+// QueueLT applies the LT predicate on the "queue" field.
+func QueueLT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldQueue, v))
+}
+
+// QueueLTE applies the LTE predicate on the "queue" field.
+func QueueLTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldQueue, v))
+}
+
+// QueueContains applies the Contains predicate on the "queue" field.
+func QueueContains(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContains(FieldQueue, v))
+}
+
+// QueueHasPrefix applies the HasPrefix predicate on the "queue" field.
+func QueueHasPrefix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasPrefix(FieldQueue, v))
+}
+
+// QueueHasSuffix applies the HasSuffix predicate on the "queue" field.
+func QueueHasSuffix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasSuffix(FieldQueue, v))
+}
+
+// QueueEqualFold applies the EqualFold predicate on the "queue" field.
+func QueueEqualFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEqualFold(FieldQueue, v))
+}
+
+// QueueContainsFold applies the ContainsFold predicate on the "queue" field.
+func QueueContainsFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContainsFold(FieldQueue, v))
+}
+
+// RunIDEQ applies the EQ predicate on the "run_id" field.
+func RunIDEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldRunID, v))
+}
+
+// RunIDNEQ applies the NEQ predicate on the "run_id" field.
+func RunIDNEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldRunID, v))
+}
+
+// RunIDIn applies the In predicate on the "run_id" field.
+func RunIDIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldRunID, vs...))
+}
+
+// RunIDNotIn applies the NotIn predicate on the "run_id" field.
+func RunIDNotIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldRunID, vs...))
+}
+
+// RunIDGT applies the GT predicate on the "run_id" field.
+func RunIDGT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldRunID, v))
+}
+
+// RunIDGTE applies the GTE predicate on the "run_id" field.
+func RunIDGTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldRunID, v))
+}
+
+// RunIDLT applies the LT predicate on the "run_id" field.
+func RunIDLT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldRunID, v))
+}
+
+// RunIDLTE applies the LTE predicate on the "run_id" field.
+func RunIDLTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldRunID, v))
+}
+
+// RunIDContains applies the Contains predicate on the "run_id" field.
+func RunIDContains(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContains(FieldRunID, v))
+}
+
+// RunIDHasPrefix applies the HasPrefix predicate on the "run_id" field.
+func RunIDHasPrefix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasPrefix(FieldRunID, v))
+}
+
+// RunIDHasSuffix applies the HasSuffix predicate on the "run_id" field.
+func RunIDHasSuffix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasSuffix(FieldRunID, v))
+}
+
+// RunIDEqualFold applies the EqualFold predicate on the "run_id" field.
+func RunIDEqualFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEqualFold(FieldRunID, v))
+}
+
+// RunIDContainsFold applies the ContainsFold predicate on the "run_id" field.
+func RunIDContainsFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContainsFold(FieldRunID, v))
+}
+
+// IntentNameEQ applies the EQ predicate on the "intent_name" field.
+func IntentNameEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldIntentName, v))
+}
+
+// IntentNameNEQ applies the NEQ predicate on the "intent_name" field.
+func IntentNameNEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldIntentName, v))
+}
+
+// IntentNameIn applies the In predicate on the "intent_name" field.
+func IntentNameIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldIntentName, vs...))
+}
+
+// IntentNameNotIn applies the NotIn predicate on the "intent_name" field.
+func IntentNameNotIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldIntentName, vs...))
+}
+
+// IntentNameGT applies the GT predicate on the "intent_name" field.
+func IntentNameGT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldIntentName, v))
+}
+
+// IntentNameGTE applies the GTE predicate on the "intent_name" field.
+func IntentNameGTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldIntentName, v))
+}
+
+// IntentNameLT applies the LT predicate on the "intent_name" field.
+func IntentNameLT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldIntentName, v))
+}
+
+// IntentNameLTE applies the LTE predicate on the "intent_name" field.
+func IntentNameLTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldIntentName, v))
+}
+
+// IntentNameContains applies the Contains predicate on the "intent_name" field.
+func IntentNameContains(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContains(FieldIntentName, v))
+}
+
+// IntentNameHasPrefix applies the HasPrefix predicate on the "intent_name" field.
+func IntentNameHasPrefix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasPrefix(FieldIntentName, v))
+}
+
+// IntentNameHasSuffix applies the HasSuffix predicate on the "intent_name" field.
+func IntentNameHasSuffix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasSuffix(FieldIntentName, v))
+}
+
+// IntentNameEqualFold applies the EqualFold predicate on the "intent_name" field.
+func IntentNameEqualFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEqualFold(FieldIntentName, v))
+}
+
+// IntentNameContainsFold applies the ContainsFold predicate on the "intent_name" field.
+func IntentNameContainsFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContainsFold(FieldIntentName, v))
+}
+
+// IdempotencyKeyEQ applies the EQ predicate on the "idempotency_key" field.
+func IdempotencyKeyEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyNEQ applies the NEQ predicate on the "idempotency_key" field.
+func IdempotencyKeyNEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyIn applies the In predicate on the "idempotency_key" field.
+func IdempotencyKeyIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldIdempotencyKey, vs...))
+}
+
+// IdempotencyKeyNotIn applies the NotIn predicate on the "idempotency_key" field.
+func IdempotencyKeyNotIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldIdempotencyKey, vs...))
+}
+
+// IdempotencyKeyGT applies the GT predicate on the "idempotency_key" field.
+func IdempotencyKeyGT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyGTE applies the GTE predicate on the "idempotency_key" field.
+func IdempotencyKeyGTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyLT applies the LT predicate on the "idempotency_key" field.
+func IdempotencyKeyLT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyLTE applies the LTE predicate on the "idempotency_key" field.
+func IdempotencyKeyLTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyContains applies the Contains predicate on the "idempotency_key" field.
+func IdempotencyKeyContains(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContains(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyHasPrefix applies the HasPrefix predicate on the "idempotency_key" field.
+func IdempotencyKeyHasPrefix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasPrefix(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyHasSuffix applies the HasSuffix predicate on the "idempotency_key" field.
+func IdempotencyKeyHasSuffix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasSuffix(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyIsNil applies the IsNil predicate on the "idempotency_key" field.
+func IdempotencyKeyIsNil() predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIsNull(FieldIdempotencyKey))
+}
+
+// IdempotencyKeyNotNil applies the NotNil predicate on the "idempotency_key" field.
+func IdempotencyKeyNotNil() predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotNull(FieldIdempotencyKey))
+}
+
+// IdempotencyKeyEqualFold applies the EqualFold predicate on the "idempotency_key" field.
+func IdempotencyKeyEqualFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEqualFold(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyContainsFold applies the ContainsFold predicate on the "idempotency_key" field.
+func IdempotencyKeyContainsFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContainsFold(FieldIdempotencyKey, v))
+}
+
+// ArgsIsNil applies the IsNil predicate on the "args" field.
+func ArgsIsNil() predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIsNull(FieldArgs))
+}
+
+// ArgsNotNil applies the NotNil predicate on the "args" field.
+func ArgsNotNil() predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotNull(FieldArgs))
+}
+
+// DepthEQ applies the EQ predicate on the "depth" field.
+func DepthEQ(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldDepth, v))
+}
+
+// DepthNEQ applies the NEQ predicate on the "depth" field.
+func DepthNEQ(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldDepth, v))
+}
+
+// DepthIn applies the In predicate on the "depth" field.
+func DepthIn(vs ...int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldDepth, vs...))
+}
+
+// DepthNotIn applies the NotIn predicate on the "depth" field.
+func DepthNotIn(vs ...int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldDepth, vs...))
+}
+
+// DepthGT applies the GT predicate on the "depth" field.
+func DepthGT(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldDepth, v))
+}
+
+// DepthGTE applies the GTE predicate on the "depth" field.
+func DepthGTE(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldDepth, v))
+}
+
+// DepthLT applies the LT predicate on the "depth" field.
+func DepthLT(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldDepth, v))
+}
+
+// DepthLTE applies the LTE predicate on the "depth" field.
+func DepthLTE(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldDepth, v))
+}
+
+// ClaimsEQ applies the EQ predicate on the "claims" field.
+func ClaimsEQ(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldClaims, v))
+}
+
+// ClaimsNEQ applies the NEQ predicate on the "claims" field.
+func ClaimsNEQ(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldClaims, v))
+}
+
+// ClaimsIn applies the In predicate on the "claims" field.
+func ClaimsIn(vs ...int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldClaims, vs...))
+}
+
+// ClaimsNotIn applies the NotIn predicate on the "claims" field.
+func ClaimsNotIn(vs ...int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldClaims, vs...))
+}
+
+// ClaimsGT applies the GT predicate on the "claims" field.
+func ClaimsGT(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldClaims, v))
+}
+
+// ClaimsGTE applies the GTE predicate on the "claims" field.
+func ClaimsGTE(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldClaims, v))
+}
+
+// ClaimsLT applies the LT predicate on the "claims" field.
+func ClaimsLT(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldClaims, v))
+}
+
+// ClaimsLTE applies the LTE predicate on the "claims" field.
+func ClaimsLTE(v int) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldClaims, v))
+}
+
+// LeaseOwnerEQ applies the EQ predicate on the "lease_owner" field.
+func LeaseOwnerEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerNEQ applies the NEQ predicate on the "lease_owner" field.
+func LeaseOwnerNEQ(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerIn applies the In predicate on the "lease_owner" field.
+func LeaseOwnerIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldLeaseOwner, vs...))
+}
+
+// LeaseOwnerNotIn applies the NotIn predicate on the "lease_owner" field.
+func LeaseOwnerNotIn(vs ...string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldLeaseOwner, vs...))
+}
+
+// LeaseOwnerGT applies the GT predicate on the "lease_owner" field.
+func LeaseOwnerGT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerGTE applies the GTE predicate on the "lease_owner" field.
+func LeaseOwnerGTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerLT applies the LT predicate on the "lease_owner" field.
+func LeaseOwnerLT(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerLTE applies the LTE predicate on the "lease_owner" field.
+func LeaseOwnerLTE(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerContains applies the Contains predicate on the "lease_owner" field.
+func LeaseOwnerContains(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContains(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerHasPrefix applies the HasPrefix predicate on the "lease_owner" field.
+func LeaseOwnerHasPrefix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasPrefix(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerHasSuffix applies the HasSuffix predicate on the "lease_owner" field.
+func LeaseOwnerHasSuffix(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldHasSuffix(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerIsNil applies the IsNil predicate on the "lease_owner" field.
+func LeaseOwnerIsNil() predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIsNull(FieldLeaseOwner))
+}
+
+// LeaseOwnerNotNil applies the NotNil predicate on the "lease_owner" field.
+func LeaseOwnerNotNil() predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotNull(FieldLeaseOwner))
+}
+
+// LeaseOwnerEqualFold applies the EqualFold predicate on the "lease_owner" field.
+func LeaseOwnerEqualFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEqualFold(FieldLeaseOwner, v))
+}
+
+// LeaseOwnerContainsFold applies the ContainsFold predicate on the "lease_owner" field.
+func LeaseOwnerContainsFold(v string) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldContainsFold(FieldLeaseOwner, v))
+}
+
+// LeaseExpiresAtEQ applies the EQ predicate on the "lease_expires_at" field.
+func LeaseExpiresAtEQ(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldLeaseExpiresAt, v))
+}
+
+// LeaseExpiresAtNEQ applies the NEQ predicate on the "lease_expires_at" field.
+func LeaseExpiresAtNEQ(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldLeaseExpiresAt, v))
+}
+
+// LeaseExpiresAtIn applies the In predicate on the "lease_expires_at" field.
+func LeaseExpiresAtIn(vs ...int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldLeaseExpiresAt, vs...))
+}
+
+// LeaseExpiresAtNotIn applies the NotIn predicate on the "lease_expires_at" field.
+func LeaseExpiresAtNotIn(vs ...int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldLeaseExpiresAt, vs...))
+}
+
+// LeaseExpiresAtGT applies the GT predicate on the "lease_expires_at" field.
+func LeaseExpiresAtGT(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldLeaseExpiresAt, v))
+}
+
+// LeaseExpiresAtGTE applies the GTE predicate on the "lease_expires_at" field.
+func LeaseExpiresAtGTE(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldLeaseExpiresAt, v))
+}
+
+// LeaseExpiresAtLT applies the LT predicate on the "lease_expires_at" field.
+func LeaseExpiresAtLT(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldLeaseExpiresAt, v))
+}
+
+// LeaseExpiresAtLTE applies the LTE predicate on the "lease_expires_at" field.
+func LeaseExpiresAtLTE(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldLeaseExpiresAt, v))
+}
+
+// AvailableAtEQ applies the EQ predicate on the "available_at" field.
+func AvailableAtEQ(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldAvailableAt, v))
+}
+
+// AvailableAtNEQ applies the NEQ predicate on the "available_at" field.
+func AvailableAtNEQ(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldAvailableAt, v))
+}
+
+// AvailableAtIn applies the In predicate on the "available_at" field.
+func AvailableAtIn(vs ...int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldAvailableAt, vs...))
+}
+
+// AvailableAtNotIn applies the NotIn predicate on the "available_at" field.
+func AvailableAtNotIn(vs ...int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldAvailableAt, vs...))
+}
+
+// AvailableAtGT applies the GT predicate on the "available_at" field.
+func AvailableAtGT(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldAvailableAt, v))
+}
+
+// AvailableAtGTE applies the GTE predicate on the "available_at" field.
+func AvailableAtGTE(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldAvailableAt, v))
+}
+
+// AvailableAtLT applies the LT predicate on the "available_at" field.
+func AvailableAtLT(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldAvailableAt, v))
+}
+
+// AvailableAtLTE applies the LTE predicate on the "available_at" field.
+func AvailableAtLTE(v int64) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldAvailableAt, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.OutboxItem) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.OutboxItem) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.OutboxItem) predicate.OutboxItem {
+	return predicate.OutboxItem(sql.NotPredicates(p))
+}
@@ -0,0 +1,418 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+)
+
+// OutboxItemCreate is the builder for creating a OutboxItem entity.
+type OutboxItemCreate struct {
+	config
+	mutation *OutboxItemMutation
+	hooks    []Hook
+}
+
+// SetItemID sets the "item_id" field.
+func (_c *OutboxItemCreate) SetItemID(v string) *OutboxItemCreate {
+	_c.mutation.SetItemID(v)
+	return _c
+}
+
+// SetQueue sets the "queue" field.
+func (_c *OutboxItemCreate) SetQueue(v string) *OutboxItemCreate {
+	_c.mutation.SetQueue(v)
+	return _c
+}
+
+// SetRunID sets the "run_id" field.
+func (_c *OutboxItemCreate) SetRunID(v string) *OutboxItemCreate {
+	_c.mutation.SetRunID(v)
+	return _c
+}
+
+// SetIntentName sets the "intent_name" field.
+func (_c *OutboxItemCreate) SetIntentName(v string) *OutboxItemCreate {
+	_c.mutation.SetIntentName(v)
+	return _c
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (_c *OutboxItemCreate) SetIdempotencyKey(v string) *OutboxItemCreate {
+	_c.mutation.SetIdempotencyKey(v)
+	return _c
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (_c *OutboxItemCreate) SetNillableIdempotencyKey(v *string) *OutboxItemCreate {
+	if v != nil {
+		_c.SetIdempotencyKey(*v)
+	}
+	return _c
+}
+
+// SetArgs sets the "args" field.
+func (_c *OutboxItemCreate) SetArgs(v map[string]interface{}) *OutboxItemCreate {
+	_c.mutation.SetArgs(v)
+	return _c
+}
+
+// SetDepth sets the "depth" field.
+func (_c *OutboxItemCreate) SetDepth(v int) *OutboxItemCreate {
+	_c.mutation.SetDepth(v)
+	return _c
+}
+
+// SetNillableDepth sets the "depth" field if the given value is not nil.
+func (_c *OutboxItemCreate) SetNillableDepth(v *int) *OutboxItemCreate {
+	if v != nil {
+		_c.SetDepth(*v)
+	}
+	return _c
+}
+
+// SetClaims sets the "claims" field.
+func (_c *OutboxItemCreate) SetClaims(v int) *OutboxItemCreate {
+	_c.mutation.SetClaims(v)
+	return _c
+}
+
+// SetNillableClaims sets the "claims" field if the given value is not nil.
+func (_c *OutboxItemCreate) SetNillableClaims(v *int) *OutboxItemCreate {
+	if v != nil {
+		_c.SetClaims(*v)
+	}
+	return _c
+}
+
+// SetLeaseOwner sets the "lease_owner" field.
+func (_c *OutboxItemCreate) SetLeaseOwner(v string) *OutboxItemCreate {
+	_c.mutation.SetLeaseOwner(v)
+	return _c
+}
+
+// SetNillableLeaseOwner sets the "lease_owner" field if the given value is not nil.
+func (_c *OutboxItemCreate) SetNillableLeaseOwner(v *string) *OutboxItemCreate {
+	if v != nil {
+		_c.SetLeaseOwner(*v)
+	}
+	return _c
+}
+
+// SetLeaseExpiresAt sets the "lease_expires_at" field.
+func (_c *OutboxItemCreate) SetLeaseExpiresAt(v int64) *OutboxItemCreate {
+	_c.mutation.SetLeaseExpiresAt(v)
+	return _c
+}
+
+// SetNillableLeaseExpiresAt sets the "lease_expires_at" field if the given value is not nil.
+func (_c *OutboxItemCreate) SetNillableLeaseExpiresAt(v *int64) *OutboxItemCreate {
+	if v != nil {
+		_c.SetLeaseExpiresAt(*v)
+	}
+	return _c
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (_c *OutboxItemCreate) SetAvailableAt(v int64) *OutboxItemCreate {
+	_c.mutation.SetAvailableAt(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *OutboxItemCreate) SetCreatedAt(v time.Time) *OutboxItemCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *OutboxItemCreate) SetNillableCreatedAt(v *time.Time) *OutboxItemCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the OutboxItemMutation object of the builder.
+func (_c *OutboxItemCreate) Mutation() *OutboxItemMutation {
+	return _c.mutation
+}
+
+// Save creates the OutboxItem in the database.
+func (_c *OutboxItemCreate) Save(ctx context.Context) (*OutboxItem, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *OutboxItemCreate) SaveX(ctx context.Context) *OutboxItem {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *OutboxItemCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *OutboxItemCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *OutboxItemCreate) defaults() {
+	if _, ok := _c.mutation.Depth(); !ok {
+		v := outboxitem.DefaultDepth
+		_c.mutation.SetDepth(v)
+	}
+	if _, ok := _c.mutation.Claims(); !ok {
+		v := outboxitem.DefaultClaims
+		_c.mutation.SetClaims(v)
+	}
+	if _, ok := _c.mutation.LeaseExpiresAt(); !ok {
+		v := outboxitem.DefaultLeaseExpiresAt
+		_c.mutation.SetLeaseExpiresAt(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := outboxitem.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *OutboxItemCreate) check() error {
+	if _, ok := _c.mutation.ItemID(); !ok {
+		return &ValidationError{Name: "item_id", err: errors.New(`ent: missing required field "OutboxItem.item_id"`)}
+	}
+	if v, ok := _c.mutation.ItemID(); ok {
+		if err := outboxitem.ItemIDValidator(v); err != nil {
+			return &ValidationError{Name: "item_id", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.item_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Queue(); !ok {
+		return &ValidationError{Name: "queue", err: errors.New(`ent: missing required field "OutboxItem.queue"`)}
+	}
+	if v, ok := _c.mutation.Queue(); ok {
+		if err := outboxitem.QueueValidator(v); err != nil {
+			return &ValidationError{Name: "queue", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.queue": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.RunID(); !ok {
+		return &ValidationError{Name: "run_id", err: errors.New(`ent: missing required field "OutboxItem.run_id"`)}
+	}
+	if v, ok := _c.mutation.RunID(); ok {
+		if err := outboxitem.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.run_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.IntentName(); !ok {
+		return &ValidationError{Name: "intent_name", err: errors.New(`ent: missing required field "OutboxItem.intent_name"`)}
+	}
+	if v, ok := _c.mutation.IntentName(); ok {
+		if err := outboxitem.IntentNameValidator(v); err != nil {
+			return &ValidationError{Name: "intent_name", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.intent_name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Depth(); !ok {
+		return &ValidationError{Name: "depth", err: errors.New(`ent: missing required field "OutboxItem.depth"`)}
+	}
+	if v, ok := _c.mutation.Depth(); ok {
+		if err := outboxitem.DepthValidator(v); err != nil {
+			return &ValidationError{Name: "depth", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.depth": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Claims(); !ok {
+		return &ValidationError{Name: "claims", err: errors.New(`ent: missing required field "OutboxItem.claims"`)}
+	}
+	if v, ok := _c.mutation.Claims(); ok {
+		if err := outboxitem.ClaimsValidator(v); err != nil {
+			return &ValidationError{Name: "claims", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.claims": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.LeaseExpiresAt(); !ok {
+		return &ValidationError{Name: "lease_expires_at", err: errors.New(`ent: missing required field "OutboxItem.lease_expires_at"`)}
+	}
+	if _, ok := _c.mutation.AvailableAt(); !ok {
+		return &ValidationError{Name: "available_at", err: errors.New(`ent: missing required field "OutboxItem.available_at"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "OutboxItem.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *OutboxItemCreate) sqlSave(ctx context.Context) (*OutboxItem, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *OutboxItemCreate) createSpec() (*OutboxItem, *sqlgraph.CreateSpec) {
+	var (
+		_node = &OutboxItem{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(outboxitem.Table, sqlgraph.NewFieldSpec(outboxitem.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.ItemID(); ok {
+		_spec.SetField(outboxitem.FieldItemID, field.TypeString, value)
+		_node.ItemID = value
+	}
+	if value, ok := _c.mutation.Queue(); ok {
+		_spec.SetField(outboxitem.FieldQueue, field.TypeString, value)
+		_node.Queue = value
+	}
+	if value, ok := _c.mutation.RunID(); ok {
+		_spec.SetField(outboxitem.FieldRunID, field.TypeString, value)
+		_node.RunID = value
+	}
+	if value, ok := _c.mutation.IntentName(); ok {
+		_spec.SetField(outboxitem.FieldIntentName, field.TypeString, value)
+		_node.IntentName = value
+	}
+	if value, ok := _c.mutation.IdempotencyKey(); ok {
+		_spec.SetField(outboxitem.FieldIdempotencyKey, field.TypeString, value)
+		_node.IdempotencyKey = value
+	}
+	if value, ok := _c.mutation.Args(); ok {
+		_spec.SetField(outboxitem.FieldArgs, field.TypeJSON, value)
+		_node.Args = value
+	}
+	if value, ok := _c.mutation.Depth(); ok {
+		_spec.SetField(outboxitem.FieldDepth, field.TypeInt, value)
+		_node.Depth = value
+	}
+	if value, ok := _c.mutation.Claims(); ok {
+		_spec.SetField(outboxitem.FieldClaims, field.TypeInt, value)
+		_node.Claims = value
+	}
+	if value, ok := _c.mutation.LeaseOwner(); ok {
+		_spec.SetField(outboxitem.FieldLeaseOwner, field.TypeString, value)
+		_node.LeaseOwner = value
+	}
+	if value, ok := _c.mutation.LeaseExpiresAt(); ok {
+		_spec.SetField(outboxitem.FieldLeaseExpiresAt, field.TypeInt64, value)
+		_node.LeaseExpiresAt = value
+	}
+	if value, ok := _c.mutation.AvailableAt(); ok {
+		_spec.SetField(outboxitem.FieldAvailableAt, field.TypeInt64, value)
+		_node.AvailableAt = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(outboxitem.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// OutboxItemCreateBulk is the builder for creating many OutboxItem entities in bulk.
+type OutboxItemCreateBulk struct {
+	config
+	err      error
+	builders []*OutboxItemCreate
+}
+
+// Save creates the OutboxItem entities in the database.
+func (_c *OutboxItemCreateBulk) Save(ctx context.Context) ([]*OutboxItem, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*OutboxItem, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*OutboxItemMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *OutboxItemCreateBulk) SaveX(ctx context.Context) []*OutboxItem {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *OutboxItemCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *OutboxItemCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
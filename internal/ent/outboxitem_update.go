@@ -0,0 +1,743 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// OutboxItemUpdate is the builder for updating OutboxItem entities.
+type OutboxItemUpdate struct {
+	config
+	hooks    []Hook
+	mutation *OutboxItemMutation
+}
+
+// Where appends a list predicates to the OutboxItemUpdate builder.
+func (_u *OutboxItemUpdate) Where(ps ...predicate.OutboxItem) *OutboxItemUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetItemID sets the "item_id" field.
+func (_u *OutboxItemUpdate) SetItemID(v string) *OutboxItemUpdate {
+	_u.mutation.SetItemID(v)
+	return _u
+}
+
+// SetNillableItemID sets the "item_id" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableItemID(v *string) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetItemID(*v)
+	}
+	return _u
+}
+
+// SetQueue sets the "queue" field.
+func (_u *OutboxItemUpdate) SetQueue(v string) *OutboxItemUpdate {
+	_u.mutation.SetQueue(v)
+	return _u
+}
+
+// SetNillableQueue sets the "queue" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableQueue(v *string) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetQueue(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *OutboxItemUpdate) SetRunID(v string) *OutboxItemUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableRunID(v *string) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetIntentName sets the "intent_name" field.
+func (_u *OutboxItemUpdate) SetIntentName(v string) *OutboxItemUpdate {
+	_u.mutation.SetIntentName(v)
+	return _u
+}
+
+// SetNillableIntentName sets the "intent_name" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableIntentName(v *string) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetIntentName(*v)
+	}
+	return _u
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (_u *OutboxItemUpdate) SetIdempotencyKey(v string) *OutboxItemUpdate {
+	_u.mutation.SetIdempotencyKey(v)
+	return _u
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableIdempotencyKey(v *string) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetIdempotencyKey(*v)
+	}
+	return _u
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (_u *OutboxItemUpdate) ClearIdempotencyKey() *OutboxItemUpdate {
+	_u.mutation.ClearIdempotencyKey()
+	return _u
+}
+
+// SetArgs sets the "args" field.
+func (_u *OutboxItemUpdate) SetArgs(v map[string]interface{}) *OutboxItemUpdate {
+	_u.mutation.SetArgs(v)
+	return _u
+}
+
+// ClearArgs clears the value of the "args" field.
+func (_u *OutboxItemUpdate) ClearArgs() *OutboxItemUpdate {
+	_u.mutation.ClearArgs()
+	return _u
+}
+
+// SetDepth sets the "depth" field.
+func (_u *OutboxItemUpdate) SetDepth(v int) *OutboxItemUpdate {
+	_u.mutation.ResetDepth()
+	_u.mutation.SetDepth(v)
+	return _u
+}
+
+// SetNillableDepth sets the "depth" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableDepth(v *int) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetDepth(*v)
+	}
+	return _u
+}
+
+// AddDepth adds value to the "depth" field.
+func (_u *OutboxItemUpdate) AddDepth(v int) *OutboxItemUpdate {
+	_u.mutation.AddDepth(v)
+	return _u
+}
+
+// SetClaims sets the "claims" field.
+func (_u *OutboxItemUpdate) SetClaims(v int) *OutboxItemUpdate {
+	_u.mutation.ResetClaims()
+	_u.mutation.SetClaims(v)
+	return _u
+}
+
+// SetNillableClaims sets the "claims" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableClaims(v *int) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetClaims(*v)
+	}
+	return _u
+}
+
+// AddClaims adds value to the "claims" field.
+func (_u *OutboxItemUpdate) AddClaims(v int) *OutboxItemUpdate {
+	_u.mutation.AddClaims(v)
+	return _u
+}
+
+// SetLeaseOwner sets the "lease_owner" field.
+func (_u *OutboxItemUpdate) SetLeaseOwner(v string) *OutboxItemUpdate {
+	_u.mutation.SetLeaseOwner(v)
+	return _u
+}
+
+// SetNillableLeaseOwner sets the "lease_owner" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableLeaseOwner(v *string) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetLeaseOwner(*v)
+	}
+	return _u
+}
+
+// ClearLeaseOwner clears the value of the "lease_owner" field.
+func (_u *OutboxItemUpdate) ClearLeaseOwner() *OutboxItemUpdate {
+	_u.mutation.ClearLeaseOwner()
+	return _u
+}
+
+// SetLeaseExpiresAt sets the "lease_expires_at" field.
+func (_u *OutboxItemUpdate) SetLeaseExpiresAt(v int64) *OutboxItemUpdate {
+	_u.mutation.ResetLeaseExpiresAt()
+	_u.mutation.SetLeaseExpiresAt(v)
+	return _u
+}
+
+// SetNillableLeaseExpiresAt sets the "lease_expires_at" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableLeaseExpiresAt(v *int64) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetLeaseExpiresAt(*v)
+	}
+	return _u
+}
+
+// AddLeaseExpiresAt adds value to the "lease_expires_at" field.
+func (_u *OutboxItemUpdate) AddLeaseExpiresAt(v int64) *OutboxItemUpdate {
+	_u.mutation.AddLeaseExpiresAt(v)
+	return _u
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (_u *OutboxItemUpdate) SetAvailableAt(v int64) *OutboxItemUpdate {
+	_u.mutation.ResetAvailableAt()
+	_u.mutation.SetAvailableAt(v)
+	return _u
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (_u *OutboxItemUpdate) SetNillableAvailableAt(v *int64) *OutboxItemUpdate {
+	if v != nil {
+		_u.SetAvailableAt(*v)
+	}
+	return _u
+}
+
+// AddAvailableAt adds value to the "available_at" field.
+func (_u *OutboxItemUpdate) AddAvailableAt(v int64) *OutboxItemUpdate {
+	_u.mutation.AddAvailableAt(v)
+	return _u
+}
+
+// Mutation returns the OutboxItemMutation object of the builder.
+func (_u *OutboxItemUpdate) Mutation() *OutboxItemMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *OutboxItemUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *OutboxItemUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *OutboxItemUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *OutboxItemUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *OutboxItemUpdate) check() error {
+	if v, ok := _u.mutation.ItemID(); ok {
+		if err := outboxitem.ItemIDValidator(v); err != nil {
+			return &ValidationError{Name: "item_id", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.item_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Queue(); ok {
+		if err := outboxitem.QueueValidator(v); err != nil {
+			return &ValidationError{Name: "queue", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.queue": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := outboxitem.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.IntentName(); ok {
+		if err := outboxitem.IntentNameValidator(v); err != nil {
+			return &ValidationError{Name: "intent_name", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.intent_name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Depth(); ok {
+		if err := outboxitem.DepthValidator(v); err != nil {
+			return &ValidationError{Name: "depth", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.depth": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Claims(); ok {
+		if err := outboxitem.ClaimsValidator(v); err != nil {
+			return &ValidationError{Name: "claims", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.claims": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *OutboxItemUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(outboxitem.Table, outboxitem.Columns, sqlgraph.NewFieldSpec(outboxitem.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ItemID(); ok {
+		_spec.SetField(outboxitem.FieldItemID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Queue(); ok {
+		_spec.SetField(outboxitem.FieldQueue, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(outboxitem.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IntentName(); ok {
+		_spec.SetField(outboxitem.FieldIntentName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IdempotencyKey(); ok {
+		_spec.SetField(outboxitem.FieldIdempotencyKey, field.TypeString, value)
+	}
+	if _u.mutation.IdempotencyKeyCleared() {
+		_spec.ClearField(outboxitem.FieldIdempotencyKey, field.TypeString)
+	}
+	if value, ok := _u.mutation.Args(); ok {
+		_spec.SetField(outboxitem.FieldArgs, field.TypeJSON, value)
+	}
+	if _u.mutation.ArgsCleared() {
+		_spec.ClearField(outboxitem.FieldArgs, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Depth(); ok {
+		_spec.SetField(outboxitem.FieldDepth, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDepth(); ok {
+		_spec.AddField(outboxitem.FieldDepth, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Claims(); ok {
+		_spec.SetField(outboxitem.FieldClaims, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedClaims(); ok {
+		_spec.AddField(outboxitem.FieldClaims, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.LeaseOwner(); ok {
+		_spec.SetField(outboxitem.FieldLeaseOwner, field.TypeString, value)
+	}
+	if _u.mutation.LeaseOwnerCleared() {
+		_spec.ClearField(outboxitem.FieldLeaseOwner, field.TypeString)
+	}
+	if value, ok := _u.mutation.LeaseExpiresAt(); ok {
+		_spec.SetField(outboxitem.FieldLeaseExpiresAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedLeaseExpiresAt(); ok {
+		_spec.AddField(outboxitem.FieldLeaseExpiresAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AvailableAt(); ok {
+		_spec.SetField(outboxitem.FieldAvailableAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAvailableAt(); ok {
+		_spec.AddField(outboxitem.FieldAvailableAt, field.TypeInt64, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{outboxitem.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// OutboxItemUpdateOne is the builder for updating a single OutboxItem entity.
+type OutboxItemUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *OutboxItemMutation
+}
+
+// SetItemID sets the "item_id" field.
+func (_u *OutboxItemUpdateOne) SetItemID(v string) *OutboxItemUpdateOne {
+	_u.mutation.SetItemID(v)
+	return _u
+}
+
+// SetNillableItemID sets the "item_id" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableItemID(v *string) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetItemID(*v)
+	}
+	return _u
+}
+
+// SetQueue sets the "queue" field.
+func (_u *OutboxItemUpdateOne) SetQueue(v string) *OutboxItemUpdateOne {
+	_u.mutation.SetQueue(v)
+	return _u
+}
+
+// SetNillableQueue sets the "queue" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableQueue(v *string) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetQueue(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *OutboxItemUpdateOne) SetRunID(v string) *OutboxItemUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableRunID(v *string) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetIntentName sets the "intent_name" field.
+func (_u *OutboxItemUpdateOne) SetIntentName(v string) *OutboxItemUpdateOne {
+	_u.mutation.SetIntentName(v)
+	return _u
+}
+
+// SetNillableIntentName sets the "intent_name" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableIntentName(v *string) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetIntentName(*v)
+	}
+	return _u
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (_u *OutboxItemUpdateOne) SetIdempotencyKey(v string) *OutboxItemUpdateOne {
+	_u.mutation.SetIdempotencyKey(v)
+	return _u
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableIdempotencyKey(v *string) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetIdempotencyKey(*v)
+	}
+	return _u
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (_u *OutboxItemUpdateOne) ClearIdempotencyKey() *OutboxItemUpdateOne {
+	_u.mutation.ClearIdempotencyKey()
+	return _u
+}
+
+// SetArgs sets the "args" field.
+func (_u *OutboxItemUpdateOne) SetArgs(v map[string]interface{}) *OutboxItemUpdateOne {
+	_u.mutation.SetArgs(v)
+	return _u
+}
+
+// ClearArgs clears the value of the "args" field.
+func (_u *OutboxItemUpdateOne) ClearArgs() *OutboxItemUpdateOne {
+	_u.mutation.ClearArgs()
+	return _u
+}
+
+// SetDepth sets the "depth" field.
+func (_u *OutboxItemUpdateOne) SetDepth(v int) *OutboxItemUpdateOne {
+	_u.mutation.ResetDepth()
+	_u.mutation.SetDepth(v)
+	return _u
+}
+
+// SetNillableDepth sets the "depth" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableDepth(v *int) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetDepth(*v)
+	}
+	return _u
+}
+
+// AddDepth adds value to the "depth" field.
+func (_u *OutboxItemUpdateOne) AddDepth(v int) *OutboxItemUpdateOne {
+	_u.mutation.AddDepth(v)
+	return _u
+}
+
+// SetClaims sets the "claims" field.
+func (_u *OutboxItemUpdateOne) SetClaims(v int) *OutboxItemUpdateOne {
+	_u.mutation.ResetClaims()
+	_u.mutation.SetClaims(v)
+	return _u
+}
+
+// SetNillableClaims sets the "claims" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableClaims(v *int) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetClaims(*v)
+	}
+	return _u
+}
+
+// AddClaims adds value to the "claims" field.
+func (_u *OutboxItemUpdateOne) AddClaims(v int) *OutboxItemUpdateOne {
+	_u.mutation.AddClaims(v)
+	return _u
+}
+
+// SetLeaseOwner sets the "lease_owner" field.
+func (_u *OutboxItemUpdateOne) SetLeaseOwner(v string) *OutboxItemUpdateOne {
+	_u.mutation.SetLeaseOwner(v)
+	return _u
+}
+
+// SetNillableLeaseOwner sets the "lease_owner" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableLeaseOwner(v *string) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetLeaseOwner(*v)
+	}
+	return _u
+}
+
+// ClearLeaseOwner clears the value of the "lease_owner" field.
+func (_u *OutboxItemUpdateOne) ClearLeaseOwner() *OutboxItemUpdateOne {
+	_u.mutation.ClearLeaseOwner()
+	return _u
+}
+
+// SetLeaseExpiresAt sets the "lease_expires_at" field.
+func (_u *OutboxItemUpdateOne) SetLeaseExpiresAt(v int64) *OutboxItemUpdateOne {
+	_u.mutation.ResetLeaseExpiresAt()
+	_u.mutation.SetLeaseExpiresAt(v)
+	return _u
+}
+
+// SetNillableLeaseExpiresAt sets the "lease_expires_at" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableLeaseExpiresAt(v *int64) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetLeaseExpiresAt(*v)
+	}
+	return _u
+}
+
+// AddLeaseExpiresAt adds value to the "lease_expires_at" field.
+func (_u *OutboxItemUpdateOne) AddLeaseExpiresAt(v int64) *OutboxItemUpdateOne {
+	_u.mutation.AddLeaseExpiresAt(v)
+	return _u
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (_u *OutboxItemUpdateOne) SetAvailableAt(v int64) *OutboxItemUpdateOne {
+	_u.mutation.ResetAvailableAt()
+	_u.mutation.SetAvailableAt(v)
+	return _u
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (_u *OutboxItemUpdateOne) SetNillableAvailableAt(v *int64) *OutboxItemUpdateOne {
+	if v != nil {
+		_u.SetAvailableAt(*v)
+	}
+	return _u
+}
+
+// AddAvailableAt adds value to the "available_at" field.
+func (_u *OutboxItemUpdateOne) AddAvailableAt(v int64) *OutboxItemUpdateOne {
+	_u.mutation.AddAvailableAt(v)
+	return _u
+}
+
+// Mutation returns the OutboxItemMutation object of the builder.
+func (_u *OutboxItemUpdateOne) Mutation() *OutboxItemMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the OutboxItemUpdate builder.
+func (_u *OutboxItemUpdateOne) Where(ps ...predicate.OutboxItem) *OutboxItemUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *OutboxItemUpdateOne) Select(field string, fields ...string) *OutboxItemUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated OutboxItem entity.
+func (_u *OutboxItemUpdateOne) Save(ctx context.Context) (*OutboxItem, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *OutboxItemUpdateOne) SaveX(ctx context.Context) *OutboxItem {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *OutboxItemUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *OutboxItemUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *OutboxItemUpdateOne) check() error {
+	if v, ok := _u.mutation.ItemID(); ok {
+		if err := outboxitem.ItemIDValidator(v); err != nil {
+			return &ValidationError{Name: "item_id", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.item_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Queue(); ok {
+		if err := outboxitem.QueueValidator(v); err != nil {
+			return &ValidationError{Name: "queue", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.queue": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := outboxitem.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.IntentName(); ok {
+		if err := outboxitem.IntentNameValidator(v); err != nil {
+			return &ValidationError{Name: "intent_name", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.intent_name": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Depth(); ok {
+		if err := outboxitem.DepthValidator(v); err != nil {
+			return &ValidationError{Name: "depth", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.depth": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Claims(); ok {
+		if err := outboxitem.ClaimsValidator(v); err != nil {
+			return &ValidationError{Name: "claims", err: fmt.Errorf(`ent: validator failed for field "OutboxItem.claims": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *OutboxItemUpdateOne) sqlSave(ctx context.Context) (_node *OutboxItem, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(outboxitem.Table, outboxitem.Columns, sqlgraph.NewFieldSpec(outboxitem.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "OutboxItem.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, outboxitem.FieldID)
+		for _, f := range fields {
+			if !outboxitem.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != outboxitem.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ItemID(); ok {
+		_spec.SetField(outboxitem.FieldItemID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Queue(); ok {
+		_spec.SetField(outboxitem.FieldQueue, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(outboxitem.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IntentName(); ok {
+		_spec.SetField(outboxitem.FieldIntentName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IdempotencyKey(); ok {
+		_spec.SetField(outboxitem.FieldIdempotencyKey, field.TypeString, value)
+	}
+	if _u.mutation.IdempotencyKeyCleared() {
+		_spec.ClearField(outboxitem.FieldIdempotencyKey, field.TypeString)
+	}
+	if value, ok := _u.mutation.Args(); ok {
+		_spec.SetField(outboxitem.FieldArgs, field.TypeJSON, value)
+	}
+	if _u.mutation.ArgsCleared() {
+		_spec.ClearField(outboxitem.FieldArgs, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Depth(); ok {
+		_spec.SetField(outboxitem.FieldDepth, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDepth(); ok {
+		_spec.AddField(outboxitem.FieldDepth, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.Claims(); ok {
+		_spec.SetField(outboxitem.FieldClaims, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedClaims(); ok {
+		_spec.AddField(outboxitem.FieldClaims, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.LeaseOwner(); ok {
+		_spec.SetField(outboxitem.FieldLeaseOwner, field.TypeString, value)
+	}
+	if _u.mutation.LeaseOwnerCleared() {
+		_spec.ClearField(outboxitem.FieldLeaseOwner, field.TypeString)
+	}
+	if value, ok := _u.mutation.LeaseExpiresAt(); ok {
+		_spec.SetField(outboxitem.FieldLeaseExpiresAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedLeaseExpiresAt(); ok {
+		_spec.AddField(outboxitem.FieldLeaseExpiresAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AvailableAt(); ok {
+		_spec.SetField(outboxitem.FieldAvailableAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAvailableAt(); ok {
+		_spec.AddField(outboxitem.FieldAvailableAt, field.TypeInt64, value)
+	}
+	_node = &OutboxItem{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{outboxitem.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -6,8 +6,32 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// ArchivedEvent is the predicate function for archivedevent builders.
+type ArchivedEvent func(*sql.Selector)
+
+// AuditEntry is the predicate function for auditentry builders.
+type AuditEntry func(*sql.Selector)
+
+// Checkpoint is the predicate function for checkpoint builders.
+type Checkpoint func(*sql.Selector)
+
+// DeadLetter is the predicate function for deadletter builders.
+type DeadLetter func(*sql.Selector)
+
 // Event is the predicate function for event builders.
 type Event func(*sql.Selector)
 
+// OutboxItem is the predicate function for outboxitem builders.
+type OutboxItem func(*sql.Selector)
+
+// Run is the predicate function for run builders.
+type Run func(*sql.Selector)
+
+// ShredKey is the predicate function for shredkey builders.
+type ShredKey func(*sql.Selector)
+
 // Snapshot is the predicate function for snapshot builders.
 type Snapshot func(*sql.Selector)
+
+// Timer is the predicate function for timer builders.
+type Timer func(*sql.Selector)
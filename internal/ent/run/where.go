@@ -0,0 +1,515 @@
+// Code generated by ent, DO NOT EDIT.
+
+package run
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldID, id))
+}
+
+// RunID applies equality check predicate on the "run_id" field. It's identical to RunIDEQ.
+func RunID(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldRunID, v))
+}
+
+// Status applies equality check predicate on the "status" field. It's identical to StatusEQ.
+func Status(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldStatus, v))
+}
+
+// AgentType applies equality check predicate on the "agent_type" field. It's identical to AgentTypeEQ.
+func AgentType(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldAgentType, v))
+}
+
+// ParentRunID applies equality check predicate on the "parent_run_id" field. It's identical to ParentRunIDEQ.
+func ParentRunID(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldParentRunID, v))
+}
+
+// LastSeq applies equality check predicate on the "last_seq" field. It's identical to LastSeqEQ.
+func LastSeq(v int64) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldLastSeq, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// RunIDEQ applies the EQ predicate on the "run_id" field.
+func RunIDEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldRunID, v))
+}
+
+// RunIDNEQ applies the NEQ predicate on the "run_id" field.
+func RunIDNEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldRunID, v))
+}
+
+// RunIDIn applies the In predicate on the "run_id" field.
+func RunIDIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldRunID, vs...))
+}
+
+// RunIDNotIn applies the NotIn predicate on the "run_id" field.
+func RunIDNotIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldRunID, vs...))
+}
+
+// RunIDGT applies the GT predicate on the "run_id" field.
+func RunIDGT(v string) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldRunID, v))
+}
+
+// RunIDGTE applies the GTE predicate on the "run_id" field.
+func RunIDGTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldRunID, v))
+}
+
+// RunIDLT applies the LT predicate on the "run_id" field.
+func RunIDLT(v string) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldRunID, v))
+}
+
+// RunIDLTE applies the LTE predicate on the "run_id" field.
+func RunIDLTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldRunID, v))
+}
+
+// RunIDContains applies the Contains predicate on the "run_id" field.
+func RunIDContains(v string) predicate.Run {
+	return predicate.Run(sql.FieldContains(FieldRunID, v))
+}
+
+// RunIDHasPrefix applies the HasPrefix predicate on the "run_id" field.
+func RunIDHasPrefix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasPrefix(FieldRunID, v))
+}
+
+// RunIDHasSuffix applies the HasSuffix predicate on the "run_id" field.
+func RunIDHasSuffix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasSuffix(FieldRunID, v))
+}
+
+// RunIDEqualFold applies the EqualFold predicate on the "run_id" field.
+func RunIDEqualFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldEqualFold(FieldRunID, v))
+}
+
+// RunIDContainsFold applies the ContainsFold predicate on the "run_id" field.
+func RunIDContainsFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldContainsFold(FieldRunID, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// StatusGT applies the GT predicate on the "status" field.
+func StatusGT(v string) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldStatus, v))
+}
+
+// StatusGTE applies the GTE predicate on the "status" field.
+func StatusGTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldStatus, v))
+}
+
+// StatusLT applies the LT predicate on the "status" field.
+func StatusLT(v string) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldStatus, v))
+}
+
+// StatusLTE applies the LTE predicate on the "status" field.
+func StatusLTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldStatus, v))
+}
+
+// StatusContains applies the Contains predicate on the "status" field.
+func StatusContains(v string) predicate.Run {
+	return predicate.Run(sql.FieldContains(FieldStatus, v))
+}
+
+// StatusHasPrefix applies the HasPrefix predicate on the "status" field.
+func StatusHasPrefix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasPrefix(FieldStatus, v))
+}
+
+// StatusHasSuffix applies the HasSuffix predicate on the "status" field.
+func StatusHasSuffix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasSuffix(FieldStatus, v))
+}
+
+// StatusEqualFold applies the EqualFold predicate on the "status" field.
+func StatusEqualFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldEqualFold(FieldStatus, v))
+}
+
+// StatusContainsFold applies the ContainsFold predicate on the "status" field.
+func StatusContainsFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldContainsFold(FieldStatus, v))
+}
+
+// AgentTypeEQ applies the EQ predicate on the "agent_type" field.
+func AgentTypeEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldAgentType, v))
+}
+
+// AgentTypeNEQ applies the NEQ predicate on the "agent_type" field.
+func AgentTypeNEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldAgentType, v))
+}
+
+// AgentTypeIn applies the In predicate on the "agent_type" field.
+func AgentTypeIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldAgentType, vs...))
+}
+
+// AgentTypeNotIn applies the NotIn predicate on the "agent_type" field.
+func AgentTypeNotIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldAgentType, vs...))
+}
+
+// AgentTypeGT applies the GT predicate on the "agent_type" field.
+func AgentTypeGT(v string) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldAgentType, v))
+}
+
+// AgentTypeGTE applies the GTE predicate on the "agent_type" field.
+func AgentTypeGTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldAgentType, v))
+}
+
+// AgentTypeLT applies the LT predicate on the "agent_type" field.
+func AgentTypeLT(v string) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldAgentType, v))
+}
+
+// AgentTypeLTE applies the LTE predicate on the "agent_type" field.
+func AgentTypeLTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldAgentType, v))
+}
+
+// AgentTypeContains applies the Contains predicate on the "agent_type" field.
+func AgentTypeContains(v string) predicate.Run {
+	return predicate.Run(sql.FieldContains(FieldAgentType, v))
+}
+
+// AgentTypeHasPrefix applies the HasPrefix predicate on the "agent_type" field.
+func AgentTypeHasPrefix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasPrefix(FieldAgentType, v))
+}
+
+// AgentTypeHasSuffix applies the HasSuffix predicate on the "agent_type" field.
+func AgentTypeHasSuffix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasSuffix(FieldAgentType, v))
+}
+
+// AgentTypeIsNil applies the IsNil predicate on the "agent_type" field.
+func AgentTypeIsNil() predicate.Run {
+	return predicate.Run(sql.FieldIsNull(FieldAgentType))
+}
+
+// AgentTypeNotNil applies the NotNil predicate on the "agent_type" field.
+func AgentTypeNotNil() predicate.Run {
+	return predicate.Run(sql.FieldNotNull(FieldAgentType))
+}
+
+// AgentTypeEqualFold applies the EqualFold predicate on the "agent_type" field.
+func AgentTypeEqualFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldEqualFold(FieldAgentType, v))
+}
+
+// AgentTypeContainsFold applies the ContainsFold predicate on the "agent_type" field.
+func AgentTypeContainsFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldContainsFold(FieldAgentType, v))
+}
+
+// LabelsIsNil applies the IsNil predicate on the "labels" field.
+func LabelsIsNil() predicate.Run {
+	return predicate.Run(sql.FieldIsNull(FieldLabels))
+}
+
+// LabelsNotNil applies the NotNil predicate on the "labels" field.
+func LabelsNotNil() predicate.Run {
+	return predicate.Run(sql.FieldNotNull(FieldLabels))
+}
+
+// ParentRunIDEQ applies the EQ predicate on the "parent_run_id" field.
+func ParentRunIDEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldParentRunID, v))
+}
+
+// ParentRunIDNEQ applies the NEQ predicate on the "parent_run_id" field.
+func ParentRunIDNEQ(v string) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldParentRunID, v))
+}
+
+// ParentRunIDIn applies the In predicate on the "parent_run_id" field.
+func ParentRunIDIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldParentRunID, vs...))
+}
+
+// ParentRunIDNotIn applies the NotIn predicate on the "parent_run_id" field.
+func ParentRunIDNotIn(vs ...string) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldParentRunID, vs...))
+}
+
+// ParentRunIDGT applies the GT predicate on the "parent_run_id" field.
+func ParentRunIDGT(v string) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldParentRunID, v))
+}
+
+// ParentRunIDGTE applies the GTE predicate on the "parent_run_id" field.
+func ParentRunIDGTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldParentRunID, v))
+}
+
+// ParentRunIDLT applies the LT predicate on the "parent_run_id" field.
+func ParentRunIDLT(v string) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldParentRunID, v))
+}
+
+// ParentRunIDLTE applies the LTE predicate on the "parent_run_id" field.
+func ParentRunIDLTE(v string) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldParentRunID, v))
+}
+
+// ParentRunIDContains applies the Contains predicate on the "parent_run_id" field.
+func ParentRunIDContains(v string) predicate.Run {
+	return predicate.Run(sql.FieldContains(FieldParentRunID, v))
+}
+
+// ParentRunIDHasPrefix applies the HasPrefix predicate on the "parent_run_id" field.
+func ParentRunIDHasPrefix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasPrefix(FieldParentRunID, v))
+}
+
+// ParentRunIDHasSuffix applies the HasSuffix predicate on the "parent_run_id" field.
+func ParentRunIDHasSuffix(v string) predicate.Run {
+	return predicate.Run(sql.FieldHasSuffix(FieldParentRunID, v))
+}
+
+// ParentRunIDIsNil applies the IsNil predicate on the "parent_run_id" field.
+func ParentRunIDIsNil() predicate.Run {
+	return predicate.Run(sql.FieldIsNull(FieldParentRunID))
+}
+
+// ParentRunIDNotNil applies the NotNil predicate on the "parent_run_id" field.
+func ParentRunIDNotNil() predicate.Run {
+	return predicate.Run(sql.FieldNotNull(FieldParentRunID))
+}
+
+// ParentRunIDEqualFold applies the EqualFold predicate on the "parent_run_id" field.
+func ParentRunIDEqualFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldEqualFold(FieldParentRunID, v))
+}
+
+// ParentRunIDContainsFold applies the ContainsFold predicate on the "parent_run_id" field.
+func ParentRunIDContainsFold(v string) predicate.Run {
+	return predicate.Run(sql.FieldContainsFold(FieldParentRunID, v))
+}
+
+// LastSeqEQ applies the EQ predicate on the "last_seq" field.
+func LastSeqEQ(v int64) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldLastSeq, v))
+}
+
+// LastSeqNEQ applies the NEQ predicate on the "last_seq" field.
+func LastSeqNEQ(v int64) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldLastSeq, v))
+}
+
+// LastSeqIn applies the In predicate on the "last_seq" field.
+func LastSeqIn(vs ...int64) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldLastSeq, vs...))
+}
+
+// LastSeqNotIn applies the NotIn predicate on the "last_seq" field.
+func LastSeqNotIn(vs ...int64) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldLastSeq, vs...))
+}
+
+// LastSeqGT applies the GT predicate on the "last_seq" field.
+func LastSeqGT(v int64) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldLastSeq, v))
+}
+
+// LastSeqGTE applies the GTE predicate on the "last_seq" field.
+func LastSeqGTE(v int64) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldLastSeq, v))
+}
+
+// LastSeqLT applies the LT predicate on the "last_seq" field.
+func LastSeqLT(v int64) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldLastSeq, v))
+}
+
+// LastSeqLTE applies the LTE predicate on the "last_seq" field.
+func LastSeqLTE(v int64) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldLastSeq, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.Run {
+	return predicate.Run(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.Run {
+	return predicate.Run(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.Run {
+	return predicate.Run(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.Run) predicate.Run {
+	return predicate.Run(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.Run) predicate.Run {
+	return predicate.Run(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.Run) predicate.Run {
+	return predicate.Run(sql.NotPredicates(p))
+}
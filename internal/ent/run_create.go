@@ -0,0 +1,351 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/run"
+)
+
+// RunCreate is the builder for creating a Run entity.
+type RunCreate struct {
+	config
+	mutation *RunMutation
+	hooks    []Hook
+}
+
+// SetRunID sets the "run_id" field.
+func (_c *RunCreate) SetRunID(v string) *RunCreate {
+	_c.mutation.SetRunID(v)
+	return _c
+}
+
+// SetStatus sets the "status" field.
+func (_c *RunCreate) SetStatus(v string) *RunCreate {
+	_c.mutation.SetStatus(v)
+	return _c
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_c *RunCreate) SetNillableStatus(v *string) *RunCreate {
+	if v != nil {
+		_c.SetStatus(*v)
+	}
+	return _c
+}
+
+// SetAgentType sets the "agent_type" field.
+func (_c *RunCreate) SetAgentType(v string) *RunCreate {
+	_c.mutation.SetAgentType(v)
+	return _c
+}
+
+// SetNillableAgentType sets the "agent_type" field if the given value is not nil.
+func (_c *RunCreate) SetNillableAgentType(v *string) *RunCreate {
+	if v != nil {
+		_c.SetAgentType(*v)
+	}
+	return _c
+}
+
+// SetLabels sets the "labels" field.
+func (_c *RunCreate) SetLabels(v map[string]string) *RunCreate {
+	_c.mutation.SetLabels(v)
+	return _c
+}
+
+// SetParentRunID sets the "parent_run_id" field.
+func (_c *RunCreate) SetParentRunID(v string) *RunCreate {
+	_c.mutation.SetParentRunID(v)
+	return _c
+}
+
+// SetNillableParentRunID sets the "parent_run_id" field if the given value is not nil.
+func (_c *RunCreate) SetNillableParentRunID(v *string) *RunCreate {
+	if v != nil {
+		_c.SetParentRunID(*v)
+	}
+	return _c
+}
+
+// SetLastSeq sets the "last_seq" field.
+func (_c *RunCreate) SetLastSeq(v int64) *RunCreate {
+	_c.mutation.SetLastSeq(v)
+	return _c
+}
+
+// SetNillableLastSeq sets the "last_seq" field if the given value is not nil.
+func (_c *RunCreate) SetNillableLastSeq(v *int64) *RunCreate {
+	if v != nil {
+		_c.SetLastSeq(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *RunCreate) SetCreatedAt(v time.Time) *RunCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *RunCreate) SetNillableCreatedAt(v *time.Time) *RunCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *RunCreate) SetUpdatedAt(v time.Time) *RunCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *RunCreate) SetNillableUpdatedAt(v *time.Time) *RunCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the RunMutation object of the builder.
+func (_c *RunCreate) Mutation() *RunMutation {
+	return _c.mutation
+}
+
+// Save creates the Run in the database.
+func (_c *RunCreate) Save(ctx context.Context) (*Run, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *RunCreate) SaveX(ctx context.Context) *Run {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *RunCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *RunCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *RunCreate) defaults() {
+	if _, ok := _c.mutation.Status(); !ok {
+		v := run.DefaultStatus
+		_c.mutation.SetStatus(v)
+	}
+	if _, ok := _c.mutation.LastSeq(); !ok {
+		v := run.DefaultLastSeq
+		_c.mutation.SetLastSeq(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := run.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := run.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *RunCreate) check() error {
+	if _, ok := _c.mutation.RunID(); !ok {
+		return &ValidationError{Name: "run_id", err: errors.New(`ent: missing required field "Run.run_id"`)}
+	}
+	if v, ok := _c.mutation.RunID(); ok {
+		if err := run.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Run.run_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "Run.status"`)}
+	}
+	if v, ok := _c.mutation.Status(); ok {
+		if err := run.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Run.status": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.LastSeq(); !ok {
+		return &ValidationError{Name: "last_seq", err: errors.New(`ent: missing required field "Run.last_seq"`)}
+	}
+	if v, ok := _c.mutation.LastSeq(); ok {
+		if err := run.LastSeqValidator(v); err != nil {
+			return &ValidationError{Name: "last_seq", err: fmt.Errorf(`ent: validator failed for field "Run.last_seq": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Run.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "Run.updated_at"`)}
+	}
+	return nil
+}
+
+func (_c *RunCreate) sqlSave(ctx context.Context) (*Run, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *RunCreate) createSpec() (*Run, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Run{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(run.Table, sqlgraph.NewFieldSpec(run.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.RunID(); ok {
+		_spec.SetField(run.FieldRunID, field.TypeString, value)
+		_node.RunID = value
+	}
+	if value, ok := _c.mutation.Status(); ok {
+		_spec.SetField(run.FieldStatus, field.TypeString, value)
+		_node.Status = value
+	}
+	if value, ok := _c.mutation.AgentType(); ok {
+		_spec.SetField(run.FieldAgentType, field.TypeString, value)
+		_node.AgentType = value
+	}
+	if value, ok := _c.mutation.Labels(); ok {
+		_spec.SetField(run.FieldLabels, field.TypeJSON, value)
+		_node.Labels = value
+	}
+	if value, ok := _c.mutation.ParentRunID(); ok {
+		_spec.SetField(run.FieldParentRunID, field.TypeString, value)
+		_node.ParentRunID = value
+	}
+	if value, ok := _c.mutation.LastSeq(); ok {
+		_spec.SetField(run.FieldLastSeq, field.TypeInt64, value)
+		_node.LastSeq = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(run.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(run.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	return _node, _spec
+}
+
+// RunCreateBulk is the builder for creating many Run entities in bulk.
+type RunCreateBulk struct {
+	config
+	err      error
+	builders []*RunCreate
+}
+
+// Save creates the Run entities in the database.
+func (_c *RunCreateBulk) Save(ctx context.Context) ([]*Run, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*Run, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*RunMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *RunCreateBulk) SaveX(ctx context.Context) []*Run {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *RunCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *RunCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
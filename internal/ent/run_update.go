@@ -0,0 +1,518 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/predicate"
+	"github.com/wilhg/orch/internal/ent/run"
+)
+
+// RunUpdate is the builder for updating Run entities.
+type RunUpdate struct {
+	config
+	hooks    []Hook
+	mutation *RunMutation
+}
+
+// Where appends a list predicates to the RunUpdate builder.
+func (_u *RunUpdate) Where(ps ...predicate.Run) *RunUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *RunUpdate) SetRunID(v string) *RunUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *RunUpdate) SetNillableRunID(v *string) *RunUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *RunUpdate) SetStatus(v string) *RunUpdate {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *RunUpdate) SetNillableStatus(v *string) *RunUpdate {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetAgentType sets the "agent_type" field.
+func (_u *RunUpdate) SetAgentType(v string) *RunUpdate {
+	_u.mutation.SetAgentType(v)
+	return _u
+}
+
+// SetNillableAgentType sets the "agent_type" field if the given value is not nil.
+func (_u *RunUpdate) SetNillableAgentType(v *string) *RunUpdate {
+	if v != nil {
+		_u.SetAgentType(*v)
+	}
+	return _u
+}
+
+// ClearAgentType clears the value of the "agent_type" field.
+func (_u *RunUpdate) ClearAgentType() *RunUpdate {
+	_u.mutation.ClearAgentType()
+	return _u
+}
+
+// SetLabels sets the "labels" field.
+func (_u *RunUpdate) SetLabels(v map[string]string) *RunUpdate {
+	_u.mutation.SetLabels(v)
+	return _u
+}
+
+// ClearLabels clears the value of the "labels" field.
+func (_u *RunUpdate) ClearLabels() *RunUpdate {
+	_u.mutation.ClearLabels()
+	return _u
+}
+
+// SetParentRunID sets the "parent_run_id" field.
+func (_u *RunUpdate) SetParentRunID(v string) *RunUpdate {
+	_u.mutation.SetParentRunID(v)
+	return _u
+}
+
+// SetNillableParentRunID sets the "parent_run_id" field if the given value is not nil.
+func (_u *RunUpdate) SetNillableParentRunID(v *string) *RunUpdate {
+	if v != nil {
+		_u.SetParentRunID(*v)
+	}
+	return _u
+}
+
+// ClearParentRunID clears the value of the "parent_run_id" field.
+func (_u *RunUpdate) ClearParentRunID() *RunUpdate {
+	_u.mutation.ClearParentRunID()
+	return _u
+}
+
+// SetLastSeq sets the "last_seq" field.
+func (_u *RunUpdate) SetLastSeq(v int64) *RunUpdate {
+	_u.mutation.ResetLastSeq()
+	_u.mutation.SetLastSeq(v)
+	return _u
+}
+
+// SetNillableLastSeq sets the "last_seq" field if the given value is not nil.
+func (_u *RunUpdate) SetNillableLastSeq(v *int64) *RunUpdate {
+	if v != nil {
+		_u.SetLastSeq(*v)
+	}
+	return _u
+}
+
+// AddLastSeq adds value to the "last_seq" field.
+func (_u *RunUpdate) AddLastSeq(v int64) *RunUpdate {
+	_u.mutation.AddLastSeq(v)
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *RunUpdate) SetUpdatedAt(v time.Time) *RunUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_u *RunUpdate) SetNillableUpdatedAt(v *time.Time) *RunUpdate {
+	if v != nil {
+		_u.SetUpdatedAt(*v)
+	}
+	return _u
+}
+
+// Mutation returns the RunMutation object of the builder.
+func (_u *RunUpdate) Mutation() *RunMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *RunUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *RunUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *RunUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *RunUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *RunUpdate) check() error {
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := run.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Run.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Status(); ok {
+		if err := run.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Run.status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.LastSeq(); ok {
+		if err := run.LastSeqValidator(v); err != nil {
+			return &ValidationError{Name: "last_seq", err: fmt.Errorf(`ent: validator failed for field "Run.last_seq": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *RunUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(run.Table, run.Columns, sqlgraph.NewFieldSpec(run.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(run.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(run.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.AgentType(); ok {
+		_spec.SetField(run.FieldAgentType, field.TypeString, value)
+	}
+	if _u.mutation.AgentTypeCleared() {
+		_spec.ClearField(run.FieldAgentType, field.TypeString)
+	}
+	if value, ok := _u.mutation.Labels(); ok {
+		_spec.SetField(run.FieldLabels, field.TypeJSON, value)
+	}
+	if _u.mutation.LabelsCleared() {
+		_spec.ClearField(run.FieldLabels, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.ParentRunID(); ok {
+		_spec.SetField(run.FieldParentRunID, field.TypeString, value)
+	}
+	if _u.mutation.ParentRunIDCleared() {
+		_spec.ClearField(run.FieldParentRunID, field.TypeString)
+	}
+	if value, ok := _u.mutation.LastSeq(); ok {
+		_spec.SetField(run.FieldLastSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedLastSeq(); ok {
+		_spec.AddField(run.FieldLastSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(run.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{run.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// RunUpdateOne is the builder for updating a single Run entity.
+type RunUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *RunMutation
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *RunUpdateOne) SetRunID(v string) *RunUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *RunUpdateOne) SetNillableRunID(v *string) *RunUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *RunUpdateOne) SetStatus(v string) *RunUpdateOne {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *RunUpdateOne) SetNillableStatus(v *string) *RunUpdateOne {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetAgentType sets the "agent_type" field.
+func (_u *RunUpdateOne) SetAgentType(v string) *RunUpdateOne {
+	_u.mutation.SetAgentType(v)
+	return _u
+}
+
+// SetNillableAgentType sets the "agent_type" field if the given value is not nil.
+func (_u *RunUpdateOne) SetNillableAgentType(v *string) *RunUpdateOne {
+	if v != nil {
+		_u.SetAgentType(*v)
+	}
+	return _u
+}
+
+// ClearAgentType clears the value of the "agent_type" field.
+func (_u *RunUpdateOne) ClearAgentType() *RunUpdateOne {
+	_u.mutation.ClearAgentType()
+	return _u
+}
+
+// SetLabels sets the "labels" field.
+func (_u *RunUpdateOne) SetLabels(v map[string]string) *RunUpdateOne {
+	_u.mutation.SetLabels(v)
+	return _u
+}
+
+// ClearLabels clears the value of the "labels" field.
+func (_u *RunUpdateOne) ClearLabels() *RunUpdateOne {
+	_u.mutation.ClearLabels()
+	return _u
+}
+
+// SetParentRunID sets the "parent_run_id" field.
+func (_u *RunUpdateOne) SetParentRunID(v string) *RunUpdateOne {
+	_u.mutation.SetParentRunID(v)
+	return _u
+}
+
+// SetNillableParentRunID sets the "parent_run_id" field if the given value is not nil.
+func (_u *RunUpdateOne) SetNillableParentRunID(v *string) *RunUpdateOne {
+	if v != nil {
+		_u.SetParentRunID(*v)
+	}
+	return _u
+}
+
+// ClearParentRunID clears the value of the "parent_run_id" field.
+func (_u *RunUpdateOne) ClearParentRunID() *RunUpdateOne {
+	_u.mutation.ClearParentRunID()
+	return _u
+}
+
+// SetLastSeq sets the "last_seq" field.
+func (_u *RunUpdateOne) SetLastSeq(v int64) *RunUpdateOne {
+	_u.mutation.ResetLastSeq()
+	_u.mutation.SetLastSeq(v)
+	return _u
+}
+
+// SetNillableLastSeq sets the "last_seq" field if the given value is not nil.
+func (_u *RunUpdateOne) SetNillableLastSeq(v *int64) *RunUpdateOne {
+	if v != nil {
+		_u.SetLastSeq(*v)
+	}
+	return _u
+}
+
+// AddLastSeq adds value to the "last_seq" field.
+func (_u *RunUpdateOne) AddLastSeq(v int64) *RunUpdateOne {
+	_u.mutation.AddLastSeq(v)
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *RunUpdateOne) SetUpdatedAt(v time.Time) *RunUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_u *RunUpdateOne) SetNillableUpdatedAt(v *time.Time) *RunUpdateOne {
+	if v != nil {
+		_u.SetUpdatedAt(*v)
+	}
+	return _u
+}
+
+// Mutation returns the RunMutation object of the builder.
+func (_u *RunUpdateOne) Mutation() *RunMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the RunUpdate builder.
+func (_u *RunUpdateOne) Where(ps ...predicate.Run) *RunUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *RunUpdateOne) Select(field string, fields ...string) *RunUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated Run entity.
+func (_u *RunUpdateOne) Save(ctx context.Context) (*Run, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *RunUpdateOne) SaveX(ctx context.Context) *Run {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *RunUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *RunUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *RunUpdateOne) check() error {
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := run.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Run.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Status(); ok {
+		if err := run.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "Run.status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.LastSeq(); ok {
+		if err := run.LastSeqValidator(v); err != nil {
+			return &ValidationError{Name: "last_seq", err: fmt.Errorf(`ent: validator failed for field "Run.last_seq": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *RunUpdateOne) sqlSave(ctx context.Context) (_node *Run, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(run.Table, run.Columns, sqlgraph.NewFieldSpec(run.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Run.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, run.FieldID)
+		for _, f := range fields {
+			if !run.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != run.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(run.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(run.FieldStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.AgentType(); ok {
+		_spec.SetField(run.FieldAgentType, field.TypeString, value)
+	}
+	if _u.mutation.AgentTypeCleared() {
+		_spec.ClearField(run.FieldAgentType, field.TypeString)
+	}
+	if value, ok := _u.mutation.Labels(); ok {
+		_spec.SetField(run.FieldLabels, field.TypeJSON, value)
+	}
+	if _u.mutation.LabelsCleared() {
+		_spec.ClearField(run.FieldLabels, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.ParentRunID(); ok {
+		_spec.SetField(run.FieldParentRunID, field.TypeString, value)
+	}
+	if _u.mutation.ParentRunIDCleared() {
+		_spec.ClearField(run.FieldParentRunID, field.TypeString)
+	}
+	if value, ok := _u.mutation.LastSeq(); ok {
+		_spec.SetField(run.FieldLastSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedLastSeq(); ok {
+		_spec.AddField(run.FieldLastSeq, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(run.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_node = &Run{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{run.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -5,15 +5,107 @@ package ent
 import (
 	"time"
 
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/auditentry"
+	"github.com/wilhg/orch/internal/ent/checkpoint"
+	"github.com/wilhg/orch/internal/ent/deadletter"
 	"github.com/wilhg/orch/internal/ent/event"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+	"github.com/wilhg/orch/internal/ent/run"
 	"github.com/wilhg/orch/internal/ent/schema"
+	"github.com/wilhg/orch/internal/ent/shredkey"
 	"github.com/wilhg/orch/internal/ent/snapshot"
+	"github.com/wilhg/orch/internal/ent/timer"
 )
 
 // The init function reads all schema descriptors with runtime code
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	archivedeventFields := schema.ArchivedEvent{}.Fields()
+	_ = archivedeventFields
+	// archivedeventDescEventID is the schema descriptor for event_id field.
+	archivedeventDescEventID := archivedeventFields[1].Descriptor()
+	// archivedevent.EventIDValidator is a validator for the "event_id" field. It is called by the builders before save.
+	archivedevent.EventIDValidator = archivedeventDescEventID.Validators[0].(func(string) error)
+	// archivedeventDescRunID is the schema descriptor for run_id field.
+	archivedeventDescRunID := archivedeventFields[2].Descriptor()
+	// archivedevent.RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	archivedevent.RunIDValidator = archivedeventDescRunID.Validators[0].(func(string) error)
+	// archivedeventDescSeq is the schema descriptor for seq field.
+	archivedeventDescSeq := archivedeventFields[3].Descriptor()
+	// archivedevent.SeqValidator is a validator for the "seq" field. It is called by the builders before save.
+	archivedevent.SeqValidator = archivedeventDescSeq.Validators[0].(func(int64) error)
+	// archivedeventDescType is the schema descriptor for type field.
+	archivedeventDescType := archivedeventFields[4].Descriptor()
+	// archivedevent.TypeValidator is a validator for the "type" field. It is called by the builders before save.
+	archivedevent.TypeValidator = archivedeventDescType.Validators[0].(func(string) error)
+	// archivedeventDescVersion is the schema descriptor for version field.
+	archivedeventDescVersion := archivedeventFields[5].Descriptor()
+	// archivedevent.DefaultVersion holds the default value on creation for the version field.
+	archivedevent.DefaultVersion = archivedeventDescVersion.Default.(int)
+	// archivedevent.VersionValidator is a validator for the "version" field. It is called by the builders before save.
+	archivedevent.VersionValidator = archivedeventDescVersion.Validators[0].(func(int) error)
+	// archivedeventDescKeyID is the schema descriptor for key_id field.
+	archivedeventDescKeyID := archivedeventFields[7].Descriptor()
+	// archivedevent.DefaultKeyID holds the default value on creation for the key_id field.
+	archivedevent.DefaultKeyID = archivedeventDescKeyID.Default.(string)
+	// archivedeventDescArchivedAt is the schema descriptor for archived_at field.
+	archivedeventDescArchivedAt := archivedeventFields[9].Descriptor()
+	// archivedevent.DefaultArchivedAt holds the default value on creation for the archived_at field.
+	archivedevent.DefaultArchivedAt = archivedeventDescArchivedAt.Default.(func() time.Time)
+	auditentryFields := schema.AuditEntry{}.Fields()
+	_ = auditentryFields
+	// auditentryDescAuditID is the schema descriptor for audit_id field.
+	auditentryDescAuditID := auditentryFields[0].Descriptor()
+	// auditentry.AuditIDValidator is a validator for the "audit_id" field. It is called by the builders before save.
+	auditentry.AuditIDValidator = auditentryDescAuditID.Validators[0].(func(string) error)
+	// auditentryDescAction is the schema descriptor for action field.
+	auditentryDescAction := auditentryFields[1].Descriptor()
+	// auditentry.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	auditentry.ActionValidator = auditentryDescAction.Validators[0].(func(string) error)
+	// auditentryDescCreatedAt is the schema descriptor for created_at field.
+	auditentryDescCreatedAt := auditentryFields[5].Descriptor()
+	// auditentry.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditentry.DefaultCreatedAt = auditentryDescCreatedAt.Default.(func() time.Time)
+	checkpointFields := schema.Checkpoint{}.Fields()
+	_ = checkpointFields
+	// checkpointDescName is the schema descriptor for name field.
+	checkpointDescName := checkpointFields[0].Descriptor()
+	// checkpoint.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	checkpoint.NameValidator = checkpointDescName.Validators[0].(func(string) error)
+	// checkpointDescPosition is the schema descriptor for position field.
+	checkpointDescPosition := checkpointFields[1].Descriptor()
+	// checkpoint.DefaultPosition holds the default value on creation for the position field.
+	checkpoint.DefaultPosition = checkpointDescPosition.Default.(int64)
+	// checkpoint.PositionValidator is a validator for the "position" field. It is called by the builders before save.
+	checkpoint.PositionValidator = checkpointDescPosition.Validators[0].(func(int64) error)
+	// checkpointDescUpdatedAt is the schema descriptor for updated_at field.
+	checkpointDescUpdatedAt := checkpointFields[2].Descriptor()
+	// checkpoint.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	checkpoint.DefaultUpdatedAt = checkpointDescUpdatedAt.Default.(func() time.Time)
+	deadletterFields := schema.DeadLetter{}.Fields()
+	_ = deadletterFields
+	// deadletterDescDeadLetterID is the schema descriptor for dead_letter_id field.
+	deadletterDescDeadLetterID := deadletterFields[0].Descriptor()
+	// deadletter.DeadLetterIDValidator is a validator for the "dead_letter_id" field. It is called by the builders before save.
+	deadletter.DeadLetterIDValidator = deadletterDescDeadLetterID.Validators[0].(func(string) error)
+	// deadletterDescRunID is the schema descriptor for run_id field.
+	deadletterDescRunID := deadletterFields[1].Descriptor()
+	// deadletter.RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	deadletter.RunIDValidator = deadletterDescRunID.Validators[0].(func(string) error)
+	// deadletterDescIntentName is the schema descriptor for intent_name field.
+	deadletterDescIntentName := deadletterFields[2].Descriptor()
+	// deadletter.IntentNameValidator is a validator for the "intent_name" field. It is called by the builders before save.
+	deadletter.IntentNameValidator = deadletterDescIntentName.Validators[0].(func(string) error)
+	// deadletterDescAttempts is the schema descriptor for attempts field.
+	deadletterDescAttempts := deadletterFields[5].Descriptor()
+	// deadletter.AttemptsValidator is a validator for the "attempts" field. It is called by the builders before save.
+	deadletter.AttemptsValidator = deadletterDescAttempts.Validators[0].(func(int) error)
+	// deadletterDescCreatedAt is the schema descriptor for created_at field.
+	deadletterDescCreatedAt := deadletterFields[7].Descriptor()
+	// deadletter.DefaultCreatedAt holds the default value on creation for the created_at field.
+	deadletter.DefaultCreatedAt = deadletterDescCreatedAt.Default.(func() time.Time)
 	eventFields := schema.Event{}.Fields()
 	_ = eventFields
 	// eventDescEventID is the schema descriptor for event_id field.
@@ -32,10 +124,94 @@ func init() {
 	eventDescType := eventFields[3].Descriptor()
 	// event.TypeValidator is a validator for the "type" field. It is called by the builders before save.
 	event.TypeValidator = eventDescType.Validators[0].(func(string) error)
+	// eventDescVersion is the schema descriptor for version field.
+	eventDescVersion := eventFields[4].Descriptor()
+	// event.DefaultVersion holds the default value on creation for the version field.
+	event.DefaultVersion = eventDescVersion.Default.(int)
+	// event.VersionValidator is a validator for the "version" field. It is called by the builders before save.
+	event.VersionValidator = eventDescVersion.Validators[0].(func(int) error)
+	// eventDescKeyID is the schema descriptor for key_id field.
+	eventDescKeyID := eventFields[6].Descriptor()
+	// event.DefaultKeyID holds the default value on creation for the key_id field.
+	event.DefaultKeyID = eventDescKeyID.Default.(string)
 	// eventDescCreatedAt is the schema descriptor for created_at field.
-	eventDescCreatedAt := eventFields[5].Descriptor()
+	eventDescCreatedAt := eventFields[7].Descriptor()
 	// event.DefaultCreatedAt holds the default value on creation for the created_at field.
 	event.DefaultCreatedAt = eventDescCreatedAt.Default.(func() time.Time)
+	outboxitemFields := schema.OutboxItem{}.Fields()
+	_ = outboxitemFields
+	// outboxitemDescItemID is the schema descriptor for item_id field.
+	outboxitemDescItemID := outboxitemFields[0].Descriptor()
+	// outboxitem.ItemIDValidator is a validator for the "item_id" field. It is called by the builders before save.
+	outboxitem.ItemIDValidator = outboxitemDescItemID.Validators[0].(func(string) error)
+	// outboxitemDescQueue is the schema descriptor for queue field.
+	outboxitemDescQueue := outboxitemFields[1].Descriptor()
+	// outboxitem.QueueValidator is a validator for the "queue" field. It is called by the builders before save.
+	outboxitem.QueueValidator = outboxitemDescQueue.Validators[0].(func(string) error)
+	// outboxitemDescRunID is the schema descriptor for run_id field.
+	outboxitemDescRunID := outboxitemFields[2].Descriptor()
+	// outboxitem.RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	outboxitem.RunIDValidator = outboxitemDescRunID.Validators[0].(func(string) error)
+	// outboxitemDescIntentName is the schema descriptor for intent_name field.
+	outboxitemDescIntentName := outboxitemFields[3].Descriptor()
+	// outboxitem.IntentNameValidator is a validator for the "intent_name" field. It is called by the builders before save.
+	outboxitem.IntentNameValidator = outboxitemDescIntentName.Validators[0].(func(string) error)
+	// outboxitemDescDepth is the schema descriptor for depth field.
+	outboxitemDescDepth := outboxitemFields[6].Descriptor()
+	// outboxitem.DefaultDepth holds the default value on creation for the depth field.
+	outboxitem.DefaultDepth = outboxitemDescDepth.Default.(int)
+	// outboxitem.DepthValidator is a validator for the "depth" field. It is called by the builders before save.
+	outboxitem.DepthValidator = outboxitemDescDepth.Validators[0].(func(int) error)
+	// outboxitemDescClaims is the schema descriptor for claims field.
+	outboxitemDescClaims := outboxitemFields[7].Descriptor()
+	// outboxitem.DefaultClaims holds the default value on creation for the claims field.
+	outboxitem.DefaultClaims = outboxitemDescClaims.Default.(int)
+	// outboxitem.ClaimsValidator is a validator for the "claims" field. It is called by the builders before save.
+	outboxitem.ClaimsValidator = outboxitemDescClaims.Validators[0].(func(int) error)
+	// outboxitemDescLeaseExpiresAt is the schema descriptor for lease_expires_at field.
+	outboxitemDescLeaseExpiresAt := outboxitemFields[9].Descriptor()
+	// outboxitem.DefaultLeaseExpiresAt holds the default value on creation for the lease_expires_at field.
+	outboxitem.DefaultLeaseExpiresAt = outboxitemDescLeaseExpiresAt.Default.(int64)
+	// outboxitemDescCreatedAt is the schema descriptor for created_at field.
+	outboxitemDescCreatedAt := outboxitemFields[11].Descriptor()
+	// outboxitem.DefaultCreatedAt holds the default value on creation for the created_at field.
+	outboxitem.DefaultCreatedAt = outboxitemDescCreatedAt.Default.(func() time.Time)
+	runFields := schema.Run{}.Fields()
+	_ = runFields
+	// runDescRunID is the schema descriptor for run_id field.
+	runDescRunID := runFields[0].Descriptor()
+	// run.RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	run.RunIDValidator = runDescRunID.Validators[0].(func(string) error)
+	// runDescStatus is the schema descriptor for status field.
+	runDescStatus := runFields[1].Descriptor()
+	// run.DefaultStatus holds the default value on creation for the status field.
+	run.DefaultStatus = runDescStatus.Default.(string)
+	// run.StatusValidator is a validator for the "status" field. It is called by the builders before save.
+	run.StatusValidator = runDescStatus.Validators[0].(func(string) error)
+	// runDescLastSeq is the schema descriptor for last_seq field.
+	runDescLastSeq := runFields[5].Descriptor()
+	// run.DefaultLastSeq holds the default value on creation for the last_seq field.
+	run.DefaultLastSeq = runDescLastSeq.Default.(int64)
+	// run.LastSeqValidator is a validator for the "last_seq" field. It is called by the builders before save.
+	run.LastSeqValidator = runDescLastSeq.Validators[0].(func(int64) error)
+	// runDescCreatedAt is the schema descriptor for created_at field.
+	runDescCreatedAt := runFields[6].Descriptor()
+	// run.DefaultCreatedAt holds the default value on creation for the created_at field.
+	run.DefaultCreatedAt = runDescCreatedAt.Default.(func() time.Time)
+	// runDescUpdatedAt is the schema descriptor for updated_at field.
+	runDescUpdatedAt := runFields[7].Descriptor()
+	// run.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	run.DefaultUpdatedAt = runDescUpdatedAt.Default.(func() time.Time)
+	shredkeyFields := schema.ShredKey{}.Fields()
+	_ = shredkeyFields
+	// shredkeyDescRunID is the schema descriptor for run_id field.
+	shredkeyDescRunID := shredkeyFields[0].Descriptor()
+	// shredkey.RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	shredkey.RunIDValidator = shredkeyDescRunID.Validators[0].(func(string) error)
+	// shredkeyDescCreatedAt is the schema descriptor for created_at field.
+	shredkeyDescCreatedAt := shredkeyFields[2].Descriptor()
+	// shredkey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	shredkey.DefaultCreatedAt = shredkeyDescCreatedAt.Default.(func() time.Time)
 	snapshotFields := schema.Snapshot{}.Fields()
 	_ = snapshotFields
 	// snapshotDescSnapshotID is the schema descriptor for snapshot_id field.
@@ -50,8 +226,48 @@ func init() {
 	snapshotDescUptoSeq := snapshotFields[2].Descriptor()
 	// snapshot.UptoSeqValidator is a validator for the "upto_seq" field. It is called by the builders before save.
 	snapshot.UptoSeqValidator = snapshotDescUptoSeq.Validators[0].(func(int64) error)
+	// snapshotDescKeyID is the schema descriptor for key_id field.
+	snapshotDescKeyID := snapshotFields[4].Descriptor()
+	// snapshot.DefaultKeyID holds the default value on creation for the key_id field.
+	snapshot.DefaultKeyID = snapshotDescKeyID.Default.(string)
+	// snapshotDescVersion is the schema descriptor for version field.
+	snapshotDescVersion := snapshotFields[5].Descriptor()
+	// snapshot.DefaultVersion holds the default value on creation for the version field.
+	snapshot.DefaultVersion = snapshotDescVersion.Default.(string)
+	// snapshotDescHash is the schema descriptor for hash field.
+	snapshotDescHash := snapshotFields[6].Descriptor()
+	// snapshot.DefaultHash holds the default value on creation for the hash field.
+	snapshot.DefaultHash = snapshotDescHash.Default.(string)
 	// snapshotDescCreatedAt is the schema descriptor for created_at field.
-	snapshotDescCreatedAt := snapshotFields[4].Descriptor()
+	snapshotDescCreatedAt := snapshotFields[7].Descriptor()
 	// snapshot.DefaultCreatedAt holds the default value on creation for the created_at field.
 	snapshot.DefaultCreatedAt = snapshotDescCreatedAt.Default.(func() time.Time)
+	timerFields := schema.Timer{}.Fields()
+	_ = timerFields
+	// timerDescTimerID is the schema descriptor for timer_id field.
+	timerDescTimerID := timerFields[0].Descriptor()
+	// timer.TimerIDValidator is a validator for the "timer_id" field. It is called by the builders before save.
+	timer.TimerIDValidator = timerDescTimerID.Validators[0].(func(string) error)
+	// timerDescQueue is the schema descriptor for queue field.
+	timerDescQueue := timerFields[1].Descriptor()
+	// timer.QueueValidator is a validator for the "queue" field. It is called by the builders before save.
+	timer.QueueValidator = timerDescQueue.Validators[0].(func(string) error)
+	// timerDescRunID is the schema descriptor for run_id field.
+	timerDescRunID := timerFields[2].Descriptor()
+	// timer.RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	timer.RunIDValidator = timerDescRunID.Validators[0].(func(string) error)
+	// timerDescAttempts is the schema descriptor for attempts field.
+	timerDescAttempts := timerFields[6].Descriptor()
+	// timer.DefaultAttempts holds the default value on creation for the attempts field.
+	timer.DefaultAttempts = timerDescAttempts.Default.(int)
+	// timer.AttemptsValidator is a validator for the "attempts" field. It is called by the builders before save.
+	timer.AttemptsValidator = timerDescAttempts.Validators[0].(func(int) error)
+	// timerDescAvailableAt is the schema descriptor for available_at field.
+	timerDescAvailableAt := timerFields[7].Descriptor()
+	// timer.DefaultAvailableAt holds the default value on creation for the available_at field.
+	timer.DefaultAvailableAt = timerDescAvailableAt.Default.(int64)
+	// timerDescCreatedAt is the schema descriptor for created_at field.
+	timerDescCreatedAt := timerFields[8].Descriptor()
+	// timer.DefaultCreatedAt holds the default value on creation for the created_at field.
+	timer.DefaultCreatedAt = timerDescCreatedAt.Default.(func() time.Time)
 }
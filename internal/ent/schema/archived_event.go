@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// ArchivedEvent holds events moved out of the live log once covered by a snapshot.
+type ArchivedEvent struct{ ent.Schema }
+
+func (ArchivedEvent) Fields() []ent.Field {
+	return []ent.Field{
+		// Global position the event had in the live log.
+		field.Int64("position").Unique(),
+		field.String("event_id").NotEmpty().Unique(),
+		field.String("run_id").NotEmpty(),
+		field.Int64("seq").NonNegative(),
+		field.String("type").NotEmpty(),
+		field.Int("version").Positive().Default(1),
+		field.Text("payload").
+			Optional().
+			SchemaType(map[string]string{dialect.Postgres: "json"}),
+		// ID of the master key the payload is encrypted under; empty for plain text.
+		field.String("key_id").Default(""),
+		field.Time("created_at").Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+		field.Time("archived_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
+
+func (ArchivedEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("run_id", "seq").Unique(),
+	}
+}
@@ -0,0 +1,33 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuditEntry records a retention or erasure operation. Entries outlive the runs they describe.
+type AuditEntry struct{ ent.Schema }
+
+func (AuditEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("audit_id").NotEmpty().Unique(),
+		field.String("action").NotEmpty(),
+		field.String("run_id").Optional(),
+		field.String("actor").Optional(),
+		field.JSON("details", map[string]any{}).Optional(),
+		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
+
+func (AuditEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("run_id"),
+	}
+}
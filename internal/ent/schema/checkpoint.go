@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+)
+
+// Checkpoint records how far a projector has read the global event feed.
+type Checkpoint struct{ ent.Schema }
+
+func (Checkpoint) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").NotEmpty().Unique(),
+		// Position of the last event projected.
+		field.Int64("position").NonNegative().Default(0),
+		field.Time("updated_at").Default(time.Now).SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// DeadLetter holds intents that exhausted their retry policy.
+type DeadLetter struct{ ent.Schema }
+
+func (DeadLetter) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("dead_letter_id").NotEmpty().Unique(),
+		field.String("run_id").NotEmpty(),
+		field.String("intent_name").NotEmpty(),
+		field.String("idempotency_key").Optional(),
+		field.JSON("args", map[string]any{}).Optional(),
+		field.Int("attempts").NonNegative(),
+		// Compact error of the last attempt.
+		field.JSON("error", errmodel.Error{}),
+		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
+
+func (DeadLetter) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("run_id"),
+	}
+}
@@ -21,9 +21,15 @@ func (Event) Fields() []ent.Field {
 		// Monotonic sequence per run.
 		field.Int64("seq").NonNegative(),
 		field.String("type").NotEmpty(),
-		// JSON payload; compatible with Postgres (JSONB) and SQLite (TEXT/BLOB).
-		field.JSON("payload", map[string]any{}).
-			Optional(),
+		// Version of the payload's schema; rows written before versioning read as 1.
+		field.Int("version").Positive().Default(1),
+		// JSON payload of any kind, kept byte-for-byte: Postgres JSON (not JSONB, which
+		// reorders keys) and SQLite TEXT.
+		field.Text("payload").
+			Optional().
+			SchemaType(map[string]string{dialect.Postgres: "json"}),
+		// ID of the master key the payload is encrypted under; empty for plain text.
+		field.String("key_id").Default(""),
 		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
 			dialect.Postgres: "TIMESTAMPTZ",
 			dialect.SQLite:   "DATETIME",
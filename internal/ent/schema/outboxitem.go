@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OutboxItem holds an intent waiting to be executed by a worker.
+type OutboxItem struct{ ent.Schema }
+
+func (OutboxItem) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("item_id").NotEmpty().Unique(),
+		// Queue served by the runner able to execute the intent.
+		field.String("queue").NotEmpty(),
+		field.String("run_id").NotEmpty(),
+		field.String("intent_name").NotEmpty(),
+		field.String("idempotency_key").Optional(),
+		field.JSON("args", map[string]any{}).Optional(),
+		// Position in the chain of intents started by an incoming event, starting at 1.
+		field.Int("depth").NonNegative().Default(0),
+		// Number of times the item was claimed.
+		field.Int("claims").NonNegative().Default(0),
+		field.String("lease_owner").Optional(),
+		// Lease and availability times are Unix milliseconds so that they compare correctly on
+		// every dialect; SQLite stores DATETIME as text.
+		field.Int64("lease_expires_at").Default(0),
+		field.Int64("available_at"),
+		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
+
+func (OutboxItem) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("queue", "available_at"),
+		index.Fields("run_id"),
+	}
+}
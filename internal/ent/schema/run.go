@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Run is the catalog entry of a run, maintained alongside its event log.
+type Run struct{ ent.Schema }
+
+func (Run) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("run_id").NotEmpty().Unique(),
+		field.String("status").NotEmpty().Default("active"),
+		field.String("agent_type").Optional(),
+		field.JSON("labels", map[string]string{}).Optional(),
+		field.String("parent_run_id").Optional(),
+		field.Int64("last_seq").NonNegative().Default(0),
+		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+		field.Time("updated_at").Default(time.Now).SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
+
+func (Run) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status"),
+		index.Fields("agent_type"),
+		index.Fields("parent_run_id"),
+	}
+}
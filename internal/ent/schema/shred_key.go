@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+)
+
+// ShredKey holds the key encrypting the sensitive payload fields of a run. Shredding clears the
+// key and keeps the row, so the run cannot be given a new key.
+type ShredKey struct{ ent.Schema }
+
+func (ShredKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("run_id").NotEmpty().Unique(),
+		field.Bytes("key").Optional().Nillable(),
+		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+		field.Time("shredded_at").Optional().Nillable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
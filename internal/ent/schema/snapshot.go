@@ -17,7 +17,16 @@ func (Snapshot) Fields() []ent.Field {
 		field.String("snapshot_id").NotEmpty().Unique(),
 		field.String("run_id").NotEmpty(),
 		field.Int64("upto_seq").NonNegative(),
-		field.JSON("state", map[string]any{}).Optional(),
+		// Encoded state, kept byte-for-byte like event payloads.
+		field.Text("state").
+			Optional().
+			SchemaType(map[string]string{dialect.Postgres: "json"}),
+		// ID of the master key the state is encrypted under; empty for plain text.
+		field.String("key_id").Default(""),
+		// Reducer/codec version the state was produced with; replays ignore other versions.
+		field.String("version").Default(""),
+		// Digest of state, checked before the snapshot is used.
+		field.String("hash").Default(""),
 		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
 			dialect.Postgres: "TIMESTAMPTZ",
 			dialect.SQLite:   "DATETIME",
@@ -29,5 +38,6 @@ func (Snapshot) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("run_id"),
 		index.Fields("run_id", "upto_seq").Unique(),
+		index.Fields("version"),
 	}
 }
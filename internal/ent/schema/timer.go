@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Timer holds a scheduled delivery of a timer_fired event to a run.
+type Timer struct{ ent.Schema }
+
+func (Timer) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("timer_id").NotEmpty().Unique(),
+		// Queue served by the runner that delivers the event.
+		field.String("queue").NotEmpty(),
+		field.String("run_id").NotEmpty(),
+		field.String("name").Optional(),
+		field.JSON("payload", map[string]any{}).Optional(),
+		// Unix milliseconds, like the outbox times.
+		field.Int64("due_at"),
+		// Number of failed deliveries.
+		field.Int("attempts").NonNegative().Default(0),
+		// After a failed delivery, the earliest time of the next one, in Unix milliseconds.
+		field.Int64("available_at").Default(0),
+		field.Time("created_at").Default(time.Now).Immutable().SchemaType(map[string]string{
+			dialect.Postgres: "TIMESTAMPTZ",
+			dialect.SQLite:   "DATETIME",
+		}),
+	}
+}
+
+func (Timer) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("queue", "due_at"),
+		index.Fields("run_id"),
+	}
+}
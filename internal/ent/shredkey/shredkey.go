@@ -0,0 +1,75 @@
+// Code generated by ent, DO NOT EDIT.
+
+package shredkey
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the shredkey type in the database.
+	Label = "shred_key"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldRunID holds the string denoting the run_id field in the database.
+	FieldRunID = "run_id"
+	// FieldKey holds the string denoting the key field in the database.
+	FieldKey = "key"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// FieldShreddedAt holds the string denoting the shredded_at field in the database.
+	FieldShreddedAt = "shredded_at"
+	// Table holds the table name of the shredkey in the database.
+	Table = "shred_keys"
+)
+
+// Columns holds all SQL columns for shredkey fields.
+var Columns = []string{
+	FieldID,
+	FieldRunID,
+	FieldKey,
+	FieldCreatedAt,
+	FieldShreddedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	RunIDValidator func(string) error
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// OrderOption defines the ordering options for the ShredKey queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByRunID orders the results by the run_id field.
+func ByRunID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRunID, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
+
+// ByShreddedAt orders the results by the shredded_at field.
+func ByShreddedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldShreddedAt, opts...).ToFunc()
+}
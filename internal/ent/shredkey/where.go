@@ -0,0 +1,295 @@
+// Code generated by ent, DO NOT EDIT.
+
+package shredkey
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLTE(FieldID, id))
+}
+
+// RunID applies equality check predicate on the "run_id" field. It's identical to RunIDEQ.
+func RunID(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldRunID, v))
+}
+
+// Key applies equality check predicate on the "key" field. It's identical to KeyEQ.
+func Key(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldKey, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// ShreddedAt applies equality check predicate on the "shredded_at" field. It's identical to ShreddedAtEQ.
+func ShreddedAt(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldShreddedAt, v))
+}
+
+// RunIDEQ applies the EQ predicate on the "run_id" field.
+func RunIDEQ(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldRunID, v))
+}
+
+// RunIDNEQ applies the NEQ predicate on the "run_id" field.
+func RunIDNEQ(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNEQ(FieldRunID, v))
+}
+
+// RunIDIn applies the In predicate on the "run_id" field.
+func RunIDIn(vs ...string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIn(FieldRunID, vs...))
+}
+
+// RunIDNotIn applies the NotIn predicate on the "run_id" field.
+func RunIDNotIn(vs ...string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotIn(FieldRunID, vs...))
+}
+
+// RunIDGT applies the GT predicate on the "run_id" field.
+func RunIDGT(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGT(FieldRunID, v))
+}
+
+// RunIDGTE applies the GTE predicate on the "run_id" field.
+func RunIDGTE(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGTE(FieldRunID, v))
+}
+
+// RunIDLT applies the LT predicate on the "run_id" field.
+func RunIDLT(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLT(FieldRunID, v))
+}
+
+// RunIDLTE applies the LTE predicate on the "run_id" field.
+func RunIDLTE(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLTE(FieldRunID, v))
+}
+
+// RunIDContains applies the Contains predicate on the "run_id" field.
+func RunIDContains(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldContains(FieldRunID, v))
+}
+
+// RunIDHasPrefix applies the HasPrefix predicate on the "run_id" field.
+func RunIDHasPrefix(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldHasPrefix(FieldRunID, v))
+}
+
+// RunIDHasSuffix applies the HasSuffix predicate on the "run_id" field.
+func RunIDHasSuffix(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldHasSuffix(FieldRunID, v))
+}
+
+// RunIDEqualFold applies the EqualFold predicate on the "run_id" field.
+func RunIDEqualFold(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEqualFold(FieldRunID, v))
+}
+
+// RunIDContainsFold applies the ContainsFold predicate on the "run_id" field.
+func RunIDContainsFold(v string) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldContainsFold(FieldRunID, v))
+}
+
+// KeyEQ applies the EQ predicate on the "key" field.
+func KeyEQ(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldKey, v))
+}
+
+// KeyNEQ applies the NEQ predicate on the "key" field.
+func KeyNEQ(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNEQ(FieldKey, v))
+}
+
+// KeyIn applies the In predicate on the "key" field.
+func KeyIn(vs ...[]byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIn(FieldKey, vs...))
+}
+
+// KeyNotIn applies the NotIn predicate on the "key" field.
+func KeyNotIn(vs ...[]byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotIn(FieldKey, vs...))
+}
+
+// KeyGT applies the GT predicate on the "key" field.
+func KeyGT(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGT(FieldKey, v))
+}
+
+// KeyGTE applies the GTE predicate on the "key" field.
+func KeyGTE(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGTE(FieldKey, v))
+}
+
+// KeyLT applies the LT predicate on the "key" field.
+func KeyLT(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLT(FieldKey, v))
+}
+
+// KeyLTE applies the LTE predicate on the "key" field.
+func KeyLTE(v []byte) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLTE(FieldKey, v))
+}
+
+// KeyIsNil applies the IsNil predicate on the "key" field.
+func KeyIsNil() predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIsNull(FieldKey))
+}
+
+// KeyNotNil applies the NotNil predicate on the "key" field.
+func KeyNotNil() predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotNull(FieldKey))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// ShreddedAtEQ applies the EQ predicate on the "shredded_at" field.
+func ShreddedAtEQ(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldEQ(FieldShreddedAt, v))
+}
+
+// ShreddedAtNEQ applies the NEQ predicate on the "shredded_at" field.
+func ShreddedAtNEQ(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNEQ(FieldShreddedAt, v))
+}
+
+// ShreddedAtIn applies the In predicate on the "shredded_at" field.
+func ShreddedAtIn(vs ...time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIn(FieldShreddedAt, vs...))
+}
+
+// ShreddedAtNotIn applies the NotIn predicate on the "shredded_at" field.
+func ShreddedAtNotIn(vs ...time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotIn(FieldShreddedAt, vs...))
+}
+
+// ShreddedAtGT applies the GT predicate on the "shredded_at" field.
+func ShreddedAtGT(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGT(FieldShreddedAt, v))
+}
+
+// ShreddedAtGTE applies the GTE predicate on the "shredded_at" field.
+func ShreddedAtGTE(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldGTE(FieldShreddedAt, v))
+}
+
+// ShreddedAtLT applies the LT predicate on the "shredded_at" field.
+func ShreddedAtLT(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLT(FieldShreddedAt, v))
+}
+
+// ShreddedAtLTE applies the LTE predicate on the "shredded_at" field.
+func ShreddedAtLTE(v time.Time) predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldLTE(FieldShreddedAt, v))
+}
+
+// ShreddedAtIsNil applies the IsNil predicate on the "shredded_at" field.
+func ShreddedAtIsNil() predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldIsNull(FieldShreddedAt))
+}
+
+// ShreddedAtNotNil applies the NotNil predicate on the "shredded_at" field.
+func ShreddedAtNotNil() predicate.ShredKey {
+	return predicate.ShredKey(sql.FieldNotNull(FieldShreddedAt))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.ShredKey) predicate.ShredKey {
+	return predicate.ShredKey(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.ShredKey) predicate.ShredKey {
+	return predicate.ShredKey(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.ShredKey) predicate.ShredKey {
+	return predicate.ShredKey(sql.NotPredicates(p))
+}
@@ -0,0 +1,248 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/shredkey"
+)
+
+// ShredKeyCreate is the builder for creating a ShredKey entity.
+type ShredKeyCreate struct {
+	config
+	mutation *ShredKeyMutation
+	hooks    []Hook
+}
+
+// SetRunID sets the "run_id" field.
+func (_c *ShredKeyCreate) SetRunID(v string) *ShredKeyCreate {
+	_c.mutation.SetRunID(v)
+	return _c
+}
+
+// SetKey sets the "key" field.
+func (_c *ShredKeyCreate) SetKey(v []byte) *ShredKeyCreate {
+	_c.mutation.SetKey(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *ShredKeyCreate) SetCreatedAt(v time.Time) *ShredKeyCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *ShredKeyCreate) SetNillableCreatedAt(v *time.Time) *ShredKeyCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetShreddedAt sets the "shredded_at" field.
+func (_c *ShredKeyCreate) SetShreddedAt(v time.Time) *ShredKeyCreate {
+	_c.mutation.SetShreddedAt(v)
+	return _c
+}
+
+// SetNillableShreddedAt sets the "shredded_at" field if the given value is not nil.
+func (_c *ShredKeyCreate) SetNillableShreddedAt(v *time.Time) *ShredKeyCreate {
+	if v != nil {
+		_c.SetShreddedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the ShredKeyMutation object of the builder.
+func (_c *ShredKeyCreate) Mutation() *ShredKeyMutation {
+	return _c.mutation
+}
+
+// Save creates the ShredKey in the database.
+func (_c *ShredKeyCreate) Save(ctx context.Context) (*ShredKey, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ShredKeyCreate) SaveX(ctx context.Context) *ShredKey {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ShredKeyCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ShredKeyCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ShredKeyCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := shredkey.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ShredKeyCreate) check() error {
+	if _, ok := _c.mutation.RunID(); !ok {
+		return &ValidationError{Name: "run_id", err: errors.New(`ent: missing required field "ShredKey.run_id"`)}
+	}
+	if v, ok := _c.mutation.RunID(); ok {
+		if err := shredkey.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "ShredKey.run_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ShredKey.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *ShredKeyCreate) sqlSave(ctx context.Context) (*ShredKey, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ShredKeyCreate) createSpec() (*ShredKey, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ShredKey{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(shredkey.Table, sqlgraph.NewFieldSpec(shredkey.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.RunID(); ok {
+		_spec.SetField(shredkey.FieldRunID, field.TypeString, value)
+		_node.RunID = value
+	}
+	if value, ok := _c.mutation.Key(); ok {
+		_spec.SetField(shredkey.FieldKey, field.TypeBytes, value)
+		_node.Key = &value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(shredkey.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.ShreddedAt(); ok {
+		_spec.SetField(shredkey.FieldShreddedAt, field.TypeTime, value)
+		_node.ShreddedAt = &value
+	}
+	return _node, _spec
+}
+
+// ShredKeyCreateBulk is the builder for creating many ShredKey entities in bulk.
+type ShredKeyCreateBulk struct {
+	config
+	err      error
+	builders []*ShredKeyCreate
+}
+
+// Save creates the ShredKey entities in the database.
+func (_c *ShredKeyCreateBulk) Save(ctx context.Context) ([]*ShredKey, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ShredKey, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ShredKeyMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ShredKeyCreateBulk) SaveX(ctx context.Context) []*ShredKey {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ShredKeyCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ShredKeyCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,324 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/predicate"
+	"github.com/wilhg/orch/internal/ent/shredkey"
+)
+
+// ShredKeyUpdate is the builder for updating ShredKey entities.
+type ShredKeyUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ShredKeyMutation
+}
+
+// Where appends a list predicates to the ShredKeyUpdate builder.
+func (_u *ShredKeyUpdate) Where(ps ...predicate.ShredKey) *ShredKeyUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *ShredKeyUpdate) SetRunID(v string) *ShredKeyUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *ShredKeyUpdate) SetNillableRunID(v *string) *ShredKeyUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetKey sets the "key" field.
+func (_u *ShredKeyUpdate) SetKey(v []byte) *ShredKeyUpdate {
+	_u.mutation.SetKey(v)
+	return _u
+}
+
+// ClearKey clears the value of the "key" field.
+func (_u *ShredKeyUpdate) ClearKey() *ShredKeyUpdate {
+	_u.mutation.ClearKey()
+	return _u
+}
+
+// SetShreddedAt sets the "shredded_at" field.
+func (_u *ShredKeyUpdate) SetShreddedAt(v time.Time) *ShredKeyUpdate {
+	_u.mutation.SetShreddedAt(v)
+	return _u
+}
+
+// SetNillableShreddedAt sets the "shredded_at" field if the given value is not nil.
+func (_u *ShredKeyUpdate) SetNillableShreddedAt(v *time.Time) *ShredKeyUpdate {
+	if v != nil {
+		_u.SetShreddedAt(*v)
+	}
+	return _u
+}
+
+// ClearShreddedAt clears the value of the "shredded_at" field.
+func (_u *ShredKeyUpdate) ClearShreddedAt() *ShredKeyUpdate {
+	_u.mutation.ClearShreddedAt()
+	return _u
+}
+
+// Mutation returns the ShredKeyMutation object of the builder.
+func (_u *ShredKeyUpdate) Mutation() *ShredKeyMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ShredKeyUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ShredKeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ShredKeyUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ShredKeyUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ShredKeyUpdate) check() error {
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := shredkey.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "ShredKey.run_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ShredKeyUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(shredkey.Table, shredkey.Columns, sqlgraph.NewFieldSpec(shredkey.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(shredkey.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Key(); ok {
+		_spec.SetField(shredkey.FieldKey, field.TypeBytes, value)
+	}
+	if _u.mutation.KeyCleared() {
+		_spec.ClearField(shredkey.FieldKey, field.TypeBytes)
+	}
+	if value, ok := _u.mutation.ShreddedAt(); ok {
+		_spec.SetField(shredkey.FieldShreddedAt, field.TypeTime, value)
+	}
+	if _u.mutation.ShreddedAtCleared() {
+		_spec.ClearField(shredkey.FieldShreddedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{shredkey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ShredKeyUpdateOne is the builder for updating a single ShredKey entity.
+type ShredKeyUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ShredKeyMutation
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *ShredKeyUpdateOne) SetRunID(v string) *ShredKeyUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *ShredKeyUpdateOne) SetNillableRunID(v *string) *ShredKeyUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetKey sets the "key" field.
+func (_u *ShredKeyUpdateOne) SetKey(v []byte) *ShredKeyUpdateOne {
+	_u.mutation.SetKey(v)
+	return _u
+}
+
+// ClearKey clears the value of the "key" field.
+func (_u *ShredKeyUpdateOne) ClearKey() *ShredKeyUpdateOne {
+	_u.mutation.ClearKey()
+	return _u
+}
+
+// SetShreddedAt sets the "shredded_at" field.
+func (_u *ShredKeyUpdateOne) SetShreddedAt(v time.Time) *ShredKeyUpdateOne {
+	_u.mutation.SetShreddedAt(v)
+	return _u
+}
+
+// SetNillableShreddedAt sets the "shredded_at" field if the given value is not nil.
+func (_u *ShredKeyUpdateOne) SetNillableShreddedAt(v *time.Time) *ShredKeyUpdateOne {
+	if v != nil {
+		_u.SetShreddedAt(*v)
+	}
+	return _u
+}
+
+// ClearShreddedAt clears the value of the "shredded_at" field.
+func (_u *ShredKeyUpdateOne) ClearShreddedAt() *ShredKeyUpdateOne {
+	_u.mutation.ClearShreddedAt()
+	return _u
+}
+
+// Mutation returns the ShredKeyMutation object of the builder.
+func (_u *ShredKeyUpdateOne) Mutation() *ShredKeyMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ShredKeyUpdate builder.
+func (_u *ShredKeyUpdateOne) Where(ps ...predicate.ShredKey) *ShredKeyUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ShredKeyUpdateOne) Select(field string, fields ...string) *ShredKeyUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ShredKey entity.
+func (_u *ShredKeyUpdateOne) Save(ctx context.Context) (*ShredKey, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ShredKeyUpdateOne) SaveX(ctx context.Context) *ShredKey {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ShredKeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ShredKeyUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ShredKeyUpdateOne) check() error {
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := shredkey.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "ShredKey.run_id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ShredKeyUpdateOne) sqlSave(ctx context.Context) (_node *ShredKey, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(shredkey.Table, shredkey.Columns, sqlgraph.NewFieldSpec(shredkey.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ShredKey.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, shredkey.FieldID)
+		for _, f := range fields {
+			if !shredkey.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != shredkey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(shredkey.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Key(); ok {
+		_spec.SetField(shredkey.FieldKey, field.TypeBytes, value)
+	}
+	if _u.mutation.KeyCleared() {
+		_spec.ClearField(shredkey.FieldKey, field.TypeBytes)
+	}
+	if value, ok := _u.mutation.ShreddedAt(); ok {
+		_spec.SetField(shredkey.FieldShreddedAt, field.TypeTime, value)
+	}
+	if _u.mutation.ShreddedAtCleared() {
+		_spec.ClearField(shredkey.FieldShreddedAt, field.TypeTime)
+	}
+	_node = &ShredKey{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{shredkey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
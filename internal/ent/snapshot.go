@@ -3,7 +3,6 @@
 package ent
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -25,7 +24,13 @@ type Snapshot struct {
 	// UptoSeq holds the value of the "upto_seq" field.
 	UptoSeq int64 `json:"upto_seq,omitempty"`
 	// State holds the value of the "state" field.
-	State map[string]interface{} `json:"state,omitempty"`
+	State string `json:"state,omitempty"`
+	// KeyID holds the value of the "key_id" field.
+	KeyID string `json:"key_id,omitempty"`
+	// Version holds the value of the "version" field.
+	Version string `json:"version,omitempty"`
+	// Hash holds the value of the "hash" field.
+	Hash string `json:"hash,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt    time.Time `json:"created_at,omitempty"`
 	selectValues sql.SelectValues
@@ -36,11 +41,9 @@ func (*Snapshot) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case snapshot.FieldState:
-			values[i] = new([]byte)
 		case snapshot.FieldID, snapshot.FieldUptoSeq:
 			values[i] = new(sql.NullInt64)
-		case snapshot.FieldSnapshotID, snapshot.FieldRunID:
+		case snapshot.FieldSnapshotID, snapshot.FieldRunID, snapshot.FieldState, snapshot.FieldKeyID, snapshot.FieldVersion, snapshot.FieldHash:
 			values[i] = new(sql.NullString)
 		case snapshot.FieldCreatedAt:
 			values[i] = new(sql.NullTime)
@@ -53,7 +56,7 @@ func (*Snapshot) scanValues(columns []string) ([]any, error) {
 
 // assignValues assigns the values that were returned from sql.Rows (after scanning)
 // to the Snapshot fields.
-func (s *Snapshot) assignValues(columns []string, values []any) error {
+func (_m *Snapshot) assignValues(columns []string, values []any) error {
 	if m, n := len(values), len(columns); m < n {
 		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
 	}
@@ -64,41 +67,57 @@ func (s *Snapshot) assignValues(columns []string, values []any) error {
 			if !ok {
 				return fmt.Errorf("unexpected type %T for field id", value)
 			}
-			s.ID = int(value.Int64)
+			_m.ID = int(value.Int64)
 		case snapshot.FieldSnapshotID:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field snapshot_id", values[i])
 			} else if value.Valid {
-				s.SnapshotID = value.String
+				_m.SnapshotID = value.String
 			}
 		case snapshot.FieldRunID:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field run_id", values[i])
 			} else if value.Valid {
-				s.RunID = value.String
+				_m.RunID = value.String
 			}
 		case snapshot.FieldUptoSeq:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field upto_seq", values[i])
 			} else if value.Valid {
-				s.UptoSeq = value.Int64
+				_m.UptoSeq = value.Int64
 			}
 		case snapshot.FieldState:
-			if value, ok := values[i].(*[]byte); !ok {
+			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field state", values[i])
-			} else if value != nil && len(*value) > 0 {
-				if err := json.Unmarshal(*value, &s.State); err != nil {
-					return fmt.Errorf("unmarshal field state: %w", err)
-				}
+			} else if value.Valid {
+				_m.State = value.String
+			}
+		case snapshot.FieldKeyID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field key_id", values[i])
+			} else if value.Valid {
+				_m.KeyID = value.String
+			}
+		case snapshot.FieldVersion:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field version", values[i])
+			} else if value.Valid {
+				_m.Version = value.String
+			}
+		case snapshot.FieldHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field hash", values[i])
+			} else if value.Valid {
+				_m.Hash = value.String
 			}
 		case snapshot.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
 			} else if value.Valid {
-				s.CreatedAt = value.Time
+				_m.CreatedAt = value.Time
 			}
 		default:
-			s.selectValues.Set(columns[i], values[i])
+			_m.selectValues.Set(columns[i], values[i])
 		}
 	}
 	return nil
@@ -106,47 +125,56 @@ func (s *Snapshot) assignValues(columns []string, values []any) error {
 
 // Value returns the ent.Value that was dynamically selected and assigned to the Snapshot.
 // This includes values selected through modifiers, order, etc.
-func (s *Snapshot) Value(name string) (ent.Value, error) {
-	return s.selectValues.Get(name)
+func (_m *Snapshot) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
 }
 
 // Update returns a builder for updating this Snapshot.
 // Note that you need to call Snapshot.Unwrap() before calling this method if this Snapshot
 // was returned from a transaction, and the transaction was committed or rolled back.
-func (s *Snapshot) Update() *SnapshotUpdateOne {
-	return NewSnapshotClient(s.config).UpdateOne(s)
+func (_m *Snapshot) Update() *SnapshotUpdateOne {
+	return NewSnapshotClient(_m.config).UpdateOne(_m)
 }
 
 // Unwrap unwraps the Snapshot entity that was returned from a transaction after it was closed,
 // so that all future queries will be executed through the driver which created the transaction.
-func (s *Snapshot) Unwrap() *Snapshot {
-	_tx, ok := s.config.driver.(*txDriver)
+func (_m *Snapshot) Unwrap() *Snapshot {
+	_tx, ok := _m.config.driver.(*txDriver)
 	if !ok {
 		panic("ent: Snapshot is not a transactional entity")
 	}
-	s.config.driver = _tx.drv
-	return s
+	_m.config.driver = _tx.drv
+	return _m
 }
 
 // String implements the fmt.Stringer.
-func (s *Snapshot) String() string {
+func (_m *Snapshot) String() string {
 	var builder strings.Builder
 	builder.WriteString("Snapshot(")
-	builder.WriteString(fmt.Sprintf("id=%v, ", s.ID))
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
 	builder.WriteString("snapshot_id=")
-	builder.WriteString(s.SnapshotID)
+	builder.WriteString(_m.SnapshotID)
 	builder.WriteString(", ")
 	builder.WriteString("run_id=")
-	builder.WriteString(s.RunID)
+	builder.WriteString(_m.RunID)
 	builder.WriteString(", ")
 	builder.WriteString("upto_seq=")
-	builder.WriteString(fmt.Sprintf("%v", s.UptoSeq))
+	builder.WriteString(fmt.Sprintf("%v", _m.UptoSeq))
 	builder.WriteString(", ")
 	builder.WriteString("state=")
-	builder.WriteString(fmt.Sprintf("%v", s.State))
+	builder.WriteString(_m.State)
+	builder.WriteString(", ")
+	builder.WriteString("key_id=")
+	builder.WriteString(_m.KeyID)
+	builder.WriteString(", ")
+	builder.WriteString("version=")
+	builder.WriteString(_m.Version)
+	builder.WriteString(", ")
+	builder.WriteString("hash=")
+	builder.WriteString(_m.Hash)
 	builder.WriteString(", ")
 	builder.WriteString("created_at=")
-	builder.WriteString(s.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -21,6 +21,12 @@ const (
 	FieldUptoSeq = "upto_seq"
 	// FieldState holds the string denoting the state field in the database.
 	FieldState = "state"
+	// FieldKeyID holds the string denoting the key_id field in the database.
+	FieldKeyID = "key_id"
+	// FieldVersion holds the string denoting the version field in the database.
+	FieldVersion = "version"
+	// FieldHash holds the string denoting the hash field in the database.
+	FieldHash = "hash"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// Table holds the table name of the snapshot in the database.
@@ -34,6 +40,9 @@ var Columns = []string{
 	FieldRunID,
 	FieldUptoSeq,
 	FieldState,
+	FieldKeyID,
+	FieldVersion,
+	FieldHash,
 	FieldCreatedAt,
 }
 
@@ -54,6 +63,12 @@ var (
 	RunIDValidator func(string) error
 	// UptoSeqValidator is a validator for the "upto_seq" field. It is called by the builders before save.
 	UptoSeqValidator func(int64) error
+	// DefaultKeyID holds the default value on creation for the "key_id" field.
+	DefaultKeyID string
+	// DefaultVersion holds the default value on creation for the "version" field.
+	DefaultVersion string
+	// DefaultHash holds the default value on creation for the "hash" field.
+	DefaultHash string
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 )
@@ -81,6 +96,26 @@ func ByUptoSeq(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUptoSeq, opts...).ToFunc()
 }
 
+// ByState orders the results by the state field.
+func ByState(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldState, opts...).ToFunc()
+}
+
+// ByKeyID orders the results by the key_id field.
+func ByKeyID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldKeyID, opts...).ToFunc()
+}
+
+// ByVersion orders the results by the version field.
+func ByVersion(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldVersion, opts...).ToFunc()
+}
+
+// ByHash orders the results by the hash field.
+func ByHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHash, opts...).ToFunc()
+}
+
 // ByCreatedAt orders the results by the created_at field.
 func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
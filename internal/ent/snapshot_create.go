@@ -21,57 +21,107 @@ type SnapshotCreate struct {
 }
 
 // SetSnapshotID sets the "snapshot_id" field.
-func (sc *SnapshotCreate) SetSnapshotID(s string) *SnapshotCreate {
-	sc.mutation.SetSnapshotID(s)
-	return sc
+func (_c *SnapshotCreate) SetSnapshotID(v string) *SnapshotCreate {
+	_c.mutation.SetSnapshotID(v)
+	return _c
 }
 
 // SetRunID sets the "run_id" field.
-func (sc *SnapshotCreate) SetRunID(s string) *SnapshotCreate {
-	sc.mutation.SetRunID(s)
-	return sc
+func (_c *SnapshotCreate) SetRunID(v string) *SnapshotCreate {
+	_c.mutation.SetRunID(v)
+	return _c
 }
 
 // SetUptoSeq sets the "upto_seq" field.
-func (sc *SnapshotCreate) SetUptoSeq(i int64) *SnapshotCreate {
-	sc.mutation.SetUptoSeq(i)
-	return sc
+func (_c *SnapshotCreate) SetUptoSeq(v int64) *SnapshotCreate {
+	_c.mutation.SetUptoSeq(v)
+	return _c
 }
 
 // SetState sets the "state" field.
-func (sc *SnapshotCreate) SetState(m map[string]interface{}) *SnapshotCreate {
-	sc.mutation.SetState(m)
-	return sc
+func (_c *SnapshotCreate) SetState(v string) *SnapshotCreate {
+	_c.mutation.SetState(v)
+	return _c
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_c *SnapshotCreate) SetNillableState(v *string) *SnapshotCreate {
+	if v != nil {
+		_c.SetState(*v)
+	}
+	return _c
+}
+
+// SetKeyID sets the "key_id" field.
+func (_c *SnapshotCreate) SetKeyID(v string) *SnapshotCreate {
+	_c.mutation.SetKeyID(v)
+	return _c
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_c *SnapshotCreate) SetNillableKeyID(v *string) *SnapshotCreate {
+	if v != nil {
+		_c.SetKeyID(*v)
+	}
+	return _c
+}
+
+// SetVersion sets the "version" field.
+func (_c *SnapshotCreate) SetVersion(v string) *SnapshotCreate {
+	_c.mutation.SetVersion(v)
+	return _c
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_c *SnapshotCreate) SetNillableVersion(v *string) *SnapshotCreate {
+	if v != nil {
+		_c.SetVersion(*v)
+	}
+	return _c
+}
+
+// SetHash sets the "hash" field.
+func (_c *SnapshotCreate) SetHash(v string) *SnapshotCreate {
+	_c.mutation.SetHash(v)
+	return _c
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (_c *SnapshotCreate) SetNillableHash(v *string) *SnapshotCreate {
+	if v != nil {
+		_c.SetHash(*v)
+	}
+	return _c
 }
 
 // SetCreatedAt sets the "created_at" field.
-func (sc *SnapshotCreate) SetCreatedAt(t time.Time) *SnapshotCreate {
-	sc.mutation.SetCreatedAt(t)
-	return sc
+func (_c *SnapshotCreate) SetCreatedAt(v time.Time) *SnapshotCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
 }
 
 // SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
-func (sc *SnapshotCreate) SetNillableCreatedAt(t *time.Time) *SnapshotCreate {
-	if t != nil {
-		sc.SetCreatedAt(*t)
+func (_c *SnapshotCreate) SetNillableCreatedAt(v *time.Time) *SnapshotCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
 	}
-	return sc
+	return _c
 }
 
 // Mutation returns the SnapshotMutation object of the builder.
-func (sc *SnapshotCreate) Mutation() *SnapshotMutation {
-	return sc.mutation
+func (_c *SnapshotCreate) Mutation() *SnapshotMutation {
+	return _c.mutation
 }
 
 // Save creates the Snapshot in the database.
-func (sc *SnapshotCreate) Save(ctx context.Context) (*Snapshot, error) {
-	sc.defaults()
-	return withHooks(ctx, sc.sqlSave, sc.mutation, sc.hooks)
+func (_c *SnapshotCreate) Save(ctx context.Context) (*Snapshot, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
 }
 
 // SaveX calls Save and panics if Save returns an error.
-func (sc *SnapshotCreate) SaveX(ctx context.Context) *Snapshot {
-	v, err := sc.Save(ctx)
+func (_c *SnapshotCreate) SaveX(ctx context.Context) *Snapshot {
+	v, err := _c.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -79,64 +129,85 @@ func (sc *SnapshotCreate) SaveX(ctx context.Context) *Snapshot {
 }
 
 // Exec executes the query.
-func (sc *SnapshotCreate) Exec(ctx context.Context) error {
-	_, err := sc.Save(ctx)
+func (_c *SnapshotCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (sc *SnapshotCreate) ExecX(ctx context.Context) {
-	if err := sc.Exec(ctx); err != nil {
+func (_c *SnapshotCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // defaults sets the default values of the builder before save.
-func (sc *SnapshotCreate) defaults() {
-	if _, ok := sc.mutation.CreatedAt(); !ok {
+func (_c *SnapshotCreate) defaults() {
+	if _, ok := _c.mutation.KeyID(); !ok {
+		v := snapshot.DefaultKeyID
+		_c.mutation.SetKeyID(v)
+	}
+	if _, ok := _c.mutation.Version(); !ok {
+		v := snapshot.DefaultVersion
+		_c.mutation.SetVersion(v)
+	}
+	if _, ok := _c.mutation.Hash(); !ok {
+		v := snapshot.DefaultHash
+		_c.mutation.SetHash(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
 		v := snapshot.DefaultCreatedAt()
-		sc.mutation.SetCreatedAt(v)
+		_c.mutation.SetCreatedAt(v)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (sc *SnapshotCreate) check() error {
-	if _, ok := sc.mutation.SnapshotID(); !ok {
+func (_c *SnapshotCreate) check() error {
+	if _, ok := _c.mutation.SnapshotID(); !ok {
 		return &ValidationError{Name: "snapshot_id", err: errors.New(`ent: missing required field "Snapshot.snapshot_id"`)}
 	}
-	if v, ok := sc.mutation.SnapshotID(); ok {
+	if v, ok := _c.mutation.SnapshotID(); ok {
 		if err := snapshot.SnapshotIDValidator(v); err != nil {
 			return &ValidationError{Name: "snapshot_id", err: fmt.Errorf(`ent: validator failed for field "Snapshot.snapshot_id": %w`, err)}
 		}
 	}
-	if _, ok := sc.mutation.RunID(); !ok {
+	if _, ok := _c.mutation.RunID(); !ok {
 		return &ValidationError{Name: "run_id", err: errors.New(`ent: missing required field "Snapshot.run_id"`)}
 	}
-	if v, ok := sc.mutation.RunID(); ok {
+	if v, ok := _c.mutation.RunID(); ok {
 		if err := snapshot.RunIDValidator(v); err != nil {
 			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Snapshot.run_id": %w`, err)}
 		}
 	}
-	if _, ok := sc.mutation.UptoSeq(); !ok {
+	if _, ok := _c.mutation.UptoSeq(); !ok {
 		return &ValidationError{Name: "upto_seq", err: errors.New(`ent: missing required field "Snapshot.upto_seq"`)}
 	}
-	if v, ok := sc.mutation.UptoSeq(); ok {
+	if v, ok := _c.mutation.UptoSeq(); ok {
 		if err := snapshot.UptoSeqValidator(v); err != nil {
 			return &ValidationError{Name: "upto_seq", err: fmt.Errorf(`ent: validator failed for field "Snapshot.upto_seq": %w`, err)}
 		}
 	}
-	if _, ok := sc.mutation.CreatedAt(); !ok {
+	if _, ok := _c.mutation.KeyID(); !ok {
+		return &ValidationError{Name: "key_id", err: errors.New(`ent: missing required field "Snapshot.key_id"`)}
+	}
+	if _, ok := _c.mutation.Version(); !ok {
+		return &ValidationError{Name: "version", err: errors.New(`ent: missing required field "Snapshot.version"`)}
+	}
+	if _, ok := _c.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "Snapshot.hash"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Snapshot.created_at"`)}
 	}
 	return nil
 }
 
-func (sc *SnapshotCreate) sqlSave(ctx context.Context) (*Snapshot, error) {
-	if err := sc.check(); err != nil {
+func (_c *SnapshotCreate) sqlSave(ctx context.Context) (*Snapshot, error) {
+	if err := _c.check(); err != nil {
 		return nil, err
 	}
-	_node, _spec := sc.createSpec()
-	if err := sqlgraph.CreateNode(ctx, sc.driver, _spec); err != nil {
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
 		if sqlgraph.IsConstraintError(err) {
 			err = &ConstraintError{msg: err.Error(), wrap: err}
 		}
@@ -144,33 +215,45 @@ func (sc *SnapshotCreate) sqlSave(ctx context.Context) (*Snapshot, error) {
 	}
 	id := _spec.ID.Value.(int64)
 	_node.ID = int(id)
-	sc.mutation.id = &_node.ID
-	sc.mutation.done = true
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
 	return _node, nil
 }
 
-func (sc *SnapshotCreate) createSpec() (*Snapshot, *sqlgraph.CreateSpec) {
+func (_c *SnapshotCreate) createSpec() (*Snapshot, *sqlgraph.CreateSpec) {
 	var (
-		_node = &Snapshot{config: sc.config}
+		_node = &Snapshot{config: _c.config}
 		_spec = sqlgraph.NewCreateSpec(snapshot.Table, sqlgraph.NewFieldSpec(snapshot.FieldID, field.TypeInt))
 	)
-	if value, ok := sc.mutation.SnapshotID(); ok {
+	if value, ok := _c.mutation.SnapshotID(); ok {
 		_spec.SetField(snapshot.FieldSnapshotID, field.TypeString, value)
 		_node.SnapshotID = value
 	}
-	if value, ok := sc.mutation.RunID(); ok {
+	if value, ok := _c.mutation.RunID(); ok {
 		_spec.SetField(snapshot.FieldRunID, field.TypeString, value)
 		_node.RunID = value
 	}
-	if value, ok := sc.mutation.UptoSeq(); ok {
+	if value, ok := _c.mutation.UptoSeq(); ok {
 		_spec.SetField(snapshot.FieldUptoSeq, field.TypeInt64, value)
 		_node.UptoSeq = value
 	}
-	if value, ok := sc.mutation.State(); ok {
-		_spec.SetField(snapshot.FieldState, field.TypeJSON, value)
+	if value, ok := _c.mutation.State(); ok {
+		_spec.SetField(snapshot.FieldState, field.TypeString, value)
 		_node.State = value
 	}
-	if value, ok := sc.mutation.CreatedAt(); ok {
+	if value, ok := _c.mutation.KeyID(); ok {
+		_spec.SetField(snapshot.FieldKeyID, field.TypeString, value)
+		_node.KeyID = value
+	}
+	if value, ok := _c.mutation.Version(); ok {
+		_spec.SetField(snapshot.FieldVersion, field.TypeString, value)
+		_node.Version = value
+	}
+	if value, ok := _c.mutation.Hash(); ok {
+		_spec.SetField(snapshot.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
 		_spec.SetField(snapshot.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
 	}
@@ -185,16 +268,16 @@ type SnapshotCreateBulk struct {
 }
 
 // Save creates the Snapshot entities in the database.
-func (scb *SnapshotCreateBulk) Save(ctx context.Context) ([]*Snapshot, error) {
-	if scb.err != nil {
-		return nil, scb.err
-	}
-	specs := make([]*sqlgraph.CreateSpec, len(scb.builders))
-	nodes := make([]*Snapshot, len(scb.builders))
-	mutators := make([]Mutator, len(scb.builders))
-	for i := range scb.builders {
+func (_c *SnapshotCreateBulk) Save(ctx context.Context) ([]*Snapshot, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*Snapshot, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
 		func(i int, root context.Context) {
-			builder := scb.builders[i]
+			builder := _c.builders[i]
 			builder.defaults()
 			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
 				mutation, ok := m.(*SnapshotMutation)
@@ -208,11 +291,11 @@ func (scb *SnapshotCreateBulk) Save(ctx context.Context) ([]*Snapshot, error) {
 				var err error
 				nodes[i], specs[i] = builder.createSpec()
 				if i < len(mutators)-1 {
-					_, err = mutators[i+1].Mutate(root, scb.builders[i+1].mutation)
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
 					// Invoke the actual operation on the latest mutation in the chain.
-					if err = sqlgraph.BatchCreate(ctx, scb.driver, spec); err != nil {
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
 							err = &ConstraintError{msg: err.Error(), wrap: err}
 						}
@@ -236,7 +319,7 @@ func (scb *SnapshotCreateBulk) Save(ctx context.Context) ([]*Snapshot, error) {
 		}(i, ctx)
 	}
 	if len(mutators) > 0 {
-		if _, err := mutators[0].Mutate(ctx, scb.builders[0].mutation); err != nil {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
 			return nil, err
 		}
 	}
@@ -244,8 +327,8 @@ func (scb *SnapshotCreateBulk) Save(ctx context.Context) ([]*Snapshot, error) {
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (scb *SnapshotCreateBulk) SaveX(ctx context.Context) []*Snapshot {
-	v, err := scb.Save(ctx)
+func (_c *SnapshotCreateBulk) SaveX(ctx context.Context) []*Snapshot {
+	v, err := _c.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -253,14 +336,14 @@ func (scb *SnapshotCreateBulk) SaveX(ctx context.Context) []*Snapshot {
 }
 
 // Exec executes the query.
-func (scb *SnapshotCreateBulk) Exec(ctx context.Context) error {
-	_, err := scb.Save(ctx)
+func (_c *SnapshotCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (scb *SnapshotCreateBulk) ExecX(ctx context.Context) {
-	if err := scb.Exec(ctx); err != nil {
+func (_c *SnapshotCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 
+	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
@@ -27,40 +28,40 @@ type SnapshotQuery struct {
 }
 
 // Where adds a new predicate for the SnapshotQuery builder.
-func (sq *SnapshotQuery) Where(ps ...predicate.Snapshot) *SnapshotQuery {
-	sq.predicates = append(sq.predicates, ps...)
-	return sq
+func (_q *SnapshotQuery) Where(ps ...predicate.Snapshot) *SnapshotQuery {
+	_q.predicates = append(_q.predicates, ps...)
+	return _q
 }
 
 // Limit the number of records to be returned by this query.
-func (sq *SnapshotQuery) Limit(limit int) *SnapshotQuery {
-	sq.ctx.Limit = &limit
-	return sq
+func (_q *SnapshotQuery) Limit(limit int) *SnapshotQuery {
+	_q.ctx.Limit = &limit
+	return _q
 }
 
 // Offset to start from.
-func (sq *SnapshotQuery) Offset(offset int) *SnapshotQuery {
-	sq.ctx.Offset = &offset
-	return sq
+func (_q *SnapshotQuery) Offset(offset int) *SnapshotQuery {
+	_q.ctx.Offset = &offset
+	return _q
 }
 
 // Unique configures the query builder to filter duplicate records on query.
 // By default, unique is set to true, and can be disabled using this method.
-func (sq *SnapshotQuery) Unique(unique bool) *SnapshotQuery {
-	sq.ctx.Unique = &unique
-	return sq
+func (_q *SnapshotQuery) Unique(unique bool) *SnapshotQuery {
+	_q.ctx.Unique = &unique
+	return _q
 }
 
 // Order specifies how the records should be ordered.
-func (sq *SnapshotQuery) Order(o ...snapshot.OrderOption) *SnapshotQuery {
-	sq.order = append(sq.order, o...)
-	return sq
+func (_q *SnapshotQuery) Order(o ...snapshot.OrderOption) *SnapshotQuery {
+	_q.order = append(_q.order, o...)
+	return _q
 }
 
 // First returns the first Snapshot entity from the query.
 // Returns a *NotFoundError when no Snapshot was found.
-func (sq *SnapshotQuery) First(ctx context.Context) (*Snapshot, error) {
-	nodes, err := sq.Limit(1).All(setContextOp(ctx, sq.ctx, "First"))
+func (_q *SnapshotQuery) First(ctx context.Context) (*Snapshot, error) {
+	nodes, err := _q.Limit(1).All(setContextOp(ctx, _q.ctx, ent.OpQueryFirst))
 	if err != nil {
 		return nil, err
 	}
@@ -71,8 +72,8 @@ func (sq *SnapshotQuery) First(ctx context.Context) (*Snapshot, error) {
 }
 
 // FirstX is like First, but panics if an error occurs.
-func (sq *SnapshotQuery) FirstX(ctx context.Context) *Snapshot {
-	node, err := sq.First(ctx)
+func (_q *SnapshotQuery) FirstX(ctx context.Context) *Snapshot {
+	node, err := _q.First(ctx)
 	if err != nil && !IsNotFound(err) {
 		panic(err)
 	}
@@ -81,9 +82,9 @@ func (sq *SnapshotQuery) FirstX(ctx context.Context) *Snapshot {
 
 // FirstID returns the first Snapshot ID from the query.
 // Returns a *NotFoundError when no Snapshot ID was found.
-func (sq *SnapshotQuery) FirstID(ctx context.Context) (id int, err error) {
+func (_q *SnapshotQuery) FirstID(ctx context.Context) (id int, err error) {
 	var ids []int
-	if ids, err = sq.Limit(1).IDs(setContextOp(ctx, sq.ctx, "FirstID")); err != nil {
+	if ids, err = _q.Limit(1).IDs(setContextOp(ctx, _q.ctx, ent.OpQueryFirstID)); err != nil {
 		return
 	}
 	if len(ids) == 0 {
@@ -94,8 +95,8 @@ func (sq *SnapshotQuery) FirstID(ctx context.Context) (id int, err error) {
 }
 
 // FirstIDX is like FirstID, but panics if an error occurs.
-func (sq *SnapshotQuery) FirstIDX(ctx context.Context) int {
-	id, err := sq.FirstID(ctx)
+func (_q *SnapshotQuery) FirstIDX(ctx context.Context) int {
+	id, err := _q.FirstID(ctx)
 	if err != nil && !IsNotFound(err) {
 		panic(err)
 	}
@@ -105,8 +106,8 @@ func (sq *SnapshotQuery) FirstIDX(ctx context.Context) int {
 // Only returns a single Snapshot entity found by the query, ensuring it only returns one.
 // Returns a *NotSingularError when more than one Snapshot entity is found.
 // Returns a *NotFoundError when no Snapshot entities are found.
-func (sq *SnapshotQuery) Only(ctx context.Context) (*Snapshot, error) {
-	nodes, err := sq.Limit(2).All(setContextOp(ctx, sq.ctx, "Only"))
+func (_q *SnapshotQuery) Only(ctx context.Context) (*Snapshot, error) {
+	nodes, err := _q.Limit(2).All(setContextOp(ctx, _q.ctx, ent.OpQueryOnly))
 	if err != nil {
 		return nil, err
 	}
@@ -121,8 +122,8 @@ func (sq *SnapshotQuery) Only(ctx context.Context) (*Snapshot, error) {
 }
 
 // OnlyX is like Only, but panics if an error occurs.
-func (sq *SnapshotQuery) OnlyX(ctx context.Context) *Snapshot {
-	node, err := sq.Only(ctx)
+func (_q *SnapshotQuery) OnlyX(ctx context.Context) *Snapshot {
+	node, err := _q.Only(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -132,9 +133,9 @@ func (sq *SnapshotQuery) OnlyX(ctx context.Context) *Snapshot {
 // OnlyID is like Only, but returns the only Snapshot ID in the query.
 // Returns a *NotSingularError when more than one Snapshot ID is found.
 // Returns a *NotFoundError when no entities are found.
-func (sq *SnapshotQuery) OnlyID(ctx context.Context) (id int, err error) {
+func (_q *SnapshotQuery) OnlyID(ctx context.Context) (id int, err error) {
 	var ids []int
-	if ids, err = sq.Limit(2).IDs(setContextOp(ctx, sq.ctx, "OnlyID")); err != nil {
+	if ids, err = _q.Limit(2).IDs(setContextOp(ctx, _q.ctx, ent.OpQueryOnlyID)); err != nil {
 		return
 	}
 	switch len(ids) {
@@ -149,8 +150,8 @@ func (sq *SnapshotQuery) OnlyID(ctx context.Context) (id int, err error) {
 }
 
 // OnlyIDX is like OnlyID, but panics if an error occurs.
-func (sq *SnapshotQuery) OnlyIDX(ctx context.Context) int {
-	id, err := sq.OnlyID(ctx)
+func (_q *SnapshotQuery) OnlyIDX(ctx context.Context) int {
+	id, err := _q.OnlyID(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -158,18 +159,18 @@ func (sq *SnapshotQuery) OnlyIDX(ctx context.Context) int {
 }
 
 // All executes the query and returns a list of Snapshots.
-func (sq *SnapshotQuery) All(ctx context.Context) ([]*Snapshot, error) {
-	ctx = setContextOp(ctx, sq.ctx, "All")
-	if err := sq.prepareQuery(ctx); err != nil {
+func (_q *SnapshotQuery) All(ctx context.Context) ([]*Snapshot, error) {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryAll)
+	if err := _q.prepareQuery(ctx); err != nil {
 		return nil, err
 	}
 	qr := querierAll[[]*Snapshot, *SnapshotQuery]()
-	return withInterceptors[[]*Snapshot](ctx, sq, qr, sq.inters)
+	return withInterceptors[[]*Snapshot](ctx, _q, qr, _q.inters)
 }
 
 // AllX is like All, but panics if an error occurs.
-func (sq *SnapshotQuery) AllX(ctx context.Context) []*Snapshot {
-	nodes, err := sq.All(ctx)
+func (_q *SnapshotQuery) AllX(ctx context.Context) []*Snapshot {
+	nodes, err := _q.All(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -177,20 +178,20 @@ func (sq *SnapshotQuery) AllX(ctx context.Context) []*Snapshot {
 }
 
 // IDs executes the query and returns a list of Snapshot IDs.
-func (sq *SnapshotQuery) IDs(ctx context.Context) (ids []int, err error) {
-	if sq.ctx.Unique == nil && sq.path != nil {
-		sq.Unique(true)
+func (_q *SnapshotQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if _q.ctx.Unique == nil && _q.path != nil {
+		_q.Unique(true)
 	}
-	ctx = setContextOp(ctx, sq.ctx, "IDs")
-	if err = sq.Select(snapshot.FieldID).Scan(ctx, &ids); err != nil {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryIDs)
+	if err = _q.Select(snapshot.FieldID).Scan(ctx, &ids); err != nil {
 		return nil, err
 	}
 	return ids, nil
 }
 
 // IDsX is like IDs, but panics if an error occurs.
-func (sq *SnapshotQuery) IDsX(ctx context.Context) []int {
-	ids, err := sq.IDs(ctx)
+func (_q *SnapshotQuery) IDsX(ctx context.Context) []int {
+	ids, err := _q.IDs(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -198,17 +199,17 @@ func (sq *SnapshotQuery) IDsX(ctx context.Context) []int {
 }
 
 // Count returns the count of the given query.
-func (sq *SnapshotQuery) Count(ctx context.Context) (int, error) {
-	ctx = setContextOp(ctx, sq.ctx, "Count")
-	if err := sq.prepareQuery(ctx); err != nil {
+func (_q *SnapshotQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryCount)
+	if err := _q.prepareQuery(ctx); err != nil {
 		return 0, err
 	}
-	return withInterceptors[int](ctx, sq, querierCount[*SnapshotQuery](), sq.inters)
+	return withInterceptors[int](ctx, _q, querierCount[*SnapshotQuery](), _q.inters)
 }
 
 // CountX is like Count, but panics if an error occurs.
-func (sq *SnapshotQuery) CountX(ctx context.Context) int {
-	count, err := sq.Count(ctx)
+func (_q *SnapshotQuery) CountX(ctx context.Context) int {
+	count, err := _q.Count(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -216,9 +217,9 @@ func (sq *SnapshotQuery) CountX(ctx context.Context) int {
 }
 
 // Exist returns true if the query has elements in the graph.
-func (sq *SnapshotQuery) Exist(ctx context.Context) (bool, error) {
-	ctx = setContextOp(ctx, sq.ctx, "Exist")
-	switch _, err := sq.FirstID(ctx); {
+func (_q *SnapshotQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryExist)
+	switch _, err := _q.FirstID(ctx); {
 	case IsNotFound(err):
 		return false, nil
 	case err != nil:
@@ -229,8 +230,8 @@ func (sq *SnapshotQuery) Exist(ctx context.Context) (bool, error) {
 }
 
 // ExistX is like Exist, but panics if an error occurs.
-func (sq *SnapshotQuery) ExistX(ctx context.Context) bool {
-	exist, err := sq.Exist(ctx)
+func (_q *SnapshotQuery) ExistX(ctx context.Context) bool {
+	exist, err := _q.Exist(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -239,19 +240,19 @@ func (sq *SnapshotQuery) ExistX(ctx context.Context) bool {
 
 // Clone returns a duplicate of the SnapshotQuery builder, including all associated steps. It can be
 // used to prepare common query builders and use them differently after the clone is made.
-func (sq *SnapshotQuery) Clone() *SnapshotQuery {
-	if sq == nil {
+func (_q *SnapshotQuery) Clone() *SnapshotQuery {
+	if _q == nil {
 		return nil
 	}
 	return &SnapshotQuery{
-		config:     sq.config,
-		ctx:        sq.ctx.Clone(),
-		order:      append([]snapshot.OrderOption{}, sq.order...),
-		inters:     append([]Interceptor{}, sq.inters...),
-		predicates: append([]predicate.Snapshot{}, sq.predicates...),
+		config:     _q.config,
+		ctx:        _q.ctx.Clone(),
+		order:      append([]snapshot.OrderOption{}, _q.order...),
+		inters:     append([]Interceptor{}, _q.inters...),
+		predicates: append([]predicate.Snapshot{}, _q.predicates...),
 		// clone intermediate query.
-		sql:  sq.sql.Clone(),
-		path: sq.path,
+		sql:  _q.sql.Clone(),
+		path: _q.path,
 	}
 }
 
@@ -269,10 +270,10 @@ func (sq *SnapshotQuery) Clone() *SnapshotQuery {
 //		GroupBy(snapshot.FieldSnapshotID).
 //		Aggregate(ent.Count()).
 //		Scan(ctx, &v)
-func (sq *SnapshotQuery) GroupBy(field string, fields ...string) *SnapshotGroupBy {
-	sq.ctx.Fields = append([]string{field}, fields...)
-	grbuild := &SnapshotGroupBy{build: sq}
-	grbuild.flds = &sq.ctx.Fields
+func (_q *SnapshotQuery) GroupBy(field string, fields ...string) *SnapshotGroupBy {
+	_q.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &SnapshotGroupBy{build: _q}
+	grbuild.flds = &_q.ctx.Fields
 	grbuild.label = snapshot.Label
 	grbuild.scan = grbuild.Scan
 	return grbuild
@@ -290,62 +291,62 @@ func (sq *SnapshotQuery) GroupBy(field string, fields ...string) *SnapshotGroupB
 //	client.Snapshot.Query().
 //		Select(snapshot.FieldSnapshotID).
 //		Scan(ctx, &v)
-func (sq *SnapshotQuery) Select(fields ...string) *SnapshotSelect {
-	sq.ctx.Fields = append(sq.ctx.Fields, fields...)
-	sbuild := &SnapshotSelect{SnapshotQuery: sq}
+func (_q *SnapshotQuery) Select(fields ...string) *SnapshotSelect {
+	_q.ctx.Fields = append(_q.ctx.Fields, fields...)
+	sbuild := &SnapshotSelect{SnapshotQuery: _q}
 	sbuild.label = snapshot.Label
-	sbuild.flds, sbuild.scan = &sq.ctx.Fields, sbuild.Scan
+	sbuild.flds, sbuild.scan = &_q.ctx.Fields, sbuild.Scan
 	return sbuild
 }
 
 // Aggregate returns a SnapshotSelect configured with the given aggregations.
-func (sq *SnapshotQuery) Aggregate(fns ...AggregateFunc) *SnapshotSelect {
-	return sq.Select().Aggregate(fns...)
+func (_q *SnapshotQuery) Aggregate(fns ...AggregateFunc) *SnapshotSelect {
+	return _q.Select().Aggregate(fns...)
 }
 
-func (sq *SnapshotQuery) prepareQuery(ctx context.Context) error {
-	for _, inter := range sq.inters {
+func (_q *SnapshotQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range _q.inters {
 		if inter == nil {
 			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
 		}
 		if trv, ok := inter.(Traverser); ok {
-			if err := trv.Traverse(ctx, sq); err != nil {
+			if err := trv.Traverse(ctx, _q); err != nil {
 				return err
 			}
 		}
 	}
-	for _, f := range sq.ctx.Fields {
+	for _, f := range _q.ctx.Fields {
 		if !snapshot.ValidColumn(f) {
 			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
 		}
 	}
-	if sq.path != nil {
-		prev, err := sq.path(ctx)
+	if _q.path != nil {
+		prev, err := _q.path(ctx)
 		if err != nil {
 			return err
 		}
-		sq.sql = prev
+		_q.sql = prev
 	}
 	return nil
 }
 
-func (sq *SnapshotQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Snapshot, error) {
+func (_q *SnapshotQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Snapshot, error) {
 	var (
 		nodes = []*Snapshot{}
-		_spec = sq.querySpec()
+		_spec = _q.querySpec()
 	)
 	_spec.ScanValues = func(columns []string) ([]any, error) {
 		return (*Snapshot).scanValues(nil, columns)
 	}
 	_spec.Assign = func(columns []string, values []any) error {
-		node := &Snapshot{config: sq.config}
+		node := &Snapshot{config: _q.config}
 		nodes = append(nodes, node)
 		return node.assignValues(columns, values)
 	}
 	for i := range hooks {
 		hooks[i](ctx, _spec)
 	}
-	if err := sqlgraph.QueryNodes(ctx, sq.driver, _spec); err != nil {
+	if err := sqlgraph.QueryNodes(ctx, _q.driver, _spec); err != nil {
 		return nil, err
 	}
 	if len(nodes) == 0 {
@@ -354,24 +355,24 @@ func (sq *SnapshotQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Sna
 	return nodes, nil
 }
 
-func (sq *SnapshotQuery) sqlCount(ctx context.Context) (int, error) {
-	_spec := sq.querySpec()
-	_spec.Node.Columns = sq.ctx.Fields
-	if len(sq.ctx.Fields) > 0 {
-		_spec.Unique = sq.ctx.Unique != nil && *sq.ctx.Unique
+func (_q *SnapshotQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := _q.querySpec()
+	_spec.Node.Columns = _q.ctx.Fields
+	if len(_q.ctx.Fields) > 0 {
+		_spec.Unique = _q.ctx.Unique != nil && *_q.ctx.Unique
 	}
-	return sqlgraph.CountNodes(ctx, sq.driver, _spec)
+	return sqlgraph.CountNodes(ctx, _q.driver, _spec)
 }
 
-func (sq *SnapshotQuery) querySpec() *sqlgraph.QuerySpec {
+func (_q *SnapshotQuery) querySpec() *sqlgraph.QuerySpec {
 	_spec := sqlgraph.NewQuerySpec(snapshot.Table, snapshot.Columns, sqlgraph.NewFieldSpec(snapshot.FieldID, field.TypeInt))
-	_spec.From = sq.sql
-	if unique := sq.ctx.Unique; unique != nil {
+	_spec.From = _q.sql
+	if unique := _q.ctx.Unique; unique != nil {
 		_spec.Unique = *unique
-	} else if sq.path != nil {
+	} else if _q.path != nil {
 		_spec.Unique = true
 	}
-	if fields := sq.ctx.Fields; len(fields) > 0 {
+	if fields := _q.ctx.Fields; len(fields) > 0 {
 		_spec.Node.Columns = make([]string, 0, len(fields))
 		_spec.Node.Columns = append(_spec.Node.Columns, snapshot.FieldID)
 		for i := range fields {
@@ -380,20 +381,20 @@ func (sq *SnapshotQuery) querySpec() *sqlgraph.QuerySpec {
 			}
 		}
 	}
-	if ps := sq.predicates; len(ps) > 0 {
+	if ps := _q.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if limit := sq.ctx.Limit; limit != nil {
+	if limit := _q.ctx.Limit; limit != nil {
 		_spec.Limit = *limit
 	}
-	if offset := sq.ctx.Offset; offset != nil {
+	if offset := _q.ctx.Offset; offset != nil {
 		_spec.Offset = *offset
 	}
-	if ps := sq.order; len(ps) > 0 {
+	if ps := _q.order; len(ps) > 0 {
 		_spec.Order = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
@@ -403,33 +404,33 @@ func (sq *SnapshotQuery) querySpec() *sqlgraph.QuerySpec {
 	return _spec
 }
 
-func (sq *SnapshotQuery) sqlQuery(ctx context.Context) *sql.Selector {
-	builder := sql.Dialect(sq.driver.Dialect())
+func (_q *SnapshotQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(_q.driver.Dialect())
 	t1 := builder.Table(snapshot.Table)
-	columns := sq.ctx.Fields
+	columns := _q.ctx.Fields
 	if len(columns) == 0 {
 		columns = snapshot.Columns
 	}
 	selector := builder.Select(t1.Columns(columns...)...).From(t1)
-	if sq.sql != nil {
-		selector = sq.sql
+	if _q.sql != nil {
+		selector = _q.sql
 		selector.Select(selector.Columns(columns...)...)
 	}
-	if sq.ctx.Unique != nil && *sq.ctx.Unique {
+	if _q.ctx.Unique != nil && *_q.ctx.Unique {
 		selector.Distinct()
 	}
-	for _, p := range sq.predicates {
+	for _, p := range _q.predicates {
 		p(selector)
 	}
-	for _, p := range sq.order {
+	for _, p := range _q.order {
 		p(selector)
 	}
-	if offset := sq.ctx.Offset; offset != nil {
+	if offset := _q.ctx.Offset; offset != nil {
 		// limit is mandatory for offset clause. We start
 		// with default value, and override it below if needed.
 		selector.Offset(*offset).Limit(math.MaxInt32)
 	}
-	if limit := sq.ctx.Limit; limit != nil {
+	if limit := _q.ctx.Limit; limit != nil {
 		selector.Limit(*limit)
 	}
 	return selector
@@ -442,41 +443,41 @@ type SnapshotGroupBy struct {
 }
 
 // Aggregate adds the given aggregation functions to the group-by query.
-func (sgb *SnapshotGroupBy) Aggregate(fns ...AggregateFunc) *SnapshotGroupBy {
-	sgb.fns = append(sgb.fns, fns...)
-	return sgb
+func (_g *SnapshotGroupBy) Aggregate(fns ...AggregateFunc) *SnapshotGroupBy {
+	_g.fns = append(_g.fns, fns...)
+	return _g
 }
 
 // Scan applies the selector query and scans the result into the given value.
-func (sgb *SnapshotGroupBy) Scan(ctx context.Context, v any) error {
-	ctx = setContextOp(ctx, sgb.build.ctx, "GroupBy")
-	if err := sgb.build.prepareQuery(ctx); err != nil {
+func (_g *SnapshotGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, _g.build.ctx, ent.OpQueryGroupBy)
+	if err := _g.build.prepareQuery(ctx); err != nil {
 		return err
 	}
-	return scanWithInterceptors[*SnapshotQuery, *SnapshotGroupBy](ctx, sgb.build, sgb, sgb.build.inters, v)
+	return scanWithInterceptors[*SnapshotQuery, *SnapshotGroupBy](ctx, _g.build, _g, _g.build.inters, v)
 }
 
-func (sgb *SnapshotGroupBy) sqlScan(ctx context.Context, root *SnapshotQuery, v any) error {
+func (_g *SnapshotGroupBy) sqlScan(ctx context.Context, root *SnapshotQuery, v any) error {
 	selector := root.sqlQuery(ctx).Select()
-	aggregation := make([]string, 0, len(sgb.fns))
-	for _, fn := range sgb.fns {
+	aggregation := make([]string, 0, len(_g.fns))
+	for _, fn := range _g.fns {
 		aggregation = append(aggregation, fn(selector))
 	}
 	if len(selector.SelectedColumns()) == 0 {
-		columns := make([]string, 0, len(*sgb.flds)+len(sgb.fns))
-		for _, f := range *sgb.flds {
+		columns := make([]string, 0, len(*_g.flds)+len(_g.fns))
+		for _, f := range *_g.flds {
 			columns = append(columns, selector.C(f))
 		}
 		columns = append(columns, aggregation...)
 		selector.Select(columns...)
 	}
-	selector.GroupBy(selector.Columns(*sgb.flds...)...)
+	selector.GroupBy(selector.Columns(*_g.flds...)...)
 	if err := selector.Err(); err != nil {
 		return err
 	}
 	rows := &sql.Rows{}
 	query, args := selector.Query()
-	if err := sgb.build.driver.Query(ctx, query, args, rows); err != nil {
+	if err := _g.build.driver.Query(ctx, query, args, rows); err != nil {
 		return err
 	}
 	defer rows.Close()
@@ -490,27 +491,27 @@ type SnapshotSelect struct {
 }
 
 // Aggregate adds the given aggregation functions to the selector query.
-func (ss *SnapshotSelect) Aggregate(fns ...AggregateFunc) *SnapshotSelect {
-	ss.fns = append(ss.fns, fns...)
-	return ss
+func (_s *SnapshotSelect) Aggregate(fns ...AggregateFunc) *SnapshotSelect {
+	_s.fns = append(_s.fns, fns...)
+	return _s
 }
 
 // Scan applies the selector query and scans the result into the given value.
-func (ss *SnapshotSelect) Scan(ctx context.Context, v any) error {
-	ctx = setContextOp(ctx, ss.ctx, "Select")
-	if err := ss.prepareQuery(ctx); err != nil {
+func (_s *SnapshotSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, _s.ctx, ent.OpQuerySelect)
+	if err := _s.prepareQuery(ctx); err != nil {
 		return err
 	}
-	return scanWithInterceptors[*SnapshotQuery, *SnapshotSelect](ctx, ss.SnapshotQuery, ss, ss.inters, v)
+	return scanWithInterceptors[*SnapshotQuery, *SnapshotSelect](ctx, _s.SnapshotQuery, _s, _s.inters, v)
 }
 
-func (ss *SnapshotSelect) sqlScan(ctx context.Context, root *SnapshotQuery, v any) error {
+func (_s *SnapshotSelect) sqlScan(ctx context.Context, root *SnapshotQuery, v any) error {
 	selector := root.sqlQuery(ctx)
-	aggregation := make([]string, 0, len(ss.fns))
-	for _, fn := range ss.fns {
+	aggregation := make([]string, 0, len(_s.fns))
+	for _, fn := range _s.fns {
 		aggregation = append(aggregation, fn(selector))
 	}
-	switch n := len(*ss.selector.flds); {
+	switch n := len(*_s.selector.flds); {
 	case n == 0 && len(aggregation) > 0:
 		selector.Select(aggregation...)
 	case n != 0 && len(aggregation) > 0:
@@ -518,7 +519,7 @@ func (ss *SnapshotSelect) sqlScan(ctx context.Context, root *SnapshotQuery, v an
 	}
 	rows := &sql.Rows{}
 	query, args := selector.Query()
-	if err := ss.driver.Query(ctx, query, args, rows); err != nil {
+	if err := _s.driver.Query(ctx, query, args, rows); err != nil {
 		return err
 	}
 	defer rows.Close()
@@ -22,85 +22,135 @@ type SnapshotUpdate struct {
 }
 
 // Where appends a list predicates to the SnapshotUpdate builder.
-func (su *SnapshotUpdate) Where(ps ...predicate.Snapshot) *SnapshotUpdate {
-	su.mutation.Where(ps...)
-	return su
+func (_u *SnapshotUpdate) Where(ps ...predicate.Snapshot) *SnapshotUpdate {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // SetSnapshotID sets the "snapshot_id" field.
-func (su *SnapshotUpdate) SetSnapshotID(s string) *SnapshotUpdate {
-	su.mutation.SetSnapshotID(s)
-	return su
+func (_u *SnapshotUpdate) SetSnapshotID(v string) *SnapshotUpdate {
+	_u.mutation.SetSnapshotID(v)
+	return _u
 }
 
 // SetNillableSnapshotID sets the "snapshot_id" field if the given value is not nil.
-func (su *SnapshotUpdate) SetNillableSnapshotID(s *string) *SnapshotUpdate {
-	if s != nil {
-		su.SetSnapshotID(*s)
+func (_u *SnapshotUpdate) SetNillableSnapshotID(v *string) *SnapshotUpdate {
+	if v != nil {
+		_u.SetSnapshotID(*v)
 	}
-	return su
+	return _u
 }
 
 // SetRunID sets the "run_id" field.
-func (su *SnapshotUpdate) SetRunID(s string) *SnapshotUpdate {
-	su.mutation.SetRunID(s)
-	return su
+func (_u *SnapshotUpdate) SetRunID(v string) *SnapshotUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
 }
 
 // SetNillableRunID sets the "run_id" field if the given value is not nil.
-func (su *SnapshotUpdate) SetNillableRunID(s *string) *SnapshotUpdate {
-	if s != nil {
-		su.SetRunID(*s)
+func (_u *SnapshotUpdate) SetNillableRunID(v *string) *SnapshotUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
 	}
-	return su
+	return _u
 }
 
 // SetUptoSeq sets the "upto_seq" field.
-func (su *SnapshotUpdate) SetUptoSeq(i int64) *SnapshotUpdate {
-	su.mutation.ResetUptoSeq()
-	su.mutation.SetUptoSeq(i)
-	return su
+func (_u *SnapshotUpdate) SetUptoSeq(v int64) *SnapshotUpdate {
+	_u.mutation.ResetUptoSeq()
+	_u.mutation.SetUptoSeq(v)
+	return _u
 }
 
 // SetNillableUptoSeq sets the "upto_seq" field if the given value is not nil.
-func (su *SnapshotUpdate) SetNillableUptoSeq(i *int64) *SnapshotUpdate {
-	if i != nil {
-		su.SetUptoSeq(*i)
+func (_u *SnapshotUpdate) SetNillableUptoSeq(v *int64) *SnapshotUpdate {
+	if v != nil {
+		_u.SetUptoSeq(*v)
 	}
-	return su
+	return _u
 }
 
-// AddUptoSeq adds i to the "upto_seq" field.
-func (su *SnapshotUpdate) AddUptoSeq(i int64) *SnapshotUpdate {
-	su.mutation.AddUptoSeq(i)
-	return su
+// AddUptoSeq adds value to the "upto_seq" field.
+func (_u *SnapshotUpdate) AddUptoSeq(v int64) *SnapshotUpdate {
+	_u.mutation.AddUptoSeq(v)
+	return _u
 }
 
 // SetState sets the "state" field.
-func (su *SnapshotUpdate) SetState(m map[string]interface{}) *SnapshotUpdate {
-	su.mutation.SetState(m)
-	return su
+func (_u *SnapshotUpdate) SetState(v string) *SnapshotUpdate {
+	_u.mutation.SetState(v)
+	return _u
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_u *SnapshotUpdate) SetNillableState(v *string) *SnapshotUpdate {
+	if v != nil {
+		_u.SetState(*v)
+	}
+	return _u
 }
 
 // ClearState clears the value of the "state" field.
-func (su *SnapshotUpdate) ClearState() *SnapshotUpdate {
-	su.mutation.ClearState()
-	return su
+func (_u *SnapshotUpdate) ClearState() *SnapshotUpdate {
+	_u.mutation.ClearState()
+	return _u
+}
+
+// SetKeyID sets the "key_id" field.
+func (_u *SnapshotUpdate) SetKeyID(v string) *SnapshotUpdate {
+	_u.mutation.SetKeyID(v)
+	return _u
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_u *SnapshotUpdate) SetNillableKeyID(v *string) *SnapshotUpdate {
+	if v != nil {
+		_u.SetKeyID(*v)
+	}
+	return _u
+}
+
+// SetVersion sets the "version" field.
+func (_u *SnapshotUpdate) SetVersion(v string) *SnapshotUpdate {
+	_u.mutation.SetVersion(v)
+	return _u
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_u *SnapshotUpdate) SetNillableVersion(v *string) *SnapshotUpdate {
+	if v != nil {
+		_u.SetVersion(*v)
+	}
+	return _u
+}
+
+// SetHash sets the "hash" field.
+func (_u *SnapshotUpdate) SetHash(v string) *SnapshotUpdate {
+	_u.mutation.SetHash(v)
+	return _u
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (_u *SnapshotUpdate) SetNillableHash(v *string) *SnapshotUpdate {
+	if v != nil {
+		_u.SetHash(*v)
+	}
+	return _u
 }
 
 // Mutation returns the SnapshotMutation object of the builder.
-func (su *SnapshotUpdate) Mutation() *SnapshotMutation {
-	return su.mutation
+func (_u *SnapshotUpdate) Mutation() *SnapshotMutation {
+	return _u.mutation
 }
 
 // Save executes the query and returns the number of nodes affected by the update operation.
-func (su *SnapshotUpdate) Save(ctx context.Context) (int, error) {
-	return withHooks(ctx, su.sqlSave, su.mutation, su.hooks)
+func (_u *SnapshotUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (su *SnapshotUpdate) SaveX(ctx context.Context) int {
-	affected, err := su.Save(ctx)
+func (_u *SnapshotUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -108,31 +158,31 @@ func (su *SnapshotUpdate) SaveX(ctx context.Context) int {
 }
 
 // Exec executes the query.
-func (su *SnapshotUpdate) Exec(ctx context.Context) error {
-	_, err := su.Save(ctx)
+func (_u *SnapshotUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (su *SnapshotUpdate) ExecX(ctx context.Context) {
-	if err := su.Exec(ctx); err != nil {
+func (_u *SnapshotUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (su *SnapshotUpdate) check() error {
-	if v, ok := su.mutation.SnapshotID(); ok {
+func (_u *SnapshotUpdate) check() error {
+	if v, ok := _u.mutation.SnapshotID(); ok {
 		if err := snapshot.SnapshotIDValidator(v); err != nil {
 			return &ValidationError{Name: "snapshot_id", err: fmt.Errorf(`ent: validator failed for field "Snapshot.snapshot_id": %w`, err)}
 		}
 	}
-	if v, ok := su.mutation.RunID(); ok {
+	if v, ok := _u.mutation.RunID(); ok {
 		if err := snapshot.RunIDValidator(v); err != nil {
 			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Snapshot.run_id": %w`, err)}
 		}
 	}
-	if v, ok := su.mutation.UptoSeq(); ok {
+	if v, ok := _u.mutation.UptoSeq(); ok {
 		if err := snapshot.UptoSeqValidator(v); err != nil {
 			return &ValidationError{Name: "upto_seq", err: fmt.Errorf(`ent: validator failed for field "Snapshot.upto_seq": %w`, err)}
 		}
@@ -140,37 +190,46 @@ func (su *SnapshotUpdate) check() error {
 	return nil
 }
 
-func (su *SnapshotUpdate) sqlSave(ctx context.Context) (n int, err error) {
-	if err := su.check(); err != nil {
-		return n, err
+func (_u *SnapshotUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
 	}
 	_spec := sqlgraph.NewUpdateSpec(snapshot.Table, snapshot.Columns, sqlgraph.NewFieldSpec(snapshot.FieldID, field.TypeInt))
-	if ps := su.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if value, ok := su.mutation.SnapshotID(); ok {
+	if value, ok := _u.mutation.SnapshotID(); ok {
 		_spec.SetField(snapshot.FieldSnapshotID, field.TypeString, value)
 	}
-	if value, ok := su.mutation.RunID(); ok {
+	if value, ok := _u.mutation.RunID(); ok {
 		_spec.SetField(snapshot.FieldRunID, field.TypeString, value)
 	}
-	if value, ok := su.mutation.UptoSeq(); ok {
+	if value, ok := _u.mutation.UptoSeq(); ok {
 		_spec.SetField(snapshot.FieldUptoSeq, field.TypeInt64, value)
 	}
-	if value, ok := su.mutation.AddedUptoSeq(); ok {
+	if value, ok := _u.mutation.AddedUptoSeq(); ok {
 		_spec.AddField(snapshot.FieldUptoSeq, field.TypeInt64, value)
 	}
-	if value, ok := su.mutation.State(); ok {
-		_spec.SetField(snapshot.FieldState, field.TypeJSON, value)
+	if value, ok := _u.mutation.State(); ok {
+		_spec.SetField(snapshot.FieldState, field.TypeString, value)
+	}
+	if _u.mutation.StateCleared() {
+		_spec.ClearField(snapshot.FieldState, field.TypeString)
+	}
+	if value, ok := _u.mutation.KeyID(); ok {
+		_spec.SetField(snapshot.FieldKeyID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Version(); ok {
+		_spec.SetField(snapshot.FieldVersion, field.TypeString, value)
 	}
-	if su.mutation.StateCleared() {
-		_spec.ClearField(snapshot.FieldState, field.TypeJSON)
+	if value, ok := _u.mutation.Hash(); ok {
+		_spec.SetField(snapshot.FieldHash, field.TypeString, value)
 	}
-	if n, err = sqlgraph.UpdateNodes(ctx, su.driver, _spec); err != nil {
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{snapshot.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -178,8 +237,8 @@ func (su *SnapshotUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		return 0, err
 	}
-	su.mutation.done = true
-	return n, nil
+	_u.mutation.done = true
+	return _node, nil
 }
 
 // SnapshotUpdateOne is the builder for updating a single Snapshot entity.
@@ -191,92 +250,142 @@ type SnapshotUpdateOne struct {
 }
 
 // SetSnapshotID sets the "snapshot_id" field.
-func (suo *SnapshotUpdateOne) SetSnapshotID(s string) *SnapshotUpdateOne {
-	suo.mutation.SetSnapshotID(s)
-	return suo
+func (_u *SnapshotUpdateOne) SetSnapshotID(v string) *SnapshotUpdateOne {
+	_u.mutation.SetSnapshotID(v)
+	return _u
 }
 
 // SetNillableSnapshotID sets the "snapshot_id" field if the given value is not nil.
-func (suo *SnapshotUpdateOne) SetNillableSnapshotID(s *string) *SnapshotUpdateOne {
-	if s != nil {
-		suo.SetSnapshotID(*s)
+func (_u *SnapshotUpdateOne) SetNillableSnapshotID(v *string) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetSnapshotID(*v)
 	}
-	return suo
+	return _u
 }
 
 // SetRunID sets the "run_id" field.
-func (suo *SnapshotUpdateOne) SetRunID(s string) *SnapshotUpdateOne {
-	suo.mutation.SetRunID(s)
-	return suo
+func (_u *SnapshotUpdateOne) SetRunID(v string) *SnapshotUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
 }
 
 // SetNillableRunID sets the "run_id" field if the given value is not nil.
-func (suo *SnapshotUpdateOne) SetNillableRunID(s *string) *SnapshotUpdateOne {
-	if s != nil {
-		suo.SetRunID(*s)
+func (_u *SnapshotUpdateOne) SetNillableRunID(v *string) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
 	}
-	return suo
+	return _u
 }
 
 // SetUptoSeq sets the "upto_seq" field.
-func (suo *SnapshotUpdateOne) SetUptoSeq(i int64) *SnapshotUpdateOne {
-	suo.mutation.ResetUptoSeq()
-	suo.mutation.SetUptoSeq(i)
-	return suo
+func (_u *SnapshotUpdateOne) SetUptoSeq(v int64) *SnapshotUpdateOne {
+	_u.mutation.ResetUptoSeq()
+	_u.mutation.SetUptoSeq(v)
+	return _u
 }
 
 // SetNillableUptoSeq sets the "upto_seq" field if the given value is not nil.
-func (suo *SnapshotUpdateOne) SetNillableUptoSeq(i *int64) *SnapshotUpdateOne {
-	if i != nil {
-		suo.SetUptoSeq(*i)
+func (_u *SnapshotUpdateOne) SetNillableUptoSeq(v *int64) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetUptoSeq(*v)
 	}
-	return suo
+	return _u
 }
 
-// AddUptoSeq adds i to the "upto_seq" field.
-func (suo *SnapshotUpdateOne) AddUptoSeq(i int64) *SnapshotUpdateOne {
-	suo.mutation.AddUptoSeq(i)
-	return suo
+// AddUptoSeq adds value to the "upto_seq" field.
+func (_u *SnapshotUpdateOne) AddUptoSeq(v int64) *SnapshotUpdateOne {
+	_u.mutation.AddUptoSeq(v)
+	return _u
 }
 
 // SetState sets the "state" field.
-func (suo *SnapshotUpdateOne) SetState(m map[string]interface{}) *SnapshotUpdateOne {
-	suo.mutation.SetState(m)
-	return suo
+func (_u *SnapshotUpdateOne) SetState(v string) *SnapshotUpdateOne {
+	_u.mutation.SetState(v)
+	return _u
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_u *SnapshotUpdateOne) SetNillableState(v *string) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetState(*v)
+	}
+	return _u
 }
 
 // ClearState clears the value of the "state" field.
-func (suo *SnapshotUpdateOne) ClearState() *SnapshotUpdateOne {
-	suo.mutation.ClearState()
-	return suo
+func (_u *SnapshotUpdateOne) ClearState() *SnapshotUpdateOne {
+	_u.mutation.ClearState()
+	return _u
+}
+
+// SetKeyID sets the "key_id" field.
+func (_u *SnapshotUpdateOne) SetKeyID(v string) *SnapshotUpdateOne {
+	_u.mutation.SetKeyID(v)
+	return _u
+}
+
+// SetNillableKeyID sets the "key_id" field if the given value is not nil.
+func (_u *SnapshotUpdateOne) SetNillableKeyID(v *string) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetKeyID(*v)
+	}
+	return _u
+}
+
+// SetVersion sets the "version" field.
+func (_u *SnapshotUpdateOne) SetVersion(v string) *SnapshotUpdateOne {
+	_u.mutation.SetVersion(v)
+	return _u
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_u *SnapshotUpdateOne) SetNillableVersion(v *string) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetVersion(*v)
+	}
+	return _u
+}
+
+// SetHash sets the "hash" field.
+func (_u *SnapshotUpdateOne) SetHash(v string) *SnapshotUpdateOne {
+	_u.mutation.SetHash(v)
+	return _u
+}
+
+// SetNillableHash sets the "hash" field if the given value is not nil.
+func (_u *SnapshotUpdateOne) SetNillableHash(v *string) *SnapshotUpdateOne {
+	if v != nil {
+		_u.SetHash(*v)
+	}
+	return _u
 }
 
 // Mutation returns the SnapshotMutation object of the builder.
-func (suo *SnapshotUpdateOne) Mutation() *SnapshotMutation {
-	return suo.mutation
+func (_u *SnapshotUpdateOne) Mutation() *SnapshotMutation {
+	return _u.mutation
 }
 
 // Where appends a list predicates to the SnapshotUpdate builder.
-func (suo *SnapshotUpdateOne) Where(ps ...predicate.Snapshot) *SnapshotUpdateOne {
-	suo.mutation.Where(ps...)
-	return suo
+func (_u *SnapshotUpdateOne) Where(ps ...predicate.Snapshot) *SnapshotUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // Select allows selecting one or more fields (columns) of the returned entity.
 // The default is selecting all fields defined in the entity schema.
-func (suo *SnapshotUpdateOne) Select(field string, fields ...string) *SnapshotUpdateOne {
-	suo.fields = append([]string{field}, fields...)
-	return suo
+func (_u *SnapshotUpdateOne) Select(field string, fields ...string) *SnapshotUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
 }
 
 // Save executes the query and returns the updated Snapshot entity.
-func (suo *SnapshotUpdateOne) Save(ctx context.Context) (*Snapshot, error) {
-	return withHooks(ctx, suo.sqlSave, suo.mutation, suo.hooks)
+func (_u *SnapshotUpdateOne) Save(ctx context.Context) (*Snapshot, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (suo *SnapshotUpdateOne) SaveX(ctx context.Context) *Snapshot {
-	node, err := suo.Save(ctx)
+func (_u *SnapshotUpdateOne) SaveX(ctx context.Context) *Snapshot {
+	node, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -284,31 +393,31 @@ func (suo *SnapshotUpdateOne) SaveX(ctx context.Context) *Snapshot {
 }
 
 // Exec executes the query on the entity.
-func (suo *SnapshotUpdateOne) Exec(ctx context.Context) error {
-	_, err := suo.Save(ctx)
+func (_u *SnapshotUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (suo *SnapshotUpdateOne) ExecX(ctx context.Context) {
-	if err := suo.Exec(ctx); err != nil {
+func (_u *SnapshotUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (suo *SnapshotUpdateOne) check() error {
-	if v, ok := suo.mutation.SnapshotID(); ok {
+func (_u *SnapshotUpdateOne) check() error {
+	if v, ok := _u.mutation.SnapshotID(); ok {
 		if err := snapshot.SnapshotIDValidator(v); err != nil {
 			return &ValidationError{Name: "snapshot_id", err: fmt.Errorf(`ent: validator failed for field "Snapshot.snapshot_id": %w`, err)}
 		}
 	}
-	if v, ok := suo.mutation.RunID(); ok {
+	if v, ok := _u.mutation.RunID(); ok {
 		if err := snapshot.RunIDValidator(v); err != nil {
 			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Snapshot.run_id": %w`, err)}
 		}
 	}
-	if v, ok := suo.mutation.UptoSeq(); ok {
+	if v, ok := _u.mutation.UptoSeq(); ok {
 		if err := snapshot.UptoSeqValidator(v); err != nil {
 			return &ValidationError{Name: "upto_seq", err: fmt.Errorf(`ent: validator failed for field "Snapshot.upto_seq": %w`, err)}
 		}
@@ -316,17 +425,17 @@ func (suo *SnapshotUpdateOne) check() error {
 	return nil
 }
 
-func (suo *SnapshotUpdateOne) sqlSave(ctx context.Context) (_node *Snapshot, err error) {
-	if err := suo.check(); err != nil {
+func (_u *SnapshotUpdateOne) sqlSave(ctx context.Context) (_node *Snapshot, err error) {
+	if err := _u.check(); err != nil {
 		return _node, err
 	}
 	_spec := sqlgraph.NewUpdateSpec(snapshot.Table, snapshot.Columns, sqlgraph.NewFieldSpec(snapshot.FieldID, field.TypeInt))
-	id, ok := suo.mutation.ID()
+	id, ok := _u.mutation.ID()
 	if !ok {
 		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Snapshot.id" for update`)}
 	}
 	_spec.Node.ID.Value = id
-	if fields := suo.fields; len(fields) > 0 {
+	if fields := _u.fields; len(fields) > 0 {
 		_spec.Node.Columns = make([]string, 0, len(fields))
 		_spec.Node.Columns = append(_spec.Node.Columns, snapshot.FieldID)
 		for _, f := range fields {
@@ -338,35 +447,44 @@ func (suo *SnapshotUpdateOne) sqlSave(ctx context.Context) (_node *Snapshot, err
 			}
 		}
 	}
-	if ps := suo.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if value, ok := suo.mutation.SnapshotID(); ok {
+	if value, ok := _u.mutation.SnapshotID(); ok {
 		_spec.SetField(snapshot.FieldSnapshotID, field.TypeString, value)
 	}
-	if value, ok := suo.mutation.RunID(); ok {
+	if value, ok := _u.mutation.RunID(); ok {
 		_spec.SetField(snapshot.FieldRunID, field.TypeString, value)
 	}
-	if value, ok := suo.mutation.UptoSeq(); ok {
+	if value, ok := _u.mutation.UptoSeq(); ok {
 		_spec.SetField(snapshot.FieldUptoSeq, field.TypeInt64, value)
 	}
-	if value, ok := suo.mutation.AddedUptoSeq(); ok {
+	if value, ok := _u.mutation.AddedUptoSeq(); ok {
 		_spec.AddField(snapshot.FieldUptoSeq, field.TypeInt64, value)
 	}
-	if value, ok := suo.mutation.State(); ok {
-		_spec.SetField(snapshot.FieldState, field.TypeJSON, value)
+	if value, ok := _u.mutation.State(); ok {
+		_spec.SetField(snapshot.FieldState, field.TypeString, value)
+	}
+	if _u.mutation.StateCleared() {
+		_spec.ClearField(snapshot.FieldState, field.TypeString)
+	}
+	if value, ok := _u.mutation.KeyID(); ok {
+		_spec.SetField(snapshot.FieldKeyID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Version(); ok {
+		_spec.SetField(snapshot.FieldVersion, field.TypeString, value)
 	}
-	if suo.mutation.StateCleared() {
-		_spec.ClearField(snapshot.FieldState, field.TypeJSON)
+	if value, ok := _u.mutation.Hash(); ok {
+		_spec.SetField(snapshot.FieldHash, field.TypeString, value)
 	}
-	_node = &Snapshot{config: suo.config}
+	_node = &Snapshot{config: _u.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
-	if err = sqlgraph.UpdateNode(ctx, suo.driver, _spec); err != nil {
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{snapshot.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -374,6 +492,6 @@ func (suo *SnapshotUpdateOne) sqlSave(ctx context.Context) (_node *Snapshot, err
 		}
 		return nil, err
 	}
-	suo.mutation.done = true
+	_u.mutation.done = true
 	return _node, nil
 }
@@ -0,0 +1,125 @@
+// Code generated by ent, DO NOT EDIT.
+
+package timer
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the timer type in the database.
+	Label = "timer"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldTimerID holds the string denoting the timer_id field in the database.
+	FieldTimerID = "timer_id"
+	// FieldQueue holds the string denoting the queue field in the database.
+	FieldQueue = "queue"
+	// FieldRunID holds the string denoting the run_id field in the database.
+	FieldRunID = "run_id"
+	// FieldName holds the string denoting the name field in the database.
+	FieldName = "name"
+	// FieldPayload holds the string denoting the payload field in the database.
+	FieldPayload = "payload"
+	// FieldDueAt holds the string denoting the due_at field in the database.
+	FieldDueAt = "due_at"
+	// FieldAttempts holds the string denoting the attempts field in the database.
+	FieldAttempts = "attempts"
+	// FieldAvailableAt holds the string denoting the available_at field in the database.
+	FieldAvailableAt = "available_at"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// Table holds the table name of the timer in the database.
+	Table = "timers"
+)
+
+// Columns holds all SQL columns for timer fields.
+var Columns = []string{
+	FieldID,
+	FieldTimerID,
+	FieldQueue,
+	FieldRunID,
+	FieldName,
+	FieldPayload,
+	FieldDueAt,
+	FieldAttempts,
+	FieldAvailableAt,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// TimerIDValidator is a validator for the "timer_id" field. It is called by the builders before save.
+	TimerIDValidator func(string) error
+	// QueueValidator is a validator for the "queue" field. It is called by the builders before save.
+	QueueValidator func(string) error
+	// RunIDValidator is a validator for the "run_id" field. It is called by the builders before save.
+	RunIDValidator func(string) error
+	// DefaultAttempts holds the default value on creation for the "attempts" field.
+	DefaultAttempts int
+	// AttemptsValidator is a validator for the "attempts" field. It is called by the builders before save.
+	AttemptsValidator func(int) error
+	// DefaultAvailableAt holds the default value on creation for the "available_at" field.
+	DefaultAvailableAt int64
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// OrderOption defines the ordering options for the Timer queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByTimerID orders the results by the timer_id field.
+func ByTimerID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTimerID, opts...).ToFunc()
+}
+
+// ByQueue orders the results by the queue field.
+func ByQueue(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldQueue, opts...).ToFunc()
+}
+
+// ByRunID orders the results by the run_id field.
+func ByRunID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRunID, opts...).ToFunc()
+}
+
+// ByName orders the results by the name field.
+func ByName(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldName, opts...).ToFunc()
+}
+
+// ByDueAt orders the results by the due_at field.
+func ByDueAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDueAt, opts...).ToFunc()
+}
+
+// ByAttempts orders the results by the attempts field.
+func ByAttempts(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAttempts, opts...).ToFunc()
+}
+
+// ByAvailableAt orders the results by the available_at field.
+func ByAvailableAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAvailableAt, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
@@ -0,0 +1,550 @@
+// Code generated by ent, DO NOT EDIT.
+
+package timer
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/wilhg/orch/internal/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldID, id))
+}
+
+// TimerID applies equality check predicate on the "timer_id" field. It's identical to TimerIDEQ.
+func TimerID(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldTimerID, v))
+}
+
+// Queue applies equality check predicate on the "queue" field. It's identical to QueueEQ.
+func Queue(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldQueue, v))
+}
+
+// RunID applies equality check predicate on the "run_id" field. It's identical to RunIDEQ.
+func RunID(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldRunID, v))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldName, v))
+}
+
+// DueAt applies equality check predicate on the "due_at" field. It's identical to DueAtEQ.
+func DueAt(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldDueAt, v))
+}
+
+// Attempts applies equality check predicate on the "attempts" field. It's identical to AttemptsEQ.
+func Attempts(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldAttempts, v))
+}
+
+// AvailableAt applies equality check predicate on the "available_at" field. It's identical to AvailableAtEQ.
+func AvailableAt(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldAvailableAt, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// TimerIDEQ applies the EQ predicate on the "timer_id" field.
+func TimerIDEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldTimerID, v))
+}
+
+// TimerIDNEQ applies the NEQ predicate on the "timer_id" field.
+func TimerIDNEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldTimerID, v))
+}
+
+// TimerIDIn applies the In predicate on the "timer_id" field.
+func TimerIDIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldTimerID, vs...))
+}
+
+// TimerIDNotIn applies the NotIn predicate on the "timer_id" field.
+func TimerIDNotIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldTimerID, vs...))
+}
+
+// TimerIDGT applies the GT predicate on the "timer_id" field.
+func TimerIDGT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldTimerID, v))
+}
+
+// TimerIDGTE applies the GTE predicate on the "timer_id" field.
+func TimerIDGTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldTimerID, v))
+}
+
+// TimerIDLT applies the LT predicate on the "timer_id" field.
+func TimerIDLT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldTimerID, v))
+}
+
+// TimerIDLTE applies the LTE predicate on the "timer_id" field.
+func TimerIDLTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldTimerID, v))
+}
+
+// TimerIDContains applies the Contains predicate on the "timer_id" field.
+func TimerIDContains(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContains(FieldTimerID, v))
+}
+
+// TimerIDHasPrefix applies the HasPrefix predicate on the "timer_id" field.
+func TimerIDHasPrefix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasPrefix(FieldTimerID, v))
+}
+
+// TimerIDHasSuffix applies the HasSuffix predicate on the "timer_id" field.
+func TimerIDHasSuffix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasSuffix(FieldTimerID, v))
+}
+
+// TimerIDEqualFold applies the EqualFold predicate on the "timer_id" field.
+func TimerIDEqualFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEqualFold(FieldTimerID, v))
+}
+
+// TimerIDContainsFold applies the ContainsFold predicate on the "timer_id" field.
+func TimerIDContainsFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContainsFold(FieldTimerID, v))
+}
+
+// QueueEQ applies the EQ predicate on the "queue" field.
+func QueueEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldQueue, v))
+}
+
+// QueueNEQ applies the NEQ predicate on the "queue" field.
+func QueueNEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldQueue, v))
+}
+
+// QueueIn applies the In predicate on the "queue" field.
+func QueueIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldQueue, vs...))
+}
+
+// QueueNotIn applies the NotIn predicate on the "queue" field.
+func QueueNotIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldQueue, vs...))
+}
+
+// QueueGT applies the GT predicate on the "queue" field.
+func QueueGT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldQueue, v))
+}
+
+// QueueGTE applies the GTE predicate on the "queue" field.
+func QueueGTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldQueue, v))
+}
+
+// QueueLT applies the LT predicate on the "queue" field.
+func QueueLT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldQueue, v))
+}
+
+// QueueLTE applies the LTE predicate on the "queue" field.
+func QueueLTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldQueue, v))
+}
+
+// QueueContains applies the Contains predicate on the "queue" field.
+func QueueContains(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContains(FieldQueue, v))
+}
+
+// QueueHasPrefix applies the HasPrefix predicate on the "queue" field.
+func QueueHasPrefix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasPrefix(FieldQueue, v))
+}
+
+// QueueHasSuffix applies the HasSuffix predicate on the "queue" field.
+func QueueHasSuffix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasSuffix(FieldQueue, v))
+}
+
+// QueueEqualFold applies the EqualFold predicate on the "queue" field.
+func QueueEqualFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEqualFold(FieldQueue, v))
+}
+
+// QueueContainsFold applies the ContainsFold predicate on the "queue" field.
+func QueueContainsFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContainsFold(FieldQueue, v))
+}
+
+// RunIDEQ applies the EQ predicate on the "run_id" field.
+func RunIDEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldRunID, v))
+}
+
+// RunIDNEQ applies the NEQ predicate on the "run_id" field.
+func RunIDNEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldRunID, v))
+}
+
+// RunIDIn applies the In predicate on the "run_id" field.
+func RunIDIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldRunID, vs...))
+}
+
+// RunIDNotIn applies the NotIn predicate on the "run_id" field.
+func RunIDNotIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldRunID, vs...))
+}
+
+// RunIDGT applies the GT predicate on the "run_id" field.
+func RunIDGT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldRunID, v))
+}
+
+// RunIDGTE applies the GTE predicate on the "run_id" field.
+func RunIDGTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldRunID, v))
+}
+
+// RunIDLT applies the LT predicate on the "run_id" field.
+func RunIDLT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldRunID, v))
+}
+
+// RunIDLTE applies the LTE predicate on the "run_id" field.
+func RunIDLTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldRunID, v))
+}
+
+// RunIDContains applies the Contains predicate on the "run_id" field.
+func RunIDContains(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContains(FieldRunID, v))
+}
+
+// RunIDHasPrefix applies the HasPrefix predicate on the "run_id" field.
+func RunIDHasPrefix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasPrefix(FieldRunID, v))
+}
+
+// RunIDHasSuffix applies the HasSuffix predicate on the "run_id" field.
+func RunIDHasSuffix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasSuffix(FieldRunID, v))
+}
+
+// RunIDEqualFold applies the EqualFold predicate on the "run_id" field.
+func RunIDEqualFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEqualFold(FieldRunID, v))
+}
+
+// RunIDContainsFold applies the ContainsFold predicate on the "run_id" field.
+func RunIDContainsFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContainsFold(FieldRunID, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameIsNil applies the IsNil predicate on the "name" field.
+func NameIsNil() predicate.Timer {
+	return predicate.Timer(sql.FieldIsNull(FieldName))
+}
+
+// NameNotNil applies the NotNil predicate on the "name" field.
+func NameNotNil() predicate.Timer {
+	return predicate.Timer(sql.FieldNotNull(FieldName))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.Timer {
+	return predicate.Timer(sql.FieldContainsFold(FieldName, v))
+}
+
+// PayloadIsNil applies the IsNil predicate on the "payload" field.
+func PayloadIsNil() predicate.Timer {
+	return predicate.Timer(sql.FieldIsNull(FieldPayload))
+}
+
+// PayloadNotNil applies the NotNil predicate on the "payload" field.
+func PayloadNotNil() predicate.Timer {
+	return predicate.Timer(sql.FieldNotNull(FieldPayload))
+}
+
+// DueAtEQ applies the EQ predicate on the "due_at" field.
+func DueAtEQ(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldDueAt, v))
+}
+
+// DueAtNEQ applies the NEQ predicate on the "due_at" field.
+func DueAtNEQ(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldDueAt, v))
+}
+
+// DueAtIn applies the In predicate on the "due_at" field.
+func DueAtIn(vs ...int64) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldDueAt, vs...))
+}
+
+// DueAtNotIn applies the NotIn predicate on the "due_at" field.
+func DueAtNotIn(vs ...int64) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldDueAt, vs...))
+}
+
+// DueAtGT applies the GT predicate on the "due_at" field.
+func DueAtGT(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldDueAt, v))
+}
+
+// DueAtGTE applies the GTE predicate on the "due_at" field.
+func DueAtGTE(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldDueAt, v))
+}
+
+// DueAtLT applies the LT predicate on the "due_at" field.
+func DueAtLT(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldDueAt, v))
+}
+
+// DueAtLTE applies the LTE predicate on the "due_at" field.
+func DueAtLTE(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldDueAt, v))
+}
+
+// AttemptsEQ applies the EQ predicate on the "attempts" field.
+func AttemptsEQ(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldAttempts, v))
+}
+
+// AttemptsNEQ applies the NEQ predicate on the "attempts" field.
+func AttemptsNEQ(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldAttempts, v))
+}
+
+// AttemptsIn applies the In predicate on the "attempts" field.
+func AttemptsIn(vs ...int) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldAttempts, vs...))
+}
+
+// AttemptsNotIn applies the NotIn predicate on the "attempts" field.
+func AttemptsNotIn(vs ...int) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldAttempts, vs...))
+}
+
+// AttemptsGT applies the GT predicate on the "attempts" field.
+func AttemptsGT(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldAttempts, v))
+}
+
+// AttemptsGTE applies the GTE predicate on the "attempts" field.
+func AttemptsGTE(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldAttempts, v))
+}
+
+// AttemptsLT applies the LT predicate on the "attempts" field.
+func AttemptsLT(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldAttempts, v))
+}
+
+// AttemptsLTE applies the LTE predicate on the "attempts" field.
+func AttemptsLTE(v int) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldAttempts, v))
+}
+
+// AvailableAtEQ applies the EQ predicate on the "available_at" field.
+func AvailableAtEQ(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldAvailableAt, v))
+}
+
+// AvailableAtNEQ applies the NEQ predicate on the "available_at" field.
+func AvailableAtNEQ(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldAvailableAt, v))
+}
+
+// AvailableAtIn applies the In predicate on the "available_at" field.
+func AvailableAtIn(vs ...int64) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldAvailableAt, vs...))
+}
+
+// AvailableAtNotIn applies the NotIn predicate on the "available_at" field.
+func AvailableAtNotIn(vs ...int64) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldAvailableAt, vs...))
+}
+
+// AvailableAtGT applies the GT predicate on the "available_at" field.
+func AvailableAtGT(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldAvailableAt, v))
+}
+
+// AvailableAtGTE applies the GTE predicate on the "available_at" field.
+func AvailableAtGTE(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldAvailableAt, v))
+}
+
+// AvailableAtLT applies the LT predicate on the "available_at" field.
+func AvailableAtLT(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldAvailableAt, v))
+}
+
+// AvailableAtLTE applies the LTE predicate on the "available_at" field.
+func AvailableAtLTE(v int64) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldAvailableAt, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.Timer {
+	return predicate.Timer(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.Timer) predicate.Timer {
+	return predicate.Timer(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.Timer) predicate.Timer {
+	return predicate.Timer(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.Timer) predicate.Timer {
+	return predicate.Timer(sql.NotPredicates(p))
+}
@@ -0,0 +1,352 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/timer"
+)
+
+// TimerCreate is the builder for creating a Timer entity.
+type TimerCreate struct {
+	config
+	mutation *TimerMutation
+	hooks    []Hook
+}
+
+// SetTimerID sets the "timer_id" field.
+func (_c *TimerCreate) SetTimerID(v string) *TimerCreate {
+	_c.mutation.SetTimerID(v)
+	return _c
+}
+
+// SetQueue sets the "queue" field.
+func (_c *TimerCreate) SetQueue(v string) *TimerCreate {
+	_c.mutation.SetQueue(v)
+	return _c
+}
+
+// SetRunID sets the "run_id" field.
+func (_c *TimerCreate) SetRunID(v string) *TimerCreate {
+	_c.mutation.SetRunID(v)
+	return _c
+}
+
+// SetName sets the "name" field.
+func (_c *TimerCreate) SetName(v string) *TimerCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_c *TimerCreate) SetNillableName(v *string) *TimerCreate {
+	if v != nil {
+		_c.SetName(*v)
+	}
+	return _c
+}
+
+// SetPayload sets the "payload" field.
+func (_c *TimerCreate) SetPayload(v map[string]interface{}) *TimerCreate {
+	_c.mutation.SetPayload(v)
+	return _c
+}
+
+// SetDueAt sets the "due_at" field.
+func (_c *TimerCreate) SetDueAt(v int64) *TimerCreate {
+	_c.mutation.SetDueAt(v)
+	return _c
+}
+
+// SetAttempts sets the "attempts" field.
+func (_c *TimerCreate) SetAttempts(v int) *TimerCreate {
+	_c.mutation.SetAttempts(v)
+	return _c
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_c *TimerCreate) SetNillableAttempts(v *int) *TimerCreate {
+	if v != nil {
+		_c.SetAttempts(*v)
+	}
+	return _c
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (_c *TimerCreate) SetAvailableAt(v int64) *TimerCreate {
+	_c.mutation.SetAvailableAt(v)
+	return _c
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (_c *TimerCreate) SetNillableAvailableAt(v *int64) *TimerCreate {
+	if v != nil {
+		_c.SetAvailableAt(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *TimerCreate) SetCreatedAt(v time.Time) *TimerCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *TimerCreate) SetNillableCreatedAt(v *time.Time) *TimerCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the TimerMutation object of the builder.
+func (_c *TimerCreate) Mutation() *TimerMutation {
+	return _c.mutation
+}
+
+// Save creates the Timer in the database.
+func (_c *TimerCreate) Save(ctx context.Context) (*Timer, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *TimerCreate) SaveX(ctx context.Context) *Timer {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *TimerCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *TimerCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *TimerCreate) defaults() {
+	if _, ok := _c.mutation.Attempts(); !ok {
+		v := timer.DefaultAttempts
+		_c.mutation.SetAttempts(v)
+	}
+	if _, ok := _c.mutation.AvailableAt(); !ok {
+		v := timer.DefaultAvailableAt
+		_c.mutation.SetAvailableAt(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := timer.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *TimerCreate) check() error {
+	if _, ok := _c.mutation.TimerID(); !ok {
+		return &ValidationError{Name: "timer_id", err: errors.New(`ent: missing required field "Timer.timer_id"`)}
+	}
+	if v, ok := _c.mutation.TimerID(); ok {
+		if err := timer.TimerIDValidator(v); err != nil {
+			return &ValidationError{Name: "timer_id", err: fmt.Errorf(`ent: validator failed for field "Timer.timer_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Queue(); !ok {
+		return &ValidationError{Name: "queue", err: errors.New(`ent: missing required field "Timer.queue"`)}
+	}
+	if v, ok := _c.mutation.Queue(); ok {
+		if err := timer.QueueValidator(v); err != nil {
+			return &ValidationError{Name: "queue", err: fmt.Errorf(`ent: validator failed for field "Timer.queue": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.RunID(); !ok {
+		return &ValidationError{Name: "run_id", err: errors.New(`ent: missing required field "Timer.run_id"`)}
+	}
+	if v, ok := _c.mutation.RunID(); ok {
+		if err := timer.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Timer.run_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.DueAt(); !ok {
+		return &ValidationError{Name: "due_at", err: errors.New(`ent: missing required field "Timer.due_at"`)}
+	}
+	if _, ok := _c.mutation.Attempts(); !ok {
+		return &ValidationError{Name: "attempts", err: errors.New(`ent: missing required field "Timer.attempts"`)}
+	}
+	if v, ok := _c.mutation.Attempts(); ok {
+		if err := timer.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "Timer.attempts": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.AvailableAt(); !ok {
+		return &ValidationError{Name: "available_at", err: errors.New(`ent: missing required field "Timer.available_at"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Timer.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *TimerCreate) sqlSave(ctx context.Context) (*Timer, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *TimerCreate) createSpec() (*Timer, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Timer{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(timer.Table, sqlgraph.NewFieldSpec(timer.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.TimerID(); ok {
+		_spec.SetField(timer.FieldTimerID, field.TypeString, value)
+		_node.TimerID = value
+	}
+	if value, ok := _c.mutation.Queue(); ok {
+		_spec.SetField(timer.FieldQueue, field.TypeString, value)
+		_node.Queue = value
+	}
+	if value, ok := _c.mutation.RunID(); ok {
+		_spec.SetField(timer.FieldRunID, field.TypeString, value)
+		_node.RunID = value
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(timer.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.Payload(); ok {
+		_spec.SetField(timer.FieldPayload, field.TypeJSON, value)
+		_node.Payload = value
+	}
+	if value, ok := _c.mutation.DueAt(); ok {
+		_spec.SetField(timer.FieldDueAt, field.TypeInt64, value)
+		_node.DueAt = value
+	}
+	if value, ok := _c.mutation.Attempts(); ok {
+		_spec.SetField(timer.FieldAttempts, field.TypeInt, value)
+		_node.Attempts = value
+	}
+	if value, ok := _c.mutation.AvailableAt(); ok {
+		_spec.SetField(timer.FieldAvailableAt, field.TypeInt64, value)
+		_node.AvailableAt = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(timer.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// TimerCreateBulk is the builder for creating many Timer entities in bulk.
+type TimerCreateBulk struct {
+	config
+	err      error
+	builders []*TimerCreate
+}
+
+// Save creates the Timer entities in the database.
+func (_c *TimerCreateBulk) Save(ctx context.Context) ([]*Timer, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*Timer, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*TimerMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *TimerCreateBulk) SaveX(ctx context.Context) []*Timer {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *TimerCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *TimerCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
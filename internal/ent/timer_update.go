@@ -0,0 +1,583 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/wilhg/orch/internal/ent/predicate"
+	"github.com/wilhg/orch/internal/ent/timer"
+)
+
+// TimerUpdate is the builder for updating Timer entities.
+type TimerUpdate struct {
+	config
+	hooks    []Hook
+	mutation *TimerMutation
+}
+
+// Where appends a list predicates to the TimerUpdate builder.
+func (_u *TimerUpdate) Where(ps ...predicate.Timer) *TimerUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetTimerID sets the "timer_id" field.
+func (_u *TimerUpdate) SetTimerID(v string) *TimerUpdate {
+	_u.mutation.SetTimerID(v)
+	return _u
+}
+
+// SetNillableTimerID sets the "timer_id" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableTimerID(v *string) *TimerUpdate {
+	if v != nil {
+		_u.SetTimerID(*v)
+	}
+	return _u
+}
+
+// SetQueue sets the "queue" field.
+func (_u *TimerUpdate) SetQueue(v string) *TimerUpdate {
+	_u.mutation.SetQueue(v)
+	return _u
+}
+
+// SetNillableQueue sets the "queue" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableQueue(v *string) *TimerUpdate {
+	if v != nil {
+		_u.SetQueue(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *TimerUpdate) SetRunID(v string) *TimerUpdate {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableRunID(v *string) *TimerUpdate {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *TimerUpdate) SetName(v string) *TimerUpdate {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableName(v *string) *TimerUpdate {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// ClearName clears the value of the "name" field.
+func (_u *TimerUpdate) ClearName() *TimerUpdate {
+	_u.mutation.ClearName()
+	return _u
+}
+
+// SetPayload sets the "payload" field.
+func (_u *TimerUpdate) SetPayload(v map[string]interface{}) *TimerUpdate {
+	_u.mutation.SetPayload(v)
+	return _u
+}
+
+// ClearPayload clears the value of the "payload" field.
+func (_u *TimerUpdate) ClearPayload() *TimerUpdate {
+	_u.mutation.ClearPayload()
+	return _u
+}
+
+// SetDueAt sets the "due_at" field.
+func (_u *TimerUpdate) SetDueAt(v int64) *TimerUpdate {
+	_u.mutation.ResetDueAt()
+	_u.mutation.SetDueAt(v)
+	return _u
+}
+
+// SetNillableDueAt sets the "due_at" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableDueAt(v *int64) *TimerUpdate {
+	if v != nil {
+		_u.SetDueAt(*v)
+	}
+	return _u
+}
+
+// AddDueAt adds value to the "due_at" field.
+func (_u *TimerUpdate) AddDueAt(v int64) *TimerUpdate {
+	_u.mutation.AddDueAt(v)
+	return _u
+}
+
+// SetAttempts sets the "attempts" field.
+func (_u *TimerUpdate) SetAttempts(v int) *TimerUpdate {
+	_u.mutation.ResetAttempts()
+	_u.mutation.SetAttempts(v)
+	return _u
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableAttempts(v *int) *TimerUpdate {
+	if v != nil {
+		_u.SetAttempts(*v)
+	}
+	return _u
+}
+
+// AddAttempts adds value to the "attempts" field.
+func (_u *TimerUpdate) AddAttempts(v int) *TimerUpdate {
+	_u.mutation.AddAttempts(v)
+	return _u
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (_u *TimerUpdate) SetAvailableAt(v int64) *TimerUpdate {
+	_u.mutation.ResetAvailableAt()
+	_u.mutation.SetAvailableAt(v)
+	return _u
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (_u *TimerUpdate) SetNillableAvailableAt(v *int64) *TimerUpdate {
+	if v != nil {
+		_u.SetAvailableAt(*v)
+	}
+	return _u
+}
+
+// AddAvailableAt adds value to the "available_at" field.
+func (_u *TimerUpdate) AddAvailableAt(v int64) *TimerUpdate {
+	_u.mutation.AddAvailableAt(v)
+	return _u
+}
+
+// Mutation returns the TimerMutation object of the builder.
+func (_u *TimerUpdate) Mutation() *TimerMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *TimerUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *TimerUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *TimerUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *TimerUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *TimerUpdate) check() error {
+	if v, ok := _u.mutation.TimerID(); ok {
+		if err := timer.TimerIDValidator(v); err != nil {
+			return &ValidationError{Name: "timer_id", err: fmt.Errorf(`ent: validator failed for field "Timer.timer_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Queue(); ok {
+		if err := timer.QueueValidator(v); err != nil {
+			return &ValidationError{Name: "queue", err: fmt.Errorf(`ent: validator failed for field "Timer.queue": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := timer.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Timer.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Attempts(); ok {
+		if err := timer.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "Timer.attempts": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *TimerUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(timer.Table, timer.Columns, sqlgraph.NewFieldSpec(timer.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.TimerID(); ok {
+		_spec.SetField(timer.FieldTimerID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Queue(); ok {
+		_spec.SetField(timer.FieldQueue, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(timer.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(timer.FieldName, field.TypeString, value)
+	}
+	if _u.mutation.NameCleared() {
+		_spec.ClearField(timer.FieldName, field.TypeString)
+	}
+	if value, ok := _u.mutation.Payload(); ok {
+		_spec.SetField(timer.FieldPayload, field.TypeJSON, value)
+	}
+	if _u.mutation.PayloadCleared() {
+		_spec.ClearField(timer.FieldPayload, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.DueAt(); ok {
+		_spec.SetField(timer.FieldDueAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedDueAt(); ok {
+		_spec.AddField(timer.FieldDueAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.Attempts(); ok {
+		_spec.SetField(timer.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAttempts(); ok {
+		_spec.AddField(timer.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AvailableAt(); ok {
+		_spec.SetField(timer.FieldAvailableAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAvailableAt(); ok {
+		_spec.AddField(timer.FieldAvailableAt, field.TypeInt64, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{timer.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// TimerUpdateOne is the builder for updating a single Timer entity.
+type TimerUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *TimerMutation
+}
+
+// SetTimerID sets the "timer_id" field.
+func (_u *TimerUpdateOne) SetTimerID(v string) *TimerUpdateOne {
+	_u.mutation.SetTimerID(v)
+	return _u
+}
+
+// SetNillableTimerID sets the "timer_id" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableTimerID(v *string) *TimerUpdateOne {
+	if v != nil {
+		_u.SetTimerID(*v)
+	}
+	return _u
+}
+
+// SetQueue sets the "queue" field.
+func (_u *TimerUpdateOne) SetQueue(v string) *TimerUpdateOne {
+	_u.mutation.SetQueue(v)
+	return _u
+}
+
+// SetNillableQueue sets the "queue" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableQueue(v *string) *TimerUpdateOne {
+	if v != nil {
+		_u.SetQueue(*v)
+	}
+	return _u
+}
+
+// SetRunID sets the "run_id" field.
+func (_u *TimerUpdateOne) SetRunID(v string) *TimerUpdateOne {
+	_u.mutation.SetRunID(v)
+	return _u
+}
+
+// SetNillableRunID sets the "run_id" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableRunID(v *string) *TimerUpdateOne {
+	if v != nil {
+		_u.SetRunID(*v)
+	}
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *TimerUpdateOne) SetName(v string) *TimerUpdateOne {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableName(v *string) *TimerUpdateOne {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// ClearName clears the value of the "name" field.
+func (_u *TimerUpdateOne) ClearName() *TimerUpdateOne {
+	_u.mutation.ClearName()
+	return _u
+}
+
+// SetPayload sets the "payload" field.
+func (_u *TimerUpdateOne) SetPayload(v map[string]interface{}) *TimerUpdateOne {
+	_u.mutation.SetPayload(v)
+	return _u
+}
+
+// ClearPayload clears the value of the "payload" field.
+func (_u *TimerUpdateOne) ClearPayload() *TimerUpdateOne {
+	_u.mutation.ClearPayload()
+	return _u
+}
+
+// SetDueAt sets the "due_at" field.
+func (_u *TimerUpdateOne) SetDueAt(v int64) *TimerUpdateOne {
+	_u.mutation.ResetDueAt()
+	_u.mutation.SetDueAt(v)
+	return _u
+}
+
+// SetNillableDueAt sets the "due_at" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableDueAt(v *int64) *TimerUpdateOne {
+	if v != nil {
+		_u.SetDueAt(*v)
+	}
+	return _u
+}
+
+// AddDueAt adds value to the "due_at" field.
+func (_u *TimerUpdateOne) AddDueAt(v int64) *TimerUpdateOne {
+	_u.mutation.AddDueAt(v)
+	return _u
+}
+
+// SetAttempts sets the "attempts" field.
+func (_u *TimerUpdateOne) SetAttempts(v int) *TimerUpdateOne {
+	_u.mutation.ResetAttempts()
+	_u.mutation.SetAttempts(v)
+	return _u
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableAttempts(v *int) *TimerUpdateOne {
+	if v != nil {
+		_u.SetAttempts(*v)
+	}
+	return _u
+}
+
+// AddAttempts adds value to the "attempts" field.
+func (_u *TimerUpdateOne) AddAttempts(v int) *TimerUpdateOne {
+	_u.mutation.AddAttempts(v)
+	return _u
+}
+
+// SetAvailableAt sets the "available_at" field.
+func (_u *TimerUpdateOne) SetAvailableAt(v int64) *TimerUpdateOne {
+	_u.mutation.ResetAvailableAt()
+	_u.mutation.SetAvailableAt(v)
+	return _u
+}
+
+// SetNillableAvailableAt sets the "available_at" field if the given value is not nil.
+func (_u *TimerUpdateOne) SetNillableAvailableAt(v *int64) *TimerUpdateOne {
+	if v != nil {
+		_u.SetAvailableAt(*v)
+	}
+	return _u
+}
+
+// AddAvailableAt adds value to the "available_at" field.
+func (_u *TimerUpdateOne) AddAvailableAt(v int64) *TimerUpdateOne {
+	_u.mutation.AddAvailableAt(v)
+	return _u
+}
+
+// Mutation returns the TimerMutation object of the builder.
+func (_u *TimerUpdateOne) Mutation() *TimerMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the TimerUpdate builder.
+func (_u *TimerUpdateOne) Where(ps ...predicate.Timer) *TimerUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *TimerUpdateOne) Select(field string, fields ...string) *TimerUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated Timer entity.
+func (_u *TimerUpdateOne) Save(ctx context.Context) (*Timer, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *TimerUpdateOne) SaveX(ctx context.Context) *Timer {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *TimerUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *TimerUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *TimerUpdateOne) check() error {
+	if v, ok := _u.mutation.TimerID(); ok {
+		if err := timer.TimerIDValidator(v); err != nil {
+			return &ValidationError{Name: "timer_id", err: fmt.Errorf(`ent: validator failed for field "Timer.timer_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Queue(); ok {
+		if err := timer.QueueValidator(v); err != nil {
+			return &ValidationError{Name: "queue", err: fmt.Errorf(`ent: validator failed for field "Timer.queue": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RunID(); ok {
+		if err := timer.RunIDValidator(v); err != nil {
+			return &ValidationError{Name: "run_id", err: fmt.Errorf(`ent: validator failed for field "Timer.run_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Attempts(); ok {
+		if err := timer.AttemptsValidator(v); err != nil {
+			return &ValidationError{Name: "attempts", err: fmt.Errorf(`ent: validator failed for field "Timer.attempts": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *TimerUpdateOne) sqlSave(ctx context.Context) (_node *Timer, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(timer.Table, timer.Columns, sqlgraph.NewFieldSpec(timer.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Timer.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, timer.FieldID)
+		for _, f := range fields {
+			if !timer.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != timer.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.TimerID(); ok {
+		_spec.SetField(timer.FieldTimerID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Queue(); ok {
+		_spec.SetField(timer.FieldQueue, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RunID(); ok {
+		_spec.SetField(timer.FieldRunID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(timer.FieldName, field.TypeString, value)
+	}
+	if _u.mutation.NameCleared() {
+		_spec.ClearField(timer.FieldName, field.TypeString)
+	}
+	if value, ok := _u.mutation.Payload(); ok {
+		_spec.SetField(timer.FieldPayload, field.TypeJSON, value)
+	}
+	if _u.mutation.PayloadCleared() {
+		_spec.ClearField(timer.FieldPayload, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.DueAt(); ok {
+		_spec.SetField(timer.FieldDueAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedDueAt(); ok {
+		_spec.AddField(timer.FieldDueAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.Attempts(); ok {
+		_spec.SetField(timer.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAttempts(); ok {
+		_spec.AddField(timer.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AvailableAt(); ok {
+		_spec.SetField(timer.FieldAvailableAt, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAvailableAt(); ok {
+		_spec.AddField(timer.FieldAvailableAt, field.TypeInt64, value)
+	}
+	_node = &Timer{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{timer.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
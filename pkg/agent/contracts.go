@@ -53,6 +53,10 @@ type Event struct {
 	// Common types include: "trigger", "tool_call", "state_change", "error"
 	Type string `json:"type"`
 
+	// Version is the version of the payload's schema for this Type, as declared in an
+	// EventRegistry. Zero means the current version.
+	Version int `json:"version,omitempty"`
+
 	// Timestamp records when the event occurred.
 	// Used for ordering events and debugging execution timelines.
 	Timestamp time.Time `json:"timestamp"`
@@ -106,6 +110,11 @@ type Intent struct {
 	// IdempotencyKey ensures the intent is executed only once.
 	// Used to prevent duplicate side effects during retries or replays.
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Parallel marks the intent as safe to execute concurrently with adjacent parallel intents.
+	// Its handler then sees the state as of the fan-out, not the effects of its siblings.
+	// Only honored by runners configured for parallel dispatch.
+	Parallel bool `json:"parallel,omitempty"`
 }
 
 // Reducer defines the pure function interface for state transformation.
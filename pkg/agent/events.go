@@ -0,0 +1,152 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// Upcaster transforms an event payload from one version to the next.
+type Upcaster func(payload any) (any, error)
+
+// EventSchema declares the current version of an event type, the JSON Schema its payload must
+// satisfy at that version, and how to bring payloads of older versions up to date.
+type EventSchema struct {
+	Type string
+	// Version is the current payload version, starting at 1.
+	Version int
+	// Schema is the JSON Schema of the current payload; empty accepts any payload.
+	Schema []byte
+	// Upcasters maps each older version to the function upgrading it to the next version.
+	Upcasters map[int]Upcaster
+	// Sensitive lists the top-level payload fields holding personal data. Runners configured
+	// for shredding store them encrypted with a key per run (see runtime.WithShredding).
+	Sensitive []string
+}
+
+// EventRegistry holds the schemas of event types. Types that are not registered are accepted
+// as they are.
+type EventRegistry struct {
+	mu    sync.RWMutex
+	types map[string]registeredEvent
+}
+
+type registeredEvent struct {
+	EventSchema
+	compiled *jsonschema.Schema
+}
+
+// NewEventRegistry returns an empty registry.
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{types: map[string]registeredEvent{}}
+}
+
+// Register adds an event type. Every version below the current one needs an upcaster.
+func (r *EventRegistry) Register(s EventSchema) error {
+	if s.Type == "" {
+		return fmt.Errorf("event type is empty")
+	}
+	if s.Version == 0 {
+		s.Version = 1
+	}
+	for v := 1; v < s.Version; v++ {
+		if s.Upcasters[v] == nil {
+			return fmt.Errorf("event %q: missing upcaster from version %d", s.Type, v)
+		}
+	}
+	reg := registeredEvent{EventSchema: s}
+	if len(s.Schema) > 0 {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(s.Schema))
+		if err != nil {
+			return fmt.Errorf("event %q: parse schema: %w", s.Type, err)
+		}
+		c := jsonschema.NewCompiler()
+		url := "mem://events/" + s.Type + ".json"
+		if err := c.AddResource(url, doc); err != nil {
+			return fmt.Errorf("event %q: %w", s.Type, err)
+		}
+		if reg.compiled, err = c.Compile(url); err != nil {
+			return fmt.Errorf("event %q: compile schema: %w", s.Type, err)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.types[s.Type]; exists {
+		return fmt.Errorf("event %q already registered", s.Type)
+	}
+	r.types[s.Type] = reg
+	return nil
+}
+
+// Lookup returns the schema of an event type.
+func (r *EventRegistry) Lookup(typ string) (EventSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[typ]
+	return t.EventSchema, ok
+}
+
+// Upcast brings the payload of a registered event to the current version. Events with version
+// 0 are taken to be current.
+func (r *EventRegistry) Upcast(ev Event) (Event, error) {
+	t, ok := r.lookup(ev.Type)
+	if !ok || ev.Version == 0 || ev.Version == t.Version {
+		return ev, nil
+	}
+	if ev.Version > t.Version {
+		return ev, errmodel.Validation("unknown_event_version", "event version is newer than the registered one", map[string]any{"type": ev.Type, "version": ev.Version, "current": t.Version})
+	}
+	for v := ev.Version; v < t.Version; v++ {
+		up := t.Upcasters[v]
+		if up == nil {
+			return ev, errmodel.Validation("unknown_event_version", "no upcaster for event version", map[string]any{"type": ev.Type, "version": v})
+		}
+		p, err := up(ev.Payload)
+		if err != nil {
+			return ev, errmodel.Validation("upcast_failed", "failed to upcast event", map[string]any{"type": ev.Type, "version": v, "error": err.Error()})
+		}
+		ev.Payload = p
+	}
+	ev.Version = t.Version
+	return ev, nil
+}
+
+// Prepare upcasts a new event, stamps it with the current version and validates its payload.
+func (r *EventRegistry) Prepare(ev Event) (Event, error) {
+	t, ok := r.lookup(ev.Type)
+	if !ok {
+		return ev, nil
+	}
+	ev, err := r.Upcast(ev)
+	if err != nil {
+		return ev, err
+	}
+	ev.Version = t.Version
+	if t.compiled == nil {
+		return ev, nil
+	}
+	// Validate the JSON form, as the payload is persisted and replayed.
+	b, err := json.Marshal(ev.Payload)
+	if err != nil {
+		return ev, errmodel.Validation("invalid_event", "event payload is not JSON serializable", map[string]any{"type": ev.Type, "error": err.Error()})
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return ev, errmodel.Validation("invalid_event", "event payload is not valid JSON", map[string]any{"type": ev.Type, "error": err.Error()})
+	}
+	if err := t.compiled.Validate(doc); err != nil {
+		return ev, errmodel.Validation("invalid_event", "event payload does not match its schema", map[string]any{"type": ev.Type, "version": t.Version, "error": err.Error()})
+	}
+	return ev, nil
+}
+
+func (r *EventRegistry) lookup(typ string) (registeredEvent, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[typ]
+	return t, ok
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+func TestEventRegistry_PrepareAndUpcast(t *testing.T) {
+	reg := NewEventRegistry()
+	// v1 payloads carried {"name"}; v2 renamed it to {"title"}.
+	rename := func(p any) (any, error) {
+		m, _ := p.(map[string]any)
+		return map[string]any{"title": m["name"]}, nil
+	}
+	if err := reg.Register(EventSchema{Type: "add", Version: 2}); err == nil {
+		t.Fatal("expected error for missing upcaster")
+	}
+	if err := reg.Register(EventSchema{
+		Type:      "add",
+		Version:   2,
+		Schema:    []byte(`{"type":"object","properties":{"title":{"type":"string"}},"required":["title"]}`),
+		Upcasters: map[int]Upcaster{1: rename},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(EventSchema{Type: "add"}); err == nil {
+		t.Fatal("expected error for duplicate type")
+	}
+
+	ev, err := reg.Prepare(Event{Type: "add", Payload: map[string]any{"title": "x"}})
+	if err != nil || ev.Version != 2 {
+		t.Fatalf("ev=%+v err=%v", ev, err)
+	}
+	if _, err := reg.Prepare(Event{Type: "add", Payload: map[string]any{"title": 1}}); !errmodel.HasCode(err, "invalid_event") {
+		t.Fatalf("err=%v want invalid_event", err)
+	}
+	// Old versions are upcast before validation; unknown types pass through.
+	if ev, err = reg.Prepare(Event{Type: "add", Version: 1, Payload: map[string]any{"name": "y"}}); err != nil || ev.Payload.(map[string]any)["title"] != "y" {
+		t.Fatalf("ev=%+v err=%v", ev, err)
+	}
+	if _, err := reg.Prepare(Event{Type: "other", Payload: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	ev, err = reg.Upcast(Event{Type: "add", Version: 1, Payload: map[string]any{"name": "z"}})
+	if err != nil || ev.Version != 2 || ev.Payload.(map[string]any)["title"] != "z" {
+		t.Fatalf("ev=%+v err=%v", ev, err)
+	}
+	if _, err := reg.Upcast(Event{Type: "add", Version: 3}); !errmodel.HasCode(err, "unknown_event_version") {
+		t.Fatalf("err=%v want unknown_event_version", err)
+	}
+}
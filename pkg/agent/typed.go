@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// HandlerFunc reduces an event whose payload has been decoded into P.
+type HandlerFunc[S State, P any] func(ctx context.Context, s S, ev Event, payload P) (S, []Intent, error)
+
+// TypedReducer is a Reducer over the concrete state type S that dispatches events by type to
+// handlers registered with On. Events without a handler leave the state unchanged.
+type TypedReducer[S State] struct {
+	handlers map[string]func(ctx context.Context, s S, ev Event) (S, []Intent, error)
+}
+
+// NewTypedReducer returns a TypedReducer without handlers.
+func NewTypedReducer[S State]() *TypedReducer[S] {
+	return &TypedReducer[S]{handlers: map[string]func(context.Context, S, Event) (S, []Intent, error){}}
+}
+
+// On registers fn for events of the given type, decoding their payload into P. A later call
+// for the same type replaces the handler. It is a function rather than a method because methods
+// cannot declare type parameters.
+func On[S State, P any](r *TypedReducer[S], typ string, fn HandlerFunc[S, P]) {
+	r.handlers[typ] = func(ctx context.Context, s S, ev Event) (S, []Intent, error) {
+		p, err := DecodePayload[P](ev)
+		if err != nil {
+			return s, nil, err
+		}
+		return fn(ctx, s, ev, p)
+	}
+}
+
+// Reduce implements Reducer. It fails if current is not an S.
+func (r *TypedReducer[S]) Reduce(ctx context.Context, current State, ev Event) (State, []Intent, error) {
+	s, ok := current.(S)
+	if !ok {
+		var want S
+		return current, nil, errmodel.Validation("bad_state", "unexpected state type", map[string]any{"got": fmt.Sprintf("%T", current), "want": fmt.Sprintf("%T", want)})
+	}
+	h, ok := r.handlers[ev.Type]
+	if !ok {
+		return current, nil, nil
+	}
+	return h(ctx, s, ev)
+}
+
+// DecodePayload converts the payload of ev into P. Payloads that already are a P are returned
+// as is; others, such as the generic maps read back from the store, go through JSON. An empty
+// payload decodes to the zero value.
+func DecodePayload[P any](ev Event) (P, error) {
+	var p P
+	switch v := ev.Payload.(type) {
+	case nil:
+		return p, nil
+	case P:
+		return v, nil
+	}
+	b, err := json.Marshal(ev.Payload)
+	if err == nil {
+		err = json.Unmarshal(b, &p)
+	}
+	if err != nil {
+		return p, errmodel.Validation("invalid_payload", "event payload does not decode", map[string]any{"type": ev.Type, "error": err.Error()})
+	}
+	return p, nil
+}
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+type counterState struct {
+	run   string
+	Count int
+}
+
+func (s counterState) RunID() string { return s.run }
+func (s counterState) Clone() State  { return s }
+
+type otherState struct{}
+
+func (otherState) RunID() string { return "" }
+func (otherState) Clone() State  { return otherState{} }
+
+type addPayload struct {
+	N int `json:"n"`
+}
+
+var _ Reducer = (*TypedReducer[counterState])(nil)
+
+func TestTypedReducer(t *testing.T) {
+	ctx := context.Background()
+	r := NewTypedReducer[counterState]()
+	On(r, "add", func(ctx context.Context, s counterState, ev Event, p addPayload) (counterState, []Intent, error) {
+		s.Count += p.N
+		return s, []Intent{{Name: "added"}}, nil
+	})
+
+	// Payloads decode from their stored form and from the Go type itself.
+	var s State = counterState{run: "r"}
+	s, intents, err := r.Reduce(ctx, s, Event{Type: "add", Payload: map[string]any{"n": 2.0}})
+	if err != nil || len(intents) != 1 {
+		t.Fatalf("intents=%v err=%v", intents, err)
+	}
+	if s, _, err = r.Reduce(ctx, s, Event{Type: "add", Payload: addPayload{N: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(counterState).Count; got != 5 {
+		t.Fatalf("count=%d want 5", got)
+	}
+
+	// Unknown events leave the state unchanged.
+	if next, intents, err := r.Reduce(ctx, s, Event{Type: "noop"}); err != nil || next != s || intents != nil {
+		t.Fatalf("next=%v intents=%v err=%v", next, intents, err)
+	}
+	if _, _, err := r.Reduce(ctx, s, Event{Type: "add", Payload: map[string]any{"n": "x"}}); !errmodel.HasCode(err, "invalid_payload") {
+		t.Fatalf("err=%v want invalid_payload", err)
+	}
+	if _, _, err := r.Reduce(ctx, otherState{}, Event{Type: "add"}); !errmodel.HasCode(err, "bad_state") {
+		t.Fatalf("err=%v want bad_state", err)
+	}
+}
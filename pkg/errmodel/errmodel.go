@@ -76,6 +76,11 @@ func Policy(code, message string, ctx map[string]any) *Error {
 	return New(CategoryPolicy, code, message, ctx)
 }
 
+// Conflict reports that a write lost an optimistic concurrency check; it maps to HTTP 409.
+func Conflict(message string, ctx map[string]any) *Error {
+	return New(CategoryValidation, "conflict", message, ctx)
+}
+
 func System(code, message string, ctx map[string]any, cause error) *Error {
 	if cause != nil {
 		return New(CategorySystem, code, message, ctx, cause)
@@ -189,3 +194,20 @@ func IsCategory(err error, category string) bool {
 	ce := From(err)
 	return ce != nil && strings.EqualFold(ce.Category, category)
 }
+
+// HasCode checks if err or any of its causes carries the given code.
+func HasCode(err error, code string) bool {
+	ce := From(err)
+	if ce == nil {
+		return false
+	}
+	if ce.Code == code {
+		return true
+	}
+	for i := range ce.Causes {
+		if HasCode(&ce.Causes[i], code) {
+			return true
+		}
+	}
+	return false
+}
@@ -31,3 +31,17 @@ func TestWriteHTTP_StatusAndEnvelope(t *testing.T) {
 		t.Fatalf("body missing code: %s", body)
 	}
 }
+
+func TestConflictAndHasCode(t *testing.T) {
+	c := Conflict("seq moved", map[string]any{"run_id": "r1"})
+	if HTTPStatus(c) != 409 {
+		t.Fatalf("status=%d want 409", HTTPStatus(c))
+	}
+	wrapped := System("store_error", "append failed", nil, c)
+	if !HasCode(wrapped, "conflict") {
+		t.Fatalf("HasCode should find conflict in causes: %#v", wrapped)
+	}
+	if HasCode(System("store_error", "append failed", nil, nil), "conflict") {
+		t.Fatal("HasCode should be false without a conflict cause")
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 	"github.com/wilhg/orch/examples/todo"
 	"github.com/wilhg/orch/pkg/agent"
-	"github.com/wilhg/orch/pkg/store/entstore"
+	"github.com/wilhg/orch/pkg/store/memstore"
 )
 
 type jsonCodec struct{}
@@ -27,14 +27,7 @@ func (jsonCodec) Decode(runID string, data []byte) (agent.State, error) {
 
 func TestReplayRun_Todo(t *testing.T) {
 	ctx := context.Background()
-	st, err := entstore.Open(ctx, "sqlite:file:replay?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() { _ = st.Close() })
-	if err := st.Migrate(ctx); err != nil {
-		t.Fatal(err)
-	}
+	st := memstore.New()
 
 	cap := Capture{
 		RunID: "r1",
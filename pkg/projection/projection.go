@@ -0,0 +1,205 @@
+// Package projection builds read models from the global event feed. Each named projector reads
+// the events of every run in position order and persists its checkpoint, so it resumes where it
+// stopped after a restart and can be rebuilt from the first event.
+package projection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// Projector applies events to a read model. Events are delivered at least once: the checkpoint
+// is saved after each batch, so a projector that stops midway sees the rest of the batch again.
+// Projectors that cannot tolerate that can record the position of the last event applied.
+type Projector interface {
+	// Name identifies the projector's checkpoint.
+	Name() string
+	Project(ctx context.Context, e store.EventRecord) error
+}
+
+// Resetter is implemented by projectors that can clear their read model for a rebuild.
+type Resetter interface {
+	Reset(ctx context.Context) error
+}
+
+// Engine runs projectors over the global event feed.
+type Engine struct {
+	events      store.EventStore
+	checkpoints store.CheckpointStore
+	projectors  []Projector
+	batch       int
+	interval    time.Duration
+	onError     func(name string, err error)
+
+	// locks serializes catch-up and rebuild per projector.
+	locks map[string]*sync.Mutex
+}
+
+// Option configures the Engine at construction time.
+type Option func(*Engine)
+
+// WithBatchSize sets how many events are read per batch. Defaults to 100.
+func WithBatchSize(n int) Option {
+	return func(e *Engine) {
+		if n > 0 {
+			e.batch = n
+		}
+	}
+}
+
+// WithPollInterval sets how long Run waits when every projector has caught up. Defaults to 500ms.
+func WithPollInterval(d time.Duration) Option {
+	return func(e *Engine) {
+		if d > 0 {
+			e.interval = d
+		}
+	}
+}
+
+// WithErrorHandler sets a callback for errors returned by projectors and the stores in Run.
+func WithErrorHandler(fn func(name string, err error)) Option {
+	return func(e *Engine) { e.onError = fn }
+}
+
+// NewEngine constructs an Engine for projectors with distinct names.
+func NewEngine(events store.EventStore, checkpoints store.CheckpointStore, projectors []Projector, opts ...Option) *Engine {
+	e := &Engine{
+		events:      events,
+		checkpoints: checkpoints,
+		projectors:  projectors,
+		batch:       100,
+		interval:    500 * time.Millisecond,
+		locks:       map[string]*sync.Mutex{},
+	}
+	for _, p := range projectors {
+		e.locks[p.Name()] = &sync.Mutex{}
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Run keeps the projectors up to date until ctx is done. A projector that fails is retried from
+// its checkpoint on the next pass without holding back the others.
+func (e *Engine) Run(ctx context.Context) error {
+	for {
+		progressed := false
+		for _, p := range e.projectors {
+			n, err := e.catchUp(ctx, p)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil && e.onError != nil {
+				e.onError(p.Name(), err)
+			}
+			progressed = progressed || n > 0
+		}
+		if progressed {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(e.interval):
+		}
+	}
+}
+
+// CatchUp applies the events appended since each projector's checkpoint and returns the first
+// error encountered.
+func (e *Engine) CatchUp(ctx context.Context) error {
+	var first error
+	for _, p := range e.projectors {
+		if _, err := e.catchUp(ctx, p); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Rebuild resets the named projector, if it implements Resetter, and projects every event
+// again from the start of the feed.
+func (e *Engine) Rebuild(ctx context.Context, name string) error {
+	p := e.projector(name)
+	if p == nil {
+		return fmt.Errorf("projection: unknown projector %q", name)
+	}
+	mu := e.locks[name]
+	mu.Lock()
+	if r, ok := p.(Resetter); ok {
+		if err := r.Reset(ctx); err != nil {
+			mu.Unlock()
+			return fmt.Errorf("projection %s: reset: %w", name, err)
+		}
+	}
+	err := e.checkpoints.SaveCheckpoint(ctx, name, 0)
+	mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("projection %s: save checkpoint: %w", name, err)
+	}
+	_, err = e.catchUp(ctx, p)
+	return err
+}
+
+// Position returns the checkpoint of the named projector.
+func (e *Engine) Position(ctx context.Context, name string) (int64, error) {
+	return e.checkpoints.LoadCheckpoint(ctx, name)
+}
+
+func (e *Engine) projector(name string) Projector {
+	for _, p := range e.projectors {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// catchUp projects batches until the feed is exhausted and returns how many events were applied.
+func (e *Engine) catchUp(ctx context.Context, p Projector) (int, error) {
+	name := p.Name()
+	mu := e.locks[name]
+	mu.Lock()
+	defer mu.Unlock()
+	pos, err := e.checkpoints.LoadCheckpoint(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("projection %s: load checkpoint: %w", name, err)
+	}
+	applied := 0
+	for {
+		events, err := e.events.ReadAll(ctx, pos, e.batch)
+		if err != nil {
+			return applied, fmt.Errorf("projection %s: read events: %w", name, err)
+		}
+		if len(events) == 0 {
+			return applied, nil
+		}
+		last := pos
+		var projectErr error
+		for _, ev := range events {
+			if projectErr = p.Project(ctx, ev); projectErr != nil {
+				projectErr = fmt.Errorf("projection %s: event %s at position %d: %w", name, ev.EventID, ev.Position, projectErr)
+				break
+			}
+			last = ev.Position
+			applied++
+		}
+		if last > pos {
+			if err := e.checkpoints.SaveCheckpoint(ctx, name, last); err != nil {
+				return applied, fmt.Errorf("projection %s: save checkpoint: %w", name, err)
+			}
+			pos = last
+		}
+		if projectErr != nil {
+			return applied, projectErr
+		}
+		if len(events) < e.batch {
+			return applied, nil
+		}
+	}
+}
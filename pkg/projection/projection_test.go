@@ -0,0 +1,110 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/entstore"
+)
+
+// typeCounter counts events per type across runs.
+type typeCounter struct {
+	counts map[string]int
+	failAt string // event ID to fail on
+}
+
+func (*typeCounter) Name() string { return "type-counter" }
+
+func (c *typeCounter) Project(ctx context.Context, e store.EventRecord) error {
+	if e.EventID == c.failAt {
+		return errors.New("boom")
+	}
+	c.counts[e.Type]++
+	return nil
+}
+
+func (c *typeCounter) Reset(ctx context.Context) error {
+	c.counts = map[string]int{}
+	return nil
+}
+
+// runLister records the runs in feed order.
+type runLister struct{ runs []string }
+
+func (*runLister) Name() string { return "run-lister" }
+
+func (l *runLister) Project(ctx context.Context, e store.EventRecord) error {
+	l.runs = append(l.runs, e.RunID)
+	return nil
+}
+
+func TestEngine_CatchUpResumeAndRebuild_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st, err := entstore.Open(ctx, "sqlite:file:projection?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	appendEvent := func(id, runID, typ string) {
+		t.Helper()
+		if _, err := st.AppendEvent(ctx, store.EventRecord{EventID: id, RunID: runID, Type: typ}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendEvent("a1", "run-a", "start")
+	appendEvent("b1", "run-b", "start")
+	appendEvent("a2", "run-a", "stop")
+
+	counter := &typeCounter{counts: map[string]int{}, failAt: "b1"}
+	lister := &runLister{}
+	eng := NewEngine(st, st, []Projector{counter, lister}, WithBatchSize(2))
+
+	// A failing projector stops at the failing event without holding back the others.
+	if err := eng.CatchUp(ctx); err == nil {
+		t.Fatal("expected projector error")
+	}
+	if counter.counts["start"] != 1 {
+		t.Fatalf("counts=%v", counter.counts)
+	}
+	if got := len(lister.runs); got != 3 || lister.runs[1] != "run-b" {
+		t.Fatalf("runs=%v want the feed in position order", lister.runs)
+	}
+
+	// Once fixed, a new engine resumes from the saved checkpoint.
+	counter.failAt = ""
+	eng = NewEngine(st, st, []Projector{counter, lister}, WithBatchSize(2))
+	appendEvent("b2", "run-b", "stop")
+	if err := eng.CatchUp(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if counter.counts["start"] != 2 || counter.counts["stop"] != 2 {
+		t.Fatalf("counts=%v", counter.counts)
+	}
+	if len(lister.runs) != 4 {
+		t.Fatalf("runs=%v: events projected twice", lister.runs)
+	}
+	all, err := st.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos, _ := eng.Position(ctx, "type-counter"); pos != all[len(all)-1].Position {
+		t.Fatalf("checkpoint=%d want %d", pos, all[len(all)-1].Position)
+	}
+
+	// Rebuilding resets the read model and projects the whole feed again.
+	counter.counts["start"] = 100
+	if err := eng.Rebuild(ctx, "type-counter"); err != nil {
+		t.Fatal(err)
+	}
+	if counter.counts["start"] != 2 || counter.counts["stop"] != 2 {
+		t.Fatalf("counts after rebuild=%v", counter.counts)
+	}
+	if err := eng.Rebuild(ctx, "missing"); err == nil {
+		t.Fatal("expected error for unknown projector")
+	}
+}
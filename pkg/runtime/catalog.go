@@ -0,0 +1,43 @@
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithRunCatalog keeps the runs handled by the Runner in rs, recording agentType for runs it
+// registers. The catalog entry is updated after every commit with the run's status and last
+// sequence.
+func WithRunCatalog(rs store.RunStore, agentType string) RunnerOption {
+	return func(r *Runner) {
+		r.runs = rs
+		r.agentType = agentType
+	}
+}
+
+// recordRun updates the catalog entry of a run after a commit. The event log is the source of
+// truth, so failures are only recorded on the span; the next commit brings the entry up to date.
+func (r *Runner) recordRun(ctx context.Context, runID string) {
+	if r.runs == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	lc, err := r.lifecycle(ctx, runID)
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	err = r.runs.RecordRunProgress(ctx, store.RunRecord{
+		RunID:     runID,
+		Status:    string(lc.status),
+		AgentType: r.agentType,
+		LastSeq:   lc.seq,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		span.RecordError(err)
+	}
+}
@@ -0,0 +1,42 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/agent"
+)
+
+func TestRunner_RunCatalog_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-catalog")
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithRunCatalog(st, "counter"))
+
+	runID := "run-catalog"
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "k1", Type: "inc", Payload: map[string]any{"n": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := st.GetRun(ctx, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, _ := st.LastSeq(ctx, runID)
+	if rec.Status != string(RunActive) || rec.AgentType != "counter" || rec.LastSeq != last {
+		t.Fatalf("run=%+v last seq=%d", rec, last)
+	}
+
+	if err := r.Pause(ctx, runID); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ = st.GetRun(ctx, runID); rec.Status != string(RunPaused) {
+		t.Fatalf("status=%s want paused", rec.Status)
+	}
+	if err := r.Cancel(ctx, runID, "done"); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ = st.GetRun(ctx, runID); rec.Status != string(RunCancelled) || rec.LastSeq != last+2 {
+		t.Fatalf("run=%+v want cancelled at seq %d", rec, last+2)
+	}
+}
@@ -0,0 +1,134 @@
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithDeadLetters records intents that exhaust their retry policy in dl, atomically with the
+// failed cycle. dl must share the database of the Runner's store.
+func WithDeadLetters(dl store.DeadLetterStore) RunnerOption {
+	return func(r *Runner) {
+		r.deadLetters = dl
+		r.committer = dl
+	}
+}
+
+// deadLetter adds the dead letters of the failed intents to the cycle. When the cycle requeues a
+// dead letter, the requeued intent is dispatched first and alone, so it is the first failure if
+// it failed again: its dead letter is then kept, with the new attempts, rather than removed.
+func (r *Runner) deadLetter(c *cycle, failed []*intentFailedError) {
+	now := time.Now().UTC()
+	for i, f := range failed {
+		var cause errmodel.Error
+		if f.cause != nil {
+			cause = *f.cause
+		}
+		id := uuid.NewString()
+		if d := c.requeued; d != nil && i == 0 && f.intent.Name == d.IntentName && f.intent.IdempotencyKey == d.IdempotencyKey {
+			id = d.DeadLetterID
+		}
+		c.deadLetters = append(c.deadLetters, store.DeadLetterRecord{
+			DeadLetterID:   id,
+			RunID:          c.runID,
+			IntentName:     f.intent.Name,
+			IdempotencyKey: f.intent.IdempotencyKey,
+			Args:           f.intent.Args,
+			Attempts:       f.attempts,
+			Error:          cause,
+			CreatedAt:      now,
+		})
+	}
+	if d := c.requeued; d != nil && !slices.ContainsFunc(c.deadLetters, func(dl store.DeadLetterRecord) bool { return dl.DeadLetterID == d.DeadLetterID }) {
+		c.resolve = d.DeadLetterID
+	}
+}
+
+// Requeue dispatches a dead-lettered intent again in a new cycle of its run, under the same retry
+// policy; with an outbox too, the intent is executed by the call. The cycle removes the dead
+// letter if the intent succeeds; if it fails again, the dead letter is kept with the attempts and
+// error of the new failure.
+func (r *Runner) Requeue(ctx context.Context, deadLetterID string) (agent.State, error) {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.Requeue", trace.WithAttributes(
+		attribute.String("dead_letter.id", deadLetterID),
+	))
+	defer span.End()
+	if r.deadLetters == nil {
+		return nil, errmodel.Validation("not_configured", "dead letters are not enabled", nil)
+	}
+	d, err := r.deadLetters.GetDeadLetter(ctx, deadLetterID)
+	if err == sql.ErrNoRows {
+		return nil, errmodel.Validation("not_found", "dead letter not found", map[string]any{"dead_letter_id": deadLetterID})
+	}
+	if err != nil {
+		return nil, errmodel.System("store_error", "failed to load dead letter", map[string]any{"dead_letter_id": deadLetterID}, err)
+	}
+	span.SetAttributes(attribute.String("run.id", d.RunID), attribute.String("intent.name", d.IntentName))
+
+	ctx, untrack := r.cancels.track(ctx, d.RunID)
+	defer untrack()
+	release, err := r.locks.acquire(ctx, d.RunID)
+	if err != nil {
+		return nil, errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": d.RunID}, err)
+	}
+	defer release()
+
+	for attempt := 0; ; attempt++ {
+		s, committed, err := r.requeue(ctx, span, d)
+		if err != nil && !committed && errmodel.HasCode(err, "conflict") && attempt < r.conflictRetries {
+			continue
+		}
+		return s, cancelledErr(ctx, d.RunID, err)
+	}
+}
+
+func (r *Runner) requeue(ctx context.Context, span trace.Span, d store.DeadLetterRecord) (agent.State, bool, error) {
+	current, lastSeq, err := r.replayState(ctx, d.RunID)
+	if err != nil {
+		return nil, false, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
+	}
+	lc, err := r.lifecycle(ctx, d.RunID)
+	if err != nil {
+		return nil, false, err
+	}
+	switch lc.status {
+	case RunCancelled:
+		return nil, false, runCancelledError(d.RunID)
+	case RunPaused:
+		return nil, false, runPausedError(d.RunID)
+	}
+	c := newCycle(d.RunID, lastSeq)
+	c.requeued = &d
+	requeued := agent.Event{
+		ID:        newEventID(d.RunID),
+		Type:      "intent_requeued",
+		Timestamp: time.Now().UTC(),
+		Payload: map[string]any{
+			"dead_letter_id": d.DeadLetterID,
+			"name":           d.IntentName,
+			"key":            d.IdempotencyKey,
+		},
+	}
+	c.stage(agentEventToRecord(d.RunID, requeued))
+	if current, _, err = r.applySingle(ctx, current, requeued); err != nil {
+		return nil, false, errmodel.System("reducer_error", "failed to apply reducer", map[string]any{"event_type": requeued.Type}, err)
+	}
+	it := agent.Intent{Name: d.IntentName, Args: d.Args, IdempotencyKey: d.IdempotencyKey}
+	if r.outbox != nil {
+		// The intent runs here rather than from the outbox, so that the cycle knows whether it
+		// succeeded; its follow-ups are enqueued.
+		return r.dispatch(ctx, span, c, current, it, 1)
+	}
+	return r.drive(ctx, span, c, current, []agent.Intent{it})
+}
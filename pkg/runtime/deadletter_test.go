@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+func TestRunner_DeadLetterAndRequeue_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-dlq")
+
+	// The handler fails four times (both attempts of the first cycle and of the first requeue),
+	// then succeeds on the second requeue.
+	calls := 0
+	h := flakyHandler{failures: 4, err: errmodel.New(errmodel.CategoryNetwork, "unavailable", "upstream unavailable", nil), calls: &calls}
+	r := NewRunner(st, reducerWithIdem{}, []agent.EffectHandler{h}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2}), WithDeadLetters(st))
+
+	if _, err := r.HandleEvent(ctx, "run-dlq", agent.Event{ID: "d1", Type: "inc", Payload: map[string]any{"n": 1}}); err == nil {
+		t.Fatal("expected effect error")
+	}
+	dls, err := st.ListDeadLetters(ctx, "run-dlq", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dls) != 1 {
+		t.Fatalf("dead letters=%d want 1", len(dls))
+	}
+	d := dls[0]
+	if d.IntentName != "emit_added" || d.IdempotencyKey != "add-two" || d.Attempts != 2 || d.Error.Code != "unavailable" {
+		t.Fatalf("unexpected dead letter: %+v", d)
+	}
+	if n, _ := d.Args["n"].(float64); n != 2 {
+		t.Fatalf("args=%v want n=2", d.Args)
+	}
+
+	// A requeued intent that fails again keeps its dead letter, updated with the new failure.
+	if _, err := r.Requeue(ctx, d.DeadLetterID); !errmodel.HasCode(err, "effect_error") {
+		t.Fatalf("err=%v want effect_error", err)
+	}
+	dls, err = st.ListDeadLetters(ctx, "run-dlq", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dls) != 1 || dls[0].DeadLetterID != d.DeadLetterID || dls[0].Attempts != 2 || dls[0].Error.Code != "unavailable" {
+		t.Fatalf("dead letters=%+v want %s kept", dls, d.DeadLetterID)
+	}
+
+	s, err := r.Requeue(ctx, d.DeadLetterID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 3 {
+		t.Fatalf("count=%d want 3 after requeue", got)
+	}
+	if _, err := st.GetDeadLetter(ctx, d.DeadLetterID); err != sql.ErrNoRows {
+		t.Fatalf("dead letter should be removed, err=%v", err)
+	}
+	if types := countTypes(t, st, "run-dlq"); types["intent_requeued"] != 2 || types["intent_processed"] != 1 {
+		t.Fatalf("types=%v", types)
+	}
+	if _, err := r.Requeue(ctx, d.DeadLetterID); !errmodel.HasCode(err, "not_found") {
+		t.Fatalf("err=%v want not_found", err)
+	}
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+func TestRunner_EventRegistry_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-events")
+	// inc v1 carried {"amount"}; v2 carries {"n"}.
+	reg := agent.NewEventRegistry()
+	if err := reg.Register(agent.EventSchema{
+		Type:    "inc",
+		Version: 2,
+		Schema:  []byte(`{"type":"object","properties":{"n":{"type":"integer"}},"required":["n"]}`),
+		Upcasters: map[int]agent.Upcaster{1: func(p any) (any, error) {
+			m, _ := p.(map[string]any)
+			return map[string]any{"n": m["amount"]}, nil
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithEventRegistry(reg))
+
+	runID := "run-events"
+	// An event written before the schema changed.
+	if _, err := st.AppendEvent(ctx, store.EventRecord{EventID: "old", RunID: runID, Type: "inc", Version: 1, Payload: []byte(`{"amount":3}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid events are rejected before anything is written.
+	_, err := r.HandleEvent(ctx, runID, agent.Event{ID: "bad", Type: "inc", Payload: map[string]any{"n": "one"}})
+	if !errmodel.HasCode(err, "invalid_event") {
+		t.Fatalf("err=%v want invalid_event", err)
+	}
+	if last, _ := st.LastSeq(ctx, runID); last != 1 {
+		t.Fatalf("last seq=%d want 1", last)
+	}
+
+	// Replay upcasts the v1 event: 3 + 1 + 2 (added by the effect).
+	s, err := r.HandleEvent(ctx, runID, agent.Event{ID: "new", Type: "inc", Payload: map[string]any{"n": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 6 {
+		t.Fatalf("count=%d want 6", got)
+	}
+	rec, err := st.GetEventByID(ctx, "new")
+	if err != nil || rec.Version != 2 {
+		t.Fatalf("rec=%+v err=%v want version 2", rec, err)
+	}
+	if rec, _ := st.GetEventByID(ctx, "old"); rec.Version != 1 {
+		t.Fatalf("old version=%d want 1", rec.Version)
+	}
+}
@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventRunForked is the event recorded at the end of the events a fork copied from its source.
+const EventRunForked = "run_forked"
+
+// StateAt reconstructs the state of a run as of the event with sequence seq. A seq of 0 returns
+// the initial state.
+func (r *Runner) StateAt(ctx context.Context, runID string, seq int64) (agent.State, error) {
+	events, err := r.eventsUpTo(ctx, runID, seq)
+	if err != nil {
+		return nil, err
+	}
+	s, err := r.reduceAll(ctx, r.newState(runID), events)
+	if err != nil {
+		return nil, errmodel.System("reducer_error", "failed to replay state", map[string]any{"run_id": runID, "seq": seq}, err)
+	}
+	return s, nil
+}
+
+// Fork starts newRunID as a copy of srcRunID up to and including uptoSeq, followed by a
+// run_forked event recording the source. The copied events keep their sequences, types and
+// payloads under new IDs, so the fork replays to the source's state at uptoSeq and then diverges
+// with the events handled for it. Intents still pending in the source's outbox and its timers
+// are not copied. Forking again with the same arguments returns the fork's current state; a
+// newRunID with other events fails with a conflict error.
+func (r *Runner) Fork(ctx context.Context, srcRunID string, uptoSeq int64, newRunID string) (agent.State, error) {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.Fork", trace.WithAttributes(
+		attribute.String("run.id", newRunID),
+		attribute.String("fork.source_run_id", srcRunID),
+		attribute.Int64("fork.source_seq", uptoSeq),
+	))
+	defer span.End()
+	if newRunID == "" || newRunID == srcRunID {
+		return nil, errmodel.Validation("bad_run", "fork needs a new run ID", map[string]any{"run_id": newRunID})
+	}
+	events, err := r.eventsUpTo(ctx, srcRunID, uptoSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := r.locks.acquire(ctx, newRunID)
+	if err != nil {
+		return nil, errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": newRunID}, err)
+	}
+	defer release()
+
+	// Sensitive fields stay sealed under the source's key, so that shredding the source shreds
+	// them in the fork too; the fork's opener finds the source through the run_forked event.
+	recs := make([]store.EventRecord, 0, len(events)+1)
+	for _, e := range events {
+		e.EventID = forkEventID(srcRunID, newRunID, e.EventID)
+		if e.Type == EventRunEventQueued {
+			// The queued event is handled under its own ID on Resume.
+			if e.Payload, err = forkQueued(srcRunID, newRunID, e.Payload); err != nil {
+				return nil, errmodel.System("decode_error", "failed to decode queued event", map[string]any{"event_id": e.EventID}, err)
+			}
+		}
+		e.RunID, e.Position = newRunID, 0
+		recs = append(recs, e)
+	}
+	recs = append(recs, agentEventToRecord(newRunID, agent.Event{
+		ID:        forkedEventID(newRunID),
+		Type:      EventRunForked,
+		Timestamp: time.Now().UTC(),
+		Payload:   map[string]any{"source_run_id": srcRunID, "source_seq": uptoSeq},
+	}))
+	if _, err := r.st.AppendEvents(ctx, newRunID, 0, recs); err != nil {
+		span.RecordError(err)
+		if errmodel.HasCode(err, "conflict") {
+			return nil, errmodel.Conflict("run already has events", map[string]any{"run_id": newRunID})
+		}
+		return nil, errmodel.System("store_error", "failed to copy events", map[string]any{"run_id": newRunID}, err)
+	}
+	if r.runs != nil {
+		_, err := r.runs.CreateRun(ctx, store.RunRecord{
+			RunID:       newRunID,
+			AgentType:   r.agentType,
+			ParentRunID: srcRunID,
+			Labels:      map[string]string{"forked_from": srcRunID, "fork_seq": strconv.FormatInt(uptoSeq, 10)},
+		})
+		if err != nil && !errmodel.HasCode(err, "conflict") {
+			span.RecordError(err)
+		}
+		r.recordRun(ctx, newRunID)
+	}
+	s, _, err := r.replayState(ctx, newRunID)
+	if err != nil {
+		return nil, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
+	}
+	return s, nil
+}
+
+// eventsUpTo lists the events of a run up to and including seq, which must not be past its end.
+func (r *Runner) eventsUpTo(ctx context.Context, runID string, seq int64) ([]store.EventRecord, error) {
+	if runID == "" {
+		return nil, errmodel.Validation("missing_run", "runID is empty", nil)
+	}
+	last, err := r.st.LastSeq(ctx, runID)
+	if err != nil {
+		return nil, errmodel.System("store_error", "failed to read run", map[string]any{"run_id": runID}, err)
+	}
+	if seq < 0 || seq > last {
+		return nil, errmodel.Validation("bad_seq", "sequence is outside the run", map[string]any{"run_id": runID, "seq": seq, "last_seq": last})
+	}
+	if seq == 0 {
+		return nil, nil
+	}
+	// Sequences start at 1 without gaps.
+	events, err := r.st.ListEvents(ctx, runID, 0, int(seq))
+	if err != nil {
+		return nil, errmodel.System("store_error", "failed to list events", map[string]any{"run_id": runID}, err)
+	}
+	return events, nil
+}
+
+// forkedEventID is the ID of the run_forked event of a fork.
+func forkedEventID(runID string) string {
+	return "forked-" + runID
+}
+
+// forkEventID maps the ID of a copied event into the fork. Intent claims and markers keep their
+// deterministic form so the fork recognizes the intents its source already ran.
+func forkEventID(src, dst, id string) string {
+	for _, prefix := range []string{"intent-claim-", "intent-"} {
+		if rest, ok := strings.CutPrefix(id, prefix+src+"-"); ok {
+			return prefix + dst + "-" + rest
+		}
+	}
+	return fmt.Sprintf("fork-%s-%s", dst, id)
+}
+
+// forkQueued maps the ID of the event carried by a run_event_queued payload into the fork.
+func forkQueued(src, dst string, payload json.RawMessage) (json.RawMessage, error) {
+	var ev agent.Event
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, err
+	}
+	ev.ID = forkEventID(src, dst, ev.ID)
+	return json.Marshal(ev)
+}
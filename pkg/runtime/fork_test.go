@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+func TestRunner_ForkAndStateAt_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-fork")
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithRunCatalog(st, "counter"))
+
+	// Each inc event is followed by an added event of 2: seqs 1-4 bring the count to 1, 3, 4, 6.
+	for _, id := range []string{"f1", "f2"} {
+		if _, err := r.HandleEvent(ctx, "run-src", agent.Event{ID: id, Type: "inc", Payload: map[string]any{"n": 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for seq, want := range []int{0, 1, 3, 4, 6} {
+		s, err := r.StateAt(ctx, "run-src", int64(seq))
+		if err != nil || s.(testState).Count != want {
+			t.Fatalf("state at %d=%+v err=%v want count %d", seq, s, err, want)
+		}
+	}
+	if _, err := r.StateAt(ctx, "run-src", 5); !errmodel.HasCode(err, "bad_seq") {
+		t.Fatalf("err=%v want bad_seq", err)
+	}
+
+	s, err := r.Fork(ctx, "run-src", 2, "run-fork")
+	if err != nil || s.(testState).Count != 3 {
+		t.Fatalf("fork state=%+v err=%v want count 3", s, err)
+	}
+	// The fork diverges without touching its source.
+	if s, err = r.HandleEvent(ctx, "run-fork", agent.Event{ID: "f3", Type: "inc", Payload: map[string]any{"n": 10}}); err != nil || s.(testState).Count != 15 {
+		t.Fatalf("fork state=%+v err=%v want count 15", s, err)
+	}
+	if s, _, _ := r.State(ctx, "run-src"); s.(testState).Count != 6 {
+		t.Fatalf("source state=%+v want count 6", s)
+	}
+	evs, _ := st.ListEvents(ctx, "run-fork", 0, 0)
+	if evs[0].Seq != 1 || evs[0].Type != "inc" || evs[2].Type != EventRunForked {
+		t.Fatalf("fork events=%+v", evs)
+	}
+	run, err := st.GetRun(ctx, "run-fork")
+	if err != nil || run.ParentRunID != "run-src" || run.Labels["fork_seq"] != "2" {
+		t.Fatalf("run=%+v err=%v", run, err)
+	}
+
+	// Repeating the fork is a no-op; forking onto another run with events is a conflict.
+	if s, err := r.Fork(ctx, "run-src", 2, "run-fork"); err != nil || s.(testState).Count != 15 {
+		t.Fatalf("state=%+v err=%v", s, err)
+	}
+	if _, err := r.Fork(ctx, "run-src", 3, "run-fork"); !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("err=%v want conflict", err)
+	}
+	if _, err := r.Fork(ctx, "run-src", 9, "run-other"); !errmodel.HasCode(err, "bad_seq") {
+		t.Fatalf("err=%v want bad_seq", err)
+	}
+}
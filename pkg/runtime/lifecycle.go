@@ -0,0 +1,354 @@
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RunStatus is the lifecycle status of a run, derived from the lifecycle events in its log.
+type RunStatus string
+
+const (
+	RunActive    RunStatus = "active"
+	RunPaused    RunStatus = "paused"
+	RunCancelled RunStatus = "cancelled"
+	RunCompleted RunStatus = "completed"
+	RunFailed    RunStatus = "failed"
+)
+
+// Lifecycle event types. run_completed and run_failed are not emitted by the Runner; agents
+// record them like any other event, for example from an effect handler.
+const (
+	EventRunPaused      = "run_paused"
+	EventRunResumed     = "run_resumed"
+	EventRunCancelled   = "run_cancelled"
+	EventRunCompleted   = "run_completed"
+	EventRunFailed      = "run_failed"
+	EventRunEventQueued = "run_event_queued"
+)
+
+var errRunCancelled = errors.New("run cancelled")
+
+// lifecycles caches the status of runs, scanning their logs incrementally.
+type lifecycles struct {
+	mu   sync.Mutex
+	runs map[string]*lifecycle
+}
+
+type lifecycle struct {
+	status    RunStatus
+	seq       int64 // last sequence scanned
+	pausedSeq int64 // sequence of the run_paused event while paused
+}
+
+// Status returns the lifecycle status of a run. Runs without lifecycle events are active.
+func (r *Runner) Status(ctx context.Context, runID string) (RunStatus, error) {
+	lc, err := r.lifecycle(ctx, runID)
+	if err != nil {
+		return "", err
+	}
+	return lc.status, nil
+}
+
+// lifecycle returns the run's lifecycle after scanning the events appended since the last call.
+func (r *Runner) lifecycle(ctx context.Context, runID string) (lifecycle, error) {
+	r.lifecycles.mu.Lock()
+	lc, ok := r.lifecycles.runs[runID]
+	var cached lifecycle
+	if ok {
+		cached = *lc
+	} else {
+		cached = lifecycle{status: RunActive}
+	}
+	r.lifecycles.mu.Unlock()
+
+	events, err := r.st.ListEvents(ctx, runID, cached.seq, 0)
+	if err != nil {
+		return lifecycle{}, errmodel.System("store_error", "failed to read run lifecycle", map[string]any{"run_id": runID}, err)
+	}
+	for _, er := range events {
+		switch er.Type {
+		case EventRunPaused:
+			cached.status, cached.pausedSeq = RunPaused, er.Seq
+		case EventRunResumed:
+			cached.status, cached.pausedSeq = RunActive, 0
+		case EventRunCancelled:
+			cached.status = RunCancelled
+		case EventRunCompleted:
+			cached.status = RunCompleted
+		case EventRunFailed:
+			cached.status = RunFailed
+		}
+		cached.seq = er.Seq
+	}
+
+	r.lifecycles.mu.Lock()
+	defer r.lifecycles.mu.Unlock()
+	if r.lifecycles.runs == nil {
+		r.lifecycles.runs = map[string]*lifecycle{}
+	}
+	// Runs that ended are evicted, so that the cache holds the runs in progress only; one that
+	// is touched again is scanned from the start.
+	if cached.ended() {
+		delete(r.lifecycles.runs, runID)
+	} else if cur, ok := r.lifecycles.runs[runID]; !ok || cur.seq < cached.seq {
+		r.lifecycles.runs[runID] = &cached
+	}
+	return cached, nil
+}
+
+// ended reports whether the run was completed, failed or cancelled.
+func (lc lifecycle) ended() bool {
+	return lc.status == RunCompleted || lc.status == RunFailed || lc.status == RunCancelled
+}
+
+// runCancels tracks the contexts of in-flight cycles per run so Cancel can interrupt them.
+type runCancels struct {
+	mu   sync.Mutex
+	next int
+	runs map[string]map[int]context.CancelCauseFunc
+}
+
+// track returns a context canceled by cancel(runID) and a func to stop tracking it.
+func (c *runCancels) track(ctx context.Context, runID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	c.mu.Lock()
+	if c.runs == nil {
+		c.runs = map[string]map[int]context.CancelCauseFunc{}
+	}
+	if c.runs[runID] == nil {
+		c.runs[runID] = map[int]context.CancelCauseFunc{}
+	}
+	id := c.next
+	c.next++
+	c.runs[runID][id] = cancel
+	c.mu.Unlock()
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.runs[runID], id)
+		if len(c.runs[runID]) == 0 {
+			delete(c.runs, runID)
+		}
+		c.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+func (c *runCancels) cancel(runID string, cause error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cancel := range c.runs[runID] {
+		cancel(cause)
+	}
+}
+
+// cancelledErr reports whether a cycle failed because its run was cancelled.
+func cancelledErr(ctx context.Context, runID string, err error) error {
+	if err != nil && errors.Is(context.Cause(ctx), errRunCancelled) {
+		return runCancelledError(runID)
+	}
+	return err
+}
+
+func runCancelledError(runID string) error {
+	return errmodel.Policy("run_cancelled", "run is cancelled", map[string]any{"run_id": runID})
+}
+
+func runPausedError(runID string) error {
+	return errmodel.Policy("run_paused", "run is paused", map[string]any{"run_id": runID})
+}
+
+// Pause stops the run from reducing events and dispatching intents. Events handled while the run
+// is paused are recorded as run_event_queued and processed in order on Resume. Pausing a paused
+// run has no effect; a cancelled run cannot be paused.
+func (r *Runner) Pause(ctx context.Context, runID string) error {
+	return r.transition(ctx, "Runner.Pause", runID, func(lc lifecycle) (string, error) {
+		switch lc.status {
+		case RunCancelled:
+			return "", runCancelledError(runID)
+		case RunPaused:
+			return "", nil
+		}
+		return EventRunPaused, nil
+	}, nil)
+}
+
+// Cancel interrupts the effects in flight for the run and records run_cancelled. Events handled
+// for a cancelled run are rejected with a run_cancelled policy error, and its pending outbox
+// intents and timers are dropped.
+func (r *Runner) Cancel(ctx context.Context, runID, reason string) error {
+	// Interrupt in-flight cycles first: they hold the run lock until they finish.
+	r.cancels.cancel(runID, errRunCancelled)
+	err := r.transition(ctx, "Runner.Cancel", runID, func(lc lifecycle) (string, error) {
+		if lc.status == RunCancelled {
+			return "", nil
+		}
+		return EventRunCancelled, nil
+	}, map[string]any{"reason": reason})
+	if err != nil {
+		return err
+	}
+	// Outbox items parked while the run was paused are claimed again, to be dropped.
+	return r.wakeOutbox(ctx, runID)
+}
+
+// Resume reactivates a paused run, handles the events queued while it was paused, in order, and
+// makes the outbox items parked meanwhile available again. It returns the state after the last
+// queued event. Resuming an active run has no effect besides waking parked items.
+func (r *Runner) Resume(ctx context.Context, runID string) (agent.State, error) {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.Resume", trace.WithAttributes(attribute.String("run.id", runID)))
+	defer span.End()
+	if runID == "" {
+		return nil, errmodel.Validation("missing_run", "runID is empty", nil)
+	}
+	ctx, untrack := r.cancels.track(ctx, runID)
+	defer untrack()
+	release, err := r.locks.acquire(ctx, runID)
+	if err != nil {
+		return nil, errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": runID}, err)
+	}
+	defer release()
+
+	lc, err := r.lifecycle(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	switch lc.status {
+	case RunCancelled:
+		return nil, runCancelledError(runID)
+	case RunPaused:
+	default:
+		// A Resume that failed after recording run_resumed may have left items parked.
+		if err := r.wakeOutbox(ctx, runID); err != nil {
+			return nil, err
+		}
+		s, _, err := r.replayState(ctx, runID)
+		if err != nil {
+			return nil, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
+		}
+		return s, nil
+	}
+
+	s, err := r.drain(ctx, span, runID, lc.pausedSeq)
+	if err != nil {
+		return nil, cancelledErr(ctx, runID, err)
+	}
+	if err := r.wakeOutbox(ctx, runID); err != nil {
+		return nil, err
+	}
+	if s == nil {
+		if s, _, err = r.replayState(ctx, runID); err != nil {
+			return nil, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
+		}
+	}
+	return s, nil
+}
+
+// drain handles the events queued after the run_paused event at pausedSeq, in order, and then
+// records run_resumed, so that the run stays paused until every queued event was handled. Each
+// event keeps its original ID, so a drain interrupted midway skips the events already handled
+// when it is repeated. Events queued by other processes meanwhile are drained too: run_resumed
+// is only appended at the sequence the drain has seen last. It returns the state after the last
+// handled event, or nil if none was queued.
+func (r *Runner) drain(ctx context.Context, span trace.Span, runID string, pausedSeq int64) (agent.State, error) {
+	var s agent.State
+	after := pausedSeq
+	open := r.opener(ctx, runID)
+	for {
+		events, err := r.st.ListEvents(ctx, runID, after, 0)
+		if err != nil {
+			return nil, errmodel.System("store_error", "failed to list queued events", map[string]any{"run_id": runID}, err)
+		}
+		handled := false
+		for _, er := range events {
+			after = er.Seq
+			if er.Type != EventRunEventQueued {
+				continue
+			}
+			if er, err = open(er); err != nil {
+				return nil, err
+			}
+			var ev agent.Event
+			if err := json.Unmarshal(er.Payload, &ev); err != nil {
+				return nil, errmodel.System("decode_error", "failed to decode queued event", map[string]any{"event_id": er.EventID}, err)
+			}
+			if s, err = r.handleWithRetry(ctx, span, runID, ev, true); err != nil {
+				return nil, err
+			}
+			handled = true
+		}
+		if handled {
+			// List again from the last queued event, past the events its cycle appended.
+			continue
+		}
+		err = r.appendLifecycle(ctx, runID, EventRunResumed, nil, after)
+		if errmodel.HasCode(err, "conflict") {
+			continue
+		}
+		return s, err
+	}
+}
+
+// transition appends the lifecycle event chosen by next for the run's current status, unless
+// next returns no type.
+func (r *Runner) transition(ctx context.Context, name, runID string, next func(lifecycle) (string, error), payload map[string]any) error {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, name, trace.WithAttributes(attribute.String("run.id", runID)))
+	defer span.End()
+	if runID == "" {
+		return errmodel.Validation("missing_run", "runID is empty", nil)
+	}
+	release, err := r.locks.acquire(ctx, runID)
+	if err != nil {
+		return errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": runID}, err)
+	}
+	defer release()
+
+	lc, err := r.lifecycle(ctx, runID)
+	if err != nil {
+		return err
+	}
+	typ, err := next(lc)
+	if err != nil || typ == "" {
+		return err
+	}
+	return r.appendLifecycle(ctx, runID, typ, payload, store.AnySeq)
+}
+
+// appendLifecycle appends a lifecycle event at expectedSeq, which may be store.AnySeq.
+func (r *Runner) appendLifecycle(ctx context.Context, runID, typ string, payload map[string]any, expectedSeq int64) error {
+	rec := agentEventToRecord(runID, agent.Event{ID: newEventID(runID), Type: typ, Timestamp: time.Now().UTC(), Payload: payload})
+	if _, err := r.st.AppendEvents(ctx, runID, expectedSeq, []store.EventRecord{rec}); err != nil {
+		return errmodel.System("store_error", "failed to record lifecycle event", map[string]any{"run_id": runID, "type": typ}, err)
+	}
+	r.recordRun(ctx, runID)
+	return nil
+}
+
+// queue records an event handled while the run is paused, for Resume to process.
+func (r *Runner) queue(ctx context.Context, span trace.Span, c *cycle, current agent.State, incoming agent.Event) (agent.State, error) {
+	queuedID := "queued-" + incoming.ID
+	if _, err := r.st.GetEventByID(ctx, queuedID); err == nil {
+		return current, nil
+	} else if err != sql.ErrNoRows {
+		return nil, errmodel.System("store_error", "failed to check existing event", map[string]any{"event_id": queuedID}, err)
+	}
+	if incoming.Timestamp.IsZero() {
+		incoming.Timestamp = time.Now().UTC()
+	}
+	c.stage(agentEventToRecord(c.runID, agent.Event{ID: queuedID, Type: EventRunEventQueued, Timestamp: time.Now().UTC(), Payload: incoming}))
+	s, _, err := r.finish(ctx, span, c, current, nil, nil)
+	return s, err
+}
@@ -0,0 +1,231 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/memstore"
+)
+
+// blockingHandler signals started and blocks until its context is done.
+type blockingHandler struct{ started chan struct{} }
+
+func (blockingHandler) CanHandle(intent agent.Intent) bool { return intent.Name == "emit_added" }
+
+func (h blockingHandler) Handle(ctx context.Context, s agent.State, intent agent.Intent) ([]agent.Event, error) {
+	close(h.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRunner_PauseResume_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-pause")
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+	runID := "run-pause"
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "p0", Type: "inc", Payload: map[string]any{"n": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Pause(ctx, runID); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Pause(ctx, runID); err != nil {
+		t.Fatalf("pause twice: %v", err)
+	}
+	if status, _ := r.Status(ctx, runID); status != RunPaused {
+		t.Fatalf("status=%s want paused", status)
+	}
+
+	// Events handled while paused are recorded but neither reduced nor dispatched.
+	for _, id := range []string{"p1", "p2"} {
+		s, err := r.HandleEvent(ctx, runID, agent.Event{ID: id, Type: "inc", Payload: map[string]any{"n": 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.(testState).Count; got != 3 {
+			t.Fatalf("count=%d want 3 while paused", got)
+		}
+	}
+	if got := countTypes(t, st, runID)[EventRunEventQueued]; got != 2 {
+		t.Fatalf("queued=%d want 2", got)
+	}
+
+	s, err := r.Resume(ctx, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 9 {
+		t.Fatalf("count=%d want 9 after resume", got)
+	}
+	if status, _ := r.Status(ctx, runID); status != RunActive {
+		t.Fatalf("status=%s want active", status)
+	}
+	// Resuming an active run has no effect, and replays do not apply queued events twice.
+	if s, err = r.Resume(ctx, runID); err != nil || s.(testState).Count != 9 {
+		t.Fatalf("resume again state=%v err=%v", s, err)
+	}
+	r2 := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+	if s, err = r2.HandleEvent(ctx, runID, agent.Event{ID: "p3", Type: "noop"}); err != nil || s.(testState).Count != 9 {
+		t.Fatalf("replayed state=%v err=%v", s, err)
+	}
+}
+
+// flakyReducer fails the "flaky" event while fails is positive, and reduces the others like
+// testReducer.
+type flakyReducer struct{ fails *int }
+
+func (r flakyReducer) Reduce(ctx context.Context, current agent.State, event agent.Event) (agent.State, []agent.Intent, error) {
+	if event.Type == "flaky" && *r.fails > 0 {
+		*r.fails--
+		return nil, nil, errmodel.Validation("flaky", "flaky event", nil)
+	}
+	return testReducer{}.Reduce(ctx, current, event)
+}
+
+func TestRunner_ResumeRetriesFailedDrain(t *testing.T) {
+	ctx := context.Background()
+	st := memstore.New()
+	fails := 1
+	r := NewRunner(st, flakyReducer{fails: &fails}, nil, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+	runID := "run-drain"
+	if err := r.Pause(ctx, runID); err != nil {
+		t.Fatal(err)
+	}
+	for _, ev := range []agent.Event{
+		{ID: "q1", Type: "added", Payload: map[string]any{"n": 1}},
+		{ID: "q2", Type: "flaky"},
+		{ID: "q3", Type: "added", Payload: map[string]any{"n": 10}},
+	} {
+		if _, err := r.HandleEvent(ctx, runID, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The drain stops at the failing event and the run stays paused, with its events queued.
+	if _, err := r.Resume(ctx, runID); !errmodel.HasCode(err, "flaky") {
+		t.Fatalf("err=%v want flaky", err)
+	}
+	if status, _ := r.Status(ctx, runID); status != RunPaused {
+		t.Fatalf("status=%s want paused", status)
+	}
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "q4", Type: "added", Payload: map[string]any{"n": 100}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resuming again handles the remaining events once each.
+	s, err := r.Resume(ctx, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 111 {
+		t.Fatalf("count=%d want 111", got)
+	}
+	if status, _ := r.Status(ctx, runID); status != RunActive {
+		t.Fatalf("status=%s want active", status)
+	}
+	evs, err := st.ListEvents(ctx, runID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := map[string]int{}
+	for _, e := range evs {
+		types[e.Type]++
+	}
+	if types[EventRunEventQueued] != 4 || types["added"] != 3 || types["flaky"] != 1 || types[EventRunResumed] != 1 {
+		t.Fatalf("events=%v", types)
+	}
+	if evs[len(evs)-1].Type != EventRunResumed {
+		t.Fatalf("last event=%s want %s", evs[len(evs)-1].Type, EventRunResumed)
+	}
+}
+
+func TestRunner_LifecycleEvictsEndedRuns(t *testing.T) {
+	ctx := context.Background()
+	st := memstore.New()
+	r := NewRunner(st, testReducer{}, nil, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+	cached := func(runID string) bool {
+		r.lifecycles.mu.Lock()
+		defer r.lifecycles.mu.Unlock()
+		_, ok := r.lifecycles.runs[runID]
+		return ok
+	}
+	if err := r.Pause(ctx, "run-live"); err != nil {
+		t.Fatal(err)
+	}
+	if !cached("run-live") {
+		t.Fatal("paused run not cached")
+	}
+	if _, err := r.HandleEvent(ctx, "run-done", agent.Event{ID: "d1", Type: "added", Payload: map[string]any{"n": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.AppendEvents(ctx, "run-done", store.AnySeq, []store.EventRecord{{EventID: "d2", RunID: "run-done", Type: EventRunCompleted, Payload: []byte(`{}`)}}); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := r.Status(ctx, "run-done"); status != RunCompleted || cached("run-done") {
+		t.Fatalf("status=%s cached=%v want completed and evicted", status, cached("run-done"))
+	}
+	if err := r.Cancel(ctx, "run-live", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := r.Status(ctx, "run-live"); status != RunCancelled || cached("run-live") {
+		t.Fatalf("status=%s cached=%v want cancelled and evicted", status, cached("run-live"))
+	}
+}
+
+func TestRunner_Cancel_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-cancel")
+	h := blockingHandler{started: make(chan struct{})}
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{h}, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+	runID := "run-cancel"
+
+	// Cancel interrupts the effect in flight.
+	errc := make(chan error, 1)
+	go func() {
+		_, err := r.HandleEvent(ctx, runID, agent.Event{ID: "c1", Type: "inc", Payload: map[string]any{"n": 1}})
+		errc <- err
+	}()
+	<-h.started
+	if err := r.Cancel(ctx, runID, "user request"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errc:
+		if !errmodel.HasCode(err, "run_cancelled") {
+			t.Fatalf("err=%v want run_cancelled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("HandleEvent was not interrupted")
+	}
+	if status, _ := r.Status(ctx, runID); status != RunCancelled {
+		t.Fatalf("status=%s want cancelled", status)
+	}
+
+	// New events and transitions are rejected.
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "c2", Type: "noop"}); !errmodel.HasCode(err, "run_cancelled") {
+		t.Fatalf("err=%v want run_cancelled", err)
+	}
+	if err := r.Pause(ctx, runID); !errmodel.HasCode(err, "run_cancelled") {
+		t.Fatalf("pause err=%v want run_cancelled", err)
+	}
+	if err := r.Cancel(ctx, runID, "again"); err != nil {
+		t.Fatalf("cancel twice: %v", err)
+	}
+	if got := countTypes(t, st, runID)[EventRunCancelled]; got != 1 {
+		t.Fatalf("run_cancelled events=%d want 1", got)
+	}
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+)
+
+// runLocks serializes work per run within a process. Locks are reference counted and
+// dropped once no caller holds or waits for them, so idle runs do not accumulate.
+type runLocks struct {
+	mu    sync.Mutex
+	locks map[string]*runLock
+}
+
+type runLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// acquire blocks until the lock for runID is held or ctx is done.
+// The returned release func must be called exactly once when acquire succeeds.
+func (l *runLocks) acquire(ctx context.Context, runID string) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*runLock{}
+	}
+	lk, ok := l.locks[runID]
+	if !ok {
+		lk = &runLock{ch: make(chan struct{}, 1)}
+		l.locks[runID] = lk
+	}
+	lk.refs++
+	l.mu.Unlock()
+
+	select {
+	case lk.ch <- struct{}{}:
+		return func() {
+			<-lk.ch
+			l.unref(runID, lk)
+		}, nil
+	case <-ctx.Done():
+		l.unref(runID, lk)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *runLocks) unref(runID string, lk *runLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lk.refs--
+	if lk.refs == 0 {
+		delete(l.locks, runID)
+	}
+}
@@ -0,0 +1,195 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithOutbox makes the Runner asynchronous: instead of executing intents inline, each cycle
+// enqueues them to the given outbox queue, atomically with its events. A Worker drains the queue
+// by executing every intent in a cycle of its own, which completes the outbox item and, in loop
+// mode, enqueues the follow-up intents. The queue name must be unique to the set of handlers
+// this Runner has, since any Worker on the queue may claim any of its items. With WithLoop,
+// maxSteps bounds the length of a chain of follow-up intents.
+func WithOutbox(ob store.OutboxStore, queue string) RunnerOption {
+	return func(r *Runner) {
+		r.outbox = ob
+		r.outboxQueue = queue
+		r.committer = ob
+	}
+}
+
+// enqueue adds intents to the items the cycle enqueues, at the given chain depth.
+func (r *Runner) enqueue(c *cycle, intents []agent.Intent, depth int) error {
+	if len(intents) == 0 {
+		return nil
+	}
+	if r.maxSteps > 0 && depth > r.maxSteps {
+		return errmodel.Policy("max_steps_exceeded", "event loop exceeded max steps", map[string]any{"run_id": c.runID, "max_steps": r.maxSteps})
+	}
+	now := time.Now().UTC()
+	for _, it := range intents {
+		c.enqueue = append(c.enqueue, store.OutboxRecord{
+			ItemID:         uuid.NewString(),
+			Queue:          r.outboxQueue,
+			RunID:          c.runID,
+			IntentName:     it.Name,
+			IdempotencyKey: it.IdempotencyKey,
+			Args:           it.Args,
+			Depth:          depth,
+			AvailableAt:    now,
+			CreatedAt:      now,
+		})
+	}
+	return nil
+}
+
+// commit writes the cycle's events, together with its outbox, dead letter and timer changes when
+// any of them is set.
+func (r *Runner) commit(ctx context.Context, c *cycle) error {
+	events, err := r.seal(ctx, c.runID, c.pending)
+	if err != nil {
+		return err
+	}
+	var recs []store.EventRecord
+	if r.committer == nil {
+		recs, err = r.st.AppendEvents(ctx, c.runID, c.base, events)
+	} else {
+		recs, err = r.committer.CommitCycle(ctx, store.CycleCommit{
+			RunID:       c.runID,
+			ExpectedSeq: c.base,
+			Events:      events,
+			Enqueue:     c.enqueue,
+			Complete:    c.complete,
+			Owner:       c.owner,
+			DeadLetters: c.deadLetters,
+			Resolve:     c.resolve,
+			Timers:      c.timers,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	c.committed(recs)
+	return nil
+}
+
+// processOutbox executes a claimed outbox item in a new cycle of its run. The item is removed
+// when the cycle commits, including when the intent exhausts its retry policy. If owner lost the
+// lease in the meantime, nothing is committed and a lease_lost error is returned. Items of a
+// paused run are parked until Resume wakes them and fail with run_paused; items of a cancelled
+// run are removed without being executed.
+func (r *Runner) processOutbox(ctx context.Context, item store.OutboxRecord, owner string) error {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.ProcessOutbox", trace.WithAttributes(
+		attribute.String("run.id", item.RunID),
+		attribute.String("intent.name", item.IntentName),
+		attribute.String("outbox.item_id", item.ItemID),
+	))
+	defer span.End()
+
+	ctx, untrack := r.cancels.track(ctx, item.RunID)
+	defer untrack()
+	release, err := r.locks.acquire(ctx, item.RunID)
+	if err != nil {
+		return errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": item.RunID}, err)
+	}
+	defer release()
+
+	for attempt := 0; ; attempt++ {
+		committed, err := r.processItem(ctx, span, item, owner)
+		if err != nil && !committed && errmodel.HasCode(err, "conflict") && attempt < r.conflictRetries {
+			span.AddEvent("conflict_retry", trace.WithAttributes(attribute.Int("attempt", attempt+1)))
+			continue
+		}
+		return cancelledErr(ctx, item.RunID, err)
+	}
+}
+
+func (r *Runner) processItem(ctx context.Context, span trace.Span, item store.OutboxRecord, owner string) (bool, error) {
+	// Intents of paused runs wait for Resume; those of cancelled runs are dropped. The status is
+	// checked first so that a paused run is not replayed for nothing.
+	lc, err := r.lifecycle(ctx, item.RunID)
+	if err != nil {
+		return false, err
+	}
+	if lc.status == RunPaused {
+		return false, r.park(ctx, item, owner)
+	}
+
+	current, lastSeq, err := r.replayState(ctx, item.RunID)
+	if err != nil {
+		return false, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
+	}
+	c := newCycle(item.RunID, lastSeq)
+	c.complete, c.owner = item.ItemID, owner
+	if lc.status == RunCancelled {
+		_, committed, err := r.finish(ctx, span, c, current, nil, nil)
+		return committed, err
+	}
+
+	it := agent.Intent{Name: item.IntentName, Args: item.Args, IdempotencyKey: item.IdempotencyKey}
+	_, committed, err := r.dispatch(ctx, span, c, current, it, item.Depth)
+	return committed, err
+}
+
+// parkedUntil is the availability of the outbox items of paused runs, which only wakeOutbox
+// brings forward.
+var parkedUntil = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// park gives up the lease of an item of a paused run until the run is resumed or cancelled, and
+// returns a run_paused error. A Resume in another process may have woken the run's items just
+// before the item was parked, so the status is checked again afterwards.
+func (r *Runner) park(ctx context.Context, item store.OutboxRecord, owner string) error {
+	if err := r.outbox.ReleaseOutbox(ctx, item.ItemID, owner, parkedUntil); err != nil {
+		return errmodel.System("store_error", "failed to park outbox item", map[string]any{"item_id": item.ItemID}, err)
+	}
+	if lc, err := r.lifecycle(ctx, item.RunID); err != nil || lc.status != RunPaused {
+		if err := r.wakeOutbox(ctx, item.RunID); err != nil {
+			return err
+		}
+	}
+	return runPausedError(item.RunID)
+}
+
+// wakeOutbox makes the parked outbox items of a run available again.
+func (r *Runner) wakeOutbox(ctx context.Context, runID string) error {
+	if r.outbox == nil {
+		return nil
+	}
+	if _, err := r.outbox.WakeOutbox(ctx, runID, time.Now()); err != nil {
+		return errmodel.System("store_error", "failed to wake outbox items", map[string]any{"run_id": runID}, err)
+	}
+	return nil
+}
+
+// dispatch executes an intent at the given chain depth in the cycle, enqueues the follow-up
+// intents in loop mode and finishes the cycle.
+func (r *Runner) dispatch(ctx context.Context, span trace.Span, c *cycle, current agent.State, it agent.Intent, depth int) (agent.State, bool, error) {
+	current, followUps, err := r.apply(ctx, c, current, r.execute(ctx, c, current, it))
+	var (
+		failed  []*intentFailedError
+		stopErr error
+	)
+	if err != nil {
+		span.RecordError(err)
+		var f *intentFailedError
+		if !errors.As(err, &f) {
+			return nil, false, err
+		}
+		failed = append(failed, f)
+		stopErr = f.err
+	} else if r.loop && (r.halt == nil || !r.halt(current)) {
+		stopErr = r.enqueue(c, followUps, depth+1)
+	}
+	return r.finish(ctx, span, c, current, failed, stopErr)
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wilhg/orch/pkg/agent"
+)
+
+// WithParallelism enables concurrent dispatch of intents the reducer marks as Parallel, using a
+// pool of at most n workers. Consecutive parallel intents are executed together against the
+// state at fan-out; their events are staged and reduced in intent order once all of them are
+// done, so replay stays deterministic. If n <= 1, intents are dispatched one after another.
+func WithParallelism(n int) RunnerOption {
+	return func(r *Runner) { r.parallelism = n }
+}
+
+// nextBatch returns the intents at the head of queue to execute together: the run of
+// consecutive Parallel intents, or just the first intent. A batch ends before an intent that
+// shares its idempotency key with an earlier one, so that intent sees the claim and is skipped.
+// budget > 0 caps the batch size.
+func (r *Runner) nextBatch(queue []agent.Intent, budget int) []agent.Intent {
+	if r.parallelism <= 1 || !queue[0].Parallel {
+		return queue[:1]
+	}
+	keys := map[string]bool{}
+	n := 0
+	for _, it := range queue {
+		if !it.Parallel || (budget > 0 && n >= budget) {
+			break
+		}
+		if it.IdempotencyKey != "" {
+			if keys[it.IdempotencyKey] {
+				break
+			}
+			keys[it.IdempotencyKey] = true
+		}
+		n++
+	}
+	return queue[:n]
+}
+
+// executeAll executes the batch and returns the results in intent order. A single intent runs
+// directly against c; larger batches run on branches of c in the worker pool.
+func (r *Runner) executeAll(ctx context.Context, c *cycle, current agent.State, batch []agent.Intent) []effectResult {
+	if len(batch) == 1 {
+		return []effectResult{r.execute(ctx, c, current, batch[0])}
+	}
+	results := make([]effectResult, len(batch))
+	sem := make(chan struct{}, r.parallelism)
+	var wg sync.WaitGroup
+	for i, it := range batch {
+		b := c.branch()
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = r.execute(ctx, b, current, it)
+			results[i].branch = b
+		})
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+)
+
+type fanState struct {
+	runID string
+	Order []int
+}
+
+func (s fanState) RunID() string { return s.runID }
+func (s fanState) Clone() agent.State {
+	return fanState{runID: s.runID, Order: append([]int(nil), s.Order...)}
+}
+
+// fanReducer fans a "fan" event out into n parallel "work" intents and records the order in
+// which their "done" events are reduced.
+type fanReducer struct{}
+
+func (fanReducer) Reduce(ctx context.Context, current agent.State, event agent.Event) (agent.State, []agent.Intent, error) {
+	s := current.(fanState).Clone().(fanState)
+	var p struct {
+		N int `json:"n"`
+		I int `json:"i"`
+	}
+	decode(event.Payload, &p)
+	switch event.Type {
+	case "fan":
+		var intents []agent.Intent
+		for i := range p.N {
+			intents = append(intents, agent.Intent{Name: "work", Args: map[string]any{"i": i}, IdempotencyKey: fmt.Sprintf("work-%d", i), Parallel: true})
+		}
+		return s, intents, nil
+	case "done":
+		s.Order = append(s.Order, p.I)
+	}
+	return s, nil, nil
+}
+
+// workHandler finishes later intents first and tracks the peak number of concurrent calls.
+type workHandler struct {
+	mu     *sync.Mutex
+	active *int
+	peak   *int
+}
+
+func (workHandler) CanHandle(intent agent.Intent) bool { return intent.Name == "work" }
+
+func (h workHandler) Handle(ctx context.Context, s agent.State, intent agent.Intent) ([]agent.Event, error) {
+	var args struct {
+		I int `json:"i"`
+	}
+	decode(intent.Args, &args)
+	h.mu.Lock()
+	*h.active++
+	*h.peak = max(*h.peak, *h.active)
+	h.mu.Unlock()
+	time.Sleep(time.Duration(10-args.I) * 5 * time.Millisecond)
+	h.mu.Lock()
+	*h.active--
+	h.mu.Unlock()
+	return []agent.Event{{Type: "done", Payload: map[string]any{"i": args.I}}}, nil
+}
+
+func TestRunner_ParallelDispatch_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-parallel")
+	var (
+		mu           sync.Mutex
+		active, peak int
+	)
+	h := workHandler{mu: &mu, active: &active, peak: &peak}
+	newState := func(runID string) agent.State { return fanState{runID: runID} }
+	r := NewRunner(st, fanReducer{}, []agent.EffectHandler{h}, newState, WithParallelism(3))
+
+	s, err := r.HandleEvent(ctx, "run-fan", agent.Event{ID: "f1", Type: "fan", Payload: map[string]any{"n": 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak < 2 || peak > 3 {
+		t.Fatalf("peak concurrency=%d want 2..3", peak)
+	}
+	if got := fmt.Sprint(s.(fanState).Order); got != "[0 1 2 3 4 5]" {
+		t.Fatalf("reduced order=%s", got)
+	}
+
+	// Events are committed in intent order, so replay reproduces the same state.
+	evs, err := st.ListEvents(ctx, "run-fan", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := 0
+	for _, e := range evs {
+		if e.Type != "done" {
+			continue
+		}
+		var p struct {
+			I int `json:"i"`
+		}
+		decode(e.Payload, &p)
+		if p.I != next {
+			t.Fatalf("done event %d at position of %d", p.I, next)
+		}
+		next++
+	}
+	replayed, _, err := r.replayState(ctx, "run-fan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(replayed.(fanState).Order); got != "[0 1 2 3 4 5]" {
+		t.Fatalf("replayed order=%s", got)
+	}
+
+	// Without WithParallelism the same intents run one at a time.
+	peak = 0
+	r = NewRunner(st, fanReducer{}, []agent.EffectHandler{h}, newState)
+	if _, err := r.HandleEvent(ctx, "run-serial", agent.Event{ID: "f2", Type: "fan", Payload: map[string]any{"n": 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if peak != 1 {
+		t.Fatalf("peak concurrency=%d want 1", peak)
+	}
+}
@@ -0,0 +1,265 @@
+package runtime
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithRunEraser lets the Runner delete runs, on request with DeleteRun or when they expire
+// under ApplyRetention.
+func WithRunEraser(e store.RunEraser) RunnerOption {
+	return func(r *Runner) { r.eraser = e }
+}
+
+// RunRetention deletes finished runs, completed, failed or cancelled, once they have not been
+// updated for MaxAge. Labels and AgentType restrict the runs it applies to; empty fields match
+// every run.
+type RunRetention struct {
+	Labels    map[string]string
+	AgentType string
+	MaxAge    time.Duration
+	// ExportDir, if set, receives an export of each run (see ExportRunFile) before it is deleted.
+	ExportDir string
+}
+
+// finishedStatuses are the statuses of runs that retention policies delete.
+var finishedStatuses = []RunStatus{RunCompleted, RunFailed, RunCancelled}
+
+// exportPage bounds the events read at once while exporting a run.
+const exportPage = 500
+
+// DeleteRun interrupts the effects in flight for the run and permanently deletes all of its
+// records (see store.RunEraser). Events handled for the run afterwards start a new run with the
+// same ID.
+func (r *Runner) DeleteRun(ctx context.Context, runID string, e Erasure) (store.RunDeletion, error) {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.DeleteRun", trace.WithAttributes(attribute.String("run.id", runID)))
+	defer span.End()
+	if runID == "" {
+		return store.RunDeletion{}, errmodel.Validation("missing_run", "runID is empty", nil)
+	}
+	if r.eraser == nil {
+		return store.RunDeletion{}, errmodel.Policy("erasure_disabled", "runner does not delete runs", nil)
+	}
+	r.cancels.cancel(runID, errRunCancelled)
+	release, err := r.locks.acquire(ctx, runID)
+	if err != nil {
+		return store.RunDeletion{}, errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": runID}, err)
+	}
+	defer release()
+
+	d, err := r.eraser.DeleteRun(ctx, runID)
+	if err != nil {
+		span.RecordError(err)
+		return store.RunDeletion{}, errmodel.System("store_error", "failed to delete run", map[string]any{"run_id": runID}, err)
+	}
+	r.lifecycles.mu.Lock()
+	delete(r.lifecycles.runs, runID)
+	r.lifecycles.mu.Unlock()
+	return d, r.recordAudit(ctx, "run_deleted", runID, e, map[string]any{
+		"events":       d.Events,
+		"snapshots":    d.Snapshots,
+		"outbox_items": d.OutboxItems,
+		"timers":       d.Timers,
+		"dead_letters": d.DeadLetters,
+	})
+}
+
+// exportedRun is the first line of a run export.
+type exportedRun struct {
+	RunID       string            `json:"run_id"`
+	Status      string            `json:"status,omitempty"`
+	AgentType   string            `json:"agent_type,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	ParentRunID string            `json:"parent_run_id,omitempty"`
+	LastSeq     int64             `json:"last_seq"`
+	CreatedAt   *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+	ExportedAt  time.Time         `json:"exported_at"`
+}
+
+// exportedEvent is a line of a run export for each of its events.
+type exportedEvent struct {
+	EventID   string          `json:"event_id"`
+	Seq       int64           `json:"seq"`
+	Position  int64           `json:"position"`
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type exportLine struct {
+	Run   *exportedRun   `json:"run,omitempty"`
+	Event *exportedEvent `json:"event,omitempty"`
+}
+
+// ExportRun writes the run to w as gzip-compressed JSON lines: {"run": ...} with its catalog
+// entry, then {"event": ...} for each event in sequence order. Payloads are written as stored,
+// so sensitive fields stay sealed. It returns the number of events written.
+func (r *Runner) ExportRun(ctx context.Context, w io.Writer, runID string) (int, error) {
+	if runID == "" {
+		return 0, errmodel.Validation("missing_run", "runID is empty", nil)
+	}
+	last, err := r.st.LastSeq(ctx, runID)
+	if err != nil {
+		return 0, errmodel.System("store_error", "failed to read run", map[string]any{"run_id": runID}, err)
+	}
+	head := exportedRun{RunID: runID, LastSeq: last, ExportedAt: time.Now().UTC()}
+	if r.runs != nil {
+		run, err := r.runs.GetRun(ctx, runID)
+		switch {
+		case err == nil:
+			head.Status, head.AgentType, head.Labels, head.ParentRunID = run.Status, run.AgentType, run.Labels, run.ParentRunID
+			head.CreatedAt, head.UpdatedAt = &run.CreatedAt, &run.UpdatedAt
+		case err != sql.ErrNoRows:
+			return 0, errmodel.System("store_error", "failed to read run", map[string]any{"run_id": runID}, err)
+		}
+	}
+	if last == 0 && head.CreatedAt == nil {
+		return 0, errmodel.Validation("not_found", "run not found", map[string]any{"run_id": runID})
+	}
+
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(exportLine{Run: &head}); err != nil {
+		return 0, err
+	}
+	n := 0
+	for after := int64(0); ; {
+		events, err := r.st.ListEvents(ctx, runID, after, exportPage)
+		if err != nil {
+			return n, errmodel.System("store_error", "failed to list events", map[string]any{"run_id": runID}, err)
+		}
+		for _, e := range events {
+			line := exportLine{Event: &exportedEvent{
+				EventID:   e.EventID,
+				Seq:       e.Seq,
+				Position:  e.Position,
+				Type:      e.Type,
+				Version:   e.Version,
+				Payload:   e.Payload,
+				CreatedAt: e.CreatedAt,
+			}}
+			if err := enc.Encode(line); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(events) < exportPage {
+			break
+		}
+		after = events[len(events)-1].Seq
+	}
+	return n, zw.Close()
+}
+
+// ExportRunFile exports the run (see ExportRun) to <dir>/<run ID>.jsonl.gz, replacing an earlier
+// export, and records it in the audit trail. It returns the path of the file.
+func (r *Runner) ExportRunFile(ctx context.Context, dir, runID string, e Erasure) (string, error) {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.ExportRunFile", trace.WithAttributes(attribute.String("run.id", runID)))
+	defer span.End()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", errmodel.System("export_error", "failed to create export directory", map[string]any{"dir": dir}, err)
+	}
+	path := filepath.Join(dir, url.PathEscape(runID)+".jsonl.gz")
+	// Write to a temporary file first so that a failed export leaves no partial archive.
+	f, err := os.CreateTemp(dir, ".export-*")
+	if err != nil {
+		return "", errmodel.System("export_error", "failed to create export file", map[string]any{"dir": dir}, err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	n, err := r.ExportRun(ctx, f, runID)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		span.RecordError(err)
+		if errmodel.From(err).Code != "internal" {
+			return "", err
+		}
+		return "", errmodel.System("export_error", "failed to write export file", map[string]any{"path": path}, err)
+	}
+	return path, r.recordAudit(ctx, "run_exported", runID, e, map[string]any{"path": path, "events": n})
+}
+
+// ApplyRetention deletes the finished runs that the policies select, exporting them first where
+// a policy says so. It needs WithRunCatalog and WithRunEraser, and returns the IDs of the
+// deleted runs; on error, the runs deleted until then.
+func (r *Runner) ApplyRetention(ctx context.Context, policies ...RunRetention) ([]string, error) {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.ApplyRetention")
+	defer span.End()
+	if r.runs == nil || r.eraser == nil {
+		return nil, errmodel.Policy("erasure_disabled", "retention needs a run catalog and eraser", nil)
+	}
+	var deleted []string
+	for i, p := range policies {
+		if p.MaxAge <= 0 {
+			return deleted, errmodel.Validation("bad_retention", "retention policy needs a positive max age", map[string]any{"policy": i})
+		}
+		expired, err := r.expiredRuns(ctx, p)
+		if err != nil {
+			return deleted, err
+		}
+		e := Erasure{Reason: "retention"}
+		for _, runID := range expired {
+			if p.ExportDir != "" {
+				if _, err := r.ExportRunFile(ctx, p.ExportDir, runID, e); err != nil {
+					return deleted, err
+				}
+			}
+			if _, err := r.DeleteRun(ctx, runID, e); err != nil {
+				return deleted, err
+			}
+			deleted = append(deleted, runID)
+		}
+	}
+	span.SetAttributes(attribute.Int("runs.deleted", len(deleted)))
+	return deleted, nil
+}
+
+// expiredRuns lists the finished runs selected by p that were last updated before its max age.
+func (r *Runner) expiredRuns(ctx context.Context, p RunRetention) ([]string, error) {
+	cutoff := time.Now().Add(-p.MaxAge)
+	var out []string
+	for _, status := range finishedStatuses {
+		f := store.RunFilter{Status: string(status), AgentType: p.AgentType, Labels: p.Labels, Limit: 100}
+		for {
+			page, err := r.runs.ListRuns(ctx, f)
+			if err != nil {
+				return nil, errmodel.System("store_error", "failed to list runs", map[string]any{"status": status}, err)
+			}
+			for _, run := range page.Runs {
+				if run.UpdatedAt.Before(cutoff) {
+					out = append(out, run.RunID)
+				}
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			f.Cursor = page.NextCursor
+		}
+	}
+	return out, nil
+}
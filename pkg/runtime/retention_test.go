@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+func TestRunner_Retention_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-retention")
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithRunCatalog(st, "counter"), WithRunEraser(st), WithAudit(st))
+
+	// ret-a and ret-b finish; ret-c is still active.
+	for runID, team := range map[string]string{"ret-a": "a", "ret-b": "b", "ret-c": "a"} {
+		if _, err := st.CreateRun(ctx, store.RunRecord{RunID: runID, Status: string(RunActive), AgentType: "counter", Labels: map[string]string{"team": team}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: runID + "-1", Type: "inc", Payload: map[string]any{"n": 1}}); err != nil {
+			t.Fatal(err)
+		}
+		if runID != "ret-c" {
+			if err := r.Cancel(ctx, runID, "done"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Nothing is old enough yet.
+	if deleted, err := r.ApplyRetention(ctx, RunRetention{Labels: map[string]string{"team": "a"}, MaxAge: time.Hour}); err != nil || len(deleted) != 0 {
+		t.Fatalf("deleted=%v err=%v", deleted, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	dir := t.TempDir()
+	deleted, err := r.ApplyRetention(ctx, RunRetention{Labels: map[string]string{"team": "a"}, MaxAge: time.Millisecond, ExportDir: dir})
+	if err != nil || !slices.Equal(deleted, []string{"ret-a"}) {
+		t.Fatalf("deleted=%v err=%v want [ret-a]", deleted, err)
+	}
+	if events, _ := st.ListEvents(ctx, "ret-a", 0, 0); len(events) != 0 {
+		t.Fatalf("events=%+v want none", events)
+	}
+	if _, err := st.GetRun(ctx, "ret-a"); err != sql.ErrNoRows {
+		t.Fatalf("err=%v want sql.ErrNoRows", err)
+	}
+	for _, runID := range []string{"ret-b", "ret-c"} {
+		if last, _ := st.LastSeq(ctx, runID); last == 0 {
+			t.Fatalf("%s lost its events", runID)
+		}
+	}
+
+	// The export holds the run and its events as they were.
+	f, err := os.Open(filepath.Join(dir, "ret-a.jsonl.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []exportLine
+	for sc := bufio.NewScanner(zr); sc.Scan(); {
+		var l exportLine
+		if err := json.Unmarshal(sc.Bytes(), &l); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) < 2 || lines[0].Run == nil || lines[0].Run.Status != string(RunCancelled) || lines[0].Run.Labels["team"] != "a" {
+		t.Fatalf("export=%+v", lines)
+	}
+	if int64(len(lines)-1) != lines[0].Run.LastSeq || lines[1].Event.EventID != "ret-a-1" {
+		t.Fatalf("export has %d events, run=%+v first=%+v", len(lines)-1, lines[0].Run, lines[1].Event)
+	}
+
+	// Runs can be deleted on request too, whatever their status.
+	d, err := r.DeleteRun(ctx, "ret-c", Erasure{Actor: "ops", Reason: "test"})
+	if err != nil || d.Events == 0 {
+		t.Fatalf("deletion=%+v err=%v", d, err)
+	}
+	page, err := st.ListAudit(ctx, store.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, a := range page.Entries {
+		actions = append(actions, a.RunID+":"+a.Action)
+	}
+	if want := []string{"ret-a:run_exported", "ret-a:run_deleted", "ret-c:run_deleted"}; !slices.Equal(actions, want) {
+		t.Fatalf("audit=%v want %v", actions, want)
+	}
+	if _, err := r.ExportRun(ctx, io.Discard, "ret-c"); !errmodel.HasCode(err, "not_found") {
+		t.Fatalf("err=%v want not_found", err)
+	}
+	if _, err := r.ApplyRetention(ctx, RunRetention{}); !errmodel.HasCode(err, "bad_retention") {
+		t.Fatalf("err=%v want bad_retention", err)
+	}
+}
@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// RetryPolicy controls how the Runner retries a failing effect handler.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first; values <= 1 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts; zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each attempt; values < 1 default to 2.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction (0..1) of its value.
+	Jitter float64
+	// AttemptTimeout bounds a single Handle call; zero means no timeout.
+	AttemptTimeout time.Duration
+	// RetryableCategories lists the errmodel categories worth retrying.
+	// Empty means DefaultRetryableCategories.
+	RetryableCategories []string
+}
+
+// DefaultRetryableCategories are retried when a policy does not list its own categories.
+// Validation, policy and tool errors are deterministic and fail on the first attempt.
+var DefaultRetryableCategories = []string{errmodel.CategoryNetwork, errmodel.CategoryModel, errmodel.CategorySystem}
+
+// WithRetryPolicy sets the retry policy for intents without a per-name policy.
+func WithRetryPolicy(p RetryPolicy) RunnerOption {
+	return func(r *Runner) { r.defaultRetry = p }
+}
+
+// WithIntentRetryPolicy sets the retry policy for intents with the given name.
+func WithIntentRetryPolicy(intentName string, p RetryPolicy) RunnerOption {
+	return func(r *Runner) {
+		if r.retries == nil {
+			r.retries = map[string]RetryPolicy{}
+		}
+		r.retries[intentName] = p
+	}
+}
+
+func (r *Runner) retryPolicy(intentName string) RetryPolicy {
+	if p, ok := r.retries[intentName]; ok {
+		return p
+	}
+	return r.defaultRetry
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	cats := p.RetryableCategories
+	if len(cats) == 0 {
+		cats = DefaultRetryableCategories
+	}
+	ce := errmodel.From(err)
+	return ce != nil && slices.Contains(cats, ce.Category)
+}
+
+// backoff returns the delay before the given attempt (2 for the first retry).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 2
+	}
+	d := float64(p.InitialBackoff)
+	for i := 2; i < attempt; i++ {
+		d *= mult
+		if p.MaxBackoff > 0 && d >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		j := min(p.Jitter, 1)
+		d += d * j * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,169 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/memstore"
+)
+
+// flakyHandler fails the first `failures` calls of "emit_added" with err, then emits "added".
+type flakyHandler struct {
+	failures int
+	err      error
+	calls    *int
+}
+
+func (flakyHandler) CanHandle(intent agent.Intent) bool { return intent.Name == "emit_added" }
+
+func (h flakyHandler) Handle(ctx context.Context, s agent.State, intent agent.Intent) ([]agent.Event, error) {
+	*h.calls++
+	if *h.calls <= h.failures {
+		return nil, h.err
+	}
+	var args struct {
+		N int `json:"n"`
+	}
+	decode(intent.Args, &args)
+	return []agent.Event{{Type: "added", Payload: map[string]any{"n": args.N}}}, nil
+}
+
+func countTypes(t *testing.T, st store.EventStore, runID string) map[string]int {
+	t.Helper()
+	evs, err := st.ListEvents(context.Background(), runID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]int{}
+	for _, e := range evs {
+		out[e.Type]++
+	}
+	return out
+}
+
+func TestRunner_RetryPolicy_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-retry")
+	newState := func(runID string) agent.State { return testState{runID: runID} }
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: 0.5}
+
+	// Transient network errors are retried until the handler succeeds.
+	calls := 0
+	netErr := errmodel.New(errmodel.CategoryNetwork, "unavailable", "upstream unavailable", nil)
+	r := NewRunner(st, reducerWithIdem{}, []agent.EffectHandler{flakyHandler{failures: 2, err: netErr, calls: &calls}}, newState,
+		WithIntentRetryPolicy("emit_added", policy))
+	s, err := r.HandleEvent(ctx, "run-retry", agent.Event{ID: "r1", Type: "inc", Payload: map[string]any{"n": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 3 {
+		t.Fatalf("count=%d want 3", got)
+	}
+	types := countTypes(t, st, "run-retry")
+	if calls != 3 || types["intent_attempt_failed"] != 2 || types["intent_processed"] != 1 {
+		t.Fatalf("calls=%d types=%v", calls, types)
+	}
+
+	// Exhausted attempts record the failure and release the claim.
+	calls = 0
+	r = NewRunner(st, reducerWithIdem{}, []agent.EffectHandler{flakyHandler{failures: 3, err: netErr, calls: &calls}}, newState,
+		WithIntentRetryPolicy("emit_added", policy))
+	_, err = r.HandleEvent(ctx, "run-exhaust", agent.Event{ID: "x1", Type: "inc", Payload: map[string]any{"n": 1}})
+	if ce := errmodel.From(err); ce == nil || ce.Code != "effect_error" {
+		t.Fatalf("err=%v want effect_error", err)
+	}
+	types = countTypes(t, st, "run-exhaust")
+	if calls != 3 || types["inc"] != 1 || types["intent_attempt_failed"] != 3 || types["intent_failed"] != 1 || types["intent_claimed"] != 0 {
+		t.Fatalf("calls=%d types=%v", calls, types)
+	}
+	// With the claim released, the same idempotency key runs again on the next trigger.
+	if _, err := r.HandleEvent(ctx, "run-exhaust", agent.Event{ID: "x2", Type: "inc", Payload: map[string]any{"n": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if types = countTypes(t, st, "run-exhaust"); types["intent_processed"] != 1 || types["added"] != 1 {
+		t.Fatalf("types=%v after rerun", types)
+	}
+
+	// Non-retryable categories fail on the first attempt.
+	calls = 0
+	r = NewRunner(st, reducerWithIdem{}, []agent.EffectHandler{flakyHandler{failures: 3, err: errmodel.Validation("bad_args", "bad", nil), calls: &calls}}, newState,
+		WithRetryPolicy(policy))
+	if _, err := r.HandleEvent(ctx, "run-noretry", agent.Event{ID: "n1", Type: "inc", Payload: map[string]any{"n": 1}}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls=%d want 1 for validation error", calls)
+	}
+}
+
+// attemptCountingReducer adds 10 to Count for every failed attempt it sees.
+type attemptCountingReducer struct{ reducerWithIdem }
+
+func (r attemptCountingReducer) Reduce(ctx context.Context, current agent.State, event agent.Event) (agent.State, []agent.Intent, error) {
+	if event.Type == "intent_attempt_failed" {
+		s := current.(testState)
+		s.Count += 10
+		return s, nil, nil
+	}
+	return r.reducerWithIdem.Reduce(ctx, current, event)
+}
+
+func TestRunner_FailedAttemptsAreReduced(t *testing.T) {
+	ctx := context.Background()
+	st := memstore.New()
+	calls := 0
+	netErr := errmodel.New(errmodel.CategoryNetwork, "unavailable", "upstream unavailable", nil)
+	r := NewRunner(st, attemptCountingReducer{}, []agent.EffectHandler{flakyHandler{failures: 2, err: netErr, calls: &calls}},
+		func(runID string) agent.State { return testState{runID: runID} },
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+	live, err := r.HandleEvent(ctx, "run-attempts", agent.Event{ID: "f1", Type: "inc", Payload: map[string]any{"n": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, _, err := r.State(ctx, "run-attempts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.(testState).Count != 23 || replayed.(testState).Count != 23 {
+		t.Fatalf("live=%+v replayed=%+v want count 23", live, replayed)
+	}
+}
+
+// slowHandler blocks until its context is done.
+type slowHandler struct{}
+
+func (slowHandler) CanHandle(intent agent.Intent) bool { return intent.Name == "emit_added" }
+
+func (slowHandler) Handle(ctx context.Context, s agent.State, intent agent.Intent) ([]agent.Event, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRunner_AttemptTimeout_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-timeout")
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{slowHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, AttemptTimeout: 10 * time.Millisecond}))
+	_, err := r.HandleEvent(ctx, "run-timeout", agent.Event{ID: "t1", Type: "inc", Payload: map[string]any{"n": 1}})
+	if !errmodel.HasCode(err, "effect_timeout") {
+		t.Fatalf("err=%v want effect_timeout cause", err)
+	}
+	if countTypes(t, st, "run-timeout")["intent_attempt_failed"] != 2 {
+		t.Fatalf("types=%v", countTypes(t, st, "run-timeout"))
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	want := []time.Duration{10, 20, 40, 50, 50}
+	for i, w := range want {
+		if got := p.backoff(i + 2); got != w*time.Millisecond {
+			t.Fatalf("attempt %d backoff=%v want %v", i+2, got, w*time.Millisecond)
+		}
+	}
+}
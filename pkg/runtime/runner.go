@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/wilhg/orch/pkg/agent"
 	"github.com/wilhg/orch/pkg/errmodel"
 	"github.com/wilhg/orch/pkg/store"
@@ -28,8 +32,57 @@ type Runner struct {
 	// snapshot settings
 	snapshotInterval int
 	snapshotCodec    SnapshotCodec
+	snapshotVersion  string
+	retention        store.SnapshotRetention
+	archive          store.EventArchiver
+
+	// loop settings
+	loop     bool
+	maxSteps int
+	halt     HaltFunc
+
+	// concurrency settings
+	locks           runLocks
+	conflictRetries int
+	parallelism     int
+
+	// effect retry settings
+	defaultRetry RetryPolicy
+	retries      map[string]RetryPolicy
+	deadLetters  store.DeadLetterStore
+
+	// committer writes cycles that carry more than events; set with an outbox, dead letters or
+	// timers
+	committer store.CycleCommitter
+
+	// outbox settings
+	outbox      store.OutboxStore
+	outboxQueue string
+
+	// timer settings
+	timers     store.TimerStore
+	timerQueue string
+
+	// lifecycle tracking
+	lifecycles lifecycles
+	cancels    runCancels
+
+	// run catalog settings
+	runs      store.RunStore
+	agentType string
+
+	// event schemas
+	events *agent.EventRegistry
+
+	// retention and erasure settings
+	keys   store.ShredKeyStore
+	audit  store.AuditStore
+	eraser store.RunEraser
 }
 
+// HaltFunc reports whether the event loop should stop dispatching intents for the given state.
+type HaltFunc func(s agent.State) bool
+
 // RunnerOption configures the Runner at construction time.
 type RunnerOption func(*Runner)
 
@@ -44,7 +97,37 @@ func WithSnapshot(codec SnapshotCodec, interval int) RunnerOption {
 	}
 }
 
-// SnapshotCodec encodes/decodes state for durable snapshots.
+// WithLoop enables the event-loop driver: intents the reducer emits for effect-produced events
+// are dispatched as well, until the reducer emits none or halt reports true for the current state.
+// maxSteps bounds the number of intents dispatched per HandleEvent call; if maxSteps <= 0 the
+// loop is unbounded. halt may be nil.
+func WithLoop(maxSteps int, halt HaltFunc) RunnerOption {
+	return func(r *Runner) {
+		r.loop = true
+		r.maxSteps = maxSteps
+		r.halt = halt
+	}
+}
+
+// WithConflictRetries sets how many times HandleEvent retries a cycle that lost an optimistic
+// concurrency check against another writer of the same run. Defaults to 3; n < 0 is treated as 0.
+func WithConflictRetries(n int) RunnerOption {
+	return func(r *Runner) {
+		if n < 0 {
+			n = 0
+		}
+		r.conflictRetries = n
+	}
+}
+
+// WithEventRegistry validates incoming and effect-produced events against their registered
+// schemas, stamping them with the current version, and upcasts older events during replay.
+func WithEventRegistry(reg *agent.EventRegistry) RunnerOption {
+	return func(r *Runner) { r.events = reg }
+}
+
+// SnapshotCodec encodes/decodes state for durable snapshots. JSONCodec covers JSON-serializable
+// states.
 type SnapshotCodec interface {
 	Encode(state agent.State) ([]byte, error)
 	Decode(runID string, data []byte) (agent.State, error)
@@ -52,7 +135,7 @@ type SnapshotCodec interface {
 
 // NewRunner constructs a new Runner.
 func NewRunner(st store.Store, r agent.Reducer, handlers []agent.EffectHandler, newState StateFactory, opts ...RunnerOption) *Runner {
-	rn := &Runner{st: st, reducer: r, handlers: handlers, newState: newState}
+	rn := &Runner{st: st, reducer: r, handlers: handlers, newState: newState, conflictRetries: 3}
 	for _, opt := range opts {
 		opt(rn)
 	}
@@ -61,6 +144,14 @@ func NewRunner(st store.Store, r agent.Reducer, handlers []agent.EffectHandler,
 
 // HandleEvent appends the incoming event, replays state, runs the reducer to compute intents,
 // dispatches intents to effect handlers, and appends resulting events.
+// With WithLoop, intents emitted for effect-produced events are dispatched as well.
+// All events of the cycle are committed atomically at the end with the sequence observed at
+// replay; nothing is written if any step fails, except when an intent exhausts its retry policy:
+// then the cycle is committed up to and including the failed attempts before the error is
+// returned. Calls for the same run are serialized within the Runner, and a cycle that conflicts
+// with another writer is replayed and retried, which re-executes its effects.
+// Events for a paused run are queued until Resume; events for a cancelled run are rejected with
+// a run_cancelled policy error (see Pause and Cancel).
 // Returns the final state after processing the entire cycle.
 func (r *Runner) HandleEvent(ctx context.Context, runID string, incoming agent.Event) (agent.State, error) {
 	tr := otel.Tracer("runtime/runner")
@@ -74,14 +165,122 @@ func (r *Runner) HandleEvent(ctx context.Context, runID string, incoming agent.E
 		return nil, errmodel.Validation("missing_run", "runID is empty", nil)
 	}
 	if incoming.ID == "" {
-		incoming.ID = fmt.Sprintf("e-%s-%d", runID, time.Now().UnixNano())
+		incoming.ID = newEventID(runID)
+	}
+
+	ctx, untrack := r.cancels.track(ctx, runID)
+	defer untrack()
+	release, err := r.locks.acquire(ctx, runID)
+	if err != nil {
+		return nil, errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": runID}, err)
+	}
+	defer release()
+
+	s, err := r.handleWithRetry(ctx, span, runID, incoming, false)
+	return s, cancelledErr(ctx, runID, err)
+}
+
+// State replays the current state of a run and returns it with the sequence it reflects.
+func (r *Runner) State(ctx context.Context, runID string) (agent.State, int64, error) {
+	s, seq, err := r.replayState(ctx, runID)
+	if err != nil {
+		return nil, 0, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
+	}
+	return s, seq, nil
+}
+
+// handleWithRetry runs the cycle for incoming, retrying it when it loses a concurrency check.
+// The caller holds the run lock. With resuming set, incoming is a queued event that Resume
+// handles while the run is still paused.
+func (r *Runner) handleWithRetry(ctx context.Context, span trace.Span, runID string, incoming agent.Event, resuming bool) (agent.State, error) {
+	for attempt := 0; ; attempt++ {
+		s, err := r.handleEvent(ctx, span, runID, incoming, resuming)
+		if err != nil && errmodel.HasCode(err, "conflict") && attempt < r.conflictRetries {
+			span.AddEvent("conflict_retry", trace.WithAttributes(attribute.Int("attempt", attempt+1)))
+			continue
+		}
+		return s, err
+	}
+}
+
+// cycle collects the events of one HandleEvent call so they can be committed atomically.
+// With an outbox, the cycle also carries the items to enqueue and the item it completes; with
+// dead letters, those of the intents it failed; with timers, those it schedules.
+type cycle struct {
+	runID   string
+	base    int64 // last sequence observed at replay
+	last    int64 // last sequence of the run, once committed
+	pending []store.EventRecord
+	staged  map[string]bool
+
+	enqueue  []store.OutboxRecord
+	complete string // outbox item executed by this cycle
+	owner    string // lease owner of the completed item
+
+	deadLetters []store.DeadLetterRecord
+	requeued    *store.DeadLetterRecord // dead letter whose intent this cycle dispatches again
+	resolve     string                  // dead letter to remove
+
+	timers []store.TimerRecord
+}
+
+func newCycle(runID string, base int64) *cycle {
+	return &cycle{runID: runID, base: base, last: base, staged: map[string]bool{}}
+}
+
+// stage queues rec for the commit at the end of the cycle.
+func (c *cycle) stage(rec store.EventRecord) {
+	rec.RunID = c.runID
+	c.pending = append(c.pending, rec)
+	c.staged[rec.EventID] = true
+}
+
+// unstage drops a staged event by ID.
+func (c *cycle) unstage(eventID string) {
+	if !c.staged[eventID] {
+		return
+	}
+	delete(c.staged, eventID)
+	c.pending = slices.DeleteFunc(c.pending, func(rec store.EventRecord) bool { return rec.EventID == eventID })
+}
+
+// branch returns an empty cycle for executing an intent concurrently with others. It sees the
+// events staged so far for idempotency checks; its own events are added back with merge.
+func (c *cycle) branch() *cycle {
+	b := newCycle(c.runID, c.base)
+	maps.Copy(b.staged, c.staged)
+	return b
+}
+
+// merge stages the events of a branch after the events already staged, and takes its timers.
+func (c *cycle) merge(b *cycle) {
+	for _, rec := range b.pending {
+		c.stage(rec)
+	}
+	c.timers = append(c.timers, b.timers...)
+}
+
+// committed records the sequences assigned by the commit. Events that were already stored are
+// returned with their earlier sequence, so they do not advance the run.
+func (c *cycle) committed(recs []store.EventRecord) {
+	for _, rec := range recs {
+		if rec.RunID == c.runID && rec.Seq > c.last {
+			c.last = rec.Seq
+		}
 	}
+}
+
+// seq returns the last sequence of the run: the one observed at replay until the cycle is
+// committed, and the last one committed afterwards.
+func (c *cycle) seq() int64 { return c.last }
 
+func (r *Runner) handleEvent(ctx context.Context, span trace.Span, runID string, incoming agent.Event, resuming bool) (agent.State, error) {
 	// 1) Rebuild state by replaying from latest snapshot + subsequent events.
 	current, lastSeq, err := r.replayState(ctx, runID)
 	if err != nil {
 		return nil, errmodel.System("store_error", "failed to replay state", map[string]any{"phase": "replay"}, err)
 	}
+	c := newCycle(runID, lastSeq)
 
 	// 2) Apply reducer on the incoming event to get next state and intents.
 	// If the incoming event was already recorded (duplicate delivery), skip processing.
@@ -92,6 +291,23 @@ func (r *Runner) HandleEvent(ctx context.Context, runID string, incoming agent.E
 			return nil, errmodel.System("store_error", "failed to check existing event", map[string]any{"event_id": incoming.ID}, err)
 		}
 	}
+	if incoming, err = r.prepare(incoming); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	// Paused runs queue the event for Resume; cancelled runs reject it.
+	lc, err := r.lifecycle(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	switch lc.status {
+	case RunCancelled:
+		return nil, runCancelledError(runID)
+	case RunPaused:
+		if !resuming {
+			return r.queue(ctx, span, c, current, incoming)
+		}
+	}
 	next, intents, err := r.reducer.Reduce(ctx, current, incoming)
 	if err != nil {
 		span.RecordError(err)
@@ -99,84 +315,318 @@ func (r *Runner) HandleEvent(ctx context.Context, runID string, incoming agent.E
 	}
 	current = next
 
-	// 3) Append the incoming event to durable log after successful reduction.
-	if _, err := r.st.AppendEvent(ctx, agentEventToRecord(runID, incoming)); err != nil {
-		return nil, errmodel.System("store_error", "failed to append incoming event", map[string]any{"event_type": incoming.Type}, err)
-	}
+	// 3) Stage the incoming event after successful reduction.
+	c.stage(agentEventToRecord(runID, incoming))
 
-	// 4) Execute intents via handlers, appending any produced events and applying reducer for each.
-	for _, it := range intents {
-		handler := r.findHandler(it)
-		if handler == nil {
-			// skip unknown intents for now; future: log/metric
-			continue
+	// 4) Execute intents and commit the cycle.
+	s, _, err := r.drive(ctx, span, c, current, intents)
+	return s, err
+}
+
+// drive executes intents via handlers, staging any produced events and applying the reducer for
+// each, then commits the cycle at the sequence observed during replay and applies the snapshot
+// policy. In loop mode, intents emitted for effect-produced events are queued and dispatched in
+// turn. Exceeding the max-steps bound or exhausting an intent's retries stops the loop; the work
+// done so far is still committed. The returned bool reports whether the cycle was committed.
+func (r *Runner) drive(ctx context.Context, span trace.Span, c *cycle, current agent.State, intents []agent.Intent) (agent.State, bool, error) {
+	var (
+		stopErr error
+		failed  []*intentFailedError
+		err     error
+	)
+	if r.outbox != nil {
+		// Intents are executed by workers draining the outbox.
+		if r.halt == nil || !r.halt(current) {
+			stopErr = r.enqueue(c, intents, 1)
 		}
-		// Idempotency: if intent has IdempotencyKey, skip if a corresponding marker exists.
-		if it.IdempotencyKey != "" {
-			// Step 1: try to claim intent atomically by inserting a claim event with a deterministic ID.
-			claimID := intentClaimEventID(runID, it.IdempotencyKey)
-			_, err := r.st.AppendEvent(ctx, store.EventRecord{EventID: claimID, RunID: runID, Type: "intent_claimed", CreatedAt: time.Now().UTC()})
+		return r.finish(ctx, span, c, current, nil, stopErr)
+	}
+	queue := intents
+	steps := 0
+	for len(queue) > 0 {
+		if r.halt != nil && r.halt(current) {
+			break
+		}
+		if r.maxSteps > 0 && steps >= r.maxSteps {
+			stopErr = errmodel.Policy("max_steps_exceeded", "event loop exceeded max steps", map[string]any{"run_id": c.runID, "max_steps": r.maxSteps})
+			span.RecordError(stopErr)
+			break
+		}
+		budget := 0
+		if r.maxSteps > 0 {
+			budget = r.maxSteps - steps
+		}
+		batch := r.nextBatch(queue, budget)
+		queue = queue[len(batch):]
+		// Results are applied in intent order regardless of completion order, so the committed
+		// events and the reduced state are the same as for sequential dispatch.
+		for _, res := range r.executeAll(ctx, c, current, batch) {
+			var followUps []agent.Intent
+			current, followUps, err = r.apply(ctx, c, current, res)
 			if err != nil {
-				// If duplicate claim (event_id unique), skip.
-				if _, gerr := r.st.GetEventByID(ctx, claimID); gerr == nil {
+				span.RecordError(err)
+				var f *intentFailedError
+				if errors.As(err, &f) {
+					failed = append(failed, f)
 					continue
 				}
-				return nil, err
+				return nil, false, err
+			}
+			if r.loop {
+				queue = append(queue, followUps...)
 			}
 		}
-		evs, err := handler.Handle(ctx, current, it)
-		if err != nil {
-			span.RecordError(err)
-			return nil, errmodel.System("effect_error", "effect handler error", map[string]any{"intent": it.Name}, err)
+		steps += len(batch)
+		if len(failed) > 0 {
+			stopErr = failed[0].err
+			break
 		}
-		for _, ev := range evs {
-			// append effect event
-			if _, err := r.st.AppendEvent(ctx, agentEventToRecord(runID, ev)); err != nil {
-				span.RecordError(err)
-				return nil, errmodel.System("store_error", "failed to append effect event", map[string]any{"event_type": ev.Type}, err)
-			}
-			// apply reducer for effect-produced event to update state deterministically
-			current, _, err = r.applySingle(ctx, current, ev)
-			if err != nil {
-				span.RecordError(err)
-				return nil, errmodel.System("reducer_error", "failed to apply reducer", map[string]any{"event_type": ev.Type}, err)
+	}
+
+	return r.finish(ctx, span, c, current, failed, stopErr)
+}
+
+// finish commits the cycle, together with dead letters for the failed intents, and applies the
+// snapshot policy. stopErr, if set, is returned once the cycle is committed.
+func (r *Runner) finish(ctx context.Context, span trace.Span, c *cycle, current agent.State, failed []*intentFailedError, stopErr error) (agent.State, bool, error) {
+	if r.deadLetters != nil {
+		r.deadLetter(c, failed)
+	}
+	// Commit the whole cycle at the sequence observed during replay.
+	if err := r.commit(ctx, c); err != nil {
+		span.RecordError(err)
+		if errmodel.HasCode(err, "run_shredded") {
+			return nil, false, err
+		}
+		return nil, false, errmodel.System("store_error", "failed to commit events", map[string]any{"run_id": c.runID, "events": len(c.pending)}, err)
+	}
+	r.recordRun(ctx, c.runID)
+
+	// Snapshot policy: snapshot every N events if enabled.
+	if r.snapshotCodec != nil && r.snapshotInterval > 0 {
+		seq := c.seq()
+		if seq > 0 && seq%int64(r.snapshotInterval) == 0 {
+			if err := r.saveSnapshot(ctx, c.runID, seq, current); err != nil {
+				return nil, true, errmodel.System("snapshot_error", "failed to save snapshot", map[string]any{"run_id": c.runID, "seq": seq}, err)
 			}
-			lastSeq++
+			r.compact(ctx, c.runID, seq)
+		}
+	}
+	if stopErr != nil {
+		return nil, true, stopErr
+	}
+	return current, true, nil
+}
+
+// effectResult is the outcome of executing a single intent, before its events are applied.
+type effectResult struct {
+	intent  agent.Intent
+	branch  *cycle // events staged by a parallel execution; merged when applied
+	skipped bool
+	notes   []agent.Event // claim and failure events staged by the runtime, reduced when applied
+	evs     []agent.Event
+	err     error
+}
+
+// note stages an event the runtime records about the intent, such as a failed attempt.
+func (res *effectResult) note(c *cycle, ev agent.Event) {
+	c.stage(agentEventToRecord(c.runID, ev))
+	res.notes = append(res.notes, ev)
+}
+
+// execute claims the intent and runs its handler under the retry policy, staging the claim and
+// any failed attempts in c. Produced events are returned for apply to stage and reduce.
+func (r *Runner) execute(ctx context.Context, c *cycle, current agent.State, it agent.Intent) effectResult {
+	runID := c.runID
+	res := effectResult{intent: it}
+	handler := r.findHandler(c, it)
+	if handler == nil {
+		// skip unknown intents for now; future: log/metric
+		res.skipped = true
+		return res
+	}
+	// Idempotency: if intent has IdempotencyKey, skip if it was already claimed.
+	if it.IdempotencyKey != "" {
+		// Claim the intent with a deterministic claim event. It is committed together with the
+		// effect events, so a concurrent claimant fails the commit with a conflict.
+		claimID := intentClaimEventID(runID, it.IdempotencyKey)
+		if c.staged[claimID] {
+			res.skipped = true
+			return res
+		}
+		if _, err := r.st.GetEventByID(ctx, claimID); err == nil {
+			res.skipped = true
+			return res
+		} else if err != sql.ErrNoRows {
+			res.err = errmodel.System("store_error", "failed to check intent claim", map[string]any{"intent": it.Name}, err)
+			return res
 		}
-		// After successful handling, write an idempotency marker event to record completion.
+		res.note(c, agent.Event{ID: claimID, Type: "intent_claimed", Timestamp: time.Now().UTC()})
+	}
+	evs, attempts, err := r.invoke(ctx, c, &res, current, handler)
+	if err != nil {
+		if ctx.Err() != nil {
+			res.err = errmodel.System("effect_error", "effect handler interrupted", map[string]any{"intent": it.Name}, err)
+			return res
+		}
+		// Release the claim so the intent can run again, and record the final failure.
 		if it.IdempotencyKey != "" {
-			marker := agent.Event{
-				ID:        intentMarkerEventID(runID, it.IdempotencyKey),
-				Type:      "intent_processed",
-				Timestamp: time.Now().UTC(),
-				Payload: map[string]any{
-					"key":  it.IdempotencyKey,
-					"name": it.Name,
-				},
-			}
-			if _, err := r.st.AppendEvent(ctx, agentEventToRecord(runID, marker)); err != nil {
-				return nil, errmodel.System("store_error", "failed to append idempotency marker", map[string]any{"intent": it.Name}, err)
-			}
+			claimID := intentClaimEventID(runID, it.IdempotencyKey)
+			c.unstage(claimID)
+			res.notes = slices.DeleteFunc(res.notes, func(ev agent.Event) bool { return ev.ID == claimID })
 		}
+		failure := errmodel.System("effect_error", "effect handler error", map[string]any{"intent": it.Name, "attempts": attempts}, err)
+		failedID := newEventID(runID)
+		res.note(c, agent.Event{
+			ID:        failedID,
+			Type:      "intent_failed",
+			Timestamp: time.Now().UTC(),
+			Payload: map[string]any{
+				"name":     it.Name,
+				"key":      it.IdempotencyKey,
+				"attempts": attempts,
+				"error":    errmodel.From(err),
+			},
+		})
+		res.err = &intentFailedError{err: failure, cause: errmodel.From(err), intent: it, attempts: attempts, eventID: failedID}
+		return res
 	}
+	res.evs = evs
+	return res
+}
 
-	// Snapshot policy: snapshot every N events if enabled.
-	if r.snapshotCodec != nil && r.snapshotInterval > 0 {
-		seq, err := r.st.LastSeq(ctx, runID)
+// apply stages the events of an executed intent and applies the reducer to each of them, in the
+// order they are committed and replayed. It returns the updated state and the intents the
+// reducer emitted for the produced events; those emitted for the runtime's own events are
+// discarded, as in replay.
+func (r *Runner) apply(ctx context.Context, c *cycle, current agent.State, res effectResult) (agent.State, []agent.Intent, error) {
+	runID := c.runID
+	if res.branch != nil {
+		c.merge(res.branch)
+	}
+	var (
+		followUps []agent.Intent
+		err       error
+	)
+	for _, ev := range res.notes {
+		if current, _, err = r.applySingle(ctx, current, ev); err != nil {
+			return current, nil, errmodel.System("reducer_error", "failed to apply reducer", map[string]any{"event_type": ev.Type}, err)
+		}
+	}
+	if res.err != nil {
+		return current, nil, res.err
+	}
+	if res.skipped {
+		return current, nil, nil
+	}
+	for _, ev := range res.evs {
+		if ev.ID == "" {
+			ev.ID = newEventID(runID)
+		}
+		if ev.Timestamp.IsZero() {
+			ev.Timestamp = time.Now().UTC()
+		}
+		if ev, err = r.prepare(ev); err != nil {
+			return current, nil, err
+		}
+		c.stage(agentEventToRecord(runID, ev))
+		// apply reducer for effect-produced event to update state deterministically
+		var next []agent.Intent
+		current, next, err = r.applySingle(ctx, current, ev)
 		if err != nil {
-			return nil, errmodel.System("store_error", "failed to get last sequence", map[string]any{"run_id": runID}, err)
+			return current, nil, errmodel.System("reducer_error", "failed to apply reducer", map[string]any{"event_type": ev.Type}, err)
 		}
-		if seq > 0 && seq%int64(r.snapshotInterval) == 0 {
-			if err := r.saveSnapshot(ctx, runID, seq, current); err != nil {
-				return nil, errmodel.System("snapshot_error", "failed to save snapshot", map[string]any{"run_id": runID, "seq": seq}, err)
+		followUps = append(followUps, next...)
+	}
+	// After successful handling, write an idempotency marker event to record completion.
+	if it := res.intent; it.IdempotencyKey != "" {
+		marker := agent.Event{
+			ID:        intentMarkerEventID(runID, it.IdempotencyKey),
+			Type:      "intent_processed",
+			Timestamp: time.Now().UTC(),
+			Payload: map[string]any{
+				"key":  it.IdempotencyKey,
+				"name": it.Name,
+			},
+		}
+		c.stage(agentEventToRecord(runID, marker))
+		if current, _, err = r.applySingle(ctx, current, marker); err != nil {
+			return current, nil, errmodel.System("reducer_error", "failed to apply reducer", map[string]any{"event_type": marker.Type}, err)
+		}
+	}
+	return current, followUps, nil
+}
+
+// intentFailedError reports an intent that exhausted its retry policy. The cycle is committed
+// with the recorded attempts before err is returned to the caller.
+type intentFailedError struct {
+	err      *errmodel.Error
+	cause    *errmodel.Error // error of the last attempt
+	intent   agent.Intent
+	attempts int
+	eventID  string // ID of the intent_failed event
+}
+
+func (e *intentFailedError) Error() string { return e.err.Error() }
+func (e *intentFailedError) Unwrap() error { return e.err }
+
+// invoke runs the handler under the intent's retry policy and returns the produced events and the
+// number of attempts made. Every failed attempt is noted as an intent_attempt_failed event.
+func (r *Runner) invoke(ctx context.Context, c *cycle, res *effectResult, current agent.State, h agent.EffectHandler) ([]agent.Event, int, error) {
+	it := res.intent
+	p := r.retryPolicy(it.Name)
+	var lastErr error
+	attempt := 1
+	for ; attempt <= p.attempts(); attempt++ {
+		if attempt > 1 {
+			if err := sleepCtx(ctx, p.backoff(attempt)); err != nil {
+				return nil, attempt - 1, err
 			}
 		}
+		evs, err := r.attempt(ctx, p, current, it, h)
+		if err == nil {
+			return evs, attempt, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, attempt, err
+		}
+		res.note(c, agent.Event{
+			ID:        newEventID(c.runID),
+			Type:      "intent_attempt_failed",
+			Timestamp: time.Now().UTC(),
+			Payload: map[string]any{
+				"name":    it.Name,
+				"key":     it.IdempotencyKey,
+				"attempt": attempt,
+				"error":   errmodel.From(err),
+			},
+		})
+		if !p.retryable(err) {
+			break
+		}
 	}
+	return nil, min(attempt, p.attempts()), lastErr
+}
 
-	return current, nil
+// attempt runs a single Handle call bounded by the policy's attempt timeout.
+func (r *Runner) attempt(ctx context.Context, p RetryPolicy, current agent.State, it agent.Intent, h agent.EffectHandler) ([]agent.Event, error) {
+	if p.AttemptTimeout <= 0 {
+		return h.Handle(ctx, current, it)
+	}
+	actx, cancel := context.WithTimeout(ctx, p.AttemptTimeout)
+	defer cancel()
+	evs, err := h.Handle(actx, current, it)
+	if err != nil && actx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return nil, errmodel.System("effect_timeout", "effect handler timed out", map[string]any{"intent": it.Name, "timeout": p.AttemptTimeout.String()}, err)
+	}
+	return evs, err
 }
 
-func (r *Runner) findHandler(it agent.Intent) agent.EffectHandler {
+func (r *Runner) findHandler(c *cycle, it agent.Intent) agent.EffectHandler {
+	if r.timers != nil && it.Name == TimerIntent {
+		return timerHandler{r: r, c: c}
+	}
 	for _, h := range r.handlers {
 		if h.CanHandle(it) {
 			return h
@@ -186,40 +636,68 @@ func (r *Runner) findHandler(it agent.Intent) agent.EffectHandler {
 }
 
 func (r *Runner) replayState(ctx context.Context, runID string) (agent.State, int64, error) {
-	// Load snapshot if exists.
-	var (
-		base agent.State
-		upto int64
-	)
-	base = r.newState(runID)
-	sn, err := r.st.LoadLatestSnapshot(ctx, runID)
-	if err == nil && len(sn.State) > 0 {
-		if r.snapshotCodec != nil {
-			if decoded, derr := r.snapshotCodec.Decode(runID, sn.State); derr == nil && decoded != nil {
-				base = decoded
-			}
-		}
-		upto = sn.UptoSeq
+	// Start from the latest usable snapshot, if any, or else from the first event.
+	base, upto, ok := r.loadSnapshot(ctx, runID)
+	if !ok {
+		base, upto = r.newState(runID), 0
 	}
 
 	events, err := r.st.ListEvents(ctx, runID, upto, 0)
 	if err != nil {
 		return nil, 0, err
 	}
-	current := base
+	current, err := r.reduceAll(ctx, base, events)
+	if err != nil {
+		return nil, 0, err
+	}
 	last := upto
+	if len(events) > 0 {
+		last = events[len(events)-1].Seq
+	}
+	return current, last, nil
+}
+
+// reduceAll applies the reducer to stored events of a run in order, discarding the intents.
+func (r *Runner) reduceAll(ctx context.Context, current agent.State, events []store.EventRecord) (agent.State, error) {
+	if len(events) == 0 {
+		return current, nil
+	}
+	open := r.opener(ctx, events[0].RunID)
 	for _, er := range events {
-		ev, err := recordToAgentEvent(er)
+		// Queued events are reduced when Resume handles them.
+		if er.Type == EventRunEventQueued {
+			continue
+		}
+		er, err := open(er)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
-		current, _, err = r.applySingle(ctx, current, ev)
+		ev, err := recordToAgentEvent(er)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
+		}
+		if r.events != nil {
+			if ev, err = r.events.Upcast(ev); err != nil {
+				return nil, err
+			}
+		}
+		if current, _, err = r.applySingle(ctx, current, ev); err != nil {
+			return nil, err
 		}
-		last = er.Seq
 	}
-	return current, last, nil
+	return current, nil
+}
+
+// prepare upcasts and validates a new event against the event registry, if any.
+func (r *Runner) prepare(ev agent.Event) (agent.Event, error) {
+	if r.events == nil {
+		return ev, nil
+	}
+	ev, err := r.events.Prepare(ev)
+	if err != nil {
+		return ev, err
+	}
+	return ev, r.rejectSealed(ev)
 }
 
 func (r *Runner) applySingle(ctx context.Context, current agent.State, ev agent.Event) (agent.State, []agent.Intent, error) {
@@ -240,6 +718,7 @@ func agentEventToRecord(runID string, e agent.Event) store.EventRecord {
 		EventID:   e.ID,
 		RunID:     runID,
 		Type:      e.Type,
+		Version:   e.Version,
 		Payload:   payload,
 		CreatedAt: e.Timestamp,
 	}
@@ -255,11 +734,17 @@ func recordToAgentEvent(er store.EventRecord) (agent.Event, error) {
 	return agent.Event{
 		ID:        er.EventID,
 		Type:      er.Type,
+		Version:   er.Version,
 		Timestamp: er.CreatedAt,
 		Payload:   v,
 	}, nil
 }
 
+// newEventID generates an ID for events that arrive without one.
+func newEventID(runID string) string {
+	return fmt.Sprintf("e-%s-%s", runID, uuid.NewString())
+}
+
 func intentMarkerEventID(runID, key string) string {
 	return fmt.Sprintf("intent-%s-%s", runID, key)
 }
@@ -281,6 +766,8 @@ func (r *Runner) saveSnapshot(ctx context.Context, runID string, upto int64, s a
 		RunID:      runID,
 		UptoSeq:    upto,
 		State:      data,
+		Version:    r.snapshotVersion,
+		Hash:       snapshotHash(data),
 		CreatedAt:  time.Now().UTC(),
 	})
 	return err
@@ -3,10 +3,14 @@ package runtime
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
 	"github.com/wilhg/orch/pkg/store/entstore"
 )
 
@@ -60,6 +64,21 @@ func (testHandler) Handle(ctx context.Context, s agent.State, intent agent.Inten
 	return []agent.Event{ev}, nil
 }
 
+// openTestStore opens a migrated in-memory SQLite store, shared by the connections that use name,
+// for tests that need the store's features beyond events and snapshots; the others use memstore.
+func openTestStore(t *testing.T, name string) *entstore.Store {
+	t.Helper()
+	st, err := entstore.Open(context.Background(), "sqlite:file:"+name+"?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
 func decode[T any](payload any, out *T) {
 	b, _ := json.Marshal(payload)
 	_ = json.Unmarshal(b, out)
@@ -68,14 +87,7 @@ func decode[T any](payload any, out *T) {
 func TestRunner_ApplyEventThenEffects_SQLite(t *testing.T) {
 	ctx := context.Background()
 
-	st, err := entstore.Open(ctx, "sqlite:file:runtime?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() { _ = st.Close() })
-	if err := st.Migrate(ctx); err != nil {
-		t.Fatal(err)
-	}
+	st := openTestStore(t, "runtime")
 
 	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
 		return testState{runID: runID, Count: 0}
@@ -110,14 +122,7 @@ func TestRunner_ApplyEventThenEffects_SQLite(t *testing.T) {
 func TestRunner_IdempotentIntent_SQLite(t *testing.T) {
 	ctx := context.Background()
 
-	st, err := entstore.Open(ctx, "sqlite:file:runtime-idem?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() { _ = st.Close() })
-	if err := st.Migrate(ctx); err != nil {
-		t.Fatal(err)
-	}
+	st := openTestStore(t, "runtime-idem")
 
 	// Reducer emits an intent with a fixed idempotency key so duplicates are skipped.
 	r := NewRunner(st, reducerWithIdem{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
@@ -168,14 +173,7 @@ func (jsonCodec) Decode(runID string, data []byte) (agent.State, error) {
 
 func TestRunner_SnapshotAndResume_SQLite(t *testing.T) {
 	ctx := context.Background()
-	st, err := entstore.Open(ctx, "sqlite:file:runtime-snap?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() { _ = st.Close() })
-	if err := st.Migrate(ctx); err != nil {
-		t.Fatal(err)
-	}
+	st := openTestStore(t, "runtime-snap")
 
 	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
 		return testState{runID: runID, Count: 0}
@@ -230,3 +228,160 @@ func (reducerWithIdem) Reduce(ctx context.Context, current agent.State, event ag
 		return st, nil, nil
 	}
 }
+
+// loopReducer chains "tick" intents: every "ticked" event increments Count and asks for another tick.
+type loopReducer struct{}
+
+func (loopReducer) Reduce(ctx context.Context, current agent.State, event agent.Event) (agent.State, []agent.Intent, error) {
+	st := current.(testState)
+	switch event.Type {
+	case "start", "ticked":
+		if event.Type == "ticked" {
+			st.Count++
+		}
+		return testState{runID: st.runID, Count: st.Count}, []agent.Intent{{Name: "tick"}}, nil
+	default:
+		return st, nil, nil
+	}
+}
+
+// tickHandler handles "tick" by returning a "ticked" event without an ID; the runner assigns one.
+type tickHandler struct{}
+
+func (tickHandler) CanHandle(intent agent.Intent) bool { return intent.Name == "tick" }
+
+func (tickHandler) Handle(ctx context.Context, s agent.State, intent agent.Intent) ([]agent.Event, error) {
+	return []agent.Event{{Type: "ticked"}}, nil
+}
+
+func TestRunner_Loop_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-loop")
+	newState := func(runID string) agent.State { return testState{runID: runID} }
+
+	// Without loop mode only the intent emitted for the incoming event is dispatched.
+	r := NewRunner(st, loopReducer{}, []agent.EffectHandler{tickHandler{}}, newState)
+	s, err := r.HandleEvent(ctx, "run-noloop", agent.Event{ID: "nl1", Type: "start"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 1 {
+		t.Fatalf("count=%d want 1 without loop", got)
+	}
+
+	// The halt condition stops the loop once the state reaches 3.
+	halt := func(s agent.State) bool { return s.(testState).Count >= 3 }
+	r = NewRunner(st, loopReducer{}, []agent.EffectHandler{tickHandler{}}, newState, WithLoop(10, halt))
+	s, err = r.HandleEvent(ctx, "run-loop", agent.Event{ID: "l1", Type: "start"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 3 {
+		t.Fatalf("count=%d want 3 with halt", got)
+	}
+	evs, err := st.ListEvents(ctx, "run-loop", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 4 {
+		t.Fatalf("events=%d want 4 (start + 3 ticked)", len(evs))
+	}
+
+	// Without a halt condition the max-steps bound applies.
+	r = NewRunner(st, loopReducer{}, []agent.EffectHandler{tickHandler{}}, newState, WithLoop(5, nil))
+	_, err = r.HandleEvent(ctx, "run-bounded", agent.Event{ID: "b1", Type: "start"})
+	if ce := errmodel.From(err); ce == nil || ce.Code != "max_steps_exceeded" {
+		t.Fatalf("err=%v want max_steps_exceeded", err)
+	}
+	cur, _, err := r.replayState(ctx, "run-bounded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cur.(testState).Count; got != 5 {
+		t.Fatalf("count=%d want 5 after max steps", got)
+	}
+}
+
+func TestRunner_ConcurrentHandleEventSameRun_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-concurrent")
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ev := agent.Event{ID: fmt.Sprintf("c%d", i), Type: "inc", Timestamp: time.Now().UTC(), Payload: map[string]any{"n": 1}}
+			if _, err := r.HandleEvent(ctx, "run-concurrent", ev); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	// Each cycle appends inc + added; cycles must not interleave or base decisions on stale state.
+	evs, err := st.ListEvents(ctx, "run-concurrent", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 2*n {
+		t.Fatalf("events=%d want %d", len(evs), 2*n)
+	}
+	for i, e := range evs {
+		if e.Seq != int64(i+1) {
+			t.Fatalf("seq gap at %d: %d", i, e.Seq)
+		}
+		want := "inc"
+		if i%2 == 1 {
+			want = "added"
+		}
+		if e.Type != want {
+			t.Fatalf("event %d type=%s want %s (interleaved cycles)", i, e.Type, want)
+		}
+	}
+	cur, _, err := r.replayState(ctx, "run-concurrent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cur.(testState).Count; got != 3*n {
+		t.Fatalf("count=%d want %d", got, 3*n)
+	}
+}
+
+// rejectingReducer fails on events of type "added", after the effect produced them.
+type rejectingReducer struct{ reducerWithIdem }
+
+func (r rejectingReducer) Reduce(ctx context.Context, current agent.State, event agent.Event) (agent.State, []agent.Intent, error) {
+	if event.Type == "added" {
+		return nil, nil, errors.New("rejected")
+	}
+	return r.reducerWithIdem.Reduce(ctx, current, event)
+}
+
+func TestRunner_CycleIsAllOrNothing_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-atomic")
+	r := NewRunner(st, rejectingReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	})
+	if _, err := r.HandleEvent(ctx, "run-atomic", agent.Event{ID: "a1", Type: "inc", Payload: map[string]any{"n": 1}}); err == nil {
+		t.Fatal("expected reducer error")
+	}
+	// Neither the incoming event nor the intent claim may be left behind.
+	evs, err := st.ListEvents(ctx, "run-atomic", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 0 {
+		t.Fatalf("events=%d want 0 after failed cycle: %+v", len(evs), evs)
+	}
+}
@@ -0,0 +1,423 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithShredding stores the payload fields that the event registry marks Sensitive encrypted
+// with a key per run kept in keys. Replays decrypt them. Once ShredRun destroys the run's key,
+// they read as null wherever the events were copied, exports included. It has no effect
+// without WithEventRegistry.
+func WithShredding(keys store.ShredKeyStore) RunnerOption {
+	return func(r *Runner) { r.keys = keys }
+}
+
+// WithAudit records retention and erasure operations in a.
+func WithAudit(a store.AuditStore) RunnerOption {
+	return func(r *Runner) { r.audit = a }
+}
+
+// Erasure identifies who asked for the data of a run to be erased and why, for the audit trail.
+type Erasure struct {
+	Actor  string
+	Reason string
+}
+
+// ShredRun destroys the key encrypting the sensitive fields of a run and deletes its snapshots,
+// which hold decrypted state. The run's events are kept; replays see the sensitive fields as
+// null. Events with sensitive fields are rejected for the run afterwards with a run_shredded
+// policy error.
+func (r *Runner) ShredRun(ctx context.Context, runID string, e Erasure) error {
+	tr := otel.Tracer("runtime/runner")
+	ctx, span := tr.Start(ctx, "Runner.ShredRun", trace.WithAttributes(attribute.String("run.id", runID)))
+	defer span.End()
+	if runID == "" {
+		return errmodel.Validation("missing_run", "runID is empty", nil)
+	}
+	if r.keys == nil {
+		return errmodel.Policy("shredding_disabled", "runner does not shred run data", nil)
+	}
+	release, err := r.locks.acquire(ctx, runID)
+	if err != nil {
+		return errmodel.System("lock_error", "failed to acquire run lock", map[string]any{"run_id": runID}, err)
+	}
+	defer release()
+
+	if err := r.keys.ShredRunKey(ctx, runID); err != nil {
+		span.RecordError(err)
+		return errmodel.System("store_error", "failed to shred run key", map[string]any{"run_id": runID}, err)
+	}
+	n, err := r.st.DeleteSnapshots(ctx, runID)
+	if err != nil {
+		span.RecordError(err)
+		return errmodel.System("store_error", "failed to delete snapshots", map[string]any{"run_id": runID}, err)
+	}
+	forks, err := r.deleteForkSnapshots(ctx, runID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return r.recordAudit(ctx, "run_shredded", runID, e, map[string]any{"snapshots": n, "fork_snapshots": forks})
+}
+
+// deleteForkSnapshots deletes the snapshots of the forks of a run that the run catalog knows,
+// and of their forks, since they hold state decrypted from the run's events. Replays ignore
+// the snapshots of forks whose source was shredded anyway.
+func (r *Runner) deleteForkSnapshots(ctx context.Context, runID string) (int, error) {
+	if r.runs == nil {
+		return 0, nil
+	}
+	total := 0
+	parents := []string{runID}
+	for len(parents) > 0 {
+		f := store.RunFilter{ParentRunID: parents[0], Limit: 100}
+		parents = parents[1:]
+		for {
+			page, err := r.runs.ListRuns(ctx, f)
+			if err != nil {
+				return 0, errmodel.System("store_error", "failed to list forks", map[string]any{"run_id": f.ParentRunID}, err)
+			}
+			for _, run := range page.Runs {
+				n, err := r.st.DeleteSnapshots(ctx, run.RunID)
+				if err != nil {
+					return 0, errmodel.System("store_error", "failed to delete snapshots", map[string]any{"run_id": run.RunID}, err)
+				}
+				total += n
+				parents = append(parents, run.RunID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			f.Cursor = page.NextCursor
+		}
+	}
+	return total, nil
+}
+
+// recordAudit appends an entry to the audit trail, if any.
+func (r *Runner) recordAudit(ctx context.Context, action, runID string, e Erasure, details map[string]any) error {
+	if r.audit == nil {
+		return nil
+	}
+	if e.Reason != "" {
+		details["reason"] = e.Reason
+	}
+	_, err := r.audit.AppendAudit(ctx, store.AuditRecord{
+		AuditID:   uuid.NewString(),
+		Action:    action,
+		RunID:     runID,
+		Actor:     e.Actor,
+		Details:   details,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return errmodel.System("store_error", "failed to record audit entry", map[string]any{"run_id": runID, "action": action}, err)
+	}
+	return nil
+}
+
+// sealedKey marks a payload field value encrypted by seal: {"$sealed": "<base64>"}.
+const sealedKey = "$sealed"
+
+// seal returns recs with the sensitive fields of their payloads encrypted under the run's key,
+// creating the key on first use. The fields of queued events are sealed too.
+func (r *Runner) seal(ctx context.Context, runID string, recs []store.EventRecord) ([]store.EventRecord, error) {
+	if r.keys == nil || r.events == nil {
+		return recs, nil
+	}
+	var aead cipher.AEAD
+	sealField := func(plain []byte) ([]byte, error) {
+		if aead == nil {
+			key, err := r.runKey(ctx, runID)
+			if err != nil {
+				return nil, err
+			}
+			if aead, err = newAEAD(key); err != nil {
+				return nil, err
+			}
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{sealedKey: base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, []byte(runID)))})
+	}
+	out := slices.Clone(recs)
+	for i, rec := range out {
+		var err error
+		if rec.Type == EventRunEventQueued {
+			out[i].Payload, err = mapQueued(rec.Payload, func(typ string, p json.RawMessage) (json.RawMessage, error) {
+				return r.sealPayload(typ, p, sealField)
+			})
+		} else {
+			out[i].Payload, err = r.sealPayload(rec.Type, rec.Payload, sealField)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// sealPayload replaces the sensitive fields of an event payload with their sealed form.
+func (r *Runner) sealPayload(typ string, payload json.RawMessage, seal func([]byte) ([]byte, error)) (json.RawMessage, error) {
+	schema, ok := r.events.Lookup(typ)
+	if !ok || len(schema.Sensitive) == 0 || len(payload) == 0 {
+		return payload, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		// Only object payloads have fields.
+		return payload, nil
+	}
+	changed := false
+	for _, name := range schema.Sensitive {
+		v, ok := fields[name]
+		if !ok || string(v) == "null" || isSealed(v) {
+			continue
+		}
+		sealed, err := seal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = sealed
+		changed = true
+	}
+	if !changed {
+		return payload, nil
+	}
+	return json.Marshal(fields)
+}
+
+// runKey returns the shredding key of a run, creating it if the run has none.
+func (r *Runner) runKey(ctx context.Context, runID string) ([]byte, error) {
+	key, err := r.keys.LoadRunKey(ctx, runID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return key, err
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return r.keys.CreateRunKey(ctx, runID, key)
+}
+
+// opener returns a function decrypting the sealed fields of the run's stored events. Without
+// the run's key, because it was shredded or never created, sealed fields read as null. The
+// events a fork copied stay sealed under its source's key, so they are opened by the source's
+// opener and are shredded with the source.
+func (r *Runner) opener(ctx context.Context, runID string) func(store.EventRecord) (store.EventRecord, error) {
+	if r.keys == nil || r.events == nil {
+		return func(rec store.EventRecord) (store.EventRecord, error) { return rec, nil }
+	}
+	var (
+		aead      cipher.AEAD
+		loaded    bool
+		source    func(store.EventRecord) (store.EventRecord, error)
+		sourceSeq int64
+		forked    bool
+	)
+	openField := func(sealed string) ([]byte, error) {
+		if !loaded {
+			key, err := r.keys.LoadRunKey(ctx, runID)
+			switch {
+			case err == nil:
+				if aead, err = newAEAD(key); err != nil {
+					return nil, err
+				}
+			case !errors.Is(err, sql.ErrNoRows) && !errmodel.HasCode(err, "run_shredded"):
+				return nil, err
+			}
+			loaded = true
+		}
+		if aead == nil {
+			return []byte("null"), nil
+		}
+		b, err := base64.StdEncoding.DecodeString(sealed)
+		if err != nil || len(b) < aead.NonceSize() {
+			return nil, fmt.Errorf("runtime: malformed sealed field")
+		}
+		return aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], []byte(runID))
+	}
+	return func(rec store.EventRecord) (store.EventRecord, error) {
+		if !bytes.Contains(rec.Payload, []byte(`"`+sealedKey+`"`)) {
+			return rec, nil
+		}
+		if !forked {
+			src, seq, err := r.forkSource(ctx, runID)
+			if err != nil {
+				return rec, errmodel.System("store_error", "failed to read fork source", map[string]any{"run_id": runID}, err)
+			}
+			if src != "" {
+				source, sourceSeq = r.opener(ctx, src), seq
+			}
+			forked = true
+		}
+		if source != nil && rec.Seq <= sourceSeq {
+			return source(rec)
+		}
+		var err error
+		if rec.Type == EventRunEventQueued {
+			rec.Payload, err = mapQueued(rec.Payload, func(typ string, p json.RawMessage) (json.RawMessage, error) {
+				return r.openPayload(typ, p, openField)
+			})
+		} else {
+			rec.Payload, err = r.openPayload(rec.Type, rec.Payload, openField)
+		}
+		if err != nil {
+			return rec, errmodel.System("decrypt_error", "failed to decrypt sensitive fields", map[string]any{"event_id": rec.EventID}, err)
+		}
+		return rec, nil
+	}
+}
+
+// forkSource returns the run a fork was copied from and the last sequence it copied, or no run
+// if runID is not a fork.
+func (r *Runner) forkSource(ctx context.Context, runID string) (string, int64, error) {
+	rec, err := r.st.GetEventByID(ctx, forkedEventID(runID))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (rec.RunID != runID || rec.Type != EventRunForked)) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	var p struct {
+		SourceRunID string `json:"source_run_id"`
+		SourceSeq   int64  `json:"source_seq"`
+	}
+	if err := json.Unmarshal(rec.Payload, &p); err != nil {
+		return "", 0, err
+	}
+	return p.SourceRunID, p.SourceSeq, nil
+}
+
+// sourceShredded reports whether runID is a fork of a run that was shredded, directly or
+// through other forks.
+func (r *Runner) sourceShredded(ctx context.Context, runID string) (bool, error) {
+	for {
+		src, _, err := r.forkSource(ctx, runID)
+		if err != nil || src == "" {
+			return false, err
+		}
+		_, err = r.keys.LoadRunKey(ctx, src)
+		if errmodel.HasCode(err, "run_shredded") {
+			return true, nil
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return false, err
+		}
+		runID = src
+	}
+}
+
+// openPayload replaces the sealed sensitive fields of an event payload with their decrypted
+// value. Other fields are left alone, whatever they hold.
+func (r *Runner) openPayload(typ string, payload json.RawMessage, open func(string) ([]byte, error)) (json.RawMessage, error) {
+	schema, ok := r.events.Lookup(typ)
+	if !ok || len(schema.Sensitive) == 0 {
+		return payload, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		return payload, nil
+	}
+	changed := false
+	for _, name := range schema.Sensitive {
+		var sealed map[string]string
+		if v, ok := fields[name]; !ok || !isSealed(v) || json.Unmarshal(v, &sealed) != nil {
+			continue
+		}
+		plain, err := open(sealed[sealedKey])
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = plain
+		changed = true
+	}
+	if !changed {
+		return payload, nil
+	}
+	return json.Marshal(fields)
+}
+
+// rejectSealed fails for a new event whose sensitive fields hold a value in sealed form, which
+// would be stored as is and decrypted on replay in place of the sender's value.
+func (r *Runner) rejectSealed(ev agent.Event) error {
+	if r.keys == nil {
+		return nil
+	}
+	schema, ok := r.events.Lookup(ev.Type)
+	if !ok || len(schema.Sensitive) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(ev.Payload)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil
+	}
+	for _, name := range schema.Sensitive {
+		if isSealed(fields[name]) {
+			return errmodel.Validation("sealed_field", "sensitive field holds a sealed value", map[string]any{"type": ev.Type, "field": name})
+		}
+	}
+	return nil
+}
+
+// isSealed reports whether a field value is in sealed form.
+func isSealed(v json.RawMessage) bool {
+	var m map[string]json.RawMessage
+	if len(v) == 0 || v[0] != '{' || json.Unmarshal(v, &m) != nil || len(m) != 1 {
+		return false
+	}
+	_, ok := m[sealedKey]
+	return ok
+}
+
+// mapQueued applies fn to the payload of the event carried by a run_event_queued payload.
+func mapQueued(payload json.RawMessage, fn func(typ string, p json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	var ev map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, err
+	}
+	var typ string
+	_ = json.Unmarshal(ev["type"], &typ)
+	p, err := fn(typ, ev["payload"])
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(p, ev["payload"]) {
+		return payload, nil
+	}
+	ev["payload"] = p
+	return json.Marshal(ev)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
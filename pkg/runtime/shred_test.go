@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+func TestRunner_ShredRun_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-shred")
+	// The amount of inc events stands in for personal data.
+	reg := agent.NewEventRegistry()
+	if err := reg.Register(agent.EventSchema{Type: "inc", Version: 1, Sensitive: []string{"n"}}); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithEventRegistry(reg), WithShredding(st), WithAudit(st), WithSnapshot(jsonCodec{}, 2))
+
+	runID := "run-shred"
+	for _, id := range []string{"s1", "s2"} {
+		if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: id, Type: "inc", Payload: map[string]any{"n": 5}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The stored payload is sealed; replays decrypt it: 5 + 2 + 5 + 2.
+	rec, err := st.GetEventByID(ctx, "s1")
+	if err != nil || !strings.Contains(string(rec.Payload), sealedKey) || strings.Contains(string(rec.Payload), `"n":5`) {
+		t.Fatalf("payload=%s err=%v want sealed", rec.Payload, err)
+	}
+	if s, _, err := NewRunner(st, testReducer{}, nil, func(runID string) agent.State {
+		return testState{runID: runID}
+	}, WithEventRegistry(reg), WithShredding(st)).State(ctx, runID); err != nil || s.(testState).Count != 14 {
+		t.Fatalf("state=%+v err=%v want count 14", s, err)
+	}
+
+	// A fork keeps the copied fields sealed under the source's key; its own are sealed under its
+	// key: 5 + 3 + 2.
+	if s, err := r.Fork(ctx, runID, 1, "run-shred-fork"); err != nil || s.(testState).Count != 5 {
+		t.Fatalf("fork state=%+v err=%v want count 5", s, err)
+	}
+	if s, err := r.HandleEvent(ctx, "run-shred-fork", agent.Event{ID: "f1", Type: "inc", Payload: map[string]any{"n": 3}}); err != nil || s.(testState).Count != 10 {
+		t.Fatalf("fork state=%+v err=%v want count 10", s, err)
+	}
+
+	// Values in sealed form are rejected in sensitive fields, where replays would decrypt them,
+	// and left alone in the others.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Payload, &fields); err != nil {
+		t.Fatal(err)
+	}
+	forged := fields["n"]
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "forged", Type: "inc", Payload: map[string]any{"n": forged}}); !errmodel.HasCode(err, "sealed_field") {
+		t.Fatalf("err=%v want sealed_field", err)
+	}
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "noted", Type: "note", Payload: map[string]any{"text": forged}}); err != nil {
+		t.Fatal(err)
+	}
+	if noted, err := st.GetEventByID(ctx, "noted"); err != nil {
+		t.Fatal(err)
+	} else if noted, err = r.opener(ctx, runID)(noted); err != nil || !strings.Contains(string(noted.Payload), sealedKey) {
+		t.Fatalf("payload=%s err=%v want the value as sent", noted.Payload, err)
+	}
+
+	if err := r.ShredRun(ctx, runID, Erasure{Actor: "dpo", Reason: "request 42"}); err != nil {
+		t.Fatal(err)
+	}
+	// The events remain, without the sensitive amounts; the snapshots are gone.
+	if _, err := st.LoadLatestSnapshot(ctx, runID); err == nil {
+		t.Fatal("snapshot survived the shred")
+	}
+	if s, last, err := r.State(ctx, runID); err != nil || last != 5 || s.(testState).Count != 4 {
+		t.Fatalf("state=%+v last=%d err=%v want count 4", s, last, err)
+	}
+	// The fork loses the amounts it copied from the run, and keeps its own: 0 + 3 + 2.
+	if s, _, err := r.State(ctx, "run-shred-fork"); err != nil || s.(testState).Count != 5 {
+		t.Fatalf("fork state=%+v err=%v want count 5", s, err)
+	}
+	if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: "s3", Type: "inc", Payload: map[string]any{"n": 1}}); !errmodel.HasCode(err, "run_shredded") {
+		t.Fatalf("err=%v want run_shredded", err)
+	}
+	// Shredding twice is harmless.
+	if err := r.ShredRun(ctx, runID, Erasure{}); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := st.ListAudit(ctx, store.AuditFilter{RunID: runID})
+	entries := page.Entries
+	if err != nil || len(entries) != 2 || entries[0].Action != "run_shredded" || entries[0].Actor != "dpo" || entries[0].Details["reason"] != "request 42" {
+		t.Fatalf("audit=%+v err=%v", entries, err)
+	}
+
+	if err := NewRunner(st, testReducer{}, nil, func(runID string) agent.State {
+		return testState{runID: runID}
+	}).ShredRun(ctx, runID, Erasure{}); !errmodel.HasCode(err, "shredding_disabled") {
+		t.Fatalf("err=%v want shredding_disabled", err)
+	}
+}
@@ -0,0 +1,170 @@
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// JSONCodec is a SnapshotCodec that stores states as JSON, tagged with the name their type was
+// registered under so Decode returns the same concrete type. States must carry everything they
+// need, including their run ID, in exported fields.
+type JSONCodec struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}
+
+// NewJSONCodec returns a JSONCodec without registered types.
+func NewJSONCodec() *JSONCodec {
+	return &JSONCodec{types: map[string]reflect.Type{}, names: map[reflect.Type]string{}}
+}
+
+// RegisterState registers the state type S under name. It panics if either is registered already.
+func RegisterState[S agent.State](c *JSONCodec, name string) {
+	t := reflect.TypeFor[S]()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.types[name]; ok {
+		panic(fmt.Sprintf("runtime: state name %q already registered", name))
+	}
+	if _, ok := c.names[t]; ok {
+		panic(fmt.Sprintf("runtime: state type %s already registered", t))
+	}
+	c.types[name] = t
+	c.names[t] = name
+}
+
+// jsonSnapshot is the stored form of a state encoded by JSONCodec.
+type jsonSnapshot struct {
+	Type  string          `json:"type"`
+	State json.RawMessage `json:"state"`
+}
+
+// Encode implements SnapshotCodec.
+func (c *JSONCodec) Encode(s agent.State) ([]byte, error) {
+	c.mu.RLock()
+	name, ok := c.names[reflect.TypeOf(s)]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("runtime: state type %T is not registered", s)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonSnapshot{Type: name, State: b})
+}
+
+// Decode implements SnapshotCodec.
+func (c *JSONCodec) Decode(runID string, data []byte) (agent.State, error) {
+	var sn jsonSnapshot
+	if err := json.Unmarshal(data, &sn); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	t, ok := c.types[sn.Type]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("runtime: state type %q is not registered", sn.Type)
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(sn.State, v.Interface()); err != nil {
+		return nil, err
+	}
+	s := v.Elem().Interface().(agent.State)
+	if s.RunID() != runID {
+		return nil, fmt.Errorf("runtime: snapshot of run %q decoded for run %q", s.RunID(), runID)
+	}
+	return s, nil
+}
+
+// WithSnapshotVersion stamps snapshots with the version of the reducer and codec. Replays only
+// start from snapshots of the same version; change it whenever the reducer logic or the encoded
+// state changes, and older snapshots are ignored in favor of the run's events.
+func WithSnapshotVersion(v string) RunnerOption {
+	return func(r *Runner) { r.snapshotVersion = v }
+}
+
+// WithSnapshotRetention prunes the snapshots of a run according to p each time one is taken.
+func WithSnapshotRetention(p store.SnapshotRetention) RunnerOption {
+	return func(r *Runner) { r.retention = p }
+}
+
+// WithEventArchive archives the events covered by each snapshot taken, once it is saved.
+// Archived events remain readable, so replays and duplicate checks are unaffected.
+func WithEventArchive(a store.EventArchiver) RunnerOption {
+	return func(r *Runner) { r.archive = a }
+}
+
+// compact applies the retention policy and archival after a snapshot up to seq was saved.
+// Failures are recorded on the span: the snapshot and the cycle are already committed.
+func (r *Runner) compact(ctx context.Context, runID string, seq int64) {
+	span := trace.SpanFromContext(ctx)
+	if _, err := r.st.PruneSnapshots(ctx, runID, r.retention); err != nil {
+		span.RecordError(err)
+	}
+	if r.archive != nil {
+		if _, err := r.archive.ArchiveEvents(ctx, runID, seq); err != nil {
+			span.RecordError(err)
+		}
+	}
+}
+
+// loadSnapshot returns the state and sequence of the run's latest snapshot if it can be trusted:
+// taken with the runner's snapshot version, unchanged since it was taken and decodable by the
+// codec. Otherwise the reason is recorded on the span and the run is rebuilt from its events.
+func (r *Runner) loadSnapshot(ctx context.Context, runID string) (agent.State, int64, bool) {
+	if r.snapshotCodec == nil {
+		return nil, 0, false
+	}
+	sn, err := r.st.LoadLatestSnapshot(ctx, runID)
+	if err != nil || len(sn.State) == 0 {
+		return nil, 0, false
+	}
+	ignore := func(reason string) (agent.State, int64, bool) {
+		trace.SpanFromContext(ctx).AddEvent("snapshot_ignored", trace.WithAttributes(
+			attribute.String("reason", reason),
+			attribute.String("snapshot.id", sn.SnapshotID),
+			attribute.String("snapshot.version", sn.Version),
+		))
+		return nil, 0, false
+	}
+	if sn.Version != r.snapshotVersion {
+		return ignore("version_mismatch")
+	}
+	// Snapshots taken before hashing was introduced have no hash.
+	if sn.Hash != "" && sn.Hash != snapshotHash(sn.State) {
+		return ignore("hash_mismatch")
+	}
+	// The state of a fork holds fields decrypted from its source's events; once the source was
+	// shredded, the snapshots are erased and the state is rebuilt without them.
+	if r.keys != nil && r.events != nil {
+		if shredded, err := r.sourceShredded(ctx, runID); err != nil || shredded {
+			if shredded {
+				_, _ = r.st.DeleteSnapshots(ctx, runID)
+			}
+			return ignore("source_shredded")
+		}
+	}
+	s, err := r.snapshotCodec.Decode(runID, sn.State)
+	if err != nil || s == nil {
+		return ignore("decode_failed")
+	}
+	return s, sn.UptoSeq, true
+}
+
+// snapshotHash returns the digest stored with a snapshot's state.
+func snapshotHash(state []byte) string {
+	sum := sha256.Sum256(state)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,208 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/memstore"
+)
+
+// tallyState keeps its run ID exported so JSONCodec can restore it.
+type tallyState struct {
+	Run   string `json:"run"`
+	Total int    `json:"total"`
+}
+
+func (s tallyState) RunID() string      { return s.Run }
+func (s tallyState) Clone() agent.State { return s }
+
+func tallyReducer() agent.Reducer {
+	r := agent.NewTypedReducer[tallyState]()
+	agent.On(r, "add", func(ctx context.Context, s tallyState, ev agent.Event, p struct {
+		N int `json:"n"`
+	}) (tallyState, []agent.Intent, error) {
+		s.Total += p.N
+		return s, nil, nil
+	})
+	return r
+}
+
+func TestJSONCodec(t *testing.T) {
+	c := NewJSONCodec()
+	RegisterState[tallyState](c, "tally")
+	b, err := c.Encode(tallyState{Run: "r1", Total: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.Decode("r1", b)
+	if err != nil || s != (tallyState{Run: "r1", Total: 3}) {
+		t.Fatalf("state=%#v err=%v", s, err)
+	}
+	if _, err := c.Decode("r2", b); err == nil {
+		t.Fatal("expected error for a snapshot of another run")
+	}
+	if _, err := c.Encode(testState{runID: "r1"}); err == nil {
+		t.Fatal("expected error for unregistered type")
+	}
+}
+
+func TestRunner_SnapshotRetentionAndArchive_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-compact")
+	codec := NewJSONCodec()
+	RegisterState[tallyState](codec, "tally")
+	newState := func(runID string) agent.State { return tallyState{Run: runID} }
+	r := NewRunner(st, tallyReducer(), nil, newState,
+		WithSnapshot(codec, 2), WithSnapshotRetention(store.SnapshotRetention{KeepLast: 1}), WithEventArchive(st))
+
+	runID := "run-compact"
+	for i := 1; i <= 6; i++ {
+		if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: fmt.Sprintf("a%d", i), Type: "add", Payload: map[string]any{"n": i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Only the latest snapshot is kept.
+	sn, err := st.LoadLatestSnapshot(ctx, runID)
+	if err != nil || sn.UptoSeq != 6 {
+		t.Fatalf("snapshot=%+v err=%v", sn, err)
+	}
+	if n, _ := st.PruneSnapshots(ctx, runID, store.SnapshotRetention{KeepLast: 1}); n != 0 {
+		t.Fatalf("pruned %d snapshots left behind", n)
+	}
+	// Archived events are still replayed, with or without the snapshot.
+	if evs, _ := st.ListEvents(ctx, runID, 0, 0); len(evs) != 6 {
+		t.Fatalf("events=%d want 6", len(evs))
+	}
+	check := func() {
+		t.Helper()
+		s, _, err := NewRunner(st, tallyReducer(), nil, newState, WithSnapshot(codec, 2)).State(ctx, runID)
+		if err != nil || s.(tallyState).Total != 21 {
+			t.Fatalf("state=%+v err=%v want total 21", s, err)
+		}
+	}
+	check()
+	if _, err := st.DeleteSnapshots(ctx, runID); err != nil {
+		t.Fatal(err)
+	}
+	check()
+	// A redelivered event that was archived is still recognized.
+	if s, err := r.HandleEvent(ctx, runID, agent.Event{ID: "a1", Type: "add", Payload: map[string]any{"n": 1}}); err != nil || s.(tallyState).Total != 21 {
+		t.Fatalf("state=%+v err=%v", s, err)
+	}
+}
+
+func TestRunner_SnapshotVersionAndHash_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-snapver")
+	codec := NewJSONCodec()
+	RegisterState[tallyState](codec, "tally")
+	newState := func(runID string) agent.State { return tallyState{Run: runID} }
+	runner := func(version string) *Runner {
+		return NewRunner(st, tallyReducer(), nil, newState, WithSnapshot(codec, 2), WithSnapshotVersion(version))
+	}
+	total := func(r *Runner) int {
+		t.Helper()
+		s, _, err := r.State(ctx, "run-snapver")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s.(tallyState).Total
+	}
+	for i := 1; i <= 4; i++ {
+		if _, err := runner("v1").HandleEvent(ctx, "run-snapver", agent.Event{Type: "add", Payload: map[string]any{"n": i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sn, err := st.LoadLatestSnapshot(ctx, "run-snapver")
+	if err != nil || sn.Version != "v1" || sn.Hash != snapshotHash(sn.State) {
+		t.Fatalf("snapshot=%+v err=%v", sn, err)
+	}
+
+	// Replace the snapshot with one that disagrees with the events, to see which one is used.
+	if n, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{RunID: "run-snapver"}); err != nil || n != 2 {
+		t.Fatalf("invalidated=%d err=%v want 2", n, err)
+	}
+	save := func(version, hash string) {
+		t.Helper()
+		_, _ = st.InvalidateSnapshots(ctx, store.SnapshotFilter{RunID: "run-snapver"})
+		state, _ := codec.Encode(tallyState{Run: "run-snapver", Total: 100})
+		if hash == "" {
+			hash = snapshotHash(state)
+		}
+		if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: "forged", RunID: "run-snapver", UptoSeq: 4, State: state, Version: version, Hash: hash}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("v1", "")
+	if got := total(runner("v1")); got != 100 {
+		t.Fatalf("total=%d want 100 from the snapshot", got)
+	}
+	// Snapshots of another version, or whose state was altered, are rebuilt from the events.
+	if got := total(runner("v2")); got != 10 {
+		t.Fatalf("total=%d want 10 from the events", got)
+	}
+	save("v1", "sha256:00")
+	if got := total(runner("v1")); got != 10 {
+		t.Fatalf("total=%d want 10 from the events", got)
+	}
+	// Without a codec, snapshots cannot be decoded and are not used.
+	save("", "")
+	if got := total(NewRunner(st, tallyReducer(), nil, newState)); got != 10 {
+		t.Fatalf("total=%d want 10 from the events", got)
+	}
+
+	if _, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{}); err == nil {
+		t.Fatal("expected error for an empty filter")
+	}
+	save("v1", "")
+	if n, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{Version: "v1"}); err != nil || n != 1 {
+		t.Fatalf("invalidated=%d err=%v want 1", n, err)
+	}
+}
+
+// noteHandler answers every "note" intent with the same event, which is stored only once.
+type noteHandler struct{}
+
+func (noteHandler) CanHandle(intent agent.Intent) bool { return intent.Name == "note" }
+
+func (noteHandler) Handle(ctx context.Context, s agent.State, intent agent.Intent) ([]agent.Event, error) {
+	return []agent.Event{{ID: "noted", Type: "add", Payload: map[string]any{"n": 0}}}, nil
+}
+
+func TestRunner_SnapshotSeqSkipsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	st := memstore.New()
+	red := agent.NewTypedReducer[tallyState]()
+	agent.On(red, "add", func(ctx context.Context, s tallyState, ev agent.Event, p struct {
+		N int `json:"n"`
+	}) (tallyState, []agent.Intent, error) {
+		s.Total += p.N
+		if p.N == 0 {
+			return s, nil, nil
+		}
+		return s, []agent.Intent{{Name: "note"}}, nil
+	})
+	codec := NewJSONCodec()
+	RegisterState[tallyState](codec, "tally")
+	r := NewRunner(st, red, []agent.EffectHandler{noteHandler{}}, func(runID string) agent.State { return tallyState{Run: runID} },
+		WithSnapshot(codec, 2))
+
+	runID := "run-dup"
+	// Each cycle stages two events, but only the first one stores "noted": the run has 2, 3 and
+	// 4 events after the cycles, so snapshots are taken at 2 and 4 only.
+	for i, want := range []int64{2, 2, 4} {
+		if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: fmt.Sprintf("a%d", i), Type: "add", Payload: map[string]any{"n": 1}}); err != nil {
+			t.Fatal(err)
+		}
+		sn, err := st.LoadLatestSnapshot(ctx, runID)
+		if err != nil || sn.UptoSeq != want {
+			t.Fatalf("cycle %d: snapshot=%+v err=%v want upto %d", i, sn, err, want)
+		}
+	}
+	if last, _ := st.LastSeq(ctx, runID); last != 4 {
+		t.Fatalf("last seq=%d want 4", last)
+	}
+}
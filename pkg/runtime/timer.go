@@ -0,0 +1,241 @@
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// TimerIntent is the name of the intent that schedules a timer for the run. Its args are:
+//   - "after": a duration such as "10m", or "at": an RFC 3339 time;
+//   - "name" (optional): a label echoed in the fired event;
+//   - "payload" (optional): an object echoed in the fired event.
+//
+// The intent produces a timer_scheduled event. When the timer is due, a Scheduler delivers a
+// timer_fired event with the timer_id, name, payload and due_at to the run via HandleEvent.
+const TimerIntent = "timer"
+
+// WithTimers makes the Runner handle TimerIntent by scheduling timers in ts under the given
+// queue, atomically with the cycle that handles the intent. The queue name must be unique to this
+// Runner, since the Scheduler for the queue delivers the fired events through it. ts must share
+// the database of the Runner's store.
+func WithTimers(ts store.TimerStore, queue string) RunnerOption {
+	return func(r *Runner) {
+		r.timers = ts
+		r.timerQueue = queue
+		r.committer = ts
+	}
+}
+
+// timerHandler adds the timers it schedules to the cycle that executes the intent.
+type timerHandler struct {
+	r *Runner
+	c *cycle
+}
+
+func (timerHandler) CanHandle(it agent.Intent) bool { return it.Name == TimerIntent }
+
+func (h timerHandler) Handle(ctx context.Context, s agent.State, it agent.Intent) ([]agent.Event, error) {
+	now := time.Now().UTC()
+	due, err := timerDue(it.Args, now)
+	if err != nil {
+		return nil, errmodel.Validation("bad_timer", err.Error(), map[string]any{"args": it.Args})
+	}
+	name, _ := it.Args["name"].(string)
+	payload, _ := it.Args["payload"].(map[string]any)
+	t := store.TimerRecord{
+		TimerID:   uuid.NewString(),
+		Queue:     h.r.timerQueue,
+		RunID:     h.c.runID,
+		Name:      name,
+		Payload:   payload,
+		DueAt:     due,
+		CreatedAt: now,
+	}
+	h.c.timers = append(h.c.timers, t)
+	return []agent.Event{{
+		ID:        timerScheduledEventID(t.TimerID),
+		Type:      "timer_scheduled",
+		Timestamp: now,
+		Payload: map[string]any{
+			"timer_id": t.TimerID,
+			"name":     name,
+			"due_at":   due.Format(time.RFC3339Nano),
+		},
+	}}, nil
+}
+
+// timerDue computes the due time from the "after" or "at" intent arg.
+func timerDue(args map[string]any, now time.Time) (time.Time, error) {
+	if v, ok := args["after"].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid after: %w", err)
+		}
+		return now.Add(d), nil
+	}
+	if v, ok := args["at"].(string); ok {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid at: %w", err)
+		}
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("timer needs an after or at arg")
+}
+
+func timerScheduledEventID(timerID string) string {
+	return fmt.Sprintf("timer-scheduled-%s", timerID)
+}
+
+func timerFiredEventID(timerID string) string {
+	return fmt.Sprintf("timer-fired-%s", timerID)
+}
+
+// Scheduler delivers due timers of a Runner configured with WithTimers. Timers are deleted once
+// their event has been handled; since the timer_fired event ID is derived from the timer ID,
+// delivering a timer twice (after a crash or from several schedulers) has no further effect.
+// A timer whose delivery fails is delivered again under the scheduler's retry policy.
+type Scheduler struct {
+	runner   *Runner
+	interval time.Duration
+	batch    int
+	retry    RetryPolicy
+	onError  func(t store.TimerRecord, err error)
+}
+
+// SchedulerOption configures the Scheduler at construction time.
+type SchedulerOption func(*Scheduler)
+
+// WithSchedulerInterval sets how often the Scheduler looks for due timers. Defaults to 1s.
+func WithSchedulerInterval(d time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if d > 0 {
+			s.interval = d
+		}
+	}
+}
+
+// WithSchedulerRetryPolicy sets when a timer whose delivery failed is delivered again. A timer
+// that exhausts MaxAttempts, or fails with an error the policy does not retry, is dropped and
+// reported to the error handler; conflicts are always retried. Defaults to 10 attempts with
+// backoff from 1s up to 5m.
+func WithSchedulerRetryPolicy(p RetryPolicy) SchedulerOption {
+	return func(s *Scheduler) { s.retry = p }
+}
+
+// WithSchedulerErrorHandler sets a callback for errors returned while delivering a timer.
+func WithSchedulerErrorHandler(fn func(t store.TimerRecord, err error)) SchedulerOption {
+	return func(s *Scheduler) { s.onError = fn }
+}
+
+// NewScheduler constructs a Scheduler for r, which must be configured with WithTimers.
+func NewScheduler(r *Runner, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		runner:   r,
+		interval: time.Second,
+		batch:    100,
+		retry:    RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 5 * time.Minute, Jitter: 0.2},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run delivers due timers until ctx is done. It looks again right away only after delivering a
+// full batch; otherwise it waits for the interval.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		n, err := s.Fire(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil && n == s.batch {
+			continue
+		}
+		if sleepCtx(ctx, s.interval) != nil {
+			return nil
+		}
+	}
+}
+
+// Fire delivers the timers due now and returns how many were delivered.
+func (s *Scheduler) Fire(ctx context.Context) (int, error) {
+	r := s.runner
+	if r.timers == nil {
+		return 0, fmt.Errorf("runtime: scheduler runner has no timer store")
+	}
+	due, err := r.timers.DueTimers(ctx, r.timerQueue, time.Now(), s.batch)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, t := range due {
+		err := s.fire(ctx, t)
+		if err == nil {
+			delivered++
+		} else if s.onError != nil {
+			s.onError(t, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return delivered, nil
+}
+
+func (s *Scheduler) fire(ctx context.Context, t store.TimerRecord) error {
+	r := s.runner
+	hctx := ctx
+	if s.retry.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		hctx, cancel = context.WithTimeout(ctx, s.retry.AttemptTimeout)
+		defer cancel()
+	}
+	_, err := r.HandleEvent(hctx, t.RunID, agent.Event{
+		ID:        timerFiredEventID(t.TimerID),
+		Type:      "timer_fired",
+		Timestamp: time.Now().UTC(),
+		Payload: map[string]any{
+			"timer_id": t.TimerID,
+			"name":     t.Name,
+			"payload":  t.Payload,
+			"due_at":   t.DueAt.Format(time.RFC3339Nano),
+		},
+	})
+	if err != nil && !errmodel.HasCode(err, "run_cancelled") {
+		// The delivered event is deduplicated if it was committed.
+		if ctx.Err() != nil {
+			return err
+		}
+		return s.failed(ctx, t, err)
+	}
+	if err := r.timers.DeleteTimer(ctx, t.TimerID); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	return nil
+}
+
+// failed delays the next delivery of a timer that failed with err, or drops the timer once the
+// retry policy gives up on it. It returns err, or the error the timer was dropped with.
+func (s *Scheduler) failed(ctx context.Context, t store.TimerRecord, err error) error {
+	ts := s.runner.timers
+	attempt := t.Attempts + 1
+	if attempt >= s.retry.attempts() || !(s.retry.retryable(err) || errmodel.HasCode(err, "conflict")) {
+		if derr := ts.DeleteTimer(ctx, t.TimerID); derr != nil && derr != sql.ErrNoRows {
+			return derr
+		}
+		return errmodel.System("timer_dropped", "timer could not be delivered", map[string]any{"timer_id": t.TimerID, "run_id": t.RunID, "attempts": attempt}, err)
+	}
+	if rerr := ts.RetryTimer(ctx, t.TimerID, time.Now().Add(s.retry.backoff(attempt+1))); rerr != nil && rerr != sql.ErrNoRows {
+		return rerr
+	}
+	return err
+}
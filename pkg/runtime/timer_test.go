@@ -0,0 +1,136 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// timerReducer schedules a timer for "remind" and counts "timer_fired" events named "ping".
+type timerReducer struct{}
+
+func (timerReducer) Reduce(ctx context.Context, current agent.State, event agent.Event) (agent.State, []agent.Intent, error) {
+	st := current.(testState)
+	switch event.Type {
+	case "remind":
+		return st, []agent.Intent{{Name: TimerIntent, Args: map[string]any{"after": "50ms", "name": "ping", "payload": map[string]any{"x": 1}}}}, nil
+	case "remind_now":
+		var p struct {
+			Name string `json:"name"`
+		}
+		decode(event.Payload, &p)
+		return st, []agent.Intent{{Name: TimerIntent, Args: map[string]any{"after": "0s", "name": p.Name}}}, nil
+	case "timer_scheduled":
+		var p struct {
+			Name string `json:"name"`
+		}
+		decode(event.Payload, &p)
+		if p.Name == "unschedulable" {
+			return nil, nil, errmodel.Validation("rejected", "timer rejected", nil)
+		}
+		return st, nil, nil
+	case "timer_fired":
+		var p struct {
+			Name    string         `json:"name"`
+			Payload map[string]any `json:"payload"`
+		}
+		decode(event.Payload, &p)
+		if p.Name == "boom" {
+			return nil, nil, errmodel.New(errmodel.CategorySystem, "boom", "cannot handle timer", nil)
+		}
+		if p.Name == "ping" && p.Payload["x"] == float64(1) {
+			st.Count++
+		}
+		return testState{runID: st.runID, Count: st.Count}, nil, nil
+	default:
+		return st, nil, nil
+	}
+}
+
+func TestScheduler_DeliversDueTimers_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-timers")
+	newState := func(runID string) agent.State { return testState{runID: runID} }
+	r := NewRunner(st, timerReducer{}, nil, newState, WithTimers(st, "timers"))
+	if _, err := r.HandleEvent(ctx, "run-timer", agent.Event{ID: "t1", Type: "remind"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := countTypes(t, st, "run-timer")["timer_scheduled"]; got != 1 {
+		t.Fatalf("timer_scheduled=%d want 1", got)
+	}
+	if n, err := NewScheduler(r).Fire(ctx); err != nil || n != 0 {
+		t.Fatalf("fired n=%d err=%v before due", n, err)
+	}
+	time.Sleep(80 * time.Millisecond)
+
+	// A new Runner and Scheduler over the same store, as after a restart, deliver the timer once.
+	r = NewRunner(st, timerReducer{}, nil, newState, WithTimers(st, "timers"))
+	s := NewScheduler(r)
+	if n, err := s.Fire(ctx); err != nil || n != 1 {
+		t.Fatalf("fired n=%d err=%v want 1", n, err)
+	}
+	if n, err := s.Fire(ctx); err != nil || n != 0 {
+		t.Fatalf("fired again n=%d err=%v", n, err)
+	}
+	cur, _, err := r.replayState(ctx, "run-timer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cur.(testState).Count; got != 1 {
+		t.Fatalf("count=%d want 1", got)
+	}
+}
+
+func TestScheduler_RetriesFailedDeliveries_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-timer-retry")
+	r := NewRunner(st, timerReducer{}, nil, func(runID string) agent.State { return testState{runID: runID} }, WithTimers(st, "retry"))
+	due := func() []store.TimerRecord {
+		t.Helper()
+		ts, err := st.DueTimers(ctx, "retry", time.Now().Add(time.Hour), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	// A timer is saved with the cycle that schedules it, so a cycle that fails saves none.
+	if _, err := r.HandleEvent(ctx, "run-timer-retry", agent.Event{ID: "u1", Type: "remind_now", Payload: map[string]any{"name": "unschedulable"}}); err == nil {
+		t.Fatal("expected reducer error")
+	}
+	if ts := due(); len(ts) != 0 {
+		t.Fatalf("timers=%+v saved by a failed cycle", ts)
+	}
+
+	if _, err := r.HandleEvent(ctx, "run-timer-retry", agent.Event{ID: "b1", Type: "remind_now", Payload: map[string]any{"name": "boom"}}); err != nil {
+		t.Fatal(err)
+	}
+	var errs []error
+	s := NewScheduler(r, WithSchedulerRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: 50 * time.Millisecond}),
+		WithSchedulerErrorHandler(func(_ store.TimerRecord, err error) { errs = append(errs, err) }))
+
+	// A failed delivery is not counted, and the timer waits for its backoff.
+	if n, err := s.Fire(ctx); err != nil || n != 0 || len(errs) != 1 || !errmodel.HasCode(errs[0], "boom") {
+		t.Fatalf("fired n=%d err=%v errs=%v", n, err, errs)
+	}
+	ts := due()
+	if len(ts) != 1 || ts[0].Attempts != 1 || !ts[0].AvailableAt.After(time.Now()) {
+		t.Fatalf("timers=%+v want one delayed after an attempt", ts)
+	}
+	if n, err := s.Fire(ctx); err != nil || n != 0 || len(errs) != 1 {
+		t.Fatalf("fired n=%d err=%v errs=%v during backoff", n, err, errs)
+	}
+
+	// The last attempt drops the timer.
+	time.Sleep(80 * time.Millisecond)
+	if n, err := s.Fire(ctx); err != nil || n != 0 || len(errs) != 2 || !errmodel.HasCode(errs[1], "timer_dropped") {
+		t.Fatalf("fired n=%d err=%v errs=%v", n, err, errs)
+	}
+	if ts := due(); len(ts) != 0 {
+		t.Fatalf("timers=%+v want the failing timer dropped", ts)
+	}
+}
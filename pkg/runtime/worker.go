@@ -0,0 +1,199 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// Worker drains the outbox queue of a Runner configured with WithOutbox. Items are claimed under
+// a lease that is renewed by heartbeats while the intent runs; items whose lease expires, for
+// example because their worker crashed, are claimed again by any worker on the queue.
+type Worker struct {
+	runner       *Runner
+	id           string
+	concurrency  int
+	lease        time.Duration
+	heartbeat    time.Duration
+	pollInterval time.Duration
+	retryDelay   time.Duration
+	onError      func(item store.OutboxRecord, err error)
+}
+
+// WorkerOption configures the Worker at construction time.
+type WorkerOption func(*Worker)
+
+// WithWorkerID sets the lease owner name; defaults to a random UUID.
+func WithWorkerID(id string) WorkerOption {
+	return func(w *Worker) { w.id = id }
+}
+
+// WithWorkerConcurrency sets how many items the Worker executes at once. Defaults to 4.
+func WithWorkerConcurrency(n int) WorkerOption {
+	return func(w *Worker) {
+		if n > 0 {
+			w.concurrency = n
+		}
+	}
+}
+
+// WithLease sets the lease duration of claimed items and the heartbeat interval renewing it.
+// Defaults to 30s and 10s; heartbeat <= 0 defaults to a third of the lease.
+func WithLease(lease, heartbeat time.Duration) WorkerOption {
+	return func(w *Worker) {
+		if lease > 0 {
+			w.lease = lease
+			w.heartbeat = heartbeat
+			if heartbeat <= 0 {
+				w.heartbeat = lease / 3
+			}
+		}
+	}
+}
+
+// WithPollInterval sets how long the Worker waits when the queue is empty. Defaults to 500ms.
+func WithPollInterval(d time.Duration) WorkerOption {
+	return func(w *Worker) {
+		if d > 0 {
+			w.pollInterval = d
+		}
+	}
+}
+
+// WithRetryDelay sets how long an item that failed without committing (for example on a store
+// error) waits before it can be claimed again. Defaults to 1s.
+func WithRetryDelay(d time.Duration) WorkerOption {
+	return func(w *Worker) { w.retryDelay = d }
+}
+
+// WithWorkerErrorHandler sets a callback for errors returned while executing an item.
+// Exhausted retry policies are reported here too, after the item was completed.
+func WithWorkerErrorHandler(fn func(item store.OutboxRecord, err error)) WorkerOption {
+	return func(w *Worker) { w.onError = fn }
+}
+
+// NewWorker constructs a Worker for r, which must be configured with WithOutbox.
+func NewWorker(r *Runner, opts ...WorkerOption) *Worker {
+	w := &Worker{
+		runner:       r,
+		id:           uuid.NewString(),
+		concurrency:  4,
+		lease:        30 * time.Second,
+		heartbeat:    10 * time.Second,
+		pollInterval: 500 * time.Millisecond,
+		retryDelay:   time.Second,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run drains the queue until ctx is done, keeping up to the configured number of items in flight.
+// It waits for the items in flight before returning.
+func (w *Worker) Run(ctx context.Context) error {
+	if w.runner.outbox == nil {
+		return errors.New("runtime: worker runner has no outbox")
+	}
+	slots := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		// Wait for a free slot, then take every other free slot too.
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+		n := 1
+	fill:
+		for n < w.concurrency {
+			select {
+			case slots <- struct{}{}:
+				n++
+			default:
+				break fill
+			}
+		}
+		items, err := w.runner.outbox.ClaimOutbox(ctx, w.runner.outboxQueue, w.id, w.lease, n)
+		for range n - len(items) {
+			<-slots
+		}
+		for _, item := range items {
+			wg.Go(func() {
+				defer func() { <-slots }()
+				w.process(ctx, item)
+			})
+		}
+		if err != nil || len(items) == 0 {
+			if sleepCtx(ctx, w.pollInterval) != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// Drain claims one batch of available items, executes them and returns how many were claimed.
+func (w *Worker) Drain(ctx context.Context) (int, error) {
+	ob := w.runner.outbox
+	if ob == nil {
+		return 0, errors.New("runtime: worker runner has no outbox")
+	}
+	items, err := ob.ClaimOutbox(ctx, w.runner.outboxQueue, w.id, w.lease, w.concurrency)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Go(func() { w.process(ctx, item) })
+	}
+	wg.Wait()
+	return len(items), nil
+}
+
+// process executes a claimed item while renewing its lease. If the lease cannot be renewed the
+// execution is canceled, since another worker may claim the item.
+func (w *Worker) process(ctx context.Context, item store.OutboxRecord) {
+	ob := w.runner.outbox
+	ictx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	var hb sync.WaitGroup
+	hb.Go(func() {
+		t := time.NewTicker(w.heartbeat)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				if err := ob.ExtendOutboxLease(ictx, item.ItemID, w.id, w.lease); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	})
+	err := w.runner.processOutbox(ictx, item, w.id)
+	close(done)
+	hb.Wait()
+	// Items of paused runs were parked by processOutbox.
+	if err == nil || errmodel.HasCode(err, "run_paused") {
+		return
+	}
+	if w.onError != nil {
+		w.onError(item, err)
+	}
+	// Give the item back unless it was completed or the lease is gone. On shutdown it becomes
+	// available right away so that another worker can take over.
+	availableAt := time.Now().Add(w.retryDelay)
+	if ctx.Err() != nil {
+		availableAt = time.Now()
+	}
+	_ = ob.ReleaseOutbox(context.WithoutCancel(ctx), item.ItemID, w.id, availableAt)
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/agent"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+func TestWorker_DrainsOutbox_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-outbox")
+	newState := func(runID string) agent.State { return testState{runID: runID} }
+	halt := func(s agent.State) bool { return s.(testState).Count >= 3 }
+	r := NewRunner(st, loopReducer{}, []agent.EffectHandler{tickHandler{}}, newState,
+		WithLoop(10, halt), WithOutbox(st, "ticks"))
+
+	// HandleEvent only records the incoming event and enqueues its intent.
+	s, err := r.HandleEvent(ctx, "run-ob", agent.Event{ID: "ob1", Type: "start"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.(testState).Count; got != 0 {
+		t.Fatalf("count=%d want 0 before the worker runs", got)
+	}
+
+	// A worker that crashed holds the first item; it is taken over once the lease expires.
+	if items, err := st.ClaimOutbox(ctx, "ticks", "crashed", 50*time.Millisecond, 1); err != nil || len(items) != 1 {
+		t.Fatalf("claim items=%v err=%v", items, err)
+	}
+	w := NewWorker(r, WithLease(time.Minute, 0))
+	if n, err := w.Drain(ctx); err != nil || n != 0 {
+		t.Fatalf("drained n=%d err=%v while leased", n, err)
+	}
+	time.Sleep(80 * time.Millisecond)
+
+	// Each follow-up is enqueued by the cycle that executed the previous intent.
+	var errs []error
+	w = NewWorker(r, WithLease(time.Minute, 0), WithWorkerErrorHandler(func(_ store.OutboxRecord, err error) { errs = append(errs, err) }))
+	for range 10 {
+		n, err := w.Drain(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		t.Fatalf("worker errors: %v", errs)
+	}
+	cur, _, err := r.replayState(ctx, "run-ob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cur.(testState).Count; got != 3 {
+		t.Fatalf("count=%d want 3 after draining", got)
+	}
+	if left, _ := st.ClaimOutbox(ctx, "ticks", "check", time.Minute, 10); len(left) != 0 {
+		t.Fatalf("outbox not empty: %+v", left)
+	}
+}
+
+func TestWorker_Run_SQLite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	st := openTestStore(t, "runtime-worker-run")
+	newState := func(runID string) agent.State { return testState{runID: runID} }
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, newState, WithOutbox(st, "adds"))
+	done := make(chan error, 1)
+	go func() { done <- NewWorker(r, WithPollInterval(10*time.Millisecond)).Run(ctx) }()
+
+	for i, id := range []string{"a", "b", "c"} {
+		if _, err := r.HandleEvent(ctx, "run-"+id, agent.Event{ID: id, Type: "inc", Payload: map[string]any{"n": i + 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for _, id := range []string{"a", "b", "c"} {
+		for {
+			evs, err := st.ListEvents(ctx, "run-"+id, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(evs) == 2 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("run-%s events=%d want 2", id, len(evs))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorker_ParksItemsOfPausedRuns_SQLite(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, "runtime-worker-park")
+	newState := func(runID string) agent.State { return testState{runID: runID} }
+	r := NewRunner(st, testReducer{}, []agent.EffectHandler{testHandler{}}, newState, WithOutbox(st, "adds"))
+	for _, runID := range []string{"run-resumed", "run-cancelled"} {
+		if _, err := r.HandleEvent(ctx, runID, agent.Event{ID: runID + "-1", Type: "inc", Payload: map[string]any{"n": 1}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Pause(ctx, runID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Items of paused runs are parked instead of being retried after the retry delay.
+	w := NewWorker(r, WithRetryDelay(0))
+	if n, err := w.Drain(ctx); err != nil || n != 2 {
+		t.Fatalf("drained n=%d err=%v want 2", n, err)
+	}
+	if n, err := w.Drain(ctx); err != nil || n != 0 {
+		t.Fatalf("drained n=%d err=%v want 0 while paused", n, err)
+	}
+
+	// Resume and Cancel wake them: the first is executed, the second dropped.
+	if _, err := r.Resume(ctx, "run-resumed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Cancel(ctx, "run-cancelled", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.Drain(ctx); err != nil || n != 2 {
+		t.Fatalf("drained n=%d err=%v want 2 after resume and cancel", n, err)
+	}
+	for runID, want := range map[string]int{"run-resumed": 1, "run-cancelled": 0} {
+		if got := countTypes(t, st, runID)["added"]; got != want {
+			t.Fatalf("%s: added events=%d want %d", runID, got, want)
+		}
+	}
+	if left, _ := st.ClaimOutbox(ctx, "adds", "check", time.Minute, 10); len(left) != 0 {
+		t.Fatalf("outbox not empty: %+v", left)
+	}
+}
@@ -0,0 +1,163 @@
+package entstore
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+
+	"entgo.io/ent/dialect"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/event"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// archiveBatch bounds the rows inserted per statement, within SQLite's variable limit.
+const archiveBatch = 100
+
+// ArchiveEvents moves the events of a run up to uptoSeq to the archived_events table. The run's
+// last event stays live so that its sequence, and the global positions, keep increasing.
+func (s *Store) ArchiveEvents(ctx context.Context, runID string, uptoSeq int64) (int, error) {
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	// Serialize with appends, whose duplicate check reads both tables.
+	if s.dialect == dialect.Postgres {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", appendLockKey); err != nil {
+			return 0, err
+		}
+	}
+	last, err := tx.Event.Query().
+		Where(event.RunID(runID)).
+		Order(ent.Desc(event.FieldSeq)).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	uptoSeq = min(uptoSeq, last.Seq-1)
+	rows, err := tx.Event.Query().
+		Where(event.RunID(runID), event.SeqLTE(uptoSeq)).
+		Order(ent.Asc(event.FieldSeq)).
+		All(ctx)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	for chunk := range slices.Chunk(rows, archiveBatch) {
+		builders := make([]*ent.ArchivedEventCreate, 0, len(chunk))
+		for _, r := range chunk {
+			b := tx.ArchivedEvent.Create().
+				SetPosition(int64(r.ID)).
+				SetEventID(r.EventID).
+				SetRunID(r.RunID).
+				SetSeq(r.Seq).
+				SetType(r.Type).
+				SetVersion(r.Version).
+				SetKeyID(r.KeyID).
+				SetCreatedAt(r.CreatedAt)
+			if r.Payload != "" {
+				b = b.SetPayload(r.Payload)
+			}
+			builders = append(builders, b)
+		}
+		if err := tx.ArchivedEvent.CreateBulk(builders...).Exec(ctx); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Event.Delete().Where(event.RunID(runID), event.SeqLTE(uptoSeq)).Exec(ctx); err != nil {
+		return 0, err
+	}
+	return len(rows), tx.Commit()
+}
+
+// listArchived lists archived events of a run after afterSeq in sequence order.
+func (s *Store) listArchived(ctx context.Context, runID string, afterSeq int64, limit int) ([]store.EventRecord, error) {
+	q := s.client.ArchivedEvent.Query().Where(archivedevent.RunID(runID))
+	if afterSeq > 0 {
+		q = q.Where(archivedevent.SeqGT(afterSeq))
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.Order(ent.Asc(archivedevent.FieldSeq)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.archivedRecords(ctx, rows)
+}
+
+// readAllArchived lists archived events of every run after a global position.
+func (s *Store) readAllArchived(ctx context.Context, afterPosition int64, limit int) ([]store.EventRecord, error) {
+	q := s.client.ArchivedEvent.Query().Where(archivedevent.PositionGT(afterPosition))
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.Order(ent.Asc(archivedevent.FieldPosition)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.archivedRecords(ctx, rows)
+}
+
+// getArchived looks up an archived event by its EventID.
+func (s *Store) getArchived(ctx context.Context, eventID string) (store.EventRecord, error) {
+	rec, err := s.client.ArchivedEvent.Query().
+		Where(archivedevent.EventID(eventID)).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return store.EventRecord{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return store.EventRecord{}, err
+	}
+	return s.toArchivedRecord(ctx, rec)
+}
+
+// mergeEvents combines events read from the live log and then from the archive. An event
+// archived between the two reads appears in both and is kept once. The result is ordered by
+// key and truncated to limit.
+func mergeEvents(live, archived []store.EventRecord, key func(store.EventRecord) int64, limit int) []store.EventRecord {
+	if len(archived) == 0 {
+		return live
+	}
+	out := slices.Concat(archived, live)
+	slices.SortFunc(out, func(a, b store.EventRecord) int { return cmp.Compare(key(a), key(b)) })
+	out = slices.CompactFunc(out, func(a, b store.EventRecord) bool { return key(a) == key(b) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func (s *Store) archivedRecords(ctx context.Context, rows []*ent.ArchivedEvent) ([]store.EventRecord, error) {
+	out := make([]store.EventRecord, 0, len(rows))
+	for _, r := range rows {
+		rec, err := s.toArchivedRecord(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// toArchivedRecord converts an archived row, decrypting its payload.
+func (s *Store) toArchivedRecord(ctx context.Context, r *ent.ArchivedEvent) (store.EventRecord, error) {
+	payload, err := s.open(ctx, r.KeyID, r.Payload, r.EventID)
+	return store.EventRecord{
+		EventID:   r.EventID,
+		RunID:     r.RunID,
+		Seq:       r.Seq,
+		Position:  r.Position,
+		Type:      r.Type,
+		Version:   r.Version,
+		Payload:   payload,
+		CreatedAt: r.CreatedAt,
+	}, err
+}
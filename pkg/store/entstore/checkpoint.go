@@ -0,0 +1,47 @@
+package entstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/checkpoint"
+)
+
+// LoadCheckpoint returns the saved position of a reader, or 0 if it has none.
+func (s *Store) LoadCheckpoint(ctx context.Context, name string) (int64, error) {
+	rec, err := s.client.Checkpoint.Query().
+		Where(checkpoint.Name(name)).
+		First(ctx)
+	if ent.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rec.Position, nil
+}
+
+// SaveCheckpoint sets the position of a reader.
+func (s *Store) SaveCheckpoint(ctx context.Context, name string, position int64) error {
+	for attempt := 0; ; attempt++ {
+		n, err := s.client.Checkpoint.Update().
+			Where(checkpoint.Name(name)).
+			SetPosition(position).
+			SetUpdatedAt(time.Now()).
+			Save(ctx)
+		if err != nil || n > 0 {
+			return err
+		}
+		err = s.client.Checkpoint.Create().
+			SetName(name).
+			SetPosition(position).
+			SetUpdatedAt(time.Now()).
+			Exec(ctx)
+		if ent.IsConstraintError(err) && attempt == 0 {
+			// Created concurrently; update it instead.
+			continue
+		}
+		return err
+	}
+}
@@ -0,0 +1,137 @@
+package entstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/deadletter"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// SaveDeadLetter inserts a dead letter.
+func (s *Store) SaveDeadLetter(ctx context.Context, d store.DeadLetterRecord) (store.DeadLetterRecord, error) {
+	created, err := createDeadLetter(s.client.DeadLetter, d).Save(ctx)
+	if err != nil {
+		return store.DeadLetterRecord{}, err
+	}
+	return toDeadLetterRecord(created), nil
+}
+
+// commitDeadLetters saves the dead letters of a cycle, replacing existing ones with the same ID,
+// and removes the resolved one.
+func commitDeadLetters(ctx context.Context, tx *ent.Tx, c store.CycleCommit) error {
+	if c.Resolve != "" {
+		if _, err := tx.DeadLetter.Delete().Where(deadletter.DeadLetterID(c.Resolve)).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	for _, d := range c.DeadLetters {
+		u := tx.DeadLetter.Update().
+			Where(deadletter.DeadLetterID(d.DeadLetterID)).
+			SetIntentName(d.IntentName).
+			SetAttempts(d.Attempts).
+			SetError(d.Error).
+			ClearIdempotencyKey().
+			ClearArgs()
+		if d.IdempotencyKey != "" {
+			u = u.SetIdempotencyKey(d.IdempotencyKey)
+		}
+		if d.Args != nil {
+			u = u.SetArgs(d.Args)
+		}
+		n, err := u.Save(ctx)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		if d.RunID == "" {
+			d.RunID = c.RunID
+		}
+		if err := createDeadLetter(tx.DeadLetter, d).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createDeadLetter(client *ent.DeadLetterClient, d store.DeadLetterRecord) *ent.DeadLetterCreate {
+	b := client.Create().
+		SetDeadLetterID(d.DeadLetterID).
+		SetRunID(d.RunID).
+		SetIntentName(d.IntentName).
+		SetAttempts(d.Attempts).
+		SetError(d.Error).
+		SetCreatedAt(time.Now())
+	if d.IdempotencyKey != "" {
+		b = b.SetIdempotencyKey(d.IdempotencyKey)
+	}
+	if d.Args != nil {
+		b = b.SetArgs(d.Args)
+	}
+	return b
+}
+
+// GetDeadLetter looks up a dead letter by ID.
+func (s *Store) GetDeadLetter(ctx context.Context, id string) (store.DeadLetterRecord, error) {
+	rec, err := s.client.DeadLetter.Query().
+		Where(deadletter.DeadLetterID(id)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return store.DeadLetterRecord{}, sql.ErrNoRows
+		}
+		return store.DeadLetterRecord{}, err
+	}
+	return toDeadLetterRecord(rec), nil
+}
+
+// ListDeadLetters lists dead letters, optionally for a single run.
+func (s *Store) ListDeadLetters(ctx context.Context, runID string, limit int) ([]store.DeadLetterRecord, error) {
+	q := s.client.DeadLetter.Query()
+	if runID != "" {
+		q = q.Where(deadletter.RunID(runID))
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.Order(ent.Asc(deadletter.FieldID)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]store.DeadLetterRecord, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toDeadLetterRecord(r))
+	}
+	return out, nil
+}
+
+// DeleteDeadLetter removes a dead letter by ID.
+func (s *Store) DeleteDeadLetter(ctx context.Context, id string) error {
+	n, err := s.client.DeadLetter.Delete().
+		Where(deadletter.DeadLetterID(id)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func toDeadLetterRecord(r *ent.DeadLetter) store.DeadLetterRecord {
+	return store.DeadLetterRecord{
+		DeadLetterID:   r.DeadLetterID,
+		RunID:          r.RunID,
+		IntentName:     r.IntentName,
+		IdempotencyKey: r.IdempotencyKey,
+		Args:           r.Args,
+		Attempts:       r.Attempts,
+		Error:          r.Error,
+		CreatedAt:      r.CreatedAt,
+	}
+}
@@ -0,0 +1,144 @@
+package entstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/event"
+	"github.com/wilhg/orch/internal/ent/snapshot"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/envelope"
+)
+
+// Option configures the Store at construction time.
+type Option func(*Store)
+
+// WithEncryption encrypts event payloads and snapshot states at rest with data keys wrapped by
+// p, recording the master key ID of each row. Reads decrypt them transparently, and rows
+// written without encryption are read as they are. Encrypted payloads cannot be queried by the
+// database.
+func WithEncryption(p envelope.KeyProvider) Option {
+	return func(s *Store) { s.cipher = envelope.NewCipher(p) }
+}
+
+var _ store.KeyRotator = (*Store)(nil)
+
+// seal encrypts a payload or state bound to the ID of its row, returning the key ID and text
+// to store. Without encryption, the text is stored as is.
+func (s *Store) seal(ctx context.Context, text []byte, rowID string) (string, string, error) {
+	if s.cipher == nil || len(text) == 0 {
+		return "", string(text), nil
+	}
+	keyID, ct, err := s.cipher.Encrypt(ctx, text, []byte(rowID))
+	if err != nil {
+		return "", "", err
+	}
+	return keyID, string(ct), nil
+}
+
+// open returns the JSON stored for a row, decrypting it if it was stored under a key.
+func (s *Store) open(ctx context.Context, keyID, text, rowID string) (json.RawMessage, error) {
+	if keyID == "" || text == "" {
+		return rawJSON(text), nil
+	}
+	if s.cipher == nil {
+		return nil, fmt.Errorf("entstore: %q is encrypted under key %q but no key provider is configured", rowID, keyID)
+	}
+	plain, err := s.cipher.Decrypt(ctx, keyID, []byte(text), []byte(rowID))
+	if err != nil {
+		return nil, fmt.Errorf("entstore: %q: %w", rowID, err)
+	}
+	return plain, nil
+}
+
+// Reencrypt rewrites up to limit payloads and states that are in plain text or encrypted under
+// a key other than the active one, so that retired keys can be removed from the key provider.
+func (s *Store) Reencrypt(ctx context.Context, limit int) (int, error) {
+	if s.cipher == nil {
+		return 0, fmt.Errorf("entstore: encryption is not configured")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	active := s.cipher.KeyID()
+	n := 0
+
+	events, err := s.client.Event.Query().
+		Where(event.KeyIDNEQ(active), event.PayloadNotNil()).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return n, err
+	}
+	for _, r := range events {
+		keyID, text, err := s.reseal(ctx, r.KeyID, r.Payload, r.EventID)
+		if err != nil {
+			return n, err
+		}
+		// Rows archived in the meantime are picked up from the archive on a later call.
+		err = s.client.Event.UpdateOneID(r.ID).Where(event.KeyID(r.KeyID)).SetKeyID(keyID).SetPayload(text).Exec(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return n, err
+		}
+		n++
+	}
+
+	if n == limit {
+		return n, nil
+	}
+
+	archived, err := s.client.ArchivedEvent.Query().
+		Where(archivedevent.KeyIDNEQ(active), archivedevent.PayloadNotNil()).
+		Limit(limit - n).
+		All(ctx)
+	if err != nil {
+		return n, err
+	}
+	for _, r := range archived {
+		keyID, text, err := s.reseal(ctx, r.KeyID, r.Payload, r.EventID)
+		if err != nil {
+			return n, err
+		}
+		err = s.client.ArchivedEvent.UpdateOneID(r.ID).Where(archivedevent.KeyID(r.KeyID)).SetKeyID(keyID).SetPayload(text).Exec(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return n, err
+		}
+		n++
+	}
+	if n == limit {
+		return n, nil
+	}
+
+	snaps, err := s.client.Snapshot.Query().
+		Where(snapshot.KeyIDNEQ(active), snapshot.StateNotNil()).
+		Limit(limit - n).
+		All(ctx)
+	if err != nil {
+		return n, err
+	}
+	for _, r := range snaps {
+		keyID, text, err := s.reseal(ctx, r.KeyID, r.State, r.SnapshotID)
+		if err != nil {
+			return n, err
+		}
+		// Snapshots pruned in the meantime need no rewrite.
+		err = s.client.Snapshot.UpdateOneID(r.ID).Where(snapshot.KeyID(r.KeyID)).SetKeyID(keyID).SetState(text).Exec(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// reseal decrypts a stored text and encrypts it under the active key.
+func (s *Store) reseal(ctx context.Context, keyID, text, rowID string) (string, string, error) {
+	plain, err := s.open(ctx, keyID, text, rowID)
+	if err != nil {
+		return "", "", err
+	}
+	return s.seal(ctx, plain, rowID)
+}
@@ -0,0 +1,191 @@
+package entstore
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"entgo.io/ent/dialect"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/auditentry"
+	"github.com/wilhg/orch/internal/ent/deadletter"
+	"github.com/wilhg/orch/internal/ent/event"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+	"github.com/wilhg/orch/internal/ent/run"
+	"github.com/wilhg/orch/internal/ent/shredkey"
+	"github.com/wilhg/orch/internal/ent/snapshot"
+	"github.com/wilhg/orch/internal/ent/timer"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// DeleteRun removes every record of a run in one transaction.
+func (s *Store) DeleteRun(ctx context.Context, runID string) (store.RunDeletion, error) {
+	var d store.RunDeletion
+	tx, err := s.client.Tx(ctx)
+	if err != nil {
+		return d, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	// Serialize with appends, whose duplicate check reads the events.
+	if s.dialect == dialect.Postgres {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", appendLockKey); err != nil {
+			return d, err
+		}
+	}
+	live, err := tx.Event.Delete().Where(event.RunID(runID)).Exec(ctx)
+	if err != nil {
+		return d, err
+	}
+	archived, err := tx.ArchivedEvent.Delete().Where(archivedevent.RunID(runID)).Exec(ctx)
+	if err != nil {
+		return d, err
+	}
+	d.Events = live + archived
+	if d.Snapshots, err = tx.Snapshot.Delete().Where(snapshot.RunID(runID)).Exec(ctx); err != nil {
+		return d, err
+	}
+	if d.OutboxItems, err = tx.OutboxItem.Delete().Where(outboxitem.RunID(runID)).Exec(ctx); err != nil {
+		return d, err
+	}
+	if d.Timers, err = tx.Timer.Delete().Where(timer.RunID(runID)).Exec(ctx); err != nil {
+		return d, err
+	}
+	if d.DeadLetters, err = tx.DeadLetter.Delete().Where(deadletter.RunID(runID)).Exec(ctx); err != nil {
+		return d, err
+	}
+	if _, err := tx.Run.Delete().Where(run.RunID(runID)).Exec(ctx); err != nil {
+		return d, err
+	}
+	if _, err := tx.ShredKey.Delete().Where(shredkey.RunID(runID)).Exec(ctx); err != nil {
+		return d, err
+	}
+	return d, tx.Commit()
+}
+
+// CreateRunKey stores the shredding key of a run unless it has one already.
+func (s *Store) CreateRunKey(ctx context.Context, runID string, key []byte) ([]byte, error) {
+	err := s.client.ShredKey.Create().
+		SetRunID(runID).
+		SetKey(key).
+		SetCreatedAt(time.Now()).
+		Exec(ctx)
+	if err != nil && !ent.IsConstraintError(err) {
+		return nil, err
+	}
+	// Another writer may have created the key first; its key is the run's key.
+	return s.LoadRunKey(ctx, runID)
+}
+
+// LoadRunKey returns the shredding key of a run.
+func (s *Store) LoadRunKey(ctx context.Context, runID string) ([]byte, error) {
+	k, err := s.client.ShredKey.Query().Where(shredkey.RunID(runID)).Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	if k.ShreddedAt != nil || k.Key == nil {
+		return nil, runShredded(runID)
+	}
+	return *k.Key, nil
+}
+
+// ShredRunKey destroys the shredding key of a run, keeping its row as a tombstone.
+func (s *Store) ShredRunKey(ctx context.Context, runID string) error {
+	now := time.Now()
+	for {
+		n, err := s.client.ShredKey.Update().
+			Where(shredkey.RunID(runID)).
+			ClearKey().
+			SetShreddedAt(now).
+			Save(ctx)
+		if err != nil || n > 0 {
+			return err
+		}
+		err = s.client.ShredKey.Create().
+			SetRunID(runID).
+			SetCreatedAt(now).
+			SetShreddedAt(now).
+			Exec(ctx)
+		// A key created concurrently is shredded by the next update.
+		if !ent.IsConstraintError(err) {
+			return err
+		}
+	}
+}
+
+func runShredded(runID string) error {
+	return errmodel.Policy("run_shredded", "run data was shredded", map[string]any{"run_id": runID})
+}
+
+// AppendAudit records an audit entry.
+func (s *Store) AppendAudit(ctx context.Context, a store.AuditRecord) (store.AuditRecord, error) {
+	b := s.client.AuditEntry.Create().
+		SetAuditID(a.AuditID).
+		SetAction(a.Action).
+		SetCreatedAt(time.Now())
+	if a.RunID != "" {
+		b = b.SetRunID(a.RunID)
+	}
+	if a.Actor != "" {
+		b = b.SetActor(a.Actor)
+	}
+	if a.Details != nil {
+		b = b.SetDetails(a.Details)
+	}
+	created, err := b.Save(ctx)
+	if err != nil {
+		return store.AuditRecord{}, err
+	}
+	return toAuditRecord(created), nil
+}
+
+// ListAudit lists audit entries oldest first. The cursor is the row ID of the last entry of the
+// previous page.
+func (s *Store) ListAudit(ctx context.Context, f store.AuditFilter) (store.AuditPage, error) {
+	q := s.client.AuditEntry.Query()
+	if f.RunID != "" {
+		q = q.Where(auditentry.RunID(f.RunID))
+	}
+	if f.Cursor != "" {
+		id, err := strconv.Atoi(f.Cursor)
+		if err != nil {
+			return store.AuditPage{}, errmodel.Validation("bad_cursor", "invalid cursor", map[string]any{"cursor": f.Cursor})
+		}
+		q = q.Where(auditentry.IDGT(id))
+	}
+	if f.Limit > 0 {
+		// Fetch one more row to tell whether there is a next page.
+		q = q.Limit(f.Limit + 1)
+	}
+	rows, err := q.Order(ent.Asc(auditentry.FieldID)).All(ctx)
+	if err != nil {
+		return store.AuditPage{}, err
+	}
+	var page store.AuditPage
+	if f.Limit > 0 && len(rows) > f.Limit {
+		rows = rows[:f.Limit]
+		page.NextCursor = strconv.Itoa(rows[len(rows)-1].ID)
+	}
+	page.Entries = make([]store.AuditRecord, 0, len(rows))
+	for _, r := range rows {
+		page.Entries = append(page.Entries, toAuditRecord(r))
+	}
+	return page, nil
+}
+
+func toAuditRecord(r *ent.AuditEntry) store.AuditRecord {
+	return store.AuditRecord{
+		AuditID:   r.AuditID,
+		Action:    r.Action,
+		RunID:     r.RunID,
+		Actor:     r.Actor,
+		Details:   r.Details,
+		CreatedAt: r.CreatedAt,
+	}
+}
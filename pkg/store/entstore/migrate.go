@@ -0,0 +1,489 @@
+package entstore
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqltool"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/schema"
+
+	"github.com/wilhg/orch/internal/ent/event"
+	entmigrate "github.com/wilhg/orch/internal/ent/migrate"
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// The versioned migrations of each dialect, generated from the ent schema by DiffMigrations.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationTable records the migrations applied to a database.
+const migrationTable = "orch_schema_migrations"
+
+// migrateLockKey is the Postgres advisory lock serializing migrations.
+const migrateLockKey int64 = 0x6f7263686d696772
+
+// Migration is a version of the database schema.
+type Migration struct {
+	Version     string
+	Description string
+	// AppliedAt is when the migration was applied, or zero while it is pending.
+	AppliedAt time.Time
+}
+
+// MigrationStatus lists the applied and pending migrations of a database, in version order.
+type MigrationStatus struct {
+	Applied []Migration
+	Pending []Migration
+}
+
+// Current returns the last applied version, or "" when none is.
+func (st MigrationStatus) Current() string {
+	if len(st.Applied) == 0 {
+		return ""
+	}
+	return st.Applied[len(st.Applied)-1].Version
+}
+
+// migrationDir is a migration directory in the golang-migrate layout: each version has an up
+// and a down file, and its atlas.sum covers both, so that neither can change unnoticed.
+type migrationDir struct{ fs.FS }
+
+var _ migrate.Dir = (*migrationDir)(nil)
+
+// Files returns the up files in version order.
+func (d *migrationDir) Files() ([]migrate.File, error) {
+	return d.glob("*.up.sql")
+}
+
+// Checksum hashes the up and down files.
+func (d *migrationDir) Checksum() (migrate.HashFile, error) {
+	files, err := d.glob("*.sql")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewHashFile(files)
+}
+
+// WriteFile writes to a local directory.
+func (d *migrationDir) WriteFile(name string, b []byte) error {
+	if w, ok := d.FS.(migrate.Dir); ok {
+		return w.WriteFile(name, b)
+	}
+	return errors.New("migration directory is read-only")
+}
+
+func (d *migrationDir) glob(pattern string) ([]migrate.File, error) {
+	names, err := fs.Glob(d.FS, pattern)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	files := make([]migrate.File, len(names))
+	for i, n := range names {
+		b, err := fs.ReadFile(d.FS, n)
+		if err != nil {
+			return nil, err
+		}
+		files[i] = &sqltool.GolangMigrateFile{LocalFile: migrate.NewLocalFile(n, b)}
+	}
+	return files, nil
+}
+
+// down returns the statements reverting an up file.
+func (d *migrationDir) down(up migrate.File) ([]string, error) {
+	name := strings.TrimSuffix(up.Name(), ".up.sql") + ".down.sql"
+	b, err := fs.ReadFile(d.FS, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errmodel.Validation("irreversible_migration", "migration has no down file", map[string]any{"version": up.Version()})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewLocalFile(name, b).Stmts()
+}
+
+// migrations are the checked files of a migration directory.
+type migrations struct {
+	dir   *migrationDir
+	files []migrate.File
+	sum   migrate.HashFile
+}
+
+// loadMigrations reads the migrations in fsys, verifying them against their atlas.sum.
+func loadMigrations(fsys fs.FS) (*migrations, error) {
+	dir := &migrationDir{fsys}
+	if err := migrate.Validate(dir); err != nil {
+		return nil, errmodel.System("migration_checksum", "migration files do not match atlas.sum", nil, err)
+	}
+	files, err := dir.Files()
+	if err != nil {
+		return nil, err
+	}
+	sum, err := dir.Checksum()
+	if err != nil {
+		return nil, err
+	}
+	return &migrations{dir: dir, files: files, sum: sum}, nil
+}
+
+// migrationsFor loads the embedded migrations of a dialect.
+func migrationsFor(d string) (*migrations, error) {
+	name := "sqlite"
+	if d == dialect.Postgres {
+		name = "postgres"
+	}
+	fsys, err := fs.Sub(migrationFiles, "migrations/"+name)
+	if err != nil {
+		return nil, err
+	}
+	return loadMigrations(fsys)
+}
+
+// revision is a migration recorded in the migration table.
+type revision struct {
+	Migration
+	hash string
+}
+
+// status matches the migration files with the applied revisions. Applied files must not have
+// changed, and files older than the current version must have been applied.
+func (m *migrations) status(revs []revision) (MigrationStatus, error) {
+	var st MigrationStatus
+	applied := map[string]revision{}
+	for _, r := range revs {
+		applied[r.Version] = r
+		st.Applied = append(st.Applied, r.Migration)
+	}
+	current := st.Current()
+	for _, f := range m.files {
+		r, ok := applied[f.Version()]
+		if !ok {
+			if f.Version() < current {
+				return MigrationStatus{}, errmodel.System("migration_out_of_order", "migration is older than the current version but was not applied",
+					map[string]any{"version": f.Version(), "current": current}, nil)
+			}
+			st.Pending = append(st.Pending, Migration{Version: f.Version(), Description: f.Desc()})
+			continue
+		}
+		if hash, _ := m.sum.SumByName(f.Name()); r.hash != hash {
+			return MigrationStatus{}, errmodel.System("migration_modified", "applied migration was modified", map[string]any{"version": f.Version()}, nil)
+		}
+	}
+	return st, nil
+}
+
+func (m *migrations) file(version string) (migrate.File, bool) {
+	i := slices.IndexFunc(m.files, func(f migrate.File) bool { return f.Version() == version })
+	if i < 0 {
+		return nil, false
+	}
+	return m.files[i], true
+}
+
+// Migrate applies the pending migrations.
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.MigrateUp(ctx, 0)
+	return err
+}
+
+// MigrationStatus compares the database with the migrations of this build, verifying that the
+// migration files and the applied ones are unchanged.
+func (s *Store) MigrationStatus(ctx context.Context) (MigrationStatus, error) {
+	m, err := migrationsFor(s.dialect)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	revs, err := s.revisions(ctx)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	return m.status(revs)
+}
+
+// CheckMigrations returns a "schema_behind" error while migrations are pending, so that a server
+// does not run against an older schema than it was built for.
+func (s *Store) CheckMigrations(ctx context.Context) error {
+	st, err := s.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if n := len(st.Pending); n > 0 {
+		return errmodel.System("schema_behind", fmt.Sprintf("the database schema is %d migration(s) behind", n),
+			map[string]any{"current": st.Current(), "latest": st.Pending[n-1].Version}, nil)
+	}
+	return nil
+}
+
+// MigrateUp applies up to n pending migrations, or all of them when n <= 0, each in its own
+// transaction, and returns the applied ones. A database created before versioned migrations has
+// to be baselined first.
+func (s *Store) MigrateUp(ctx context.Context, n int) ([]Migration, error) {
+	m, err := migrationsFor(s.dialect)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.createMigrationTable(ctx); err != nil {
+		return nil, err
+	}
+	revs, err := s.revisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 {
+		exists, err := s.tableExists(ctx, event.Table)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errmodel.Validation("baseline_required", "the database has tables but no migration history; baseline it at the version its schema matches", nil)
+		}
+	}
+	st, err := m.status(revs)
+	if err != nil {
+		return nil, err
+	}
+	pending := st.Pending
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+	var done []Migration
+	for _, p := range pending {
+		f, _ := m.file(p.Version)
+		stmts, err := f.Stmts()
+		if err != nil {
+			return done, err
+		}
+		hash, _ := m.sum.SumByName(f.Name())
+		p.AppliedAt = time.Now().UTC()
+		ok, err := s.execMigration(ctx, revision{p, hash}, stmts, true)
+		if err != nil {
+			return done, fmt.Errorf("migration %s: %w", f.Name(), err)
+		}
+		if ok {
+			done = append(done, p)
+		}
+	}
+	return done, nil
+}
+
+// MigrateDown reverts up to n applied migrations, or all of them when n <= 0, newest first, and
+// returns the reverted ones.
+func (s *Store) MigrateDown(ctx context.Context, n int) ([]Migration, error) {
+	m, err := migrationsFor(s.dialect)
+	if err != nil {
+		return nil, err
+	}
+	revs, err := s.revisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.status(revs); err != nil {
+		return nil, err
+	}
+	if n > 0 && n < len(revs) {
+		revs = revs[len(revs)-n:]
+	}
+	var done []Migration
+	for _, r := range slices.Backward(revs) {
+		f, ok := m.file(r.Version)
+		if !ok {
+			return done, errmodel.Validation("unknown_migration", "applied migration is not in this build", map[string]any{"version": r.Version})
+		}
+		stmts, err := m.dir.down(f)
+		if err != nil {
+			return done, err
+		}
+		ok, err = s.execMigration(ctx, r, stmts, false)
+		if err != nil {
+			return done, fmt.Errorf("migration %s: %w", f.Name(), err)
+		}
+		if ok {
+			done = append(done, r.Migration)
+		}
+	}
+	return done, nil
+}
+
+// BaselineMigrations records the migrations up to version as applied without running them, for
+// a database whose schema was created before versioned migrations. The database must have no
+// migration history.
+func (s *Store) BaselineMigrations(ctx context.Context, version string) error {
+	m, err := migrationsFor(s.dialect)
+	if err != nil {
+		return err
+	}
+	if _, ok := m.file(version); !ok {
+		return errmodel.Validation("unknown_migration", "baseline version is not in this build", map[string]any{"version": version})
+	}
+	if err := s.createMigrationTable(ctx); err != nil {
+		return err
+	}
+	revs, err := s.revisions(ctx)
+	if err != nil {
+		return err
+	}
+	if len(revs) > 0 {
+		return errmodel.Conflict("the database already has a migration history", map[string]any{"current": revs[len(revs)-1].Version})
+	}
+	now := time.Now().UTC()
+	for _, f := range m.files {
+		if f.Version() > version {
+			break
+		}
+		hash, _ := m.sum.SumByName(f.Name())
+		if _, err := s.execMigration(ctx, revision{Migration{f.Version(), f.Desc(), now}, hash}, nil, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execMigration runs the statements of a migration and records it as applied, or with up false
+// as reverted, in one transaction. It reports false when another process got there first.
+func (s *Store) execMigration(ctx context.Context, r revision, stmts []string, up bool) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if s.dialect == dialect.Postgres {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrateLockKey); err != nil {
+			return false, err
+		}
+	}
+	b := entsql.Dialect(s.dialect)
+	var n int
+	query, args := b.Select(entsql.Count("*")).From(entsql.Table(migrationTable)).Where(entsql.EQ("version", r.Version)).Query()
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+		return false, err
+	}
+	if (n > 0) == up {
+		return false, nil
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return false, err
+		}
+	}
+	if up {
+		query, args = b.Insert(migrationTable).
+			Columns("version", "description", "hash", "applied_at").
+			Values(r.Version, r.Description, r.hash, r.AppliedAt).
+			Query()
+	} else {
+		query, args = b.Delete(migrationTable).Where(entsql.EQ("version", r.Version)).Query()
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (s *Store) createMigrationTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrationTable+` (
+		version VARCHAR(64) PRIMARY KEY,
+		description TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// revisions lists the applied migrations in version order.
+func (s *Store) revisions(ctx context.Context) ([]revision, error) {
+	exists, err := s.tableExists(ctx, migrationTable)
+	if err != nil || !exists {
+		return nil, err
+	}
+	query, args := entsql.Dialect(s.dialect).
+		Select("version", "description", "hash", "applied_at").
+		From(entsql.Table(migrationTable)).
+		OrderBy("version").
+		Query()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var revs []revision
+	for rows.Next() {
+		var r revision
+		if err := rows.Scan(&r.Version, &r.Description, &r.hash, &r.AppliedAt); err != nil {
+			return nil, err
+		}
+		revs = append(revs, r)
+	}
+	return revs, rows.Err()
+}
+
+func (s *Store) tableExists(ctx context.Context, name string) (bool, error) {
+	query := "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if s.dialect == dialect.Postgres {
+		query = "SELECT count(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1"
+	}
+	var n int
+	err := s.db.QueryRowContext(ctx, query, name).Scan(&n)
+	return n > 0, err
+}
+
+// DiffMigrations writes the migration that brings the schema of the migrations in the directory
+// at path up to the ent schema, as name's up and down files, and updates the directory's
+// atlas.sum. The migrations are replayed on the empty database at devURL, whose dialect must be
+// the directory's. It reports false when the migrations are up to date.
+func DiffMigrations(ctx context.Context, devURL, path, name string) (bool, error) {
+	dev, err := Open(ctx, devURL)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = dev.Close() }()
+	dir, err := migrate.NewLocalDir(path)
+	if err != nil {
+		return false, err
+	}
+	return dev.diffMigrations(ctx, &migrationDir{dir}, name)
+}
+
+func (s *Store) diffMigrations(ctx context.Context, dir migrate.Dir, name string) (bool, error) {
+	// An in-memory SQLite database lives on its connection.
+	s.db.SetMaxOpenConns(1)
+	a, err := schema.NewMigrate(entsql.OpenDB(s.dialect, s.db),
+		schema.WithDir(dir),
+		schema.WithFormatter(sqltool.GolangMigrateFormatter),
+		schema.WithMigrationMode(schema.ModeReplay),
+		schema.WithDialect(s.dialect),
+		schema.WithErrNoPlan(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	err = a.NamedDiff(ctx, name, entmigrate.Tables...)
+	if errors.Is(err, migrate.ErrNoPlan) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// HashMigrations rewrites the atlas.sum of the migration directory at path, after its files were
+// edited by hand.
+func HashMigrations(path string) error {
+	dir, err := migrate.NewLocalDir(path)
+	if err != nil {
+		return err
+	}
+	md := &migrationDir{dir}
+	sum, err := md.Checksum()
+	if err != nil {
+		return err
+	}
+	return migrate.WriteSumFile(md, sum)
+}
@@ -0,0 +1,16 @@
+-- reverse: create index "snapshot_run_id_upto_seq" to table: "snapshots"
+DROP INDEX "snapshot_run_id_upto_seq";
+-- reverse: create index "snapshot_run_id" to table: "snapshots"
+DROP INDEX "snapshot_run_id";
+-- reverse: create index "snapshots_snapshot_id_key" to table: "snapshots"
+DROP INDEX "snapshots_snapshot_id_key";
+-- reverse: create "snapshots" table
+DROP TABLE "snapshots";
+-- reverse: create index "event_run_id" to table: "events"
+DROP INDEX "event_run_id";
+-- reverse: create index "event_run_id_seq" to table: "events"
+DROP INDEX "event_run_id_seq";
+-- reverse: create index "events_event_id_key" to table: "events"
+DROP INDEX "events_event_id_key";
+-- reverse: create "events" table
+DROP TABLE "events";
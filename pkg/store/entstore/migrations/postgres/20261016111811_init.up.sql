@@ -0,0 +1,16 @@
+-- create "events" table
+CREATE TABLE "events" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "event_id" character varying NOT NULL, "run_id" character varying NOT NULL, "seq" bigint NOT NULL, "type" character varying NOT NULL, "payload" jsonb NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "events_event_id_key" to table: "events"
+CREATE UNIQUE INDEX "events_event_id_key" ON "events" ("event_id");
+-- create index "event_run_id_seq" to table: "events"
+CREATE UNIQUE INDEX "event_run_id_seq" ON "events" ("run_id", "seq");
+-- create index "event_run_id" to table: "events"
+CREATE INDEX "event_run_id" ON "events" ("run_id");
+-- create "snapshots" table
+CREATE TABLE "snapshots" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "snapshot_id" character varying NOT NULL, "run_id" character varying NOT NULL, "upto_seq" bigint NOT NULL, "state" jsonb NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "snapshots_snapshot_id_key" to table: "snapshots"
+CREATE UNIQUE INDEX "snapshots_snapshot_id_key" ON "snapshots" ("snapshot_id");
+-- create index "snapshot_run_id" to table: "snapshots"
+CREATE INDEX "snapshot_run_id" ON "snapshots" ("run_id");
+-- create index "snapshot_run_id_upto_seq" to table: "snapshots"
+CREATE UNIQUE INDEX "snapshot_run_id_upto_seq" ON "snapshots" ("run_id", "upto_seq");
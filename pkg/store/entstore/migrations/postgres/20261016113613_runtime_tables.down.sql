@@ -0,0 +1,60 @@
+-- reverse: create index "timer_run_id" to table: "timers"
+DROP INDEX "timer_run_id";
+-- reverse: create index "timer_queue_due_at" to table: "timers"
+DROP INDEX "timer_queue_due_at";
+-- reverse: create index "timers_timer_id_key" to table: "timers"
+DROP INDEX "timers_timer_id_key";
+-- reverse: create "timers" table
+DROP TABLE "timers";
+-- reverse: create index "snapshot_version" to table: "snapshots"
+DROP INDEX "snapshot_version";
+-- reverse: create index "shred_keys_run_id_key" to table: "shred_keys"
+DROP INDEX "shred_keys_run_id_key";
+-- reverse: create "shred_keys" table
+DROP TABLE "shred_keys";
+-- reverse: create index "run_parent_run_id" to table: "runs"
+DROP INDEX "run_parent_run_id";
+-- reverse: create index "run_agent_type" to table: "runs"
+DROP INDEX "run_agent_type";
+-- reverse: create index "run_status" to table: "runs"
+DROP INDEX "run_status";
+-- reverse: create index "runs_run_id_key" to table: "runs"
+DROP INDEX "runs_run_id_key";
+-- reverse: create "runs" table
+DROP TABLE "runs";
+-- reverse: create index "outboxitem_run_id" to table: "outbox_items"
+DROP INDEX "outboxitem_run_id";
+-- reverse: create index "outboxitem_queue_available_at" to table: "outbox_items"
+DROP INDEX "outboxitem_queue_available_at";
+-- reverse: create index "outbox_items_item_id_key" to table: "outbox_items"
+DROP INDEX "outbox_items_item_id_key";
+-- reverse: create "outbox_items" table
+DROP TABLE "outbox_items";
+-- reverse: create index "deadletter_run_id" to table: "dead_letters"
+DROP INDEX "deadletter_run_id";
+-- reverse: create index "dead_letters_dead_letter_id_key" to table: "dead_letters"
+DROP INDEX "dead_letters_dead_letter_id_key";
+-- reverse: create "dead_letters" table
+DROP TABLE "dead_letters";
+-- reverse: create index "checkpoints_name_key" to table: "checkpoints"
+DROP INDEX "checkpoints_name_key";
+-- reverse: create "checkpoints" table
+DROP TABLE "checkpoints";
+-- reverse: create index "auditentry_run_id" to table: "audit_entries"
+DROP INDEX "auditentry_run_id";
+-- reverse: create index "audit_entries_audit_id_key" to table: "audit_entries"
+DROP INDEX "audit_entries_audit_id_key";
+-- reverse: create "audit_entries" table
+DROP TABLE "audit_entries";
+-- reverse: create index "archivedevent_run_id_seq" to table: "archived_events"
+DROP INDEX "archivedevent_run_id_seq";
+-- reverse: create index "archived_events_event_id_key" to table: "archived_events"
+DROP INDEX "archived_events_event_id_key";
+-- reverse: create index "archived_events_position_key" to table: "archived_events"
+DROP INDEX "archived_events_position_key";
+-- reverse: create "archived_events" table
+DROP TABLE "archived_events";
+-- reverse: modify "snapshots" table
+ALTER TABLE "snapshots" DROP COLUMN "hash", DROP COLUMN "version", DROP COLUMN "key_id", ALTER COLUMN "state" TYPE jsonb USING "state"::jsonb;
+-- reverse: modify "events" table
+ALTER TABLE "events" DROP COLUMN "key_id", DROP COLUMN "version", ALTER COLUMN "payload" TYPE jsonb USING "payload"::jsonb;
@@ -0,0 +1,60 @@
+-- modify "events" table
+ALTER TABLE "events" ALTER COLUMN "payload" TYPE json USING "payload"::json, ADD COLUMN "version" bigint NOT NULL DEFAULT 1, ADD COLUMN "key_id" character varying NOT NULL DEFAULT '';
+-- modify "snapshots" table
+ALTER TABLE "snapshots" ALTER COLUMN "state" TYPE json USING "state"::json, ADD COLUMN "key_id" character varying NOT NULL DEFAULT '', ADD COLUMN "version" character varying NOT NULL DEFAULT '', ADD COLUMN "hash" character varying NOT NULL DEFAULT '';
+-- create index "snapshot_version" to table: "snapshots"
+CREATE INDEX "snapshot_version" ON "snapshots" ("version");
+-- create "archived_events" table
+CREATE TABLE "archived_events" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "position" bigint NOT NULL, "event_id" character varying NOT NULL, "run_id" character varying NOT NULL, "seq" bigint NOT NULL, "type" character varying NOT NULL, "version" bigint NOT NULL DEFAULT 1, "payload" json NULL, "key_id" character varying NOT NULL DEFAULT '', "created_at" timestamptz NOT NULL, "archived_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "archived_events_position_key" to table: "archived_events"
+CREATE UNIQUE INDEX "archived_events_position_key" ON "archived_events" ("position");
+-- create index "archived_events_event_id_key" to table: "archived_events"
+CREATE UNIQUE INDEX "archived_events_event_id_key" ON "archived_events" ("event_id");
+-- create index "archivedevent_run_id_seq" to table: "archived_events"
+CREATE UNIQUE INDEX "archivedevent_run_id_seq" ON "archived_events" ("run_id", "seq");
+-- create "audit_entries" table
+CREATE TABLE "audit_entries" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "audit_id" character varying NOT NULL, "action" character varying NOT NULL, "run_id" character varying NULL, "actor" character varying NULL, "details" jsonb NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "audit_entries_audit_id_key" to table: "audit_entries"
+CREATE UNIQUE INDEX "audit_entries_audit_id_key" ON "audit_entries" ("audit_id");
+-- create index "auditentry_run_id" to table: "audit_entries"
+CREATE INDEX "auditentry_run_id" ON "audit_entries" ("run_id");
+-- create "checkpoints" table
+CREATE TABLE "checkpoints" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "name" character varying NOT NULL, "position" bigint NOT NULL DEFAULT 0, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "checkpoints_name_key" to table: "checkpoints"
+CREATE UNIQUE INDEX "checkpoints_name_key" ON "checkpoints" ("name");
+-- create "dead_letters" table
+CREATE TABLE "dead_letters" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "dead_letter_id" character varying NOT NULL, "run_id" character varying NOT NULL, "intent_name" character varying NOT NULL, "idempotency_key" character varying NULL, "args" jsonb NULL, "attempts" bigint NOT NULL, "error" jsonb NOT NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "dead_letters_dead_letter_id_key" to table: "dead_letters"
+CREATE UNIQUE INDEX "dead_letters_dead_letter_id_key" ON "dead_letters" ("dead_letter_id");
+-- create index "deadletter_run_id" to table: "dead_letters"
+CREATE INDEX "deadletter_run_id" ON "dead_letters" ("run_id");
+-- create "outbox_items" table
+CREATE TABLE "outbox_items" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "item_id" character varying NOT NULL, "queue" character varying NOT NULL, "run_id" character varying NOT NULL, "intent_name" character varying NOT NULL, "idempotency_key" character varying NULL, "args" jsonb NULL, "depth" bigint NOT NULL DEFAULT 0, "claims" bigint NOT NULL DEFAULT 0, "lease_owner" character varying NULL, "lease_expires_at" bigint NOT NULL DEFAULT 0, "available_at" bigint NOT NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "outbox_items_item_id_key" to table: "outbox_items"
+CREATE UNIQUE INDEX "outbox_items_item_id_key" ON "outbox_items" ("item_id");
+-- create index "outboxitem_queue_available_at" to table: "outbox_items"
+CREATE INDEX "outboxitem_queue_available_at" ON "outbox_items" ("queue", "available_at");
+-- create index "outboxitem_run_id" to table: "outbox_items"
+CREATE INDEX "outboxitem_run_id" ON "outbox_items" ("run_id");
+-- create "runs" table
+CREATE TABLE "runs" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "run_id" character varying NOT NULL, "status" character varying NOT NULL DEFAULT 'active', "agent_type" character varying NULL, "labels" jsonb NULL, "parent_run_id" character varying NULL, "last_seq" bigint NOT NULL DEFAULT 0, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "runs_run_id_key" to table: "runs"
+CREATE UNIQUE INDEX "runs_run_id_key" ON "runs" ("run_id");
+-- create index "run_status" to table: "runs"
+CREATE INDEX "run_status" ON "runs" ("status");
+-- create index "run_agent_type" to table: "runs"
+CREATE INDEX "run_agent_type" ON "runs" ("agent_type");
+-- create index "run_parent_run_id" to table: "runs"
+CREATE INDEX "run_parent_run_id" ON "runs" ("parent_run_id");
+-- create "shred_keys" table
+CREATE TABLE "shred_keys" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "run_id" character varying NOT NULL, "key" bytea NULL, "created_at" timestamptz NOT NULL, "shredded_at" timestamptz NULL, PRIMARY KEY ("id"));
+-- create index "shred_keys_run_id_key" to table: "shred_keys"
+CREATE UNIQUE INDEX "shred_keys_run_id_key" ON "shred_keys" ("run_id");
+-- create "timers" table
+CREATE TABLE "timers" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "timer_id" character varying NOT NULL, "queue" character varying NOT NULL, "run_id" character varying NOT NULL, "name" character varying NULL, "payload" jsonb NULL, "due_at" bigint NOT NULL, "attempts" bigint NOT NULL DEFAULT 0, "available_at" bigint NOT NULL DEFAULT 0, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "timers_timer_id_key" to table: "timers"
+CREATE UNIQUE INDEX "timers_timer_id_key" ON "timers" ("timer_id");
+-- create index "timer_queue_due_at" to table: "timers"
+CREATE INDEX "timer_queue_due_at" ON "timers" ("queue", "due_at");
+-- create index "timer_run_id" to table: "timers"
+CREATE INDEX "timer_run_id" ON "timers" ("run_id");
@@ -0,0 +1,16 @@
+-- create "events" table
+CREATE TABLE `events` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `event_id` text NOT NULL, `run_id` text NOT NULL, `seq` integer NOT NULL, `type` text NOT NULL, `payload` json NULL, `created_at` datetime NOT NULL);
+-- create index "events_event_id_key" to table: "events"
+CREATE UNIQUE INDEX `events_event_id_key` ON `events` (`event_id`);
+-- create index "event_run_id_seq" to table: "events"
+CREATE UNIQUE INDEX `event_run_id_seq` ON `events` (`run_id`, `seq`);
+-- create index "event_run_id" to table: "events"
+CREATE INDEX `event_run_id` ON `events` (`run_id`);
+-- create "snapshots" table
+CREATE TABLE `snapshots` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `snapshot_id` text NOT NULL, `run_id` text NOT NULL, `upto_seq` integer NOT NULL, `state` json NULL, `created_at` datetime NOT NULL);
+-- create index "snapshots_snapshot_id_key" to table: "snapshots"
+CREATE UNIQUE INDEX `snapshots_snapshot_id_key` ON `snapshots` (`snapshot_id`);
+-- create index "snapshot_run_id" to table: "snapshots"
+CREATE INDEX `snapshot_run_id` ON `snapshots` (`run_id`);
+-- create index "snapshot_run_id_upto_seq" to table: "snapshots"
+CREATE UNIQUE INDEX `snapshot_run_id_upto_seq` ON `snapshots` (`run_id`, `upto_seq`);
@@ -0,0 +1,88 @@
+-- disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- create "new_events" table
+CREATE TABLE `new_events` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `event_id` text NOT NULL, `run_id` text NOT NULL, `seq` integer NOT NULL, `type` text NOT NULL, `version` integer NOT NULL DEFAULT (1), `payload` text NULL, `key_id` text NOT NULL DEFAULT (''), `created_at` datetime NOT NULL);
+-- copy rows from old table "events" to new temporary table "new_events"
+INSERT INTO `new_events` (`id`, `event_id`, `run_id`, `seq`, `type`, `payload`, `created_at`) SELECT `id`, `event_id`, `run_id`, `seq`, `type`, `payload`, `created_at` FROM `events`;
+-- drop "events" table after copying rows
+DROP TABLE `events`;
+-- rename temporary table "new_events" to "events"
+ALTER TABLE `new_events` RENAME TO `events`;
+-- create index "events_event_id_key" to table: "events"
+CREATE UNIQUE INDEX `events_event_id_key` ON `events` (`event_id`);
+-- create index "event_run_id_seq" to table: "events"
+CREATE UNIQUE INDEX `event_run_id_seq` ON `events` (`run_id`, `seq`);
+-- create index "event_run_id" to table: "events"
+CREATE INDEX `event_run_id` ON `events` (`run_id`);
+-- create "new_snapshots" table
+CREATE TABLE `new_snapshots` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `snapshot_id` text NOT NULL, `run_id` text NOT NULL, `upto_seq` integer NOT NULL, `state` text NULL, `key_id` text NOT NULL DEFAULT (''), `version` text NOT NULL DEFAULT (''), `hash` text NOT NULL DEFAULT (''), `created_at` datetime NOT NULL);
+-- copy rows from old table "snapshots" to new temporary table "new_snapshots"
+INSERT INTO `new_snapshots` (`id`, `snapshot_id`, `run_id`, `upto_seq`, `state`, `created_at`) SELECT `id`, `snapshot_id`, `run_id`, `upto_seq`, `state`, `created_at` FROM `snapshots`;
+-- drop "snapshots" table after copying rows
+DROP TABLE `snapshots`;
+-- rename temporary table "new_snapshots" to "snapshots"
+ALTER TABLE `new_snapshots` RENAME TO `snapshots`;
+-- create index "snapshots_snapshot_id_key" to table: "snapshots"
+CREATE UNIQUE INDEX `snapshots_snapshot_id_key` ON `snapshots` (`snapshot_id`);
+-- create index "snapshot_run_id" to table: "snapshots"
+CREATE INDEX `snapshot_run_id` ON `snapshots` (`run_id`);
+-- create index "snapshot_run_id_upto_seq" to table: "snapshots"
+CREATE UNIQUE INDEX `snapshot_run_id_upto_seq` ON `snapshots` (`run_id`, `upto_seq`);
+-- create index "snapshot_version" to table: "snapshots"
+CREATE INDEX `snapshot_version` ON `snapshots` (`version`);
+-- create "archived_events" table
+CREATE TABLE `archived_events` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `position` integer NOT NULL, `event_id` text NOT NULL, `run_id` text NOT NULL, `seq` integer NOT NULL, `type` text NOT NULL, `version` integer NOT NULL DEFAULT (1), `payload` text NULL, `key_id` text NOT NULL DEFAULT (''), `created_at` datetime NOT NULL, `archived_at` datetime NOT NULL);
+-- create index "archived_events_position_key" to table: "archived_events"
+CREATE UNIQUE INDEX `archived_events_position_key` ON `archived_events` (`position`);
+-- create index "archived_events_event_id_key" to table: "archived_events"
+CREATE UNIQUE INDEX `archived_events_event_id_key` ON `archived_events` (`event_id`);
+-- create index "archivedevent_run_id_seq" to table: "archived_events"
+CREATE UNIQUE INDEX `archivedevent_run_id_seq` ON `archived_events` (`run_id`, `seq`);
+-- create "audit_entries" table
+CREATE TABLE `audit_entries` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `audit_id` text NOT NULL, `action` text NOT NULL, `run_id` text NULL, `actor` text NULL, `details` json NULL, `created_at` datetime NOT NULL);
+-- create index "audit_entries_audit_id_key" to table: "audit_entries"
+CREATE UNIQUE INDEX `audit_entries_audit_id_key` ON `audit_entries` (`audit_id`);
+-- create index "auditentry_run_id" to table: "audit_entries"
+CREATE INDEX `auditentry_run_id` ON `audit_entries` (`run_id`);
+-- create "checkpoints" table
+CREATE TABLE `checkpoints` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` text NOT NULL, `position` integer NOT NULL DEFAULT (0), `updated_at` datetime NOT NULL);
+-- create index "checkpoints_name_key" to table: "checkpoints"
+CREATE UNIQUE INDEX `checkpoints_name_key` ON `checkpoints` (`name`);
+-- create "dead_letters" table
+CREATE TABLE `dead_letters` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `dead_letter_id` text NOT NULL, `run_id` text NOT NULL, `intent_name` text NOT NULL, `idempotency_key` text NULL, `args` json NULL, `attempts` integer NOT NULL, `error` json NOT NULL, `created_at` datetime NOT NULL);
+-- create index "dead_letters_dead_letter_id_key" to table: "dead_letters"
+CREATE UNIQUE INDEX `dead_letters_dead_letter_id_key` ON `dead_letters` (`dead_letter_id`);
+-- create index "deadletter_run_id" to table: "dead_letters"
+CREATE INDEX `deadletter_run_id` ON `dead_letters` (`run_id`);
+-- create "outbox_items" table
+CREATE TABLE `outbox_items` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `item_id` text NOT NULL, `queue` text NOT NULL, `run_id` text NOT NULL, `intent_name` text NOT NULL, `idempotency_key` text NULL, `args` json NULL, `depth` integer NOT NULL DEFAULT (0), `claims` integer NOT NULL DEFAULT (0), `lease_owner` text NULL, `lease_expires_at` integer NOT NULL DEFAULT (0), `available_at` integer NOT NULL, `created_at` datetime NOT NULL);
+-- create index "outbox_items_item_id_key" to table: "outbox_items"
+CREATE UNIQUE INDEX `outbox_items_item_id_key` ON `outbox_items` (`item_id`);
+-- create index "outboxitem_queue_available_at" to table: "outbox_items"
+CREATE INDEX `outboxitem_queue_available_at` ON `outbox_items` (`queue`, `available_at`);
+-- create index "outboxitem_run_id" to table: "outbox_items"
+CREATE INDEX `outboxitem_run_id` ON `outbox_items` (`run_id`);
+-- create "runs" table
+CREATE TABLE `runs` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `run_id` text NOT NULL, `status` text NOT NULL DEFAULT ('active'), `agent_type` text NULL, `labels` json NULL, `parent_run_id` text NULL, `last_seq` integer NOT NULL DEFAULT (0), `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL);
+-- create index "runs_run_id_key" to table: "runs"
+CREATE UNIQUE INDEX `runs_run_id_key` ON `runs` (`run_id`);
+-- create index "run_status" to table: "runs"
+CREATE INDEX `run_status` ON `runs` (`status`);
+-- create index "run_agent_type" to table: "runs"
+CREATE INDEX `run_agent_type` ON `runs` (`agent_type`);
+-- create index "run_parent_run_id" to table: "runs"
+CREATE INDEX `run_parent_run_id` ON `runs` (`parent_run_id`);
+-- create "shred_keys" table
+CREATE TABLE `shred_keys` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `run_id` text NOT NULL, `key` blob NULL, `created_at` datetime NOT NULL, `shredded_at` datetime NULL);
+-- create index "shred_keys_run_id_key" to table: "shred_keys"
+CREATE UNIQUE INDEX `shred_keys_run_id_key` ON `shred_keys` (`run_id`);
+-- create "timers" table
+CREATE TABLE `timers` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `timer_id` text NOT NULL, `queue` text NOT NULL, `run_id` text NOT NULL, `name` text NULL, `payload` json NULL, `due_at` integer NOT NULL, `attempts` integer NOT NULL DEFAULT (0), `available_at` integer NOT NULL DEFAULT (0), `created_at` datetime NOT NULL);
+-- create index "timers_timer_id_key" to table: "timers"
+CREATE UNIQUE INDEX `timers_timer_id_key` ON `timers` (`timer_id`);
+-- create index "timer_queue_due_at" to table: "timers"
+CREATE INDEX `timer_queue_due_at` ON `timers` (`queue`, `due_at`);
+-- create index "timer_run_id" to table: "timers"
+CREATE INDEX `timer_run_id` ON `timers` (`run_id`);
+-- enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
@@ -0,0 +1,159 @@
+package entstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/outboxitem"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// commitOutbox removes the completed item and enqueues the new items of a cycle.
+func commitOutbox(ctx context.Context, tx *ent.Tx, c store.CycleCommit) error {
+	if c.Complete != "" {
+		n, err := tx.OutboxItem.Delete().
+			Where(outboxitem.ItemID(c.Complete), outboxitem.LeaseOwner(c.Owner)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errmodel.System("lease_lost", "outbox lease is no longer held", map[string]any{"item_id": c.Complete, "owner": c.Owner}, nil)
+		}
+	}
+	if len(c.Enqueue) == 0 {
+		return nil
+	}
+	builders := make([]*ent.OutboxItemCreate, 0, len(c.Enqueue))
+	for _, it := range c.Enqueue {
+		b := tx.OutboxItem.Create().
+			SetItemID(it.ItemID).
+			SetQueue(it.Queue).
+			SetRunID(c.RunID).
+			SetIntentName(it.IntentName).
+			SetDepth(it.Depth).
+			SetAvailableAt(toMillis(it.AvailableAt)).
+			SetCreatedAt(time.Now())
+		if it.IdempotencyKey != "" {
+			b = b.SetIdempotencyKey(it.IdempotencyKey)
+		}
+		if it.Args != nil {
+			b = b.SetArgs(it.Args)
+		}
+		builders = append(builders, b)
+	}
+	return tx.OutboxItem.CreateBulk(builders...).Exec(ctx)
+}
+
+// ClaimOutbox leases available items whose lease is unset or expired. Each item is claimed with
+// a conditional update, so concurrent claimants never lease the same item twice.
+func (s *Store) ClaimOutbox(ctx context.Context, queue, owner string, lease time.Duration, limit int) ([]store.OutboxRecord, error) {
+	now := toMillis(time.Now())
+	claimable := outboxitem.And(
+		outboxitem.Queue(queue),
+		outboxitem.AvailableAtLTE(now),
+		outboxitem.LeaseExpiresAtLT(now),
+	)
+	q := s.client.OutboxItem.Query().Where(claimable)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.Order(ent.Asc(outboxitem.FieldID)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]store.OutboxRecord, 0, len(rows))
+	for _, r := range rows {
+		n, err := s.client.OutboxItem.Update().
+			Where(outboxitem.ID(r.ID), claimable).
+			SetLeaseOwner(owner).
+			SetLeaseExpiresAt(now + lease.Milliseconds()).
+			AddClaims(1).
+			Save(ctx)
+		if err != nil {
+			return out, err
+		}
+		if n == 0 {
+			// Claimed by someone else in the meantime.
+			continue
+		}
+		claimed, err := s.client.OutboxItem.Get(ctx, r.ID)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, toOutboxRecord(claimed))
+	}
+	return out, nil
+}
+
+// ExtendOutboxLease renews a lease held by owner.
+func (s *Store) ExtendOutboxLease(ctx context.Context, itemID, owner string, lease time.Duration) error {
+	n, err := s.client.OutboxItem.Update().
+		Where(outboxitem.ItemID(itemID), outboxitem.LeaseOwner(owner)).
+		SetLeaseExpiresAt(toMillis(time.Now()) + lease.Milliseconds()).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReleaseOutbox clears a lease held by owner and reschedules the item.
+func (s *Store) ReleaseOutbox(ctx context.Context, itemID, owner string, availableAt time.Time) error {
+	n, err := s.client.OutboxItem.Update().
+		Where(outboxitem.ItemID(itemID), outboxitem.LeaseOwner(owner)).
+		ClearLeaseOwner().
+		SetLeaseExpiresAt(0).
+		SetAvailableAt(toMillis(availableAt)).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// WakeOutbox moves the unleased items of a run due after availableAt forward to it.
+func (s *Store) WakeOutbox(ctx context.Context, runID string, availableAt time.Time) (int, error) {
+	at := toMillis(availableAt)
+	return s.client.OutboxItem.Update().
+		Where(outboxitem.RunID(runID), outboxitem.AvailableAtGT(at), outboxitem.LeaseOwnerIsNil()).
+		SetAvailableAt(at).
+		Save(ctx)
+}
+
+func toOutboxRecord(r *ent.OutboxItem) store.OutboxRecord {
+	rec := store.OutboxRecord{
+		ItemID:         r.ItemID,
+		Queue:          r.Queue,
+		RunID:          r.RunID,
+		IntentName:     r.IntentName,
+		IdempotencyKey: r.IdempotencyKey,
+		Args:           r.Args,
+		Depth:          r.Depth,
+		Claims:         r.Claims,
+		LeaseOwner:     r.LeaseOwner,
+		AvailableAt:    time.UnixMilli(r.AvailableAt).UTC(),
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.LeaseExpiresAt > 0 {
+		rec.LeaseExpiresAt = time.UnixMilli(r.LeaseExpiresAt).UTC()
+	}
+	return rec
+}
+
+// toMillis converts t to Unix milliseconds; the zero time maps to 0.
+func toMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
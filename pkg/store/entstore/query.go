@@ -0,0 +1,297 @@
+package entstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/archivedevent"
+	"github.com/wilhg/orch/internal/ent/event"
+	"github.com/wilhg/orch/internal/ent/predicate"
+	"github.com/wilhg/orch/internal/ent/run"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+var _ store.EventQuerier = (*Store)(nil)
+
+// QueryEvents searches live and archived events in the order of the global feed. Payload
+// predicates use json_extract on SQLite and JSONB operators on Postgres; encrypted payloads are
+// matched once decrypted, which reads every encrypted event that passes the other filters.
+// No index serves payload predicates: on Postgres the json column is cast to jsonb row by row,
+// so a search reads every event that passes the run, type and time filters.
+func (s *Store) QueryEvents(ctx context.Context, q store.EventQuery) (store.EventPage, error) {
+	if err := q.Validate(); err != nil {
+		return store.EventPage{}, err
+	}
+	after, err := store.ParseEventCursor(q.Cursor)
+	if err != nil {
+		return store.EventPage{}, err
+	}
+	for k := range q.Labels {
+		if !labelKey.MatchString(k) {
+			return store.EventPage{}, errmodel.Validation("bad_label", "label keys may only contain letters, digits, '_' and '-'", map[string]any{"label": k})
+		}
+	}
+	where := eventFilter(q)
+	// Fetch one more event to tell whether there is a next page.
+	batch := 0
+	if q.Limit > 0 {
+		batch = q.Limit + 1
+	}
+	var page store.EventPage
+	for {
+		events, sealed, err := s.queryBatch(ctx, where, after, batch)
+		if err != nil {
+			return store.EventPage{}, err
+		}
+		for _, e := range events {
+			after = e.Position
+			if sealed[e.EventID] && !q.MatchPayload(e.Payload) {
+				continue
+			}
+			page.Events = append(page.Events, e)
+		}
+		if batch == 0 || len(events) < batch || len(page.Events) > q.Limit {
+			break
+		}
+	}
+	if q.Limit > 0 && len(page.Events) > q.Limit {
+		page.Events = page.Events[:q.Limit]
+		page.NextCursor = strconv.FormatInt(page.Events[q.Limit-1].Position, 10)
+	}
+	return page, nil
+}
+
+// queryBatch reads up to limit events after a global position that match where, and reports
+// which of them were encrypted and still need their payload matched.
+func (s *Store) queryBatch(ctx context.Context, where func(*entsql.Selector), after int64, limit int) ([]store.EventRecord, map[string]bool, error) {
+	sealed := map[string]bool{}
+	lq := s.client.Event.Query().Where(predicate.Event(where), event.IDGT(int(after)))
+	aq := s.client.ArchivedEvent.Query().Where(predicate.ArchivedEvent(where), archivedevent.PositionGT(after))
+	if limit > 0 {
+		lq = lq.Limit(limit)
+		aq = aq.Limit(limit)
+	}
+	rows, err := lq.Order(ent.Asc(event.FieldID)).All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range rows {
+		sealed[r.EventID] = r.KeyID != ""
+	}
+	live, err := s.eventRecords(ctx, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	arows, err := aq.Order(ent.Asc(archivedevent.FieldPosition)).All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range arows {
+		sealed[r.EventID] = r.KeyID != ""
+	}
+	archived, err := s.archivedRecords(ctx, arows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mergeEvents(live, archived, func(e store.EventRecord) int64 { return e.Position }, limit), sealed, nil
+}
+
+// eventFilter applies q to the events or archived_events table, which share their columns.
+func eventFilter(q store.EventQuery) func(*entsql.Selector) {
+	return func(sel *entsql.Selector) {
+		var ps []*entsql.Predicate
+		if q.RunID != "" {
+			ps = append(ps, entsql.EQ(sel.C(event.FieldRunID), q.RunID))
+		}
+		if len(q.Types) > 0 {
+			types := make([]any, len(q.Types))
+			for i, t := range q.Types {
+				types[i] = t
+			}
+			ps = append(ps, entsql.In(sel.C(event.FieldType), types...))
+		}
+		if !q.Since.IsZero() {
+			ps = append(ps, entsql.GTE(sel.C(event.FieldCreatedAt), q.Since))
+		}
+		if !q.Until.IsZero() {
+			ps = append(ps, entsql.LT(sel.C(event.FieldCreatedAt), q.Until))
+		}
+		if len(q.Labels) > 0 {
+			runs := entsql.Select(run.FieldRunID).From(entsql.Table(run.Table))
+			for k, v := range q.Labels {
+				runs.Where(sqljson.ValueEQ(run.FieldLabels, v, sqljson.Path(k)))
+			}
+			ps = append(ps, entsql.In(sel.C(event.FieldRunID), runs))
+		}
+		if q.MatchesPayload() {
+			col := sel.C(event.FieldPayload)
+			var match []*entsql.Predicate
+			for _, p := range q.Where {
+				match = append(match, payloadPredicate(col, p))
+			}
+			if q.Text != "" {
+				match = append(match, textPredicate(col, q.Text))
+			}
+			// Encrypted payloads are matched once decrypted.
+			ps = append(ps, entsql.Or(entsql.NEQ(sel.C(event.FieldKeyID), ""), entsql.And(match...)))
+		}
+		if len(ps) > 0 {
+			sel.Where(entsql.And(ps...))
+		}
+	}
+}
+
+// payloadPredicate matches the JSON in col against a valid predicate.
+func payloadPredicate(col string, p store.PayloadPredicate) *entsql.Predicate {
+	path, _ := store.ParsePayloadPath(p.Path)
+	v, _ := p.DecodeValue()
+	return entsql.P(func(b *entsql.Builder) {
+		b.Wrap(func(b *entsql.Builder) {
+			if b.Dialect() == dialect.Postgres {
+				pgPredicate(b, col, path, p, v)
+				return
+			}
+			sqlitePredicate(b, col, path, p, v)
+		})
+	})
+}
+
+// pgPredicate writes p with JSONB operators. Values of different JSON types never compare equal,
+// and ordering operators are restricted to values of the predicate's type. The path is walked one
+// element at a time so a numeric key such as "0" never selects an array element, or the reverse.
+func pgPredicate(b *entsql.Builder, col string, path []store.PathElem, p store.PayloadPredicate, v any) {
+	value := func() {
+		b.WriteString("(CAST(").Ident(col).WriteString(" AS jsonb)")
+		for _, e := range path {
+			if e.Key == "" {
+				b.WriteString(" -> " + strconv.Itoa(e.Index))
+				continue
+			}
+			b.WriteString(" -> CAST(").Arg(e.Key).WriteString(" AS text)")
+		}
+		b.WriteString(")")
+	}
+	arg := func() { b.WriteString("CAST(").Arg(string(p.Value)).WriteString(" AS jsonb)") }
+	switch p.Op {
+	case store.PayloadExists:
+		value()
+		b.WriteString(" IS NOT NULL")
+	case store.PayloadEQ:
+		value()
+		b.WriteString(" = ")
+		arg()
+	case store.PayloadNE:
+		value()
+		b.WriteString(" <> ")
+		arg()
+	case store.PayloadContains:
+		value()
+		b.WriteString(" @> ")
+		arg()
+	default:
+		typ := "number"
+		if _, ok := v.(string); ok {
+			typ = "string"
+		}
+		b.WriteString("jsonb_typeof")
+		value()
+		b.WriteString(" = ").Arg(typ).WriteString(" AND ")
+		value()
+		b.WriteString(" " + orderingOp(p.Op) + " ")
+		arg()
+	}
+}
+
+// sqlitePredicate writes p with json_type and json_extract, comparing values of the same JSON
+// type only.
+func sqlitePredicate(b *entsql.Builder, col string, path []store.PathElem, p store.PayloadPredicate, v any) {
+	var jp strings.Builder
+	jp.WriteString("$")
+	for _, e := range path {
+		if e.Key != "" {
+			jp.WriteString(`."` + e.Key + `"`)
+		} else {
+			fmt.Fprintf(&jp, "[%d]", e.Index)
+		}
+	}
+	typ := func() { b.WriteString("json_type(").Ident(col).Comma().Arg(jp.String()).WriteString(")") }
+	value := func() { b.WriteString("json_extract(").Ident(col).Comma().Arg(jp.String()).WriteString(")") }
+	switch p.Op {
+	case store.PayloadExists:
+		typ()
+		b.WriteString(" IS NOT NULL")
+	case store.PayloadEQ:
+		sqliteCompare(b, typ, value, "=", v)
+	case store.PayloadNE:
+		typ()
+		b.WriteString(" IS NOT NULL AND NOT (")
+		sqliteCompare(b, typ, value, "=", v)
+		b.WriteString(")")
+	case store.PayloadContains:
+		// json_each lists the elements of an array, or a scalar itself.
+		typ()
+		b.WriteString(" <> 'object' AND EXISTS (SELECT 1 FROM json_each(").Ident(col).Comma().Arg(jp.String()).WriteString(") AS je WHERE ")
+		sqliteCompare(b, func() { b.WriteString("je.type") }, func() { b.WriteString("je.value") }, "=", v)
+		b.WriteString(")")
+	default:
+		sqliteCompare(b, typ, value, orderingOp(p.Op), v)
+	}
+}
+
+// sqliteCompare writes a comparison of a JSON value, given its json_type and SQL value, with v.
+func sqliteCompare(b *entsql.Builder, typ, value func(), op string, v any) {
+	switch v := v.(type) {
+	case bool:
+		typ()
+		b.WriteString(" = ").Arg(strconv.FormatBool(v))
+		return
+	case nil:
+		typ()
+		b.WriteString(" = 'null'")
+		return
+	case string:
+		typ()
+		b.WriteString(" = 'text'")
+	default:
+		typ()
+		b.WriteString(" IN ('integer', 'real')")
+	}
+	b.WriteString(" AND ")
+	value()
+	b.WriteString(" " + op + " ").Arg(v)
+}
+
+func orderingOp(op store.PayloadOp) string {
+	switch op {
+	case store.PayloadGT:
+		return ">"
+	case store.PayloadGTE:
+		return ">="
+	case store.PayloadLT:
+		return "<"
+	default:
+		return "<="
+	}
+}
+
+// textPredicate matches the JSON text in col that contains text, ignoring case.
+func textPredicate(col, text string) *entsql.Predicate {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(text)) + "%"
+	return entsql.P(func(b *entsql.Builder) {
+		if b.Dialect() == dialect.Postgres {
+			b.WriteString("CAST(").Ident(col).WriteString(" AS text) ILIKE ").Arg(pattern).WriteString(` ESCAPE '\'`)
+			return
+		}
+		b.WriteString("lower(").Ident(col).WriteString(") LIKE ").Arg(pattern).WriteString(` ESCAPE '\'`)
+	})
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,167 @@
+package entstore
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/predicate"
+	"github.com/wilhg/orch/internal/ent/run"
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// labelKey restricts label keys used in filters, since they become part of a JSON path.
+var labelKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// CreateRun registers a run in the catalog.
+func (s *Store) CreateRun(ctx context.Context, r store.RunRecord) (store.RunRecord, error) {
+	created, err := s.createRun(ctx, r)
+	if ent.IsConstraintError(err) {
+		return store.RunRecord{}, errmodel.Conflict("run already exists", map[string]any{"run_id": r.RunID})
+	}
+	if err != nil {
+		return store.RunRecord{}, err
+	}
+	return toRunRecord(created), nil
+}
+
+func (s *Store) createRun(ctx context.Context, r store.RunRecord) (*ent.Run, error) {
+	now := time.Now()
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now
+	}
+	if r.UpdatedAt.IsZero() {
+		r.UpdatedAt = r.CreatedAt
+	}
+	b := s.client.Run.Create().
+		SetRunID(r.RunID).
+		SetLastSeq(r.LastSeq).
+		SetCreatedAt(r.CreatedAt).
+		SetUpdatedAt(r.UpdatedAt)
+	if r.Status != "" {
+		b = b.SetStatus(r.Status)
+	}
+	if r.AgentType != "" {
+		b = b.SetAgentType(r.AgentType)
+	}
+	if r.Labels != nil {
+		b = b.SetLabels(r.Labels)
+	}
+	if r.ParentRunID != "" {
+		b = b.SetParentRunID(r.ParentRunID)
+	}
+	return b.Save(ctx)
+}
+
+// RecordRunProgress updates the status and last sequence of a run, registering it if needed.
+func (s *Store) RecordRunProgress(ctx context.Context, r store.RunRecord) error {
+	if r.UpdatedAt.IsZero() {
+		r.UpdatedAt = time.Now()
+	}
+	for attempt := 0; ; attempt++ {
+		u := s.client.Run.Update().
+			Where(run.RunID(r.RunID), run.LastSeqLTE(r.LastSeq)).
+			SetLastSeq(r.LastSeq).
+			SetUpdatedAt(r.UpdatedAt)
+		if r.Status != "" {
+			u = u.SetStatus(r.Status)
+		}
+		n, err := u.Save(ctx)
+		if err != nil || n > 0 {
+			return err
+		}
+		exists, err := s.client.Run.Query().Where(run.RunID(r.RunID)).Exist(ctx)
+		if err != nil || exists {
+			// A newer update was recorded already.
+			return err
+		}
+		_, err = s.createRun(ctx, r)
+		if ent.IsConstraintError(err) && attempt == 0 {
+			// Registered concurrently; update it instead.
+			continue
+		}
+		return err
+	}
+}
+
+// GetRun looks up a run by ID.
+func (s *Store) GetRun(ctx context.Context, runID string) (store.RunRecord, error) {
+	rec, err := s.client.Run.Query().
+		Where(run.RunID(runID)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return store.RunRecord{}, sql.ErrNoRows
+		}
+		return store.RunRecord{}, err
+	}
+	return toRunRecord(rec), nil
+}
+
+// ListRuns lists runs newest first. The cursor is the row ID of the last run of the previous page.
+func (s *Store) ListRuns(ctx context.Context, f store.RunFilter) (store.RunPage, error) {
+	var ps []predicate.Run
+	if f.Status != "" {
+		ps = append(ps, run.Status(f.Status))
+	}
+	if f.AgentType != "" {
+		ps = append(ps, run.AgentType(f.AgentType))
+	}
+	if f.ParentRunID != "" {
+		ps = append(ps, run.ParentRunID(f.ParentRunID))
+	}
+	for k, v := range f.Labels {
+		if !labelKey.MatchString(k) {
+			return store.RunPage{}, errmodel.Validation("bad_label", "label keys may only contain letters, digits, '_' and '-'", map[string]any{"label": k})
+		}
+		ps = append(ps, predicate.Run(func(s *entsql.Selector) {
+			s.Where(sqljson.ValueEQ(run.FieldLabels, v, sqljson.Path(k)))
+		}))
+	}
+	if f.Cursor != "" {
+		id, err := strconv.Atoi(f.Cursor)
+		if err != nil {
+			return store.RunPage{}, errmodel.Validation("bad_cursor", "invalid cursor", map[string]any{"cursor": f.Cursor})
+		}
+		ps = append(ps, run.IDLT(id))
+	}
+	q := s.client.Run.Query().Where(ps...).Order(ent.Desc(run.FieldID))
+	if f.Limit > 0 {
+		// Fetch one more row to tell whether there is a next page.
+		q = q.Limit(f.Limit + 1)
+	}
+	rows, err := q.All(ctx)
+	if err != nil {
+		return store.RunPage{}, err
+	}
+	var page store.RunPage
+	if f.Limit > 0 && len(rows) > f.Limit {
+		rows = rows[:f.Limit]
+		page.NextCursor = strconv.Itoa(rows[len(rows)-1].ID)
+	}
+	page.Runs = make([]store.RunRecord, 0, len(rows))
+	for _, r := range rows {
+		page.Runs = append(page.Runs, toRunRecord(r))
+	}
+	return page, nil
+}
+
+func toRunRecord(r *ent.Run) store.RunRecord {
+	return store.RunRecord{
+		RunID:       r.RunID,
+		Status:      r.Status,
+		AgentType:   r.AgentType,
+		Labels:      r.Labels,
+		ParentRunID: r.ParentRunID,
+		LastSeq:     r.LastSeq,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
@@ -5,11 +5,15 @@ package entstore
 import (
 	"context"
 	"encoding/json"
+	"io/fs"
+	"os"
 	"testing"
 	"time"
 
 	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/wilhg/orch/pkg/errmodel"
 	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/storetest"
 )
 
 func TestPostgresEventFlow(t *testing.T) {
@@ -80,4 +84,163 @@ func TestPostgresEventFlow(t *testing.T) {
 		t.Fatal(err)
 	}
 	_ = sn
+
+	// JSONB would reorder keys and normalize numbers; payloads must come back unchanged.
+	checkRawPayloads(t, st, "runpg-raw")
+
+	// The global feed lists every run in position order.
+	all, err := st.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Position <= all[i-1].Position {
+			t.Fatalf("positions out of order: %d after %d", all[i].Position, all[i-1].Position)
+		}
+	}
+
+	// Archiving keeps the feed unchanged.
+	if _, err := st.ArchiveEvents(ctx, "runpg-raw", 1<<62); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := st.ReadAll(ctx, 0, 0); err != nil || len(again) != len(all) {
+		t.Fatalf("feed after archive=%d err=%v want %d", len(again), err, len(all))
+	}
+
+	// Appends from another store instance reach subscribers through LISTEN/NOTIFY.
+	other, err := Open(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = other.Close() })
+	sctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	received := make(chan string, 1)
+	go func() {
+		for e, err := range st.Subscribe(sctx, "runpg-sub", 0) {
+			if err == nil {
+				received <- e.EventID
+			}
+			return
+		}
+	}()
+	if _, err := other.AppendEvent(ctx, structToEvent("sub1", "runpg-sub", "typ", nil)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case id := <-received:
+		if id != "sub1" {
+			t.Fatalf("event=%s want sub1", id)
+		}
+	case <-sctx.Done():
+		t.Fatal("no event received")
+	}
+}
+
+func TestPostgresConformance(t *testing.T) {
+	ctx := context.Background()
+	pg, err := tcpostgres.RunContainer(ctx,
+		tcpostgres.WithDatabase("orch"),
+		tcpostgres.WithUsername("orch"),
+		tcpostgres.WithPassword("orch"),
+		tcpostgres.WithSQLDriver("pgx"),
+	)
+	if err != nil {
+		t.Skipf("skip: cannot start postgres: %v", err)
+	}
+	t.Cleanup(func() { _ = pg.Terminate(ctx) })
+	dsn, err := pg.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storetest.Run(t, func(t *testing.T) store.Store {
+		var st *Store
+		deadline := time.Now().Add(45 * time.Second)
+		for {
+			st, err = Open(ctx, dsn)
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("connect to postgres after retries: %v", err)
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+		t.Cleanup(func() { _ = st.Close() })
+		if err := st.Migrate(ctx); err != nil {
+			t.Fatal(err)
+		}
+		// Subtests share the database; each starts from empty tables.
+		if _, err := st.db.ExecContext(ctx, `TRUNCATE events, archived_events, snapshots`); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	})
+}
+
+func TestPostgresMigrations(t *testing.T) {
+	ctx := context.Background()
+	pg, err := tcpostgres.RunContainer(ctx,
+		tcpostgres.WithDatabase("orch"),
+		tcpostgres.WithUsername("orch"),
+		tcpostgres.WithPassword("orch"),
+		tcpostgres.WithSQLDriver("pgx"),
+	)
+	if err != nil {
+		t.Skipf("skip: cannot start postgres: %v", err)
+	}
+	t.Cleanup(func() { _ = pg.Terminate(ctx) })
+	dsn, err := pg.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st *Store
+	deadline := time.Now().Add(45 * time.Second)
+	for {
+		st, err = Open(ctx, dsn)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("connect to postgres after retries: %v", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	// The Postgres migrations produce the ent schema, replayed on the empty database.
+	dir := t.TempDir()
+	src, err := fs.Sub(migrationFiles, "migrations/postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.CopyFS(dir, src); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := DiffMigrations(ctx, dsn, dir, "drift"); err != nil || changed {
+		t.Fatalf("changed=%v err=%v; run `orch migrate diff -dev <postgres url>` after changing the ent schema", changed, err)
+	}
+
+	if err := st.CheckMigrations(ctx); !errmodel.HasCode(err, "schema_behind") {
+		t.Fatalf("err=%v want schema_behind", err)
+	}
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.CheckMigrations(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.AppendEvent(ctx, structToEvent("pm1", "run-pm", "typ", json.RawMessage(`{"k":1}`))); err != nil {
+		t.Fatal(err)
+	}
+	if done, err := st.MigrateDown(ctx, 0); err != nil || len(done) == 0 {
+		t.Fatalf("reverted=%+v err=%v", done, err)
+	}
+	if exists, err := st.tableExists(ctx, "events"); err != nil || exists {
+		t.Fatalf("events table exists=%v err=%v", exists, err)
+	}
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
 }
@@ -2,8 +2,25 @@ package entstore
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/envelope"
+	"github.com/wilhg/orch/pkg/store/storetest"
 )
 
 func TestSQLiteEventAppendAndList(t *testing.T) {
@@ -43,3 +60,1010 @@ func TestSQLiteEventAppendAndList(t *testing.T) {
 		t.Fatalf("len=%d want 2", len(events))
 	}
 }
+
+func TestSQLiteAppendExpectedSeqConflict(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-conflict?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	e1 := structToEvent("c1", "run-c", "typ", nil)
+	e1.Seq = 1
+	if _, err := st.AppendEvent(ctx, e1); err != nil {
+		t.Fatal(err)
+	}
+	// A writer that also observed an empty run loses the check.
+	e2 := structToEvent("c2", "run-c", "typ", nil)
+	e2.Seq = 1
+	if _, err := st.AppendEvent(ctx, e2); !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("err=%v want conflict", err)
+	}
+	// Re-appending an existing event stays idempotent even with a stale expected seq.
+	got, err := st.AppendEvent(ctx, e1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.EventID != "c1" || got.Seq != 1 {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+	e2.Seq = 2
+	if got, err := st.AppendEvent(ctx, e2); err != nil || got.Seq != 2 {
+		t.Fatalf("append at expected seq: rec=%+v err=%v", got, err)
+	}
+}
+
+func TestSQLiteAppendEventsAtomicBatch(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-batch?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := []store.EventRecord{
+		structToEvent("b1", "run-b", "typ", nil),
+		structToEvent("b2", "run-b", "typ", nil),
+		structToEvent("b3", "run-b", "typ", nil),
+	}
+	out, err := st.AppendEvents(ctx, "run-b", 0, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range out {
+		if e.Seq != int64(i+1) {
+			t.Fatalf("seq=%d want %d", e.Seq, i+1)
+		}
+	}
+	// Replaying a committed batch returns the stored records.
+	again, err := st.AppendEvents(ctx, "run-b", 0, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 3 || again[2].Seq != 3 {
+		t.Fatalf("replayed batch: %+v", again)
+	}
+	// A stale batch with new events writes nothing.
+	stale := []store.EventRecord{structToEvent("b4", "run-b", "typ", nil), structToEvent("b5", "run-b", "typ", nil)}
+	if _, err := st.AppendEvents(ctx, "run-b", 1, stale); !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("err=%v want conflict", err)
+	}
+	if _, err := st.GetEventByID(ctx, "b4"); err != sql.ErrNoRows {
+		t.Fatalf("b4 should not exist, err=%v", err)
+	}
+	last, err := st.LastSeq(ctx, "run-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != 3 {
+		t.Fatalf("last seq=%d want 3", last)
+	}
+}
+
+func TestSQLiteOutboxLeases(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-outbox?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events and items are committed together.
+	_, err = st.CommitCycle(ctx, store.CycleCommit{
+		RunID:   "run-ob",
+		Events:  []store.EventRecord{structToEvent("ob-1", "run-ob", "start", nil)},
+		Enqueue: []store.OutboxRecord{{ItemID: "item-1", Queue: "q", IntentName: "tick", Depth: 1, AvailableAt: time.Now()}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := st.ClaimOutbox(ctx, "q", "w1", 50*time.Millisecond, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ItemID != "item-1" || items[0].RunID != "run-ob" || items[0].Claims != 1 {
+		t.Fatalf("claimed=%+v", items)
+	}
+	if again, _ := st.ClaimOutbox(ctx, "q", "w2", time.Minute, 10); len(again) != 0 {
+		t.Fatalf("leased item claimed twice: %+v", again)
+	}
+	if err := st.ExtendOutboxLease(ctx, "item-1", "w1", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the lease expires another worker takes the item over and the first one loses it.
+	time.Sleep(80 * time.Millisecond)
+	items, err = st.ClaimOutbox(ctx, "q", "w2", time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Claims != 2 {
+		t.Fatalf("expired lease not reclaimed: %+v", items)
+	}
+	if err := st.ExtendOutboxLease(ctx, "item-1", "w1", time.Minute); err != sql.ErrNoRows {
+		t.Fatalf("extend by previous owner err=%v want sql.ErrNoRows", err)
+	}
+	_, err = st.CommitCycle(ctx, store.CycleCommit{
+		RunID: "run-ob", ExpectedSeq: 1, Complete: "item-1", Owner: "w1",
+		Events: []store.EventRecord{structToEvent("ob-stale", "run-ob", "ticked", nil)},
+	})
+	if !errmodel.HasCode(err, "lease_lost") {
+		t.Fatalf("err=%v want lease_lost", err)
+	}
+	if _, err := st.GetEventByID(ctx, "ob-stale"); err != sql.ErrNoRows {
+		t.Fatalf("stale commit wrote events: %v", err)
+	}
+
+	// Released items become claimable again at the given time.
+	if err := st.ReleaseOutbox(ctx, "item-1", "w2", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := st.ClaimOutbox(ctx, "q", "w3", time.Minute, 10); len(again) != 0 {
+		t.Fatalf("item claimed before available: %+v", again)
+	}
+
+	// Waking the run brings its unleased items forward.
+	if n, err := st.WakeOutbox(ctx, "run-ob", time.Now()); err != nil || n != 1 {
+		t.Fatalf("woken=%d err=%v want 1", n, err)
+	}
+	if items, _ := st.ClaimOutbox(ctx, "q", "w3", time.Minute, 10); len(items) != 1 {
+		t.Fatalf("woken item not claimable: %+v", items)
+	}
+	if n, err := st.WakeOutbox(ctx, "run-ob", time.Now()); err != nil || n != 0 {
+		t.Fatalf("woken=%d err=%v want 0 for a leased item", n, err)
+	}
+}
+
+func TestSQLiteRunCatalog(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-runs?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.CreateRun(ctx, store.RunRecord{RunID: "r1", AgentType: "todo", Labels: map[string]string{"team": "a", "env": "prod"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.CreateRun(ctx, store.RunRecord{RunID: "r1"}); !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("err=%v want conflict", err)
+	}
+	if _, err := st.CreateRun(ctx, store.RunRecord{RunID: "r2", AgentType: "todo", ParentRunID: "r1", Labels: map[string]string{"team": "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Progress registers unknown runs and never goes back to an older sequence.
+	if err := st.RecordRunProgress(ctx, store.RunRecord{RunID: "r3", AgentType: "tool", Status: "active", LastSeq: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.RecordRunProgress(ctx, store.RunRecord{RunID: "r1", Status: "failed", LastSeq: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.RecordRunProgress(ctx, store.RunRecord{RunID: "r1", Status: "active", LastSeq: 4}); err != nil {
+		t.Fatal(err)
+	}
+	r1, err := st.GetRun(ctx, "r1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1.Status != "failed" || r1.LastSeq != 5 || r1.AgentType != "todo" || r1.Labels["env"] != "prod" {
+		t.Fatalf("r1=%+v", r1)
+	}
+	if _, err := st.GetRun(ctx, "missing"); err != sql.ErrNoRows {
+		t.Fatalf("err=%v want sql.ErrNoRows", err)
+	}
+
+	ids := func(p store.RunPage) []string {
+		var out []string
+		for _, r := range p.Runs {
+			out = append(out, r.RunID)
+		}
+		return out
+	}
+	cases := []struct {
+		f    store.RunFilter
+		want string
+	}{
+		{store.RunFilter{}, "[r3 r2 r1]"},
+		{store.RunFilter{Status: "failed"}, "[r1]"},
+		{store.RunFilter{AgentType: "todo"}, "[r2 r1]"},
+		{store.RunFilter{ParentRunID: "r1"}, "[r2]"},
+		{store.RunFilter{Labels: map[string]string{"team": "a", "env": "prod"}}, "[r1]"},
+		{store.RunFilter{Labels: map[string]string{"team": "c"}}, "[]"},
+	}
+	for _, c := range cases {
+		p, err := st.ListRuns(ctx, c.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(ids(p)); got != c.want {
+			t.Fatalf("filter %+v: runs=%s want %s", c.f, got, c.want)
+		}
+	}
+
+	// Pages follow the cursor until it is empty.
+	var pages []string
+	f := store.RunFilter{Limit: 2}
+	for {
+		p, err := st.ListRuns(ctx, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, fmt.Sprint(ids(p)))
+		if p.NextCursor == "" {
+			break
+		}
+		f.Cursor = p.NextCursor
+	}
+	if got := fmt.Sprint(pages); got != "[[r3 r2] [r1]]" {
+		t.Fatalf("pages=%s", got)
+	}
+	if _, err := st.ListRuns(ctx, store.RunFilter{Labels: map[string]string{"a')": "x"}}); !errmodel.HasCode(err, "bad_label") {
+		t.Fatalf("err=%v want bad_label", err)
+	}
+}
+
+// rawPayloads are JSON values that must survive a round trip byte-for-byte.
+var rawPayloads = []string{
+	`{"zeta": 1, "alpha": {"b": 2, "a": 1}}`,
+	`[3, 1, 2]`,
+	`"just a string"`,
+	`12345678901234567890.12345678901234567890`,
+	`1e400`,
+	`true`,
+	`null`,
+}
+
+// checkRawPayloads appends rawPayloads as events and snapshot states of runID and reads them back.
+func TestSQLiteQueryEventsByLabels(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-query?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for run, labels := range map[string]map[string]string{
+		"r1": {"team": "a", "env": "prod"},
+		"r2": {"team": "a", "env": "dev"},
+		"r3": {"team": "b", "env": "prod"},
+	} {
+		if _, err := st.CreateRun(ctx, store.RunRecord{RunID: run, Labels: labels}); err != nil {
+			t.Fatal(err)
+		}
+		for i := range 2 {
+			payload := json.RawMessage(fmt.Sprintf(`{"step":%d}`, i))
+			if _, err := st.AppendEvent(ctx, structToEvent(fmt.Sprintf("%s-%d", run, i), run, "step", payload)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Events of runs outside the catalog never match labels.
+	if _, err := st.AppendEvent(ctx, structToEvent("x-0", "x", "step", json.RawMessage(`{"step":1}`))); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := st.QueryEvents(ctx, store.EventQuery{
+		Labels: map[string]string{"env": "prod"},
+		Where:  []store.PayloadPredicate{{Path: "step", Op: store.PayloadEQ, Value: json.RawMessage(`1`)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range page.Events {
+		got = append(got, e.EventID)
+	}
+	slices.Sort(got)
+	if fmt.Sprint(got) != "[r1-1 r3-1]" {
+		t.Fatalf("events=%v", got)
+	}
+	page, err = st.QueryEvents(ctx, store.EventQuery{Labels: map[string]string{"team": "a", "env": "dev"}})
+	if err != nil || len(page.Events) != 2 || page.Events[0].RunID != "r2" {
+		t.Fatalf("page=%+v err=%v", page, err)
+	}
+	if _, err := st.QueryEvents(ctx, store.EventQuery{Labels: map[string]string{"team'": "a"}}); !errmodel.HasCode(err, "bad_label") {
+		t.Fatalf("err=%v want bad_label", err)
+	}
+}
+
+func checkRawPayloads(t *testing.T, st *Store, runID string) {
+	t.Helper()
+	ctx := context.Background()
+	var events []store.EventRecord
+	for i, p := range rawPayloads {
+		events = append(events, structToEvent(fmt.Sprintf("%s-raw-%d", runID, i), runID, "raw", json.RawMessage(p)))
+	}
+	if _, err := st.AppendEvents(ctx, runID, 0, events); err != nil {
+		t.Fatal(err)
+	}
+	got, err := st.ListEvents(ctx, runID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range rawPayloads {
+		if string(got[i].Payload) != p {
+			t.Fatalf("payload %d=%s want %s", i, got[i].Payload, p)
+		}
+	}
+	for i, p := range rawPayloads {
+		if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: fmt.Sprintf("%s-snap-%d", runID, i), RunID: runID, UptoSeq: int64(i + 1), State: json.RawMessage(p)}); err != nil {
+			t.Fatal(err)
+		}
+		sn, err := st.LoadLatestSnapshot(ctx, runID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(sn.State) != p {
+			t.Fatalf("state %d=%s want %s", i, sn.State, p)
+		}
+	}
+	if _, err := st.AppendEvent(ctx, structToEvent(runID+"-bad", runID, "raw", json.RawMessage(`{"a":`))); err == nil {
+		t.Fatal("invalid JSON payload was accepted")
+	}
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		ctx := context.Background()
+		// Concurrent appends open more connections, and one opened while the others are busy
+		// may not see the tables of a shared in-memory database; a file is shared by all.
+		st, err := Open(ctx, "sqlite:file:"+filepath.Join(t.TempDir(), "orch.sqlite")+"?_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = st.Close() })
+		if err := st.Migrate(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	})
+}
+
+func TestSQLiteConformanceEncrypted(t *testing.T) {
+	ring := testKeyring(t, testKeys(t, "k1"), "k1")
+	storetest.Run(t, func(t *testing.T) store.Store {
+		ctx := context.Background()
+		// Encryption slows appends down enough for concurrent ones to open more connections, which
+		// each get their own in-memory database. A file is shared by all of them, and immediate
+		// transactions keep concurrent writers from deadlocking on it.
+		st, err := Open(ctx, "sqlite:file:"+filepath.Join(t.TempDir(), "orch.sqlite")+"?_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1", WithEncryption(ring))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = st.Close() })
+		if err := st.Migrate(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	})
+}
+
+// testKeys returns random master keys by ID, and testKeyring a keyring of some of them whose
+// first ID is the active key.
+func testKeys(t *testing.T, ids ...string) map[string]string {
+	t.Helper()
+	keys := map[string]string{}
+	for _, id := range ids {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal(err)
+		}
+		keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	return keys
+}
+
+func testKeyring(t *testing.T, keys map[string]string, ids ...string) *envelope.Keyring {
+	t.Helper()
+	var entries []string
+	for _, id := range ids {
+		entries = append(entries, id+":"+keys[id])
+	}
+	ring, err := envelope.ParseKeyring(strings.Join(entries, ","))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func TestSQLiteEncryptionAtRest(t *testing.T) {
+	ctx := context.Background()
+	// Stores with different keys share one database, so it lives in a file.
+	dsn := "sqlite:file:" + filepath.Join(t.TempDir(), "orch.sqlite") + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1"
+	plain, err := Open(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = plain.Close() })
+	if err := plain.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Written before encryption was turned on.
+	if _, err := plain.AppendEvent(ctx, structToEvent("enc-0", "run-enc", "typ", json.RawMessage(`{"n":0}`))); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := testKeys(t, "k1", "k2")
+	st, err := Open(ctx, dsn, WithEncryption(testKeyring(t, keys, "k1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	for i, p := range []string{`{"secret": "a"}`, `{"secret": "b"}`, `{"secret": "c"}`} {
+		if _, err := st.AppendEvent(ctx, structToEvent(fmt.Sprintf("enc-%d", i+1), "run-enc", "typ", json.RawMessage(p))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: "enc-snap", RunID: "run-enc", UptoSeq: 2, State: json.RawMessage(`{"secret":"s"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.ArchiveEvents(ctx, "run-enc", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stored under k1, without the plain text.
+	rows, err := st.client.Event.Query().All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, err := st.client.ArchivedEvent.Query().All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyIDs := map[string]string{}
+	for _, r := range rows {
+		keyIDs[r.EventID] = r.KeyID
+		if r.KeyID != "" && strings.Contains(r.Payload, "secret") {
+			t.Fatalf("payload stored in plain text: %s", r.Payload)
+		}
+	}
+	for _, r := range archived {
+		keyIDs[r.EventID] = r.KeyID
+	}
+	if keyIDs["enc-0"] != "" || keyIDs["enc-1"] != "k1" || keyIDs["enc-2"] != "k1" || keyIDs["enc-3"] != "k1" {
+		t.Fatalf("key IDs=%v", keyIDs)
+	}
+	want := []string{`{"n":0}`, `{"secret": "a"}`, `{"secret": "b"}`, `{"secret": "c"}`}
+	checkPayloads := func(st *Store) {
+		t.Helper()
+		events, err := st.ListEvents(ctx, "run-enc", 0, 0)
+		if err != nil || len(events) != len(want) {
+			t.Fatalf("events=%+v err=%v", events, err)
+		}
+		for i, e := range events {
+			if string(e.Payload) != want[i] {
+				t.Fatalf("payload %d=%s want %s", i, e.Payload, want[i])
+			}
+		}
+		if sn, err := st.LoadLatestSnapshot(ctx, "run-enc"); err != nil || string(sn.State) != `{"secret":"s"}` {
+			t.Fatalf("snapshot=%+v err=%v", sn, err)
+		}
+	}
+	checkPayloads(st)
+	// Without the key, encrypted rows cannot be read.
+	if _, err := plain.GetEventByID(ctx, "enc-3"); err == nil {
+		t.Fatal("encrypted event read without a key")
+	}
+
+	// Rotating to k2 keeps k1 rows readable until they are re-encrypted.
+	rotated, err := Open(ctx, dsn, WithEncryption(testKeyring(t, keys, "k2", "k1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = rotated.Close() })
+	checkPayloads(rotated)
+	total := 0
+	for {
+		n, err := rotated.Reencrypt(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	// Four events, including the plain one, and the snapshot.
+	if total != 5 {
+		t.Fatalf("re-encrypted %d rows want 5", total)
+	}
+	// Once every row is under k2, k1 can be retired.
+	final, err := Open(ctx, dsn, WithEncryption(testKeyring(t, keys, "k2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = final.Close() })
+	checkPayloads(final)
+}
+
+func TestSQLiteRawPayloads(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-raw?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkRawPayloads(t, st, "run-raw")
+}
+
+func TestSQLiteSubscribe(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-subscribe?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"s1", "s2"} {
+		if _, err := st.AppendEvent(ctx, structToEvent(id, "run-sub", "typ", nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sctx, cancel := context.WithCancel(ctx)
+	got := make(chan store.EventRecord)
+	done := make(chan error, 1)
+	go func() {
+		for e, err := range st.Subscribe(sctx, "run-sub", 1) {
+			if err != nil {
+				done <- err
+				return
+			}
+			got <- e
+		}
+		done <- nil
+	}()
+	next := func() store.EventRecord {
+		select {
+		case e := <-got:
+			return e
+		case <-time.After(500 * time.Millisecond):
+			// Appends in this process wake subscribers well before the polling fallback.
+			t.Fatal("no event received")
+			return store.EventRecord{}
+		}
+	}
+	if e := next(); e.EventID != "s2" || e.Seq != 2 {
+		t.Fatalf("first event=%+v want s2", e)
+	}
+	// Events of other runs are not delivered.
+	if _, err := st.AppendEvent(ctx, structToEvent("other", "run-other", "typ", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.AppendEvents(ctx, "run-sub", 2, []store.EventRecord{structToEvent("s3", "run-sub", "typ", nil), structToEvent("s4", "run-sub", "typ", nil)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"s3", "s4"} {
+		if e := next(); e.EventID != want {
+			t.Fatalf("event=%s want %s", e.EventID, want)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("subscription ended with %v", err)
+	}
+}
+
+func TestSQLiteSnapshotRetentionAndArchive(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-compact?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		if _, err := st.AppendEvent(ctx, structToEvent(fmt.Sprintf("c%d", i), "run-compact", "typ", json.RawMessage(`{"i":`+fmt.Sprint(i)+`}`))); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: fmt.Sprintf("snap-%d", i), RunID: "run-compact", UptoSeq: int64(i), State: json.RawMessage(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := st.AppendEvent(ctx, structToEvent("other", "run-other", "typ", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := st.PruneSnapshots(ctx, "run-compact", store.SnapshotRetention{KeepLast: 2}); err != nil || n != 3 {
+		t.Fatalf("pruned=%d err=%v want 3", n, err)
+	}
+	// Age-based pruning never removes the latest snapshot.
+	if n, err := st.PruneSnapshots(ctx, "run-compact", store.SnapshotRetention{MaxAge: time.Nanosecond}); err != nil || n != 1 {
+		t.Fatalf("pruned=%d err=%v want 1", n, err)
+	}
+	if sn, err := st.LoadLatestSnapshot(ctx, "run-compact"); err != nil || sn.UptoSeq != 5 {
+		t.Fatalf("latest=%+v err=%v", sn, err)
+	}
+
+	// The run's last event stays live even when the snapshot covers it.
+	before, _ := st.ListEvents(ctx, "run-compact", 0, 0)
+	if n, err := st.ArchiveEvents(ctx, "run-compact", 5); err != nil || n != 4 {
+		t.Fatalf("archived=%d err=%v want 4", n, err)
+	}
+	if live, _ := st.client.Event.Query().Count(ctx); live != 2 {
+		t.Fatalf("live events=%d want 2", live)
+	}
+	after, err := st.ListEvents(ctx, "run-compact", 0, 0)
+	if err != nil || len(after) != len(before) {
+		t.Fatalf("events=%d err=%v want %d", len(after), err, len(before))
+	}
+	for i := range before {
+		if after[i].EventID != before[i].EventID || after[i].Position != before[i].Position || string(after[i].Payload) != string(before[i].Payload) {
+			t.Fatalf("event %d=%+v want %+v", i, after[i], before[i])
+		}
+	}
+	if page, _ := st.ListEvents(ctx, "run-compact", 2, 2); len(page) != 2 || page[0].Seq != 3 || page[1].Seq != 4 {
+		t.Fatalf("page=%+v want seqs 3,4", page)
+	}
+	all, _ := st.ReadAll(ctx, 0, 0)
+	if len(all) != 6 || all[5].EventID != "other" {
+		t.Fatalf("feed=%+v", all)
+	}
+	if e, err := st.GetEventByID(ctx, "c1"); err != nil || e.Seq != 1 {
+		t.Fatalf("archived event=%+v err=%v", e, err)
+	}
+	// Archived events still deduplicate appends, and the run's sequence continues.
+	if e, err := st.AppendEvent(ctx, structToEvent("c2", "run-compact", "typ", nil)); err != nil || e.Seq != 2 {
+		t.Fatalf("dup=%+v err=%v", e, err)
+	}
+	if last, _ := st.LastSeq(ctx, "run-compact"); last != 5 {
+		t.Fatalf("last seq=%d want 5", last)
+	}
+
+	if n, err := st.DeleteSnapshots(ctx, "run-compact"); err != nil || n != 1 {
+		t.Fatalf("deleted=%d err=%v want 1", n, err)
+	}
+	if _, err := st.LoadLatestSnapshot(ctx, "run-compact"); err != sql.ErrNoRows {
+		t.Fatalf("err=%v want sql.ErrNoRows", err)
+	}
+}
+
+func TestSQLiteEraseShredAndAudit(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:ent-erase?mode=memory&cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys: the first one created wins, and a shredded key cannot come back.
+	if _, err := st.LoadRunKey(ctx, "run-erase"); err != sql.ErrNoRows {
+		t.Fatalf("err=%v want sql.ErrNoRows", err)
+	}
+	if k, err := st.CreateRunKey(ctx, "run-erase", []byte("k1")); err != nil || string(k) != "k1" {
+		t.Fatalf("key=%q err=%v", k, err)
+	}
+	if k, err := st.CreateRunKey(ctx, "run-erase", []byte("k2")); err != nil || string(k) != "k1" {
+		t.Fatalf("key=%q err=%v want k1", k, err)
+	}
+	if err := st.ShredRunKey(ctx, "run-erase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.LoadRunKey(ctx, "run-erase"); !errmodel.HasCode(err, "run_shredded") {
+		t.Fatalf("err=%v want run_shredded", err)
+	}
+	if _, err := st.CreateRunKey(ctx, "run-erase", []byte("k3")); !errmodel.HasCode(err, "run_shredded") {
+		t.Fatalf("err=%v want run_shredded", err)
+	}
+	// Shredding a run without a key leaves a tombstone.
+	if err := st.ShredRunKey(ctx, "run-nokey"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.LoadRunKey(ctx, "run-nokey"); !errmodel.HasCode(err, "run_shredded") {
+		t.Fatalf("err=%v want run_shredded", err)
+	}
+
+	// DeleteRun removes everything recorded for the run and nothing else.
+	if _, err := st.CreateRun(ctx, store.RunRecord{RunID: "run-erase", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.CommitCycle(ctx, store.CycleCommit{
+		RunID:   "run-erase",
+		Events:  []store.EventRecord{structToEvent("er-1", "run-erase", "start", nil), structToEvent("er-2", "run-erase", "next", nil)},
+		Enqueue: []store.OutboxRecord{{ItemID: "er-item", Queue: "q", IntentName: "tick", Depth: 1, AvailableAt: time.Now()}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.AppendEvent(ctx, structToEvent("other-1", "run-other", "start", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: "er-snap", RunID: "run-erase", UptoSeq: 1, State: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.ArchiveEvents(ctx, "run-erase", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.SaveTimer(ctx, store.TimerRecord{TimerID: "er-timer", Queue: "q", RunID: "run-erase", Name: "wake", DueAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.SaveDeadLetter(ctx, store.DeadLetterRecord{DeadLetterID: "er-dl", RunID: "run-erase", IntentName: "tick"}); err != nil {
+		t.Fatal(err)
+	}
+	d, err := st.DeleteRun(ctx, "run-erase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (store.RunDeletion{Events: 2, Snapshots: 1, OutboxItems: 1, Timers: 1, DeadLetters: 1}); d != want {
+		t.Fatalf("deletion=%+v want %+v", d, want)
+	}
+	if evs, _ := st.ListEvents(ctx, "run-erase", 0, 0); len(evs) != 0 {
+		t.Fatalf("events=%+v", evs)
+	}
+	if _, err := st.GetRun(ctx, "run-erase"); err != sql.ErrNoRows {
+		t.Fatalf("err=%v want sql.ErrNoRows", err)
+	}
+	if _, err := st.LoadRunKey(ctx, "run-erase"); err != sql.ErrNoRows {
+		t.Fatalf("err=%v want sql.ErrNoRows", err)
+	}
+	if all, _ := st.ReadAll(ctx, 0, 0); len(all) != 1 || all[0].EventID != "other-1" {
+		t.Fatalf("feed=%+v", all)
+	}
+
+	for _, a := range []store.AuditRecord{
+		{AuditID: "a1", Action: "run_shredded", RunID: "run-erase", Actor: "dpo", Details: map[string]any{"reason": "request"}},
+		{AuditID: "a2", Action: "run_deleted", RunID: "run-erase"},
+		{AuditID: "a3", Action: "run_deleted", RunID: "run-other"},
+	} {
+		if _, err := st.AppendAudit(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, err := st.ListAudit(ctx, store.AuditFilter{RunID: "run-erase"})
+	entries := page.Entries
+	if err != nil || len(entries) != 2 || entries[0].AuditID != "a1" || entries[0].Details["reason"] != "request" || entries[1].AuditID != "a2" || page.NextCursor != "" {
+		t.Fatalf("audit=%+v err=%v", page, err)
+	}
+	// Pages continue after the cursor.
+	first, _ := st.ListAudit(ctx, store.AuditFilter{Limit: 2})
+	if len(first.Entries) != 2 || first.Entries[1].AuditID != "a2" || first.NextCursor == "" {
+		t.Fatalf("first page=%+v", first)
+	}
+	rest, _ := st.ListAudit(ctx, store.AuditFilter{Cursor: first.NextCursor, Limit: 2})
+	if len(rest.Entries) != 1 || rest.Entries[0].AuditID != "a3" || rest.NextCursor != "" {
+		t.Fatalf("second page=%+v", rest)
+	}
+	if _, err := st.ListAudit(ctx, store.AuditFilter{Cursor: "x"}); !errmodel.HasCode(err, "bad_cursor") {
+		t.Fatalf("err=%v want bad_cursor", err)
+	}
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	ctx := context.Background()
+	dsn := "sqlite:file:" + filepath.Join(t.TempDir(), "orch.sqlite") + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1"
+	st, err := Open(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	status, err := st.MigrationStatus(ctx)
+	if err != nil || len(status.Applied) != 0 || len(status.Pending) == 0 {
+		t.Fatalf("status=%+v err=%v", status, err)
+	}
+	if err := st.CheckMigrations(ctx); !errmodel.HasCode(err, "schema_behind") {
+		t.Fatalf("err=%v want schema_behind", err)
+	}
+	done, err := st.MigrateUp(ctx, 0)
+	if err != nil || len(done) != len(status.Pending) {
+		t.Fatalf("applied=%+v err=%v", done, err)
+	}
+	if err := st.CheckMigrations(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if done, err := st.MigrateUp(ctx, 0); err != nil || len(done) != 0 {
+		t.Fatalf("applied=%+v err=%v want none", done, err)
+	}
+	if _, err := st.AppendEvent(ctx, structToEvent("m1", "run-m", "typ", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Down reverts the newest migration; reverting them all leaves no tables.
+	if done, err := st.MigrateDown(ctx, 0); err != nil || len(done) != len(status.Pending) {
+		t.Fatalf("reverted=%+v err=%v", done, err)
+	}
+	if exists, err := st.tableExists(ctx, "events"); err != nil || exists {
+		t.Fatalf("events table exists=%v err=%v", exists, err)
+	}
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// An applied migration must not change.
+	if _, err := st.db.ExecContext(ctx, "UPDATE "+migrationTable+" SET hash = 'h1:edited'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.CheckMigrations(ctx); !errmodel.HasCode(err, "migration_modified") {
+		t.Fatalf("err=%v want migration_modified", err)
+	}
+}
+
+func TestSQLiteMigrationBaseline(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:"+filepath.Join(t.TempDir(), "orch.sqlite")+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	// A database created by ent's auto migration has tables but no history.
+	if err := st.client.Schema.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.MigrateUp(ctx, 0); !errmodel.HasCode(err, "baseline_required") {
+		t.Fatalf("err=%v want baseline_required", err)
+	}
+	status, err := st.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := status.Pending[len(status.Pending)-1].Version
+	if err := st.BaselineMigrations(ctx, "19700101000000"); !errmodel.HasCode(err, "unknown_migration") {
+		t.Fatalf("err=%v want unknown_migration", err)
+	}
+	if err := st.BaselineMigrations(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.CheckMigrations(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.BaselineMigrations(ctx, latest); !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("err=%v want conflict", err)
+	}
+}
+
+// preVersionedSchema is the schema ent's auto migration created before versioned migrations.
+var preVersionedSchema = []string{
+	"CREATE TABLE `events` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `event_id` text NOT NULL, `run_id` text NOT NULL, `seq` integer NOT NULL, `type` text NOT NULL, `payload` json NULL, `created_at` datetime NOT NULL)",
+	"CREATE UNIQUE INDEX `events_event_id_key` ON `events` (`event_id`)",
+	"CREATE UNIQUE INDEX `event_run_id_seq` ON `events` (`run_id`, `seq`)",
+	"CREATE INDEX `event_run_id` ON `events` (`run_id`)",
+	"CREATE TABLE `snapshots` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `snapshot_id` text NOT NULL, `run_id` text NOT NULL, `upto_seq` integer NOT NULL, `state` json NULL, `created_at` datetime NOT NULL)",
+	"CREATE UNIQUE INDEX `snapshots_snapshot_id_key` ON `snapshots` (`snapshot_id`)",
+	"CREATE INDEX `snapshot_run_id` ON `snapshots` (`run_id`)",
+	"CREATE UNIQUE INDEX `snapshot_run_id_upto_seq` ON `snapshots` (`run_id`, `upto_seq`)",
+}
+
+func TestSQLiteMigrationUpgradesBaseline(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(ctx, "sqlite:file:"+filepath.Join(t.TempDir(), "orch.sqlite")+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	for _, stmt := range preVersionedSchema {
+		if _, err := st.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	if _, err := st.db.ExecContext(ctx, "INSERT INTO events (event_id, run_id, seq, type, payload, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		"old1", "run-old", 1, "typ", `{"k":1}`, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.db.ExecContext(ctx, "INSERT INTO snapshots (snapshot_id, run_id, upto_seq, state, created_at) VALUES (?, ?, ?, ?, ?)",
+		"snap1", "run-old", 1, `{"n":1}`, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first migration is the schema such a database has; the later ones upgrade it.
+	status, err := st.MigrationStatus(ctx)
+	if err != nil || len(status.Pending) < 2 {
+		t.Fatalf("status=%+v err=%v", status, err)
+	}
+	if err := st.BaselineMigrations(ctx, status.Pending[0].Version); err != nil {
+		t.Fatal(err)
+	}
+	done, err := st.MigrateUp(ctx, 0)
+	if err != nil || len(done) != len(status.Pending)-1 {
+		t.Fatalf("applied=%+v err=%v", done, err)
+	}
+	if err := st.CheckMigrations(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	evs, err := st.ListEvents(ctx, "run-old", 0, 0)
+	if err != nil || len(evs) != 1 || evs[0].Version != 1 || string(evs[0].Payload) != `{"k":1}` {
+		t.Fatalf("events=%+v err=%v", evs, err)
+	}
+	sn, err := st.LoadLatestSnapshot(ctx, "run-old")
+	if err != nil || string(sn.State) != `{"n":1}` {
+		t.Fatalf("snapshot=%+v err=%v", sn, err)
+	}
+	if _, err := st.AppendEvent(ctx, structToEvent("new1", "run-old", "typ", json.RawMessage(`{"k":2}`))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.CreateRun(ctx, store.RunRecord{RunID: "run-old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reverting the upgrade restores the old schema.
+	if _, err := st.MigrateDown(ctx, len(done)); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := st.tableExists(ctx, "runs"); err != nil || exists {
+		t.Fatalf("runs table exists=%v err=%v", exists, err)
+	}
+	var n int
+	if err := st.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events").Scan(&n); err != nil || n != 2 {
+		t.Fatalf("events=%d err=%v", n, err)
+	}
+}
+
+func TestMigrationFiles(t *testing.T) {
+	ctx := context.Background()
+	// The SQLite migrations produce the ent schema. The Postgres ones are checked by the
+	// integration tests.
+	dev, err := Open(ctx, "sqlite:file:migrations-dev?mode=memory&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = dev.Close() })
+	dir, err := migrate.NewLocalDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := fs.Sub(migrationFiles, "migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied := fstest.MapFS{}
+	err = fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := fs.ReadFile(src, path)
+		if err != nil {
+			return err
+		}
+		copied[path] = &fstest.MapFile{Data: b}
+		return dir.WriteFile(path, b)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := dev.diffMigrations(ctx, &migrationDir{dir}, "drift"); err != nil || changed {
+		t.Fatalf("changed=%v err=%v; run `orch migrate diff` after changing the ent schema", changed, err)
+	}
+
+	// Every file is covered by atlas.sum, down files included.
+	if _, err := loadMigrations(copied); err != nil {
+		t.Fatal(err)
+	}
+	for name, f := range copied {
+		if strings.HasSuffix(name, ".down.sql") {
+			f.Data = append(slices.Clone(f.Data), "DROP TABLE `runs`;\n"...)
+		}
+	}
+	if _, err := loadMigrations(copied); !errmodel.HasCode(err, "migration_checksum") {
+		t.Fatalf("err=%v want migration_checksum", err)
+	}
+	delete(copied, migrate.HashFileName)
+	if _, err := loadMigrations(copied); !errmodel.HasCode(err, "migration_checksum") {
+		t.Fatalf("err=%v want migration_checksum", err)
+	}
+}
@@ -0,0 +1,154 @@
+package entstore
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/wilhg/orch/pkg/store"
+)
+
+const (
+	// eventsChannel is the Postgres notification channel; the payload is the run ID.
+	eventsChannel = "orch_events"
+	// subscribePoll bounds how long a subscriber waits without a notification, covering appends
+	// by other processes on SQLite and notifications lost while the listener reconnects.
+	subscribePoll  = time.Second
+	subscribeBatch = 100
+)
+
+// hub wakes the subscribers of a run when events are appended to it.
+type hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[chan struct{}]struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	listen sync.Once
+}
+
+func newHub() *hub {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &hub{subs: map[string]map[chan struct{}]struct{}{}, ctx: ctx, cancel: cancel}
+}
+
+// watch returns a channel signalled after appends to runID and a func to stop watching.
+func (h *hub) watch(runID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	h.mu.Lock()
+	if h.subs[runID] == nil {
+		h.subs[runID] = map[chan struct{}]struct{}{}
+	}
+	h.subs[runID][ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs[runID], ch)
+		if len(h.subs[runID]) == 0 {
+			delete(h.subs, runID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// wake signals the subscribers of runID, or of every run if runID is empty.
+func (h *hub) wake(runID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, chans := range h.subs {
+		if runID != "" && id != runID {
+			continue
+		}
+		for ch := range chans {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (h *hub) close() { h.cancel() }
+
+// notify wakes the subscribers of runID after a commit, in this process and, on Postgres,
+// in every process listening on the database.
+func (s *Store) notify(ctx context.Context, runID string) {
+	s.hub.wake(runID)
+	if s.dialect == dialect.Postgres {
+		// Best effort: subscribers fall back to polling.
+		_, _ = s.db.ExecContext(context.WithoutCancel(ctx), "SELECT pg_notify($1, $2)", eventsChannel, runID)
+	}
+}
+
+// Subscribe yields the events of runID after afterSeq, then waits for new ones. Appends in this
+// process wake subscribers immediately; on Postgres, so do appends from other processes, through
+// LISTEN/NOTIFY. Otherwise new events are picked up within a second.
+func (s *Store) Subscribe(ctx context.Context, runID string, afterSeq int64) iter.Seq2[store.EventRecord, error] {
+	return func(yield func(store.EventRecord, error) bool) {
+		if s.dialect == dialect.Postgres {
+			s.hub.listen.Do(func() { go s.listenPostgres() })
+		}
+		// Watch before reading so that no append between the read and the wait is missed.
+		wake, stop := s.hub.watch(runID)
+		defer stop()
+		poll := time.NewTicker(subscribePoll)
+		defer poll.Stop()
+		last := afterSeq
+		for {
+			events, err := s.ListEvents(ctx, runID, last, subscribeBatch)
+			if err != nil {
+				if ctx.Err() == nil && s.hub.ctx.Err() == nil {
+					yield(store.EventRecord{}, err)
+				}
+				return
+			}
+			for _, e := range events {
+				if !yield(e, nil) {
+					return
+				}
+				last = e.Seq
+			}
+			if len(events) == subscribeBatch {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.hub.ctx.Done():
+				return
+			case <-wake:
+			case <-poll.C:
+			}
+		}
+	}
+}
+
+// listenPostgres relays notifications to the hub until the store is closed, reconnecting on
+// failure.
+func (s *Store) listenPostgres() {
+	ctx := s.hub.ctx
+	for ctx.Err() == nil {
+		conn, err := pgx.Connect(ctx, s.dsn)
+		if err == nil {
+			if _, err = conn.Exec(ctx, "LISTEN "+eventsChannel); err == nil {
+				// Notifications may have been missed while disconnected.
+				s.hub.wake("")
+				for {
+					n, err := conn.WaitForNotification(ctx)
+					if err != nil {
+						break
+					}
+					s.hub.wake(n.Payload)
+				}
+			}
+			_ = conn.Close(context.Background())
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package entstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wilhg/orch/internal/ent"
+	"github.com/wilhg/orch/internal/ent/timer"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// SaveTimer inserts a timer.
+func (s *Store) SaveTimer(ctx context.Context, t store.TimerRecord) (store.TimerRecord, error) {
+	created, err := createTimer(s.client.Timer, t).Save(ctx)
+	if err != nil {
+		return store.TimerRecord{}, err
+	}
+	return toTimerRecord(created), nil
+}
+
+// commitTimers inserts the timers of a cycle.
+func commitTimers(ctx context.Context, tx *ent.Tx, c store.CycleCommit) error {
+	if len(c.Timers) == 0 {
+		return nil
+	}
+	builders := make([]*ent.TimerCreate, 0, len(c.Timers))
+	for _, t := range c.Timers {
+		if t.RunID == "" {
+			t.RunID = c.RunID
+		}
+		builders = append(builders, createTimer(tx.Timer, t))
+	}
+	return tx.Timer.CreateBulk(builders...).Exec(ctx)
+}
+
+func createTimer(client *ent.TimerClient, t store.TimerRecord) *ent.TimerCreate {
+	b := client.Create().
+		SetTimerID(t.TimerID).
+		SetQueue(t.Queue).
+		SetRunID(t.RunID).
+		SetDueAt(toMillis(t.DueAt)).
+		SetCreatedAt(time.Now())
+	if t.Name != "" {
+		b = b.SetName(t.Name)
+	}
+	if t.Payload != nil {
+		b = b.SetPayload(t.Payload)
+	}
+	if !t.AvailableAt.IsZero() {
+		b = b.SetAvailableAt(toMillis(t.AvailableAt))
+	}
+	return b
+}
+
+// DueTimers lists the timers of queue that are due at now.
+func (s *Store) DueTimers(ctx context.Context, queue string, now time.Time, limit int) ([]store.TimerRecord, error) {
+	q := s.client.Timer.Query().
+		Where(timer.Queue(queue), timer.DueAtLTE(toMillis(now)), timer.AvailableAtLTE(toMillis(now)))
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	rows, err := q.Order(ent.Asc(timer.FieldDueAt), ent.Asc(timer.FieldID)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]store.TimerRecord, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toTimerRecord(r))
+	}
+	return out, nil
+}
+
+// DeleteTimer removes a timer by ID.
+func (s *Store) DeleteTimer(ctx context.Context, timerID string) error {
+	n, err := s.client.Timer.Delete().
+		Where(timer.TimerID(timerID)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RetryTimer counts a failed delivery and delays the timer until availableAt.
+func (s *Store) RetryTimer(ctx context.Context, timerID string, availableAt time.Time) error {
+	n, err := s.client.Timer.Update().
+		Where(timer.TimerID(timerID)).
+		AddAttempts(1).
+		SetAvailableAt(toMillis(availableAt)).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func toTimerRecord(r *ent.Timer) store.TimerRecord {
+	t := store.TimerRecord{
+		TimerID:   r.TimerID,
+		Queue:     r.Queue,
+		RunID:     r.RunID,
+		Name:      r.Name,
+		Payload:   r.Payload,
+		DueAt:     time.UnixMilli(r.DueAt).UTC(),
+		Attempts:  r.Attempts,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.AvailableAt > 0 {
+		t.AvailableAt = time.UnixMilli(r.AvailableAt).UTC()
+	}
+	return t
+}
@@ -0,0 +1,206 @@
+// Package envelope encrypts stored payloads and states with envelope encryption. Data is sealed
+// with AES-256-GCM under a data key, and the data key is wrapped by a master key of a
+// KeyProvider. Each ciphertext carries its wrapped data key, and stores record the ID of the
+// master key per row, so master keys can be rotated while rows written under older keys stay
+// readable.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeyProvider holds the master keys that wrap data keys. Implementations must be safe for
+// concurrent use.
+type KeyProvider interface {
+	// ActiveKeyID returns the ID of the master key that wraps new data keys.
+	ActiveKeyID() string
+	// WrapKey encrypts a data key under the master key keyID.
+	WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key that WrapKey encrypted under the master key keyID.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+var (
+	// ErrUnknownKey is returned for a master key ID the provider does not hold.
+	ErrUnknownKey = errors.New("envelope: unknown key")
+	// ErrMalformed is returned for a ciphertext that Cipher did not produce.
+	ErrMalformed = errors.New("envelope: malformed ciphertext")
+)
+
+const (
+	// prefix starts every ciphertext, which is a JSON string so that it fits JSON columns:
+	// "v1:<base64 wrapped data key>:<base64 nonce and sealed data>".
+	prefix = `"v1:`
+	// dataKeyUses bounds the messages sealed under one data key, well within the limit of
+	// random GCM nonces.
+	dataKeyUses = 1 << 20
+	// maxCachedKeys bounds the unwrapped data keys kept for decryption.
+	maxCachedKeys = 1024
+)
+
+// Cipher encrypts and decrypts data under the keys of a KeyProvider. A data key is generated
+// for the active master key and reused for many messages, so the provider is called once per
+// data key rather than per message. It is safe for concurrent use.
+type Cipher struct {
+	p KeyProvider
+
+	mu sync.Mutex
+	// dek is the data key sealing new messages.
+	dek *dataKey
+	// opened caches unwrapped data keys by master key ID and wrapped key.
+	opened map[string]cipher.AEAD
+}
+
+type dataKey struct {
+	keyID   string
+	wrapped []byte
+	aead    cipher.AEAD
+	uses    int
+}
+
+// NewCipher returns a Cipher using the keys of p.
+func NewCipher(p KeyProvider) *Cipher {
+	return &Cipher{p: p, opened: map[string]cipher.AEAD{}}
+}
+
+// KeyID returns the ID of the master key new messages are encrypted under.
+func (c *Cipher) KeyID() string { return c.p.ActiveKeyID() }
+
+// Encrypt seals plaintext, authenticating aad with it, and returns the ID of the master key it
+// is encrypted under along with the ciphertext, a JSON string.
+func (c *Cipher) Encrypt(ctx context.Context, plaintext, aad []byte) (string, []byte, error) {
+	dek, err := c.dataKey(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	sealed, err := seal(dek.aead, plaintext, aad)
+	if err != nil {
+		return "", nil, err
+	}
+	enc := base64.StdEncoding
+	out := make([]byte, 0, len(prefix)+enc.EncodedLen(len(dek.wrapped))+enc.EncodedLen(len(sealed))+2)
+	out = append(out, prefix...)
+	out = enc.AppendEncode(out, dek.wrapped)
+	out = append(out, ':')
+	out = enc.AppendEncode(out, sealed)
+	return dek.keyID, append(out, '"'), nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt under the master key keyID with the same aad.
+func (c *Cipher) Decrypt(ctx context.Context, keyID string, ciphertext, aad []byte) ([]byte, error) {
+	body, ok := bytes.CutPrefix(ciphertext, []byte(prefix))
+	if !ok || len(body) == 0 || body[len(body)-1] != '"' {
+		return nil, ErrMalformed
+	}
+	w, s, ok := bytes.Cut(body[:len(body)-1], []byte(":"))
+	if !ok {
+		return nil, ErrMalformed
+	}
+	wrapped, err1 := base64.StdEncoding.AppendDecode(nil, w)
+	sealed, err2 := base64.StdEncoding.AppendDecode(nil, s)
+	if err1 != nil || err2 != nil {
+		return nil, ErrMalformed
+	}
+	aead, err := c.open(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(aead, sealed, aad)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decrypt under key %q: %w", keyID, err)
+	}
+	return plaintext, nil
+}
+
+// dataKey returns the data key for new messages, generating one when the active master key
+// changed or the current one was used up.
+func (c *Cipher) dataKey(ctx context.Context) (*dataKey, error) {
+	keyID := c.p.ActiveKeyID()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dek != nil && c.dek.keyID == keyID && c.dek.uses < dataKeyUses {
+		c.dek.uses++
+		return c.dek, nil
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	wrapped, err := c.p.WrapKey(ctx, keyID, raw)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: wrap data key under %q: %w", keyID, err)
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	c.dek = &dataKey{keyID: keyID, wrapped: wrapped, aead: aead, uses: 1}
+	c.cache(keyID, wrapped, aead)
+	return c.dek, nil
+}
+
+// open returns the data key wrapped under the master key keyID.
+func (c *Cipher) open(ctx context.Context, keyID string, wrapped []byte) (cipher.AEAD, error) {
+	c.mu.Lock()
+	aead, ok := c.opened[cacheKey(keyID, wrapped)]
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+	raw, err := c.p.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: unwrap data key under %q: %w", keyID, err)
+	}
+	if aead, err = newAEAD(raw); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.cache(keyID, wrapped, aead)
+	c.mu.Unlock()
+	return aead, nil
+}
+
+// cache keeps an unwrapped data key; c.mu must be held.
+func (c *Cipher) cache(keyID string, wrapped []byte, aead cipher.AEAD) {
+	if len(c.opened) >= maxCachedKeys {
+		clear(c.opened)
+	}
+	c.opened[cacheKey(keyID, wrapped)] = aead
+}
+
+func cacheKey(keyID string, wrapped []byte) string {
+	return keyID + "\x00" + string(wrapped)
+}
+
+// seal encrypts plaintext with a random nonce, which prefixes the result.
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open decrypts the output of seal.
+func open(aead cipher.AEAD, ciphertext, aad []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	return aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], aad)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,153 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// countingProvider counts the data keys wrapped and unwrapped by a provider.
+type countingProvider struct {
+	KeyProvider
+	wraps, unwraps int
+}
+
+func (p *countingProvider) WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	p.wraps++
+	return p.KeyProvider.WrapKey(ctx, keyID, dek)
+}
+
+func (p *countingProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	p.unwraps++
+	return p.KeyProvider.UnwrapKey(ctx, keyID, wrapped)
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	ring, err := ParseKeyring("k1:" + newKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &countingProvider{KeyProvider: ring}
+	c := NewCipher(p)
+	plain := []byte(`{"b":1, "a":[true]}`)
+	keyID, ct, err := c.Encrypt(ctx, plain, []byte("ev-1"))
+	if err != nil || keyID != "k1" {
+		t.Fatalf("key=%q err=%v", keyID, err)
+	}
+	if !json.Valid(ct) || bytes.Contains(ct, []byte(`"a"`)) {
+		t.Fatalf("ciphertext=%s", ct)
+	}
+	got, err := c.Decrypt(ctx, keyID, ct, []byte("ev-1"))
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("plain=%s err=%v", got, err)
+	}
+	// The data key is reused, and kept unwrapped.
+	for range 10 {
+		if _, _, err := c.Encrypt(ctx, plain, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.wraps != 1 || p.unwraps != 0 {
+		t.Fatalf("wraps=%d unwraps=%d want 1, 0", p.wraps, p.unwraps)
+	}
+
+	// A ciphertext only opens for its row, untouched.
+	if _, err := c.Decrypt(ctx, keyID, ct, []byte("ev-2")); err == nil {
+		t.Fatal("decrypted with the wrong associated data")
+	}
+	tampered := bytes.Clone(ct)
+	// The last base64 characters may carry padding bits; flip one that does not.
+	tampered[len(tampered)-8] ^= 1
+	if _, err := c.Decrypt(ctx, keyID, tampered, []byte("ev-1")); err == nil {
+		t.Fatal("decrypted a tampered ciphertext")
+	}
+	if _, err := c.Decrypt(ctx, keyID, plain, nil); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("err=%v want ErrMalformed", err)
+	}
+	if _, err := c.Decrypt(ctx, "k0", ct, []byte("ev-1")); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("err=%v want ErrUnknownKey", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	k1, k2 := newKey(t), newKey(t)
+	old, err := ParseKeyring("k1:" + k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ct, err := NewCipher(old).Encrypt(ctx, []byte(`"v"`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The successor goes first; the previous key still opens older data.
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys")
+	if err := os.WriteFile(path, []byte("# current\nk2:"+k2+"\n\nk1:"+k1+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ring, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCipher(ring)
+	if got, err := c.Decrypt(ctx, "k1", ct, nil); err != nil || string(got) != `"v"` {
+		t.Fatalf("plain=%s err=%v", got, err)
+	}
+	if keyID, _, err := c.Encrypt(ctx, []byte(`"v"`), nil); err != nil || keyID != "k2" {
+		t.Fatalf("key=%q err=%v want k2", keyID, err)
+	}
+
+	t.Setenv("ORCH_TEST_KEYS", "k2:"+k2+",k1:"+k1)
+	if ring, err := KeyringFromEnv("ORCH_TEST_KEYS"); err != nil || ring.ActiveKeyID() != "k2" {
+		t.Fatalf("ring=%v err=%v", ring, err)
+	}
+	if _, err := KeyringFromEnv("ORCH_TEST_MISSING"); err == nil {
+		t.Fatal("missing variable accepted")
+	}
+	for _, bad := range []string{"", "k1", "k1:short", "k1:" + k1 + ",k1:" + k2, "k1:!!"} {
+		if _, err := ParseKeyring(bad); err == nil {
+			t.Fatalf("keyring %q accepted", bad)
+		}
+	}
+}
+
+func TestKMSProvider(t *testing.T) {
+	ctx := context.Background()
+	kms := NewLocalKMS()
+	for _, id := range []string{"projects/p/keys/a", "projects/p/keys/b"} {
+		if err := kms.CreateKey(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keyID, ct, err := NewCipher(NewKMSProvider(kms, "projects/p/keys/a")).Encrypt(ctx, []byte(`{"secret":1}`), []byte("snap-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// After rotating to key b, key a still opens the older data.
+	c := NewCipher(NewKMSProvider(kms, "projects/p/keys/b"))
+	if got, err := c.Decrypt(ctx, keyID, ct, []byte("snap-1")); err != nil || string(got) != `{"secret":1}` {
+		t.Fatalf("plain=%s err=%v", got, err)
+	}
+	if _, _, err := NewCipher(NewKMSProvider(kms, "projects/p/keys/c")).Encrypt(ctx, nil, nil); !errors.Is(err, ErrUnknownKey) || !strings.Contains(err.Error(), "keys/c") {
+		t.Fatalf("err=%v want ErrUnknownKey", err)
+	}
+}
@@ -0,0 +1,204 @@
+package envelope
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Keyring is a KeyProvider holding AES-256 master keys in memory, typically loaded from a key
+// file or the environment.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring returns a Keyring wrapping new data keys under keys[active]. Keys must be 32
+// bytes long; the other keys decrypt data keys wrapped before a rotation.
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("envelope: active key %q not in keyring", active)
+	}
+	k := &Keyring{active: active, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.ContainsAny(id, ":,") {
+			return nil, fmt.Errorf("envelope: invalid key ID %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("envelope: key %q is %d bytes, want 32", id, len(key))
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// ParseKeyring parses keys written as id:base64key, separated by commas or newlines. The first
+// key is the active one, so a key is rotated by adding its successor in front. Blank lines and
+// lines starting with # are ignored.
+func ParseKeyring(s string) (*Keyring, error) {
+	var (
+		active string
+		keys   = map[string][]byte{}
+	)
+	for _, line := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, b64, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("envelope: key entry %q is not id:base64key", line)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if err != nil {
+			return nil, fmt.Errorf("envelope: key %q: %w", id, err)
+		}
+		id = strings.TrimSpace(id)
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("envelope: duplicate key %q", id)
+		}
+		if active == "" {
+			active = id
+		}
+		keys[id] = key
+	}
+	if active == "" {
+		return nil, fmt.Errorf("envelope: no keys")
+	}
+	return NewKeyring(active, keys)
+}
+
+// LoadKeyFile reads a keyring in the format of ParseKeyring from a file, one key per line.
+func LoadKeyFile(path string) (*Keyring, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyring(string(b))
+}
+
+// KeyringFromEnv reads a keyring in the format of ParseKeyring from an environment variable.
+func KeyringFromEnv(name string) (*Keyring, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("envelope: %s is not set", name)
+	}
+	return ParseKeyring(v)
+}
+
+// ActiveKeyID returns the ID of the first key.
+func (k *Keyring) ActiveKeyID() string { return k.active }
+
+// WrapKey encrypts dek under the key keyID.
+func (k *Keyring) WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	return seal(aead, dek, []byte(keyID))
+}
+
+// UnwrapKey decrypts a data key wrapped under the key keyID.
+func (k *Keyring) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	return open(aead, wrapped, []byte(keyID))
+}
+
+// KMS is the part of a key management service that envelope encryption needs: encrypting and
+// decrypting small secrets under a key that never leaves the service.
+type KMS interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// NewKMSProvider returns a KeyProvider wrapping data keys with kms under keyID. Rows written
+// under earlier key IDs are decrypted with the key ID recorded for them.
+func NewKMSProvider(kms KMS, keyID string) KeyProvider {
+	return kmsProvider{kms: kms, keyID: keyID}
+}
+
+type kmsProvider struct {
+	kms   KMS
+	keyID string
+}
+
+func (p kmsProvider) ActiveKeyID() string { return p.keyID }
+
+func (p kmsProvider) WrapKey(ctx context.Context, keyID string, dek []byte) ([]byte, error) {
+	return p.kms.Encrypt(ctx, keyID, dek)
+}
+
+func (p kmsProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	return p.kms.Decrypt(ctx, keyID, wrapped)
+}
+
+// LocalKMS is an in-process stand-in for a key management service, for development and tests.
+// Its keys are lost when the process exits.
+type LocalKMS struct {
+	mu   sync.Mutex
+	keys map[string]cipher.AEAD
+}
+
+// NewLocalKMS returns a LocalKMS without keys.
+func NewLocalKMS() *LocalKMS {
+	return &LocalKMS{keys: map[string]cipher.AEAD{}}
+}
+
+// CreateKey generates a random key named keyID, unless it exists.
+func (k *LocalKMS) CreateKey(keyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[keyID]; ok {
+		return nil
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return err
+	}
+	k.keys[keyID] = aead
+	return nil
+}
+
+// Encrypt encrypts plaintext under the key keyID.
+func (k *LocalKMS) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	aead, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, plaintext, []byte(keyID))
+}
+
+// Decrypt decrypts a ciphertext that Encrypt produced under the key keyID.
+func (k *LocalKMS) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	aead, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext, []byte(keyID))
+}
+
+func (k *LocalKMS) key(keyID string) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	return aead, nil
+}
@@ -3,43 +3,420 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"iter"
 	"time"
+
+	"github.com/wilhg/orch/pkg/errmodel"
 )
 
 // EventRecord is the persisted representation of an event.
-// Payload holds the event data as JSON.
+// Payload holds the event data as any JSON value, which stores keep byte-for-byte.
 type EventRecord struct {
-	EventID   string
-	RunID     string
-	Seq       int64
-	Type      string
+	EventID string
+	RunID   string
+	Seq     int64
+	// Position orders events across all runs in commit order; assigned by the store.
+	Position int64
+	Type     string
+	// Version of the payload's schema; the store records 1 when zero.
+	Version   int
 	Payload   json.RawMessage
 	CreatedAt time.Time
 }
 
-// SnapshotRecord stores a materialized state up to a given sequence.
+// SnapshotRecord stores a materialized state up to a given sequence. State is JSON kept
+// byte-for-byte, like event payloads.
 type SnapshotRecord struct {
 	SnapshotID string
 	RunID      string
 	UptoSeq    int64
 	State      json.RawMessage
-	CreatedAt  time.Time
+	// Version identifies the reducer and codec that produced State.
+	Version string
+	// Hash is a digest of State, such as "sha256:<hex>".
+	Hash      string
+	CreatedAt time.Time
+}
+
+// SnapshotFilter selects snapshots to invalidate. Empty fields match every run or version, but
+// at least one must be set.
+type SnapshotFilter struct {
+	RunID   string
+	Version string
+}
+
+// SnapshotRetention selects the snapshots of a run to keep. The latest snapshot is always kept;
+// others are removed when they are not among the KeepLast newest or are older than MaxAge. Zero
+// fields impose no limit.
+type SnapshotRetention struct {
+	KeepLast int
+	MaxAge   time.Duration
+}
+
+// DeadLetterRecord is an intent that exhausted its retry policy.
+type DeadLetterRecord struct {
+	DeadLetterID   string
+	RunID          string
+	IntentName     string
+	IdempotencyKey string
+	Args           map[string]any
+	Attempts       int
+	// Error is the compact error of the last attempt.
+	Error     errmodel.Error
+	CreatedAt time.Time
+}
+
+// OutboxRecord is an intent persisted for execution by a worker.
+type OutboxRecord struct {
+	ItemID         string
+	Queue          string
+	RunID          string
+	IntentName     string
+	IdempotencyKey string
+	Args           map[string]any
+	// Depth is the position of the intent in the chain started by an incoming event: 1 for
+	// intents emitted for the event, plus one for each follow-up.
+	Depth int
+	// Claims counts how many times the item was claimed by a worker.
+	Claims         int
+	LeaseOwner     string
+	LeaseExpiresAt time.Time
+	// AvailableAt is the earliest time the item may be claimed.
+	AvailableAt time.Time
+	CreatedAt   time.Time
+}
+
+// TimerRecord schedules a timer_fired event for a run.
+type TimerRecord struct {
+	TimerID string
+	// Queue identifies the runner that delivers the event.
+	Queue   string
+	RunID   string
+	Name    string
+	Payload map[string]any
+	DueAt   time.Time
+	// Attempts counts the failed deliveries of the timer.
+	Attempts int
+	// AvailableAt, if set, is the earliest time of the next delivery after a failed one.
+	AvailableAt time.Time
+	CreatedAt   time.Time
+}
+
+// RunRecord is the catalog entry of a run.
+type RunRecord struct {
+	RunID string
+	// Status is the lifecycle status of the run, such as "active", "paused" or "cancelled".
+	Status string
+	// AgentType names the agent that handles the run.
+	AgentType   string
+	Labels      map[string]string
+	ParentRunID string
+	// LastSeq is the sequence of the last event the catalog has seen for the run.
+	LastSeq   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RunFilter selects runs to list. Empty fields match every run; a run matches Labels if it has
+// all of them.
+type RunFilter struct {
+	Status      string
+	AgentType   string
+	ParentRunID string
+	Labels      map[string]string
+	// Cursor is the NextCursor of the previous page; empty starts from the newest run.
+	Cursor string
+	Limit  int
+}
+
+// RunPage is a page of runs, newest first.
+type RunPage struct {
+	Runs []RunRecord
+	// NextCursor continues the listing; it is empty on the last page.
+	NextCursor string
+}
+
+// EventQuery selects events to search. Empty fields match every event.
+type EventQuery struct {
+	RunID string
+	// Types matches events of any of the types.
+	Types []string
+	// Since and Until bound the creation time of events; Since is inclusive, Until exclusive.
+	Since time.Time
+	Until time.Time
+	// Labels matches events of runs that have all of the labels in the run catalog.
+	Labels map[string]string
+	// Where matches events whose payload satisfies every predicate.
+	Where []PayloadPredicate
+	// Text matches events whose payload contains it, ignoring case.
+	Text string
+	// Cursor is the NextCursor of the previous page; empty starts from the oldest event.
+	Cursor string
+	Limit  int
+}
+
+// PayloadOp compares the payload value at a path with the value of a predicate.
+type PayloadOp string
+
+const (
+	PayloadEQ  PayloadOp = "eq"
+	PayloadNE  PayloadOp = "ne"
+	PayloadGT  PayloadOp = "gt"
+	PayloadGTE PayloadOp = "gte"
+	PayloadLT  PayloadOp = "lt"
+	PayloadLTE PayloadOp = "lte"
+	// PayloadExists matches payloads that have a value at the path, JSON null included.
+	PayloadExists PayloadOp = "exists"
+	// PayloadContains matches arrays that have the value as an element, and values equal to it.
+	PayloadContains PayloadOp = "contains"
+)
+
+// PayloadPredicate compares the value at Path in an event's payload with Value.
+type PayloadPredicate struct {
+	// Path is a dot-separated path of object keys, with [n] for array elements, such as
+	// "order.items[0].sku".
+	Path string
+	Op   PayloadOp
+	// Value is a JSON string, number, boolean or null; ordering operators take a string or a
+	// number, and only match payload values of the same type. PayloadExists ignores it.
+	Value json.RawMessage
+}
+
+// EventPage is a page of events, in the order of the global feed.
+type EventPage struct {
+	Events []EventRecord
+	// NextCursor continues the search; it is empty on the last page.
+	NextCursor string
+}
+
+// AuditRecord is an entry of the audit trail of retention and erasure operations.
+type AuditRecord struct {
+	AuditID string
+	// Action names the operation, such as "run_exported", "run_deleted" or "run_shredded".
+	Action string
+	RunID  string
+	// Actor identifies who requested the operation; it is empty for retention policies.
+	Actor     string
+	Details   map[string]any
+	CreatedAt time.Time
+}
+
+// AuditFilter selects audit entries to list. An empty RunID matches every run.
+type AuditFilter struct {
+	RunID string
+	// Cursor is the NextCursor of the previous page; empty starts from the oldest entry.
+	Cursor string
+	Limit  int
 }
 
+// AuditPage is a page of audit entries, oldest first.
+type AuditPage struct {
+	Entries []AuditRecord
+	// NextCursor continues the listing; it is empty on the last page.
+	NextCursor string
+}
+
+// RunDeletion counts the records DeleteRun removed.
+type RunDeletion struct {
+	Events      int
+	Snapshots   int
+	OutboxItems int
+	Timers      int
+	DeadLetters int
+}
+
+// CycleCommit is a cycle of a run committed together with the outbox changes, dead letters and
+// timers it produced.
+type CycleCommit struct {
+	RunID       string
+	ExpectedSeq int64
+	Events      []EventRecord
+	// Enqueue lists the items to add to the outbox.
+	Enqueue []OutboxRecord
+	// Complete, if set, is the ID of an item to remove; Owner must still hold its lease.
+	Complete string
+	Owner    string
+	// DeadLetters lists the dead letters to save; one with the ID of an existing dead letter
+	// replaces it.
+	DeadLetters []DeadLetterRecord
+	// Resolve, if set, is the ID of a dead letter to remove.
+	Resolve string
+	// Timers lists the timers to schedule.
+	Timers []TimerRecord
+}
+
+// AnySeq disables the expected sequence check of AppendEvents.
+const AnySeq int64 = -1
+
 // EventStore defines operations for event logs.
 type EventStore interface {
+	// AppendEvent appends e with the next sequence of its run. Appending an EventID that already
+	// exists returns the existing record. If e.Seq > 0 it is the expected sequence of the new
+	// record; implementations must fail with an errmodel conflict error when the run's last
+	// sequence is no longer e.Seq-1.
 	AppendEvent(ctx context.Context, e EventRecord) (EventRecord, error)
+	// AppendEvents atomically appends events to runID in order, assigning sequences
+	// expectedSeq+1, expectedSeq+2, ... Either all records are written or none.
+	// Implementations must fail with an errmodel conflict error when the run's last sequence is
+	// not expectedSeq, unless expectedSeq is AnySeq. Records whose EventID already exists are not
+	// written again; the existing record is returned in their place.
+	AppendEvents(ctx context.Context, runID string, expectedSeq int64, events []EventRecord) ([]EventRecord, error)
 	ListEvents(ctx context.Context, runID string, afterSeq int64, limit int) ([]EventRecord, error)
 	LastSeq(ctx context.Context, runID string) (int64, error)
+	// ReadAll lists the events of every run after afterPosition, in position order. Positions
+	// increase in commit order, so a reader never sees a position lower than one it has read.
+	ReadAll(ctx context.Context, afterPosition int64, limit int) ([]EventRecord, error)
 	// GetEventByID returns the event by its stable EventID.
 	// Implementations should return sql.ErrNoRows if the event does not exist.
 	GetEventByID(ctx context.Context, eventID string) (EventRecord, error)
 }
 
+// EventSubscriber streams the events of a run as they are appended.
+type EventSubscriber interface {
+	// Subscribe yields the events of runID after afterSeq in sequence order: first those already
+	// stored, then new ones as they are committed. The sequence ends when ctx is done, or after
+	// yielding an error if the store cannot be read; callers resume from the last seq they saw.
+	Subscribe(ctx context.Context, runID string, afterSeq int64) iter.Seq2[EventRecord, error]
+}
+
 // SnapshotStore defines operations for reading/writing snapshots.
 type SnapshotStore interface {
 	SaveSnapshot(ctx context.Context, s SnapshotRecord) (SnapshotRecord, error)
 	LoadLatestSnapshot(ctx context.Context, runID string) (SnapshotRecord, error)
+	// DeleteSnapshots removes every snapshot of runID and returns how many were removed.
+	DeleteSnapshots(ctx context.Context, runID string) (int, error)
+	// PruneSnapshots removes the snapshots of runID that p does not keep and returns how many
+	// were removed.
+	PruneSnapshots(ctx context.Context, runID string, p SnapshotRetention) (int, error)
+	// InvalidateSnapshots removes the snapshots matching f, so that states are rebuilt from
+	// events, and returns how many were removed.
+	InvalidateSnapshots(ctx context.Context, f SnapshotFilter) (int, error)
+}
+
+// EventArchiver moves events that a snapshot already covers out of the live event log. Archived
+// events are still returned by ListEvents, ReadAll and GetEventByID, from colder storage.
+type EventArchiver interface {
+	// ArchiveEvents archives the events of runID up to uptoSeq, keeping the run's last event
+	// live, and returns how many were archived.
+	ArchiveEvents(ctx context.Context, runID string, uptoSeq int64) (int, error)
+}
+
+// EventQuerier searches events across runs, archived events included. Payloads encrypted at rest
+// are matched after they are decrypted.
+type EventQuerier interface {
+	// QueryEvents fails with an errmodel validation error if the query is malformed or uses a
+	// filter the store cannot apply.
+	QueryEvents(ctx context.Context, q EventQuery) (EventPage, error)
+}
+
+// KeyRotator is implemented by stores that encrypt payloads and states at rest.
+type KeyRotator interface {
+	// Reencrypt rewrites up to limit event payloads and snapshot states that are stored in
+	// plain text or under another key than the active one, and returns how many it rewrote.
+	// Calling it until it returns 0 moves every row to the active key.
+	Reencrypt(ctx context.Context, limit int) (int, error)
+}
+
+// RunEraser permanently removes runs.
+type RunEraser interface {
+	// DeleteRun atomically removes every record of runID: its events, archived or not,
+	// snapshots, outbox items, timers, dead letters, catalog entry and shredding key. Deleting
+	// a run without records succeeds and removes nothing.
+	DeleteRun(ctx context.Context, runID string) (RunDeletion, error)
+}
+
+// ShredKeyStore keeps the keys that encrypt the sensitive payload fields of each run. Shredding
+// a run's key makes those fields unreadable wherever the events were copied to.
+type ShredKeyStore interface {
+	// CreateRunKey stores key for runID unless the run has one already, and returns the run's
+	// key. It fails with an errmodel run_shredded policy error if the run's key was shredded.
+	CreateRunKey(ctx context.Context, runID string, key []byte) ([]byte, error)
+	// LoadRunKey returns sql.ErrNoRows if the run has no key, and an errmodel run_shredded
+	// policy error if it was shredded.
+	LoadRunKey(ctx context.Context, runID string) ([]byte, error)
+	// ShredRunKey destroys the key of runID and remembers that it was shredded, so that the
+	// run cannot be given a new one.
+	ShredRunKey(ctx context.Context, runID string) error
+}
+
+// AuditStore keeps the audit trail.
+type AuditStore interface {
+	AppendAudit(ctx context.Context, a AuditRecord) (AuditRecord, error)
+	// ListAudit lists entries oldest first.
+	ListAudit(ctx context.Context, f AuditFilter) (AuditPage, error)
+}
+
+// CycleCommitter writes the cycles of runs.
+type CycleCommitter interface {
+	// CommitCycle atomically appends the events (as AppendEvents does) and applies the other
+	// changes of c. It fails without writing anything if the completed item's lease is no longer
+	// held by Owner.
+	CommitCycle(ctx context.Context, c CycleCommit) ([]EventRecord, error)
+}
+
+// DeadLetterStore persists intents that exhausted their retries so they can be inspected,
+// requeued or discarded. Dead letters are recorded with the cycle that failed the intent.
+type DeadLetterStore interface {
+	CycleCommitter
+	SaveDeadLetter(ctx context.Context, d DeadLetterRecord) (DeadLetterRecord, error)
+	// GetDeadLetter returns sql.ErrNoRows if the dead letter does not exist.
+	GetDeadLetter(ctx context.Context, id string) (DeadLetterRecord, error)
+	// ListDeadLetters lists dead letters oldest first; an empty runID lists all runs.
+	ListDeadLetters(ctx context.Context, runID string, limit int) ([]DeadLetterRecord, error)
+	// DeleteDeadLetter returns sql.ErrNoRows if the dead letter does not exist.
+	DeleteDeadLetter(ctx context.Context, id string) error
+}
+
+// OutboxStore persists intents until a worker has executed them. Items are claimed under a
+// lease; an item whose lease expired can be claimed by another worker. Items are enqueued and
+// completed with the cycles of their runs.
+type OutboxStore interface {
+	CycleCommitter
+	// ClaimOutbox leases up to limit available items of queue to owner, oldest first.
+	ClaimOutbox(ctx context.Context, queue, owner string, lease time.Duration, limit int) ([]OutboxRecord, error)
+	// ExtendOutboxLease renews a held lease. It returns sql.ErrNoRows if owner no longer holds it.
+	ExtendOutboxLease(ctx context.Context, itemID, owner string, lease time.Duration) error
+	// ReleaseOutbox gives up a held lease and makes the item available again at availableAt.
+	// It returns sql.ErrNoRows if owner no longer holds the lease.
+	ReleaseOutbox(ctx context.Context, itemID, owner string, availableAt time.Time) error
+	// WakeOutbox makes the unleased items of a run that are available after availableAt
+	// available at availableAt, and returns how many it changed.
+	WakeOutbox(ctx context.Context, runID string, availableAt time.Time) (int, error)
+}
+
+// TimerStore persists timers until their event has been delivered. Timers are scheduled with the
+// cycles of their runs.
+type TimerStore interface {
+	CycleCommitter
+	SaveTimer(ctx context.Context, t TimerRecord) (TimerRecord, error)
+	// DueTimers lists timers of queue due at or before now, earliest first, leaving out those
+	// whose AvailableAt is after now.
+	DueTimers(ctx context.Context, queue string, now time.Time, limit int) ([]TimerRecord, error)
+	// RetryTimer records a failed delivery and delays the next one until availableAt. It
+	// returns sql.ErrNoRows if the timer does not exist.
+	RetryTimer(ctx context.Context, timerID string, availableAt time.Time) error
+	// DeleteTimer returns sql.ErrNoRows if the timer does not exist.
+	DeleteTimer(ctx context.Context, timerID string) error
+}
+
+// RunStore keeps the catalog of runs.
+type RunStore interface {
+	// CreateRun registers a run. It fails with an errmodel conflict error if the run exists.
+	CreateRun(ctx context.Context, r RunRecord) (RunRecord, error)
+	// RecordRunProgress sets the status, last sequence and update time of a run, registering it
+	// with r's other fields if it does not exist. Updates older than the recorded LastSeq are
+	// ignored.
+	RecordRunProgress(ctx context.Context, r RunRecord) error
+	// GetRun returns sql.ErrNoRows if the run does not exist.
+	GetRun(ctx context.Context, runID string) (RunRecord, error)
+	ListRuns(ctx context.Context, f RunFilter) (RunPage, error)
+}
+
+// CheckpointStore persists how far named readers of the global event feed have read.
+type CheckpointStore interface {
+	// LoadCheckpoint returns 0 for a reader without a checkpoint.
+	LoadCheckpoint(ctx context.Context, name string) (int64, error)
+	SaveCheckpoint(ctx context.Context, name string, position int64) error
 }
 
 // Store aggregates event and snapshot stores.
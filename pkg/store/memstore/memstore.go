@@ -0,0 +1,332 @@
+// Package memstore provides an in-memory implementation of store.Store with the same semantics
+// as the database-backed stores. It is meant for tests and examples; nothing is persisted.
+package memstore
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// Store implements store.Store in memory. It is safe for concurrent use.
+type Store struct {
+	mu sync.Mutex
+	// events holds every event in position order; position p is events[p-1]. Events of deleted
+	// runs are left as zero records.
+	events    []store.EventRecord
+	runs      map[string][]int
+	byID      map[string]int
+	snapshots map[string][]store.SnapshotRecord
+}
+
+var (
+	_ store.Store        = (*Store)(nil)
+	_ store.RunEraser    = (*Store)(nil)
+	_ store.EventQuerier = (*Store)(nil)
+)
+
+// New returns an empty Store.
+func New() *Store {
+	return &Store{
+		runs:      map[string][]int{},
+		byID:      map[string]int{},
+		snapshots: map[string][]store.SnapshotRecord{},
+	}
+}
+
+// AppendEvent appends a new event with an incremented sequence per run.
+// A non-zero e.Seq is checked against the next sequence (see AppendEvents).
+func (s *Store) AppendEvent(ctx context.Context, e store.EventRecord) (store.EventRecord, error) {
+	expected := store.AnySeq
+	if e.Seq > 0 {
+		expected = e.Seq - 1
+	}
+	out, err := s.AppendEvents(ctx, e.RunID, expected, []store.EventRecord{e})
+	if err != nil {
+		return store.EventRecord{}, err
+	}
+	return out[0], nil
+}
+
+// AppendEvents appends events to a run atomically. A last sequence other than expectedSeq
+// yields an errmodel conflict error unless every record already exists (a replayed batch).
+func (s *Store) AppendEvents(ctx context.Context, runID string, expectedSeq int64, events []store.EventRecord) ([]store.EventRecord, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lastSeq := int64(len(s.runs[runID]))
+	if expectedSeq != store.AnySeq && expectedSeq != lastSeq {
+		out := make([]store.EventRecord, 0, len(events))
+		for _, e := range events {
+			i, ok := s.byID[e.EventID]
+			if !ok {
+				return nil, errmodel.Conflict("run sequence moved", map[string]any{
+					"run_id":       runID,
+					"expected_seq": expectedSeq,
+					"observed_seq": lastSeq,
+				})
+			}
+			out = append(out, clone(s.events[i]))
+		}
+		return out, nil
+	}
+
+	// Validate the batch before writing anything.
+	for _, e := range events {
+		if _, ok := s.byID[e.EventID]; !ok && len(e.Payload) > 0 && !json.Valid(e.Payload) {
+			return nil, fmt.Errorf("invalid payload json")
+		}
+	}
+	out := make([]store.EventRecord, 0, len(events))
+	for _, e := range events {
+		// Duplicate event_id returns the existing record (idempotent append).
+		if i, ok := s.byID[e.EventID]; ok {
+			out = append(out, clone(s.events[i]))
+			continue
+		}
+		rec := store.EventRecord{
+			EventID:   e.EventID,
+			RunID:     runID,
+			Seq:       int64(len(s.runs[runID])) + 1,
+			Position:  int64(len(s.events)) + 1,
+			Type:      e.Type,
+			Version:   max(e.Version, 1),
+			CreatedAt: time.Now(),
+		}
+		if len(e.Payload) > 0 {
+			rec.Payload = slices.Clone(e.Payload)
+		}
+		s.byID[rec.EventID] = len(s.events)
+		s.runs[runID] = append(s.runs[runID], len(s.events))
+		s.events = append(s.events, rec)
+		out = append(out, clone(rec))
+	}
+	return out, nil
+}
+
+// ListEvents lists events for a run after a given sequence.
+func (s *Store) ListEvents(ctx context.Context, runID string, afterSeq int64, limit int) ([]store.EventRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx := s.runs[runID]
+	// Sequences start at 1 without gaps.
+	idx = idx[min(max(afterSeq, 0), int64(len(idx))):]
+	if limit > 0 && len(idx) > limit {
+		idx = idx[:limit]
+	}
+	out := make([]store.EventRecord, 0, len(idx))
+	for _, i := range idx {
+		out = append(out, clone(s.events[i]))
+	}
+	return out, nil
+}
+
+// LastSeq returns the last sequence for a run, or 0 if it has no events.
+func (s *Store) LastSeq(ctx context.Context, runID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.runs[runID])), nil
+}
+
+// ReadAll lists events of every run after a global position, in position order.
+func (s *Store) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]store.EventRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []store.EventRecord
+	for _, e := range s.events[min(max(afterPosition, 0), int64(len(s.events))):] {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		if e.EventID != "" {
+			out = append(out, clone(e))
+		}
+	}
+	return out, nil
+}
+
+// QueryEvents searches events in position order. Without a run catalog, it cannot filter by
+// labels.
+func (s *Store) QueryEvents(ctx context.Context, q store.EventQuery) (store.EventPage, error) {
+	if err := q.Validate(); err != nil {
+		return store.EventPage{}, err
+	}
+	after, err := store.ParseEventCursor(q.Cursor)
+	if err != nil {
+		return store.EventPage{}, err
+	}
+	if len(q.Labels) > 0 {
+		return store.EventPage{}, errmodel.Validation("unsupported_filter", "memstore cannot filter events by run labels", nil)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var page store.EventPage
+	for _, e := range s.events[min(after, int64(len(s.events))):] {
+		switch {
+		case e.EventID == "",
+			q.RunID != "" && e.RunID != q.RunID,
+			len(q.Types) > 0 && !slices.Contains(q.Types, e.Type),
+			!q.Since.IsZero() && e.CreatedAt.Before(q.Since),
+			!q.Until.IsZero() && !e.CreatedAt.Before(q.Until),
+			!q.MatchPayload(e.Payload):
+			continue
+		}
+		if q.Limit > 0 && len(page.Events) == q.Limit {
+			page.NextCursor = fmt.Sprint(page.Events[q.Limit-1].Position)
+			break
+		}
+		page.Events = append(page.Events, clone(e))
+	}
+	return page, nil
+}
+
+// GetEventByID looks up an event by its stable EventID.
+func (s *Store) GetEventByID(ctx context.Context, eventID string) (store.EventRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.byID[eventID]
+	if !ok {
+		return store.EventRecord{}, sql.ErrNoRows
+	}
+	return clone(s.events[i]), nil
+}
+
+// DeleteRun removes the events and snapshots of a run.
+func (s *Store) DeleteRun(ctx context.Context, runID string) (store.RunDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := store.RunDeletion{Events: len(s.runs[runID]), Snapshots: len(s.snapshots[runID])}
+	for _, i := range s.runs[runID] {
+		delete(s.byID, s.events[i].EventID)
+		s.events[i] = store.EventRecord{}
+	}
+	delete(s.runs, runID)
+	delete(s.snapshots, runID)
+	return d, nil
+}
+
+// SaveSnapshot saves a snapshot; unique per (run_id, upto_seq) and per snapshot ID.
+func (s *Store) SaveSnapshot(ctx context.Context, sn store.SnapshotRecord) (store.SnapshotRecord, error) {
+	if len(sn.State) > 0 && !json.Valid(sn.State) {
+		return store.SnapshotRecord{}, fmt.Errorf("invalid state json")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, snaps := range s.snapshots {
+		for _, o := range snaps {
+			if o.SnapshotID == sn.SnapshotID || (o.RunID == sn.RunID && o.UptoSeq == sn.UptoSeq) {
+				return store.SnapshotRecord{}, errmodel.Conflict("snapshot exists", map[string]any{
+					"snapshot_id": sn.SnapshotID,
+					"run_id":      sn.RunID,
+					"upto_seq":    sn.UptoSeq,
+				})
+			}
+		}
+	}
+	rec := sn
+	rec.State = nil
+	if len(sn.State) > 0 {
+		rec.State = slices.Clone(sn.State)
+	}
+	rec.CreatedAt = time.Now()
+	snaps := append(s.snapshots[sn.RunID], rec)
+	// Kept in upto_seq order, newest last.
+	slices.SortFunc(snaps, func(a, b store.SnapshotRecord) int { return cmp.Compare(a.UptoSeq, b.UptoSeq) })
+	s.snapshots[sn.RunID] = snaps
+	return cloneSnapshot(rec), nil
+}
+
+// LoadLatestSnapshot loads the latest snapshot for the run.
+func (s *Store) LoadLatestSnapshot(ctx context.Context, runID string) (store.SnapshotRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snaps := s.snapshots[runID]
+	if len(snaps) == 0 {
+		return store.SnapshotRecord{}, sql.ErrNoRows
+	}
+	return cloneSnapshot(snaps[len(snaps)-1]), nil
+}
+
+// DeleteSnapshots deletes every snapshot of the run.
+func (s *Store) DeleteSnapshots(ctx context.Context, runID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.snapshots[runID])
+	delete(s.snapshots, runID)
+	return n, nil
+}
+
+// PruneSnapshots deletes the snapshots of the run that the retention policy does not keep.
+func (s *Store) PruneSnapshots(ctx context.Context, runID string, p store.SnapshotRetention) (int, error) {
+	if p.KeepLast <= 0 && p.MaxAge <= 0 {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snaps := s.snapshots[runID]
+	cutoff := time.Now().Add(-p.MaxAge)
+	kept := make([]store.SnapshotRecord, 0, len(snaps))
+	for i, sn := range snaps {
+		// rank 1 is the latest snapshot, which is always kept.
+		rank := len(snaps) - i
+		if rank > 1 && ((p.KeepLast > 0 && rank > p.KeepLast) || (p.MaxAge > 0 && sn.CreatedAt.Before(cutoff))) {
+			continue
+		}
+		kept = append(kept, sn)
+	}
+	s.setSnapshots(runID, kept)
+	return len(snaps) - len(kept), nil
+}
+
+// InvalidateSnapshots deletes the snapshots of a run, of a version, or of a version within a run.
+func (s *Store) InvalidateSnapshots(ctx context.Context, f store.SnapshotFilter) (int, error) {
+	if f.RunID == "" && f.Version == "" {
+		return 0, errmodel.Validation("missing_filter", "run ID or version required", nil)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for runID, snaps := range s.snapshots {
+		if f.RunID != "" && runID != f.RunID {
+			continue
+		}
+		kept := slices.DeleteFunc(snaps, func(sn store.SnapshotRecord) bool {
+			return f.Version == "" || sn.Version == f.Version
+		})
+		n += len(snaps) - len(kept)
+		s.setSnapshots(runID, kept)
+	}
+	return n, nil
+}
+
+func (s *Store) setSnapshots(runID string, snaps []store.SnapshotRecord) {
+	if len(snaps) == 0 {
+		delete(s.snapshots, runID)
+		return
+	}
+	s.snapshots[runID] = snaps
+}
+
+// clone copies e so that callers cannot modify the stored payload.
+func clone(e store.EventRecord) store.EventRecord {
+	if e.Payload != nil {
+		e.Payload = slices.Clone(e.Payload)
+	}
+	return e
+}
+
+func cloneSnapshot(sn store.SnapshotRecord) store.SnapshotRecord {
+	if sn.State != nil {
+		sn.State = slices.Clone(sn.State)
+	}
+	return sn
+}
@@ -0,0 +1,12 @@
+package memstore
+
+import (
+	"testing"
+
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store { return New() })
+}
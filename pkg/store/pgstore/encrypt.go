@@ -0,0 +1,111 @@
+package pgstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// stored is the form in which a payload or state is written.
+type stored struct {
+	// doc is the JSONB document, nil when encrypted.
+	doc *string
+	// raw is the text returned by reads: the original or its ciphertext.
+	raw   *string
+	keyID string
+}
+
+// seal prepares a payload or state for storage, encrypting it bound to the ID of its row.
+func (s *Store) seal(ctx context.Context, text []byte, rowID string) (stored, error) {
+	if len(text) == 0 {
+		return stored{}, nil
+	}
+	if s.cipher == nil {
+		v := string(text)
+		return stored{doc: &v, raw: &v}, nil
+	}
+	keyID, ct, err := s.cipher.Encrypt(ctx, text, []byte(rowID))
+	if err != nil {
+		return stored{}, err
+	}
+	v := string(ct)
+	return stored{raw: &v, keyID: keyID}, nil
+}
+
+// open returns the JSON stored for a row, decrypting it if it was stored under a key.
+func (s *Store) open(ctx context.Context, keyID string, text json.RawMessage, rowID string) (json.RawMessage, error) {
+	if keyID == "" || len(text) == 0 {
+		return text, nil
+	}
+	if s.cipher == nil {
+		return nil, fmt.Errorf("pgstore: %q is encrypted under key %q but no key provider is configured", rowID, keyID)
+	}
+	plain, err := s.cipher.Decrypt(ctx, keyID, text, []byte(rowID))
+	if err != nil {
+		return nil, fmt.Errorf("pgstore: %q: %w", rowID, err)
+	}
+	return plain, nil
+}
+
+// Reencrypt rewrites up to limit payloads and states that are in plain text or encrypted under
+// a key other than the active one, so that retired keys can be removed from the key provider.
+func (s *Store) Reencrypt(ctx context.Context, limit int) (int, error) {
+	if s.cipher == nil {
+		return 0, fmt.Errorf("pgstore: encryption is not configured")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	active := s.cipher.KeyID()
+	n, err := s.reencrypt(ctx, limit, active,
+		`SELECT event_id, key_id, payload_raw FROM orch_events WHERE key_id <> $1 AND payload_raw IS NOT NULL LIMIT $2`,
+		`UPDATE orch_events SET payload = NULL, payload_raw = $1, key_id = $2
+		WHERE event_id = $3 AND key_id = $4 AND run_id = (SELECT run_id FROM orch_event_ids WHERE event_id = $3)`)
+	if err != nil || n == limit {
+		return n, err
+	}
+	m, err := s.reencrypt(ctx, limit-n, active,
+		`SELECT snapshot_id, key_id, state_raw FROM orch_snapshots WHERE key_id <> $1 AND state_raw IS NOT NULL LIMIT $2`,
+		`UPDATE orch_snapshots SET state = NULL, state_raw = $1, key_id = $2 WHERE snapshot_id = $3 AND key_id = $4`)
+	return n + m, err
+}
+
+// reencrypt rewrites the rows that query selects with update. A row changed concurrently is
+// left to a later call.
+func (s *Store) reencrypt(ctx context.Context, limit int, active, query, update string) (int, error) {
+	type row struct {
+		id, keyID string
+		text      []byte
+	}
+	rows, err := s.pool.Query(ctx, query, active, limit)
+	if err != nil {
+		return 0, err
+	}
+	var todo []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.keyID, &r.text); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		todo = append(todo, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for i, r := range todo {
+		plain, err := s.open(ctx, r.keyID, r.text, r.id)
+		if err != nil {
+			return i, err
+		}
+		p, err := s.seal(ctx, plain, r.id)
+		if err != nil {
+			return i, err
+		}
+		if _, err := s.pool.Exec(ctx, update, p.raw, p.keyID, r.id, r.keyID); err != nil {
+			return i, err
+		}
+	}
+	return len(todo), nil
+}
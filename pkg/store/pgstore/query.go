@@ -0,0 +1,157 @@
+package pgstore
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+var _ store.EventQuerier = (*Store)(nil)
+
+// QueryEvents searches events in position order, matching payloads with JSONB operators.
+// Encrypted payloads have no JSONB copy and are matched once decrypted, which reads every
+// encrypted event that passes the other filters. The store keeps no run catalog, so it cannot
+// filter by labels.
+func (s *Store) QueryEvents(ctx context.Context, q store.EventQuery) (store.EventPage, error) {
+	if err := q.Validate(); err != nil {
+		return store.EventPage{}, err
+	}
+	after, err := store.ParseEventCursor(q.Cursor)
+	if err != nil {
+		return store.EventPage{}, err
+	}
+	if len(q.Labels) > 0 {
+		return store.EventPage{}, errmodel.Validation("unsupported_filter", "pgstore cannot filter events by run labels", nil)
+	}
+	// Fetch one more event to tell whether there is a next page.
+	batch := 0
+	if q.Limit > 0 {
+		batch = q.Limit + 1
+	}
+	// $1 and $2 are the position and limit of each batch.
+	args := queryArgs{nil, nil}
+	where := eventFilter(q, &args)
+	var page store.EventPage
+	for {
+		args[0], args[1] = after, limitOrAll(batch)
+		rows, err := s.pool.Query(ctx, `SELECT `+eventColumns+` FROM orch_events
+			WHERE position > $1`+where+` ORDER BY position LIMIT $2`, args...)
+		if err != nil {
+			return store.EventPage{}, err
+		}
+		type found struct {
+			rec   store.EventRecord
+			keyID string
+		}
+		events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (found, error) {
+			e, keyID, err := scanEvent(row)
+			return found{e, keyID}, err
+		})
+		if err != nil {
+			return store.EventPage{}, err
+		}
+		for _, f := range events {
+			after = f.rec.Position
+			f.rec.Payload, err = s.open(ctx, f.keyID, f.rec.Payload, f.rec.EventID)
+			if err != nil {
+				return store.EventPage{}, err
+			}
+			if f.keyID != "" && !q.MatchPayload(f.rec.Payload) {
+				continue
+			}
+			page.Events = append(page.Events, f.rec)
+		}
+		if batch == 0 || len(events) < batch || len(page.Events) > q.Limit {
+			break
+		}
+	}
+	if q.Limit > 0 && len(page.Events) > q.Limit {
+		page.Events = page.Events[:q.Limit]
+		page.NextCursor = strconv.FormatInt(page.Events[q.Limit-1].Position, 10)
+	}
+	return page, nil
+}
+
+// queryArgs collects the arguments of a query.
+type queryArgs []any
+
+// add appends an argument and returns its placeholder.
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// eventFilter returns the conditions of q on orch_events, each preceded by AND.
+func eventFilter(q store.EventQuery, args *queryArgs) string {
+	var b strings.Builder
+	if q.RunID != "" {
+		b.WriteString(" AND run_id = " + args.add(q.RunID))
+	}
+	if len(q.Types) > 0 {
+		b.WriteString(" AND type = ANY(" + args.add(q.Types) + ")")
+	}
+	if !q.Since.IsZero() {
+		b.WriteString(" AND created_at >= " + args.add(q.Since))
+	}
+	if !q.Until.IsZero() {
+		b.WriteString(" AND created_at < " + args.add(q.Until))
+	}
+	if q.MatchesPayload() {
+		var match []string
+		for _, p := range q.Where {
+			match = append(match, "("+payloadPredicate(p, args)+")")
+		}
+		if q.Text != "" {
+			pattern := "%" + likeEscaper.Replace(q.Text) + "%"
+			match = append(match, `payload_raw ILIKE `+args.add(pattern)+` ESCAPE '\'`)
+		}
+		// Encrypted payloads are matched once decrypted.
+		b.WriteString(" AND (key_id <> '' OR (" + strings.Join(match, " AND ") + "))")
+	}
+	return b.String()
+}
+
+// payloadPredicate returns the condition of a valid predicate on the payload column. Values of
+// different JSON types never compare equal, and ordering operators are restricted to values of
+// the predicate's type.
+func payloadPredicate(p store.PayloadPredicate, args *queryArgs) string {
+	path, _ := store.ParsePayloadPath(p.Path)
+	// Walking the path element by element keeps a numeric key such as "0" apart from an array index.
+	value := "(payload"
+	for _, e := range path {
+		if e.Key == "" {
+			value += " -> " + strconv.Itoa(e.Index)
+			continue
+		}
+		value += " -> " + args.add(e.Key) + "::text"
+	}
+	value += ")"
+	switch p.Op {
+	case store.PayloadExists:
+		return value + " IS NOT NULL"
+	case store.PayloadEQ:
+		return value + " = " + args.add(string(p.Value)) + "::jsonb"
+	case store.PayloadNE:
+		return value + " <> " + args.add(string(p.Value)) + "::jsonb"
+	case store.PayloadContains:
+		return value + " @> " + args.add(string(p.Value)) + "::jsonb"
+	}
+	typ := "number"
+	if v, _ := p.DecodeValue(); isString(v) {
+		typ = "string"
+	}
+	op := map[store.PayloadOp]string{store.PayloadGT: ">", store.PayloadGTE: ">=", store.PayloadLT: "<", store.PayloadLTE: "<="}[p.Op]
+	return "jsonb_typeof" + value + " = " + args.add(typ) + " AND " + value + " " + op + " " + args.add(string(p.Value)) + "::jsonb"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
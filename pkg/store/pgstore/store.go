@@ -0,0 +1,482 @@
+// Package pgstore implements store.Store for PostgreSQL on pgx, without ent. Events live in a
+// table hash-partitioned by run, payloads and states are JSONB for querying and indexing, and
+// per-run sequences are assigned by the INSERT itself under the (run_id, seq) primary key, with
+// appends serialized so that global positions follow commit order.
+//
+// JSONB normalizes documents, so the text received is stored next to it and returned as is, as
+// the store contract requires. With WithEncryption, only the encrypted text is stored.
+package pgstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/envelope"
+)
+
+// Store implements store.Store on a pgx connection pool.
+type Store struct {
+	pool       *pgxpool.Pool
+	partitions int
+	// cipher encrypts payloads and states at rest; nil stores them in plain text.
+	cipher *envelope.Cipher
+}
+
+var (
+	_ store.Store      = (*Store)(nil)
+	_ store.RunEraser  = (*Store)(nil)
+	_ store.KeyRotator = (*Store)(nil)
+)
+
+// Option configures the Store at construction time.
+type Option func(*Store)
+
+// WithPartitions sets how many hash partitions Migrate creates for the events table. Defaults
+// to 16. It only applies when the table is created.
+func WithPartitions(n int) Option {
+	return func(s *Store) {
+		if n > 0 {
+			s.partitions = n
+		}
+	}
+}
+
+// WithEncryption encrypts event payloads and snapshot states at rest with data keys wrapped by
+// p, recording the master key ID of each row. Reads decrypt them transparently, and rows
+// written without encryption are read as they are. Encrypted rows have no JSONB document, so
+// they cannot be queried or indexed.
+func WithEncryption(p envelope.KeyProvider) Option {
+	return func(s *Store) { s.cipher = envelope.NewCipher(p) }
+}
+
+// Open connects to the database at dsn, a postgres:// URL or keyword/value DSN.
+func Open(ctx context.Context, dsn string, opts ...Option) (*Store, error) {
+	if dsn == "" {
+		return nil, errors.New("dsn is empty")
+	}
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping db: %w", err)
+	}
+	s := &Store{pool: pool, partitions: 16}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Close closes the connection pool.
+func (s *Store) Close() error {
+	s.pool.Close()
+	return nil
+}
+
+// Migrate creates the tables if they do not exist.
+func (s *Store) Migrate(ctx context.Context) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	// Serialize concurrent migrations; CREATE ... IF NOT EXISTS races otherwise.
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrateLockKey); err != nil {
+		return err
+	}
+	for _, stmt := range schema {
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+	for i := range s.partitions {
+		stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS orch_events_p%d PARTITION OF orch_events
+			FOR VALUES WITH (MODULUS %d, REMAINDER %d)`, i, s.partitions, i)
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+const (
+	// appendLockKey orders appends so that positions follow commit order.
+	appendLockKey int64 = 0x6f72636870677374
+	// migrateLockKey serializes Migrate.
+	migrateLockKey int64 = 0x6f7263686d696772
+)
+
+var schema = []string{
+	`CREATE SEQUENCE IF NOT EXISTS orch_event_positions`,
+	// Partitioned tables cannot enforce uniqueness on columns outside the partition key, so
+	// event IDs are kept unique in their own table, which also locates events by ID.
+	`CREATE TABLE IF NOT EXISTS orch_events (
+		position    BIGINT NOT NULL DEFAULT nextval('orch_event_positions'),
+		event_id    TEXT NOT NULL,
+		run_id      TEXT NOT NULL,
+		seq         BIGINT NOT NULL CHECK (seq > 0),
+		type        TEXT NOT NULL,
+		version     INT NOT NULL DEFAULT 1,
+		payload     JSONB,
+		payload_raw TEXT,
+		key_id      TEXT NOT NULL DEFAULT '',
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (run_id, seq)
+	) PARTITION BY HASH (run_id)`,
+	`CREATE INDEX IF NOT EXISTS orch_events_position ON orch_events (position)`,
+	`CREATE INDEX IF NOT EXISTS orch_events_payload ON orch_events USING GIN (payload jsonb_path_ops)`,
+	`CREATE TABLE IF NOT EXISTS orch_event_ids (
+		event_id TEXT PRIMARY KEY,
+		run_id   TEXT NOT NULL,
+		seq      BIGINT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS orch_snapshots (
+		snapshot_id TEXT PRIMARY KEY,
+		run_id      TEXT NOT NULL,
+		upto_seq    BIGINT NOT NULL CHECK (upto_seq >= 0),
+		state       JSONB,
+		state_raw   TEXT,
+		key_id      TEXT NOT NULL DEFAULT '',
+		version     TEXT NOT NULL DEFAULT '',
+		hash        TEXT NOT NULL DEFAULT '',
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		UNIQUE (run_id, upto_seq)
+	)`,
+	`CREATE INDEX IF NOT EXISTS orch_snapshots_version ON orch_snapshots (version)`,
+	// Tables created before encryption at rest.
+	`ALTER TABLE orch_events ADD COLUMN IF NOT EXISTS key_id TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE orch_snapshots ADD COLUMN IF NOT EXISTS key_id TEXT NOT NULL DEFAULT ''`,
+}
+
+const eventColumns = `position, event_id, run_id, seq, type, version, payload_raw, key_id, created_at`
+
+// AppendEvent appends a new event with the next sequence of its run. A non-zero e.Seq is
+// checked against the next sequence (see AppendEvents).
+func (s *Store) AppendEvent(ctx context.Context, e store.EventRecord) (store.EventRecord, error) {
+	expected := store.AnySeq
+	if e.Seq > 0 {
+		expected = e.Seq - 1
+	}
+	out, err := s.AppendEvents(ctx, e.RunID, expected, []store.EventRecord{e})
+	if err != nil {
+		return store.EventRecord{}, err
+	}
+	return out[0], nil
+}
+
+// AppendEvents appends events to a run in one transaction. Appends of every run are serialized
+// by an advisory lock, so that global positions follow commit order and ReadAll never skips an
+// event committed after a later position; each INSERT then takes the run's next sequence. A
+// sequence other than the expected one is a conflict unless every record already exists.
+func (s *Store) AppendEvents(ctx context.Context, runID string, expectedSeq int64, events []store.EventRecord) ([]store.EventRecord, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	payloads := make([]stored, len(events))
+	for i, e := range events {
+		if len(e.Payload) > 0 && !json.Valid(e.Payload) {
+			return nil, fmt.Errorf("invalid payload json")
+		}
+		p, err := s.seal(ctx, e.Payload, e.EventID)
+		if err != nil {
+			return nil, err
+		}
+		payloads[i] = p
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", appendLockKey); err != nil {
+		return nil, err
+	}
+	if expectedSeq != store.AnySeq {
+		var last int64
+		if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(seq), 0) FROM orch_events WHERE run_id = $1`, runID).Scan(&last); err != nil {
+			return nil, err
+		}
+		if last != expectedSeq {
+			return s.existingOrConflict(ctx, tx, runID, events, expectedSeq, last)
+		}
+	}
+	out := make([]store.EventRecord, 0, len(events))
+	for i, e := range events {
+		existing, err := s.getEvent(ctx, tx, e.EventID)
+		if err == nil {
+			out = append(out, existing)
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+		version := e.Version
+		if version <= 0 {
+			version = 1
+		}
+		p := payloads[i]
+		row := tx.QueryRow(ctx, `INSERT INTO orch_events (event_id, run_id, seq, type, version, payload, payload_raw, key_id, created_at)
+			SELECT $1, $2, COALESCE(MAX(seq), 0) + 1, $3, $4, $5::text::jsonb, $6, $7, now()
+			FROM orch_events WHERE run_id = $2
+			RETURNING `+eventColumns, e.EventID, runID, e.Type, version, p.doc, p.raw, p.keyID)
+		rec, _, err := scanEvent(row)
+		if err != nil {
+			return nil, err
+		}
+		// The caller's payload is what was sealed.
+		rec.Payload = nil
+		if len(e.Payload) > 0 {
+			rec.Payload = json.RawMessage(e.Payload)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO orch_event_ids (event_id, run_id, seq) VALUES ($1, $2, $3)`, rec.EventID, runID, rec.Seq); err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, tx.Commit(ctx)
+}
+
+// existingOrConflict returns the stored records if every event of a batch already exists,
+// otherwise a conflict error.
+func (s *Store) existingOrConflict(ctx context.Context, q querier, runID string, events []store.EventRecord, expectedSeq, observedSeq int64) ([]store.EventRecord, error) {
+	out := make([]store.EventRecord, 0, len(events))
+	for _, e := range events {
+		existing, err := s.getEvent(ctx, q, e.EventID)
+		if err == sql.ErrNoRows {
+			return nil, errmodel.Conflict("run sequence moved", map[string]any{
+				"run_id":       runID,
+				"expected_seq": expectedSeq,
+				"observed_seq": observedSeq,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, existing)
+	}
+	return out, nil
+}
+
+// ListEvents lists events for a run after a given sequence.
+func (s *Store) ListEvents(ctx context.Context, runID string, afterSeq int64, limit int) ([]store.EventRecord, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+eventColumns+` FROM orch_events
+		WHERE run_id = $1 AND seq > $2 ORDER BY seq LIMIT $3`, runID, afterSeq, limitOrAll(limit))
+	if err != nil {
+		return nil, err
+	}
+	return s.collectEvents(ctx, rows)
+}
+
+// ReadAll lists events of every run after a global position, in position order.
+func (s *Store) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]store.EventRecord, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+eventColumns+` FROM orch_events
+		WHERE position > $1 ORDER BY position LIMIT $2`, afterPosition, limitOrAll(limit))
+	if err != nil {
+		return nil, err
+	}
+	return s.collectEvents(ctx, rows)
+}
+
+// LastSeq returns the last sequence for a run, or 0 if it has no events.
+func (s *Store) LastSeq(ctx context.Context, runID string) (int64, error) {
+	var seq int64
+	err := s.pool.QueryRow(ctx, `SELECT COALESCE(MAX(seq), 0) FROM orch_events WHERE run_id = $1`, runID).Scan(&seq)
+	return seq, err
+}
+
+// GetEventByID looks up an event by its stable EventID.
+func (s *Store) GetEventByID(ctx context.Context, eventID string) (store.EventRecord, error) {
+	return s.getEvent(ctx, s.pool, eventID)
+}
+
+// querier is implemented by the pool and transactions.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (s *Store) getEvent(ctx context.Context, q querier, eventID string) (store.EventRecord, error) {
+	// Joining on run_id lets the planner prune the other partitions.
+	rec, keyID, err := scanEvent(q.QueryRow(ctx, `SELECT e.position, e.event_id, e.run_id, e.seq, e.type, e.version, e.payload_raw, e.key_id, e.created_at
+		FROM orch_event_ids i
+		JOIN orch_events e ON e.run_id = i.run_id AND e.seq = i.seq
+		WHERE i.event_id = $1`, eventID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return store.EventRecord{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return store.EventRecord{}, err
+	}
+	rec.Payload, err = s.open(ctx, keyID, rec.Payload, rec.EventID)
+	return rec, err
+}
+
+// DeleteRun removes the events and snapshots of a run in one transaction.
+func (s *Store) DeleteRun(ctx context.Context, runID string) (store.RunDeletion, error) {
+	var d store.RunDeletion
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return d, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	// Serialize with appends, whose duplicate check reads the event IDs.
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", appendLockKey); err != nil {
+		return d, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM orch_event_ids WHERE run_id = $1`, runID); err != nil {
+		return d, err
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM orch_events WHERE run_id = $1`, runID)
+	if err != nil {
+		return d, err
+	}
+	d.Events = int(tag.RowsAffected())
+	if tag, err = tx.Exec(ctx, `DELETE FROM orch_snapshots WHERE run_id = $1`, runID); err != nil {
+		return d, err
+	}
+	d.Snapshots = int(tag.RowsAffected())
+	return d, tx.Commit(ctx)
+}
+
+// SaveSnapshot saves a snapshot; unique per (run_id, upto_seq).
+func (s *Store) SaveSnapshot(ctx context.Context, sn store.SnapshotRecord) (store.SnapshotRecord, error) {
+	if len(sn.State) > 0 && !json.Valid(sn.State) {
+		return store.SnapshotRecord{}, fmt.Errorf("invalid state json")
+	}
+	p, err := s.seal(ctx, sn.State, sn.SnapshotID)
+	if err != nil {
+		return store.SnapshotRecord{}, err
+	}
+	rec, _, err := scanSnapshot(s.pool.QueryRow(ctx, `INSERT INTO orch_snapshots (snapshot_id, run_id, upto_seq, state, state_raw, key_id, version, hash, created_at)
+		VALUES ($1, $2, $3, $4::text::jsonb, $5, $6, $7, $8, now())
+		RETURNING `+snapshotColumns, sn.SnapshotID, sn.RunID, sn.UptoSeq, p.doc, p.raw, p.keyID, sn.Version, sn.Hash))
+	if err != nil {
+		return store.SnapshotRecord{}, err
+	}
+	rec.State = nil
+	if len(sn.State) > 0 {
+		rec.State = json.RawMessage(sn.State)
+	}
+	return rec, nil
+}
+
+const snapshotColumns = `snapshot_id, run_id, upto_seq, state_raw, key_id, version, hash, created_at`
+
+// LoadLatestSnapshot loads the latest snapshot for the run.
+func (s *Store) LoadLatestSnapshot(ctx context.Context, runID string) (store.SnapshotRecord, error) {
+	sn, keyID, err := scanSnapshot(s.pool.QueryRow(ctx, `SELECT `+snapshotColumns+` FROM orch_snapshots
+		WHERE run_id = $1 ORDER BY upto_seq DESC LIMIT 1`, runID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return store.SnapshotRecord{}, sql.ErrNoRows
+	}
+	if err != nil {
+		return store.SnapshotRecord{}, err
+	}
+	sn.State, err = s.open(ctx, keyID, sn.State, sn.SnapshotID)
+	return sn, err
+}
+
+// DeleteSnapshots deletes every snapshot of the run.
+func (s *Store) DeleteSnapshots(ctx context.Context, runID string) (int, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM orch_snapshots WHERE run_id = $1`, runID)
+	return int(tag.RowsAffected()), err
+}
+
+// PruneSnapshots deletes the snapshots of the run that the retention policy does not keep.
+func (s *Store) PruneSnapshots(ctx context.Context, runID string, p store.SnapshotRetention) (int, error) {
+	if p.KeepLast <= 0 && p.MaxAge <= 0 {
+		return 0, nil
+	}
+	// The latest snapshot (rank 1) is always kept.
+	tag, err := s.pool.Exec(ctx, `DELETE FROM orch_snapshots WHERE snapshot_id IN (
+		SELECT snapshot_id FROM (
+			SELECT snapshot_id, created_at, row_number() OVER (ORDER BY upto_seq DESC) AS rank
+			FROM orch_snapshots WHERE run_id = $1
+		) ranked
+		WHERE rank > 1 AND (($2 > 0 AND rank > $2) OR ($3::timestamptz IS NOT NULL AND created_at < $3))
+	)`, runID, p.KeepLast, cutoff(p.MaxAge))
+	return int(tag.RowsAffected()), err
+}
+
+// InvalidateSnapshots deletes the snapshots of a run, of a version, or of a version within a run.
+func (s *Store) InvalidateSnapshots(ctx context.Context, f store.SnapshotFilter) (int, error) {
+	if f.RunID == "" && f.Version == "" {
+		return 0, errmodel.Validation("missing_filter", "run ID or version required", nil)
+	}
+	tag, err := s.pool.Exec(ctx, `DELETE FROM orch_snapshots
+		WHERE ($1 = '' OR run_id = $1) AND ($2 = '' OR version = $2)`, f.RunID, f.Version)
+	return int(tag.RowsAffected()), err
+}
+
+// scanEvent scans a row of eventColumns. The payload is returned as stored, with the ID of the
+// key it is encrypted under.
+func scanEvent(row pgx.Row) (store.EventRecord, string, error) {
+	var (
+		e       store.EventRecord
+		payload *string
+		keyID   string
+	)
+	if err := row.Scan(&e.Position, &e.EventID, &e.RunID, &e.Seq, &e.Type, &e.Version, &payload, &keyID, &e.CreatedAt); err != nil {
+		return store.EventRecord{}, "", err
+	}
+	if payload != nil {
+		e.Payload = json.RawMessage(*payload)
+	}
+	return e, keyID, nil
+}
+
+// collectEvents scans rows of eventColumns, decrypting their payloads.
+func (s *Store) collectEvents(ctx context.Context, rows pgx.Rows) ([]store.EventRecord, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (store.EventRecord, error) {
+		e, keyID, err := scanEvent(row)
+		if err != nil {
+			return e, err
+		}
+		e.Payload, err = s.open(ctx, keyID, e.Payload, e.EventID)
+		return e, err
+	})
+}
+
+// scanSnapshot scans a row of snapshotColumns. The state is returned as stored, with the ID of
+// the key it is encrypted under.
+func scanSnapshot(row pgx.Row) (store.SnapshotRecord, string, error) {
+	var (
+		sn    store.SnapshotRecord
+		state *string
+		keyID string
+	)
+	if err := row.Scan(&sn.SnapshotID, &sn.RunID, &sn.UptoSeq, &state, &keyID, &sn.Version, &sn.Hash, &sn.CreatedAt); err != nil {
+		return store.SnapshotRecord{}, "", err
+	}
+	if state != nil {
+		sn.State = json.RawMessage(*state)
+	}
+	return sn, keyID, nil
+}
+
+// limitOrAll maps a non-positive limit to LIMIT ALL.
+func limitOrAll(limit int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	return &limit
+}
+
+// cutoff returns the creation time before which snapshots are too old, or nil without a limit.
+func cutoff(maxAge time.Duration) *time.Time {
+	if maxAge <= 0 {
+		return nil
+	}
+	t := time.Now().Add(-maxAge)
+	return &t
+}
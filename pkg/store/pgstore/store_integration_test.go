@@ -0,0 +1,212 @@
+//go:build integration
+
+package pgstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/wilhg/orch/pkg/store"
+	"github.com/wilhg/orch/pkg/store/envelope"
+	"github.com/wilhg/orch/pkg/store/storetest"
+)
+
+// startPostgres starts a Postgres container for the test and returns its DSN.
+func startPostgres(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	pg, err := tcpostgres.RunContainer(ctx,
+		tcpostgres.WithDatabase("orch"),
+		tcpostgres.WithUsername("orch"),
+		tcpostgres.WithPassword("orch"),
+		tcpostgres.WithSQLDriver("pgx"),
+	)
+	if err != nil {
+		t.Skipf("skip: cannot start postgres: %v", err)
+	}
+	t.Cleanup(func() { _ = pg.Terminate(ctx) })
+	dsn, err := pg.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dsn
+}
+
+// openTestStore opens and migrates a store on dsn.
+func openTestStore(t *testing.T, dsn string, opts ...Option) *Store {
+	t.Helper()
+	ctx := context.Background()
+	var (
+		st  *Store
+		err error
+	)
+	deadline := time.Now().Add(45 * time.Second)
+	for {
+		st, err = Open(ctx, dsn, opts...)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("connect to postgres after retries: %v", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Migrate is idempotent.
+	if err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestPostgresConformance(t *testing.T) {
+	dsn := startPostgres(t)
+	storetest.Run(t, func(t *testing.T) store.Store {
+		st := openTestStore(t, dsn)
+		// Subtests share the database; each starts from empty tables.
+		if _, err := st.pool.Exec(context.Background(), `TRUNCATE orch_events, orch_event_ids, orch_snapshots`); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	})
+}
+
+func event(id, runID, typ string, payload string) store.EventRecord {
+	e := store.EventRecord{EventID: id, RunID: runID, Type: typ}
+	if payload != "" {
+		e.Payload = json.RawMessage(payload)
+	}
+	return e
+}
+
+func TestPostgresPartitions(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t, startPostgres(t), WithPartitions(4))
+
+	var parts int
+	if err := st.pool.QueryRow(ctx, `SELECT count(*) FROM pg_inherits WHERE inhparent = 'orch_events'::regclass`).Scan(&parts); err != nil {
+		t.Fatal(err)
+	}
+	if parts != 4 {
+		t.Fatalf("partitions=%d want 4", parts)
+	}
+
+	// Runs land in different partitions and are still read back in order.
+	for i := range 20 {
+		if _, err := st.AppendEvent(ctx, event(fmt.Sprintf("e%d", i), fmt.Sprintf("run-%d", i%5), "typ", fmt.Sprintf(`{"n":%d}`, i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var used int
+	if err := st.pool.QueryRow(ctx, `SELECT count(DISTINCT tableoid) FROM orch_events`).Scan(&used); err != nil {
+		t.Fatal(err)
+	}
+	if used < 2 {
+		t.Fatalf("events use %d partitions", used)
+	}
+	all, err := st.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range all {
+		if e.EventID != fmt.Sprintf("e%d", i) {
+			t.Fatalf("feed[%d]=%s", i, e.EventID)
+		}
+	}
+
+	// The JSONB copy of payloads is queryable.
+	var n int
+	if err := st.pool.QueryRow(ctx, `SELECT count(*) FROM orch_events WHERE payload @> '{"n":7}'`).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("jsonb query: n=%d err=%v", n, err)
+	}
+}
+
+func TestPostgresEncryption(t *testing.T) {
+	ctx := context.Background()
+	dsn := startPostgres(t)
+	keys := map[string]string{}
+	for _, id := range []string{"k1", "k2"} {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			t.Fatal(err)
+		}
+		keys[id] = id + ":" + base64.StdEncoding.EncodeToString(key)
+	}
+	keyring := func(ids ...string) *envelope.Keyring {
+		var entries []string
+		for _, id := range ids {
+			entries = append(entries, keys[id])
+		}
+		ring, err := envelope.ParseKeyring(strings.Join(entries, ","))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ring
+	}
+
+	storetest.Run(t, func(t *testing.T) store.Store {
+		st := openTestStore(t, dsn, WithEncryption(keyring("k1")))
+		if _, err := st.pool.Exec(ctx, `TRUNCATE orch_events, orch_event_ids, orch_snapshots`); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	})
+
+	st := openTestStore(t, dsn, WithEncryption(keyring("k1")))
+	if _, err := st.pool.Exec(ctx, `TRUNCATE orch_events, orch_event_ids, orch_snapshots`); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 3 {
+		if _, err := st.AppendEvent(ctx, event(fmt.Sprintf("e%d", i), "run-enc", "typ", fmt.Sprintf(`{"secret":%d}`, i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: "snap", RunID: "run-enc", UptoSeq: 2, State: json.RawMessage(`{"secret":"s"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	// Encrypted payloads have no JSONB copy.
+	var plain int
+	if err := st.pool.QueryRow(ctx, `SELECT count(*) FROM orch_events WHERE payload IS NOT NULL OR key_id <> 'k1'`).Scan(&plain); err != nil || plain != 0 {
+		t.Fatalf("plain rows=%d err=%v", plain, err)
+	}
+
+	// After rotating to k2 and re-encrypting, k1 can be retired.
+	rotated := openTestStore(t, dsn, WithEncryption(keyring("k2", "k1")))
+	total := 0
+	for {
+		n, err := rotated.Reencrypt(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	if total != 4 {
+		t.Fatalf("re-encrypted %d rows want 4", total)
+	}
+	final := openTestStore(t, dsn, WithEncryption(keyring("k2")))
+	events, err := final.ListEvents(ctx, "run-enc", 0, 0)
+	if err != nil || len(events) != 3 {
+		t.Fatalf("events=%+v err=%v", events, err)
+	}
+	for i, e := range events {
+		if string(e.Payload) != fmt.Sprintf(`{"secret":%d}`, i) {
+			t.Fatalf("payload %d=%s", i, e.Payload)
+		}
+	}
+	if sn, err := final.LoadLatestSnapshot(ctx, "run-enc"); err != nil || string(sn.State) != `{"secret":"s"}` {
+		t.Fatalf("snapshot=%+v err=%v", sn, err)
+	}
+}
@@ -0,0 +1,235 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+)
+
+// PathElem is an element of a payload path: an object key, or an array index when Key is empty.
+type PathElem struct {
+	Key   string
+	Index int
+}
+
+// pathKey restricts the object keys of payload paths, since stores write them into SQL.
+var pathKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ParsePayloadPath parses a path such as "order.items[0].sku".
+func ParsePayloadPath(path string) ([]PathElem, error) {
+	bad := func(msg string) error {
+		return errmodel.Validation("bad_path", msg, map[string]any{"path": path})
+	}
+	if path == "" {
+		return nil, bad("empty payload path")
+	}
+	var elems []PathElem
+	for _, part := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key == "" && rest == "" {
+			return nil, bad("payload path has an empty key")
+		}
+		if key != "" {
+			if !pathKey.MatchString(key) {
+				return nil, bad("payload path keys may only contain letters, digits, '_' and '-'")
+			}
+			elems = append(elems, PathElem{Key: key})
+		}
+		if rest == "" {
+			continue
+		}
+		// rest is "n]" or "n][m]...".
+		for _, idx := range strings.Split("["+rest, "[")[1:] {
+			n, err := strconv.Atoi(strings.TrimSuffix(idx, "]"))
+			if !strings.HasSuffix(idx, "]") || err != nil || n < 0 {
+				return nil, bad("payload path indexes must be non-negative integers in brackets")
+			}
+			elems = append(elems, PathElem{Index: n})
+		}
+	}
+	return elems, nil
+}
+
+// DecodeValue returns the value of p as a string, float64, bool or nil.
+func (p PayloadPredicate) DecodeValue() (any, error) {
+	var v any
+	if err := json.Unmarshal(p.Value, &v); err != nil {
+		return nil, errmodel.Validation("bad_value", "predicate value must be JSON", map[string]any{"path": p.Path, "value": string(p.Value)})
+	}
+	switch v.(type) {
+	case string, float64, bool, nil:
+		return v, nil
+	}
+	return nil, errmodel.Validation("bad_value", "predicate value must be a string, number, boolean or null", map[string]any{"path": p.Path, "value": string(p.Value)})
+}
+
+// Validate checks the payload predicates of q.
+func (q EventQuery) Validate() error {
+	for _, p := range q.Where {
+		if _, err := ParsePayloadPath(p.Path); err != nil {
+			return err
+		}
+		switch p.Op {
+		case PayloadExists:
+			continue
+		case PayloadEQ, PayloadNE, PayloadContains, PayloadGT, PayloadGTE, PayloadLT, PayloadLTE:
+		default:
+			return errmodel.Validation("bad_op", "unknown predicate operator", map[string]any{"path": p.Path, "op": string(p.Op)})
+		}
+		v, err := p.DecodeValue()
+		if err != nil {
+			return err
+		}
+		if p.Ordered() {
+			switch v.(type) {
+			case string, float64:
+			default:
+				return errmodel.Validation("bad_value", "ordering operators compare strings or numbers", map[string]any{"path": p.Path, "op": string(p.Op)})
+			}
+		}
+	}
+	return nil
+}
+
+// Ordered reports whether p uses an ordering operator.
+func (p PayloadPredicate) Ordered() bool {
+	switch p.Op {
+	case PayloadGT, PayloadGTE, PayloadLT, PayloadLTE:
+		return true
+	}
+	return false
+}
+
+// MatchesPayload reports whether q has payload filters: predicates or text.
+func (q EventQuery) MatchesPayload() bool {
+	return len(q.Where) > 0 || q.Text != ""
+}
+
+// MatchPayload reports whether payload satisfies the predicates and text of a valid query. Stores
+// use it for payloads the database cannot match, such as encrypted ones.
+func (q EventQuery) MatchPayload(payload json.RawMessage) bool {
+	if q.Text != "" && !bytes.Contains(bytes.ToLower(payload), bytes.ToLower([]byte(q.Text))) {
+		return false
+	}
+	if len(q.Where) == 0 {
+		return true
+	}
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return false
+	}
+	for _, p := range q.Where {
+		if !p.match(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p PayloadPredicate) match(doc any) bool {
+	path, err := ParsePayloadPath(p.Path)
+	if err != nil {
+		return false
+	}
+	for _, e := range path {
+		switch d := doc.(type) {
+		case map[string]any:
+			v, ok := d[e.Key]
+			if e.Key == "" || !ok {
+				return false
+			}
+			doc = v
+		case []any:
+			if e.Key != "" || e.Index >= len(d) {
+				return false
+			}
+			doc = d[e.Index]
+		default:
+			return false
+		}
+	}
+	if p.Op == PayloadExists {
+		return true
+	}
+	want, err := p.DecodeValue()
+	if err != nil {
+		return false
+	}
+	switch p.Op {
+	case PayloadEQ:
+		return scalarEqual(doc, want)
+	case PayloadNE:
+		return !scalarEqual(doc, want)
+	case PayloadContains:
+		if arr, ok := doc.([]any); ok {
+			for _, e := range arr {
+				if scalarEqual(e, want) {
+					return true
+				}
+			}
+			return false
+		}
+		return scalarEqual(doc, want)
+	}
+	c, ok := compareScalars(doc, want)
+	if !ok {
+		return false
+	}
+	switch p.Op {
+	case PayloadGT:
+		return c > 0
+	case PayloadGTE:
+		return c >= 0
+	case PayloadLT:
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+// scalarEqual reports whether a decoded JSON value equals a scalar of the same type.
+func scalarEqual(v, want any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return v == want
+}
+
+// compareScalars compares two strings or two numbers.
+func compareScalars(v, want any) (int, bool) {
+	switch w := want.(type) {
+	case string:
+		s, ok := v.(string)
+		return strings.Compare(s, w), ok
+	case float64:
+		n, ok := v.(float64)
+		switch {
+		case !ok:
+			return 0, false
+		case n < w:
+			return -1, true
+		case n > w:
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// ParseEventCursor parses the cursor of an EventQuery: the global position of the last event of
+// the previous page, or 0 for an empty cursor.
+func ParseEventCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	pos, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || pos < 0 {
+		return 0, errmodel.Validation("bad_cursor", "invalid cursor", map[string]any{"cursor": cursor})
+	}
+	return pos, nil
+}
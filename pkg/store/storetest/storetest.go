@@ -0,0 +1,650 @@
+// Package storetest provides a conformance suite for store.Store implementations. Every backend
+// runs it to prove that it behaves like the others:
+//
+//	func TestConformance(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) store.Store { return memstore.New() })
+//	}
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wilhg/orch/pkg/errmodel"
+	"github.com/wilhg/orch/pkg/store"
+)
+
+// Factory returns an empty store for one test. It registers any cleanup with t.
+type Factory func(t *testing.T) store.Store
+
+// Run runs the conformance suite as subtests of t, each against a store returned by newStore.
+// Stores that also implement store.EventArchiver are checked to keep archived events readable,
+// stores implementing store.RunEraser to delete runs completely, and stores implementing
+// store.EventQuerier to search events.
+func Run(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, st store.Store)
+	}{
+		{"AppendAndList", testAppendAndList},
+		{"DuplicateEventID", testDuplicateEventID},
+		{"ExpectedSeq", testExpectedSeq},
+		{"AtomicBatch", testAtomicBatch},
+		{"ReadAll", testReadAll},
+		{"ConcurrentAppends", testConcurrentAppends},
+		{"RawPayloads", testRawPayloads},
+		{"Snapshots", testSnapshots},
+		{"SnapshotRetention", testSnapshotRetention},
+		{"InvalidateSnapshots", testInvalidateSnapshots},
+		{"Archive", testArchive},
+		{"DeleteRun", testDeleteRun},
+		{"QueryEvents", testQueryEvents},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) { tc.fn(t, newStore(t)) })
+	}
+}
+
+func ev(id, runID string, payload string) store.EventRecord {
+	e := store.EventRecord{EventID: id, RunID: runID, Type: "test"}
+	if payload != "" {
+		e.Payload = json.RawMessage(payload)
+	}
+	return e
+}
+
+func mustAppend(t *testing.T, st store.Store, events ...store.EventRecord) []store.EventRecord {
+	t.Helper()
+	out := make([]store.EventRecord, 0, len(events))
+	for _, e := range events {
+		rec, err := st.AppendEvent(context.Background(), e)
+		if err != nil {
+			t.Fatalf("append %s: %v", e.EventID, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func ids(events []store.EventRecord) []string {
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		out = append(out, e.EventID)
+	}
+	return out
+}
+
+func wantIDs(t *testing.T, what string, got []store.EventRecord, want ...string) {
+	t.Helper()
+	if g := fmt.Sprint(ids(got)); g != fmt.Sprint(want) {
+		t.Fatalf("%s: got %s want %v", what, g, want)
+	}
+}
+
+func testAppendAndList(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	if last, err := st.LastSeq(ctx, "run"); err != nil || last != 0 {
+		t.Fatalf("last seq of empty run=%d err=%v", last, err)
+	}
+	recs := mustAppend(t, st, ev("e1", "run", `{"n":1}`), ev("o1", "other", ""), ev("e2", "run", ""), ev("e3", "run", `{"n":3}`))
+	for i, want := range []int64{1, 1, 2, 3} {
+		if recs[i].Seq != want {
+			t.Fatalf("event %s seq=%d want %d", recs[i].EventID, recs[i].Seq, want)
+		}
+	}
+	r := recs[0]
+	if r.RunID != "run" || r.Type != "test" || r.Version != 1 || r.Position <= 0 || r.CreatedAt.IsZero() || string(r.Payload) != `{"n":1}` {
+		t.Fatalf("appended record: %+v", r)
+	}
+	if recs[2].Payload != nil {
+		t.Fatalf("empty payload read back as %q", recs[2].Payload)
+	}
+	v2, err := st.AppendEvent(ctx, store.EventRecord{EventID: "e4", RunID: "run", Type: "test", Version: 2})
+	if err != nil || v2.Version != 2 {
+		t.Fatalf("versioned append: %+v, %v", v2, err)
+	}
+
+	got, err := st.ListEvents(ctx, "run", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIDs(t, "list", got, "e1", "e2", "e3", "e4")
+	got, _ = st.ListEvents(ctx, "run", 1, 2)
+	wantIDs(t, "list after 1, limit 2", got, "e2", "e3")
+	got, _ = st.ListEvents(ctx, "run", 4, 0)
+	wantIDs(t, "list past the end", got)
+	got, _ = st.ListEvents(ctx, "missing", 0, 0)
+	wantIDs(t, "list of unknown run", got)
+	if last, _ := st.LastSeq(ctx, "run"); last != 4 {
+		t.Fatalf("last seq=%d want 4", last)
+	}
+
+	byID, err := st.GetEventByID(ctx, "e3")
+	if err != nil || byID.RunID != "run" || byID.Seq != 3 || byID.Position != recs[3].Position || string(byID.Payload) != `{"n":3}` {
+		t.Fatalf("get by id: %+v, %v", byID, err)
+	}
+	if _, err := st.GetEventByID(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("missing event err=%v want sql.ErrNoRows", err)
+	}
+}
+
+func testDuplicateEventID(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	first := mustAppend(t, st, ev("e1", "run", `{"v":1}`))[0]
+	dup, err := st.AppendEvent(ctx, ev("e1", "run", `{"v":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup.Seq != first.Seq || dup.Position != first.Position || string(dup.Payload) != `{"v":1}` {
+		t.Fatalf("duplicate returned %+v want %+v", dup, first)
+	}
+	// Also when claimed under another run.
+	if dup, err := st.AppendEvent(ctx, ev("e1", "other", "")); err != nil || dup.RunID != "run" {
+		t.Fatalf("duplicate in other run: %+v, %v", dup, err)
+	}
+	if last, _ := st.LastSeq(ctx, "run"); last != 1 {
+		t.Fatalf("last seq=%d want 1", last)
+	}
+	if last, _ := st.LastSeq(ctx, "other"); last != 0 {
+		t.Fatalf("other run last seq=%d want 0", last)
+	}
+	// A batch mixing an existing and a new event writes only the new one.
+	out, err := st.AppendEvents(ctx, "run", 1, []store.EventRecord{ev("e1", "run", ""), ev("e2", "run", "")})
+	if err != nil || len(out) != 2 || out[0].Seq != 1 || out[1].Seq != 2 {
+		t.Fatalf("mixed batch: %+v, %v", out, err)
+	}
+}
+
+func testExpectedSeq(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	mustAppend(t, st, ev("e1", "run", ""), ev("e2", "run", ""))
+
+	// An AppendEvent with a Seq other than the next one conflicts.
+	for _, seq := range []int64{2, 4} {
+		e := ev(fmt.Sprintf("seq-%d", seq), "run", "")
+		e.Seq = seq
+		if _, err := st.AppendEvent(ctx, e); !errmodel.HasCode(err, "conflict") {
+			t.Fatalf("append with seq %d: err=%v want conflict", seq, err)
+		}
+	}
+	e := ev("e3", "run", "")
+	e.Seq = 3
+	if rec, err := st.AppendEvent(ctx, e); err != nil || rec.Seq != 3 {
+		t.Fatalf("append with next seq: %+v, %v", rec, err)
+	}
+
+	_, err := st.AppendEvents(ctx, "run", 2, []store.EventRecord{ev("e4", "run", ""), ev("e5", "run", "")})
+	if !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("stale batch err=%v want conflict", err)
+	}
+	if _, err := st.GetEventByID(ctx, "e4"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("conflicting batch was written: %v", err)
+	}
+	batch := []store.EventRecord{ev("e4", "run", ""), ev("e5", "run", "")}
+	out, err := st.AppendEvents(ctx, "run", 3, batch)
+	if err != nil || out[0].Seq != 4 || out[1].Seq != 5 {
+		t.Fatalf("batch: %+v, %v", out, err)
+	}
+	// Replaying a committed batch returns its records, even though the run moved on.
+	out, err = st.AppendEvents(ctx, "run", 3, batch)
+	if err != nil || out[0].Seq != 4 || out[1].Seq != 5 {
+		t.Fatalf("replayed batch: %+v, %v", out, err)
+	}
+	// The first events of a run expect sequence 0.
+	if _, err := st.AppendEvents(ctx, "fresh", 0, []store.EventRecord{ev("f1", "fresh", "")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.AppendEvents(ctx, "fresh", 0, []store.EventRecord{ev("f2", "fresh", "")}); !errmodel.HasCode(err, "conflict") {
+		t.Fatalf("second first batch err=%v want conflict", err)
+	}
+	if out, err := st.AppendEvents(ctx, "run", 0, nil); err != nil || len(out) != 0 {
+		t.Fatalf("empty batch: %+v, %v", out, err)
+	}
+}
+
+func testAtomicBatch(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	mustAppend(t, st, ev("e1", "run", ""))
+	_, err := st.AppendEvents(ctx, "run", store.AnySeq, []store.EventRecord{ev("e2", "run", `{}`), ev("e3", "run", `{"a":`)})
+	if err == nil {
+		t.Fatal("batch with an invalid payload was accepted")
+	}
+	got, _ := st.ListEvents(ctx, "run", 0, 0)
+	wantIDs(t, "after failed batch", got, "e1")
+	if _, err := st.GetEventByID(ctx, "e2"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("event of failed batch was written: %v", err)
+	}
+}
+
+func testReadAll(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	mustAppend(t, st, ev("a1", "a", ""), ev("b1", "b", ""), ev("a2", "a", ""))
+	if _, err := st.AppendEvents(ctx, "c", 0, []store.EventRecord{ev("c1", "c", ""), ev("c2", "c", "")}); err != nil {
+		t.Fatal(err)
+	}
+	all, err := st.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIDs(t, "feed", all, "a1", "b1", "a2", "c1", "c2")
+	for i := 1; i < len(all); i++ {
+		if all[i].Position <= all[i-1].Position {
+			t.Fatalf("positions not increasing: %d then %d", all[i-1].Position, all[i].Position)
+		}
+	}
+	page, _ := st.ReadAll(ctx, all[1].Position, 2)
+	wantIDs(t, "page", page, "a2", "c1")
+	page, _ = st.ReadAll(ctx, all[4].Position, 0)
+	wantIDs(t, "end of feed", page)
+}
+
+func testConcurrentAppends(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	const writers, perWriter = 4, 10
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for w := range writers {
+		wg.Go(func() {
+			for i := range perWriter {
+				if _, err := st.AppendEvent(ctx, ev(fmt.Sprintf("w%d-%d", w, i), "run", "")); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+	got, err := st.ListEvents(ctx, "run", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != writers*perWriter {
+		t.Fatalf("len=%d want %d", len(got), writers*perWriter)
+	}
+	for i, e := range got {
+		if e.Seq != int64(i+1) || (i > 0 && e.Position <= got[i-1].Position) {
+			t.Fatalf("event %d: seq=%d position=%d", i, e.Seq, e.Position)
+		}
+	}
+}
+
+// rawPayloads are JSON values that a store normalizing JSON would not return unchanged.
+var rawPayloads = []string{
+	`{"b":1,"a":2}`,
+	`{"a":1,"a":2}`,
+	`{ "spaced" : [ 1, 2 ] }`,
+	`{"unicode":"é","float":1.0}`,
+	`"just a string"`,
+	`12345678901234567890.12345678901234567890`,
+	`1e400`,
+	`true`,
+	`null`,
+}
+
+func testRawPayloads(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	var events []store.EventRecord
+	for i, p := range rawPayloads {
+		events = append(events, ev(fmt.Sprintf("raw-%d", i), "run", p))
+	}
+	if _, err := st.AppendEvents(ctx, "run", 0, events); err != nil {
+		t.Fatal(err)
+	}
+	got, err := st.ListEvents(ctx, "run", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range rawPayloads {
+		if string(got[i].Payload) != p {
+			t.Fatalf("payload %d=%s want %s", i, got[i].Payload, p)
+		}
+	}
+	all, _ := st.ReadAll(ctx, 0, 0)
+	byID, _ := st.GetEventByID(ctx, "raw-0")
+	if string(all[0].Payload) != rawPayloads[0] || string(byID.Payload) != rawPayloads[0] {
+		t.Fatalf("payload read back as %s and %s", all[0].Payload, byID.Payload)
+	}
+	for i, p := range rawPayloads {
+		if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: fmt.Sprintf("snap-%d", i), RunID: "run", UptoSeq: int64(i + 1), State: json.RawMessage(p)}); err != nil {
+			t.Fatal(err)
+		}
+		sn, err := st.LoadLatestSnapshot(ctx, "run")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(sn.State) != p {
+			t.Fatalf("state %d=%s want %s", i, sn.State, p)
+		}
+	}
+	if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: "bad", RunID: "run", UptoSeq: 100, State: json.RawMessage(`{"a":`)}); err == nil {
+		t.Fatal("invalid JSON state was accepted")
+	}
+
+	// Records handed out must not alias the stored bytes.
+	got[0].Payload[0] = '['
+	again, _ := st.GetEventByID(ctx, "raw-0")
+	if string(again.Payload) != rawPayloads[0] {
+		t.Fatalf("stored payload changed to %s", again.Payload)
+	}
+}
+
+func saveSnapshots(t *testing.T, st store.Store, runID, version string, seqs ...int64) {
+	t.Helper()
+	for _, seq := range seqs {
+		_, err := st.SaveSnapshot(context.Background(), store.SnapshotRecord{
+			SnapshotID: fmt.Sprintf("%s-%s-%d", runID, version, seq),
+			RunID:      runID,
+			UptoSeq:    seq,
+			State:      json.RawMessage(fmt.Sprintf(`{"seq":%d}`, seq)),
+			Version:    version,
+			Hash:       fmt.Sprintf("sha256:%d", seq),
+		})
+		if err != nil {
+			t.Fatalf("save snapshot %d: %v", seq, err)
+		}
+	}
+}
+
+func latestSeq(t *testing.T, st store.Store, runID string) int64 {
+	t.Helper()
+	sn, err := st.LoadLatestSnapshot(context.Background(), runID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sn.UptoSeq
+}
+
+func testSnapshots(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	if _, err := st.LoadLatestSnapshot(ctx, "run"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("missing snapshot err=%v want sql.ErrNoRows", err)
+	}
+	// Saved out of order, the latest is the one with the highest sequence.
+	saveSnapshots(t, st, "run", "v1", 5, 10, 7)
+	saveSnapshots(t, st, "other", "v1", 20)
+	sn, err := st.LoadLatestSnapshot(ctx, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sn.SnapshotID != "run-v1-10" || sn.RunID != "run" || sn.UptoSeq != 10 || sn.Version != "v1" || sn.Hash != "sha256:10" || string(sn.State) != `{"seq":10}` || sn.CreatedAt.IsZero() {
+		t.Fatalf("latest snapshot: %+v", sn)
+	}
+	if _, err := st.SaveSnapshot(ctx, store.SnapshotRecord{SnapshotID: "again", RunID: "run", UptoSeq: 10}); err == nil {
+		t.Fatal("second snapshot at the same sequence was accepted")
+	}
+	if n, err := st.DeleteSnapshots(ctx, "run"); err != nil || n != 3 {
+		t.Fatalf("delete: n=%d err=%v", n, err)
+	}
+	if got := latestSeq(t, st, "run"); got != 0 {
+		t.Fatalf("snapshot %d left after delete", got)
+	}
+	if got := latestSeq(t, st, "other"); got != 20 {
+		t.Fatalf("other run's snapshot: %d", got)
+	}
+}
+
+func testSnapshotRetention(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	saveSnapshots(t, st, "run", "v1", 1, 2, 3, 4)
+	if n, err := st.PruneSnapshots(ctx, "run", store.SnapshotRetention{}); err != nil || n != 0 {
+		t.Fatalf("prune without limits: n=%d err=%v", n, err)
+	}
+	if n, err := st.PruneSnapshots(ctx, "run", store.SnapshotRetention{KeepLast: 2}); err != nil || n != 2 {
+		t.Fatalf("keep last 2: n=%d err=%v", n, err)
+	}
+	if got := latestSeq(t, st, "run"); got != 4 {
+		t.Fatalf("latest after keep last: %d", got)
+	}
+	time.Sleep(10 * time.Millisecond)
+	// The latest snapshot is kept however old it is.
+	if n, err := st.PruneSnapshots(ctx, "run", store.SnapshotRetention{MaxAge: time.Millisecond}); err != nil || n != 1 {
+		t.Fatalf("max age: n=%d err=%v", n, err)
+	}
+	if got := latestSeq(t, st, "run"); got != 4 {
+		t.Fatalf("latest after prune: %d", got)
+	}
+	if n, _ := st.DeleteSnapshots(ctx, "run"); n != 1 {
+		t.Fatalf("%d snapshots left, want 1", n)
+	}
+}
+
+func testInvalidateSnapshots(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	saveSnapshots(t, st, "a", "v1", 1, 2)
+	saveSnapshots(t, st, "a", "v2", 3)
+	saveSnapshots(t, st, "b", "v1", 1)
+	saveSnapshots(t, st, "c", "v2", 1)
+	if _, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{}); !errmodel.HasCode(err, "missing_filter") {
+		t.Fatalf("empty filter err=%v want missing_filter", err)
+	}
+	if n, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{RunID: "a", Version: "v2"}); err != nil || n != 1 {
+		t.Fatalf("run and version: n=%d err=%v", n, err)
+	}
+	if got := latestSeq(t, st, "a"); got != 2 {
+		t.Fatalf("latest of a=%d want 2", got)
+	}
+	if n, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{Version: "v1"}); err != nil || n != 3 {
+		t.Fatalf("version: n=%d err=%v", n, err)
+	}
+	if n, err := st.InvalidateSnapshots(ctx, store.SnapshotFilter{RunID: "c"}); err != nil || n != 1 {
+		t.Fatalf("run: n=%d err=%v", n, err)
+	}
+	for _, run := range []string{"a", "b", "c"} {
+		if got := latestSeq(t, st, run); got != 0 {
+			t.Fatalf("snapshot %d of %s left", got, run)
+		}
+	}
+}
+
+func testArchive(t *testing.T, st store.Store) {
+	a, ok := st.(store.EventArchiver)
+	if !ok {
+		t.Skip("store does not archive events")
+	}
+	ctx := context.Background()
+	mustAppend(t, st, ev("e1", "run", `{"b":1,"a":2}`), ev("o1", "other", ""), ev("e2", "run", ""), ev("e3", "run", ""))
+	before, _ := st.ReadAll(ctx, 0, 0)
+
+	// The run's last event stays live.
+	n, err := a.ArchiveEvents(ctx, "run", 10)
+	if err != nil || n != 2 {
+		t.Fatalf("archive: n=%d err=%v", n, err)
+	}
+	got, _ := st.ListEvents(ctx, "run", 0, 0)
+	wantIDs(t, "list after archive", got, "e1", "e2", "e3")
+	got, _ = st.ListEvents(ctx, "run", 1, 1)
+	wantIDs(t, "list page after archive", got, "e2")
+	after, _ := st.ReadAll(ctx, 0, 0)
+	if fmt.Sprint(ids(after)) != fmt.Sprint(ids(before)) || after[0].Position != before[0].Position || string(after[0].Payload) != `{"b":1,"a":2}` {
+		t.Fatalf("feed changed by archiving: %v", ids(after))
+	}
+	if e, err := st.GetEventByID(ctx, "e1"); err != nil || e.Seq != 1 {
+		t.Fatalf("archived event by id: %+v, %v", e, err)
+	}
+	// Archived events still deduplicate appends, and sequences continue.
+	if dup, err := st.AppendEvent(ctx, ev("e1", "run", "")); err != nil || dup.Seq != 1 {
+		t.Fatalf("duplicate of archived event: %+v, %v", dup, err)
+	}
+	if rec := mustAppend(t, st, ev("e4", "run", ""))[0]; rec.Seq != 4 || rec.Position <= after[len(after)-1].Position {
+		t.Fatalf("append after archive: %+v", rec)
+	}
+	if last, _ := st.LastSeq(ctx, "run"); last != 4 {
+		t.Fatalf("last seq=%d want 4", last)
+	}
+}
+
+func testDeleteRun(t *testing.T, st store.Store) {
+	e, ok := st.(store.RunEraser)
+	if !ok {
+		t.Skip("store does not delete runs")
+	}
+	ctx := context.Background()
+	mustAppend(t, st, ev("a1", "a", `{"secret":1}`), ev("b1", "b", ""), ev("a2", "a", ""), ev("a3", "a", ""))
+	saveSnapshots(t, st, "a", "v1", 2)
+	saveSnapshots(t, st, "b", "v1", 1)
+	if a, ok := st.(store.EventArchiver); ok {
+		if _, err := a.ArchiveEvents(ctx, "a", 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := e.DeleteRun(ctx, "a")
+	if err != nil || d.Events != 3 || d.Snapshots != 1 {
+		t.Fatalf("delete: %+v, %v", d, err)
+	}
+	got, _ := st.ListEvents(ctx, "a", 0, 0)
+	wantIDs(t, "deleted run", got)
+	all, _ := st.ReadAll(ctx, 0, 0)
+	wantIDs(t, "feed after delete", all, "b1")
+	if _, err := st.GetEventByID(ctx, "a1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deleted event err=%v want sql.ErrNoRows", err)
+	}
+	if got := latestSeq(t, st, "a"); got != 0 {
+		t.Fatalf("snapshot %d of deleted run left", got)
+	}
+	if got := latestSeq(t, st, "b"); got != 1 {
+		t.Fatalf("other run's snapshot: %d", got)
+	}
+	if d, err := e.DeleteRun(ctx, "a"); err != nil || d != (store.RunDeletion{}) {
+		t.Fatalf("second delete: %+v, %v", d, err)
+	}
+
+	// The run starts over, with positions after every earlier one.
+	rec := mustAppend(t, st, ev("a1", "a", ""))[0]
+	if rec.Seq != 1 || rec.Position <= all[0].Position {
+		t.Fatalf("append after delete: %+v", rec)
+	}
+}
+
+func testQueryEvents(t *testing.T, st store.Store) {
+	qr, ok := st.(store.EventQuerier)
+	if !ok {
+		t.Skip("store does not query events")
+	}
+	ctx := context.Background()
+	typed := func(e store.EventRecord, typ string) store.EventRecord {
+		e.Type = typ
+		return e
+	}
+	q1 := `{"order": {"id": "o-1", "total": 120, "items": [{"sku": "x"}, {"sku": "y"}], "tags": ["rush", "gift"]}, "note": "Hello World"}`
+	mustAppend(t, st,
+		typed(ev("q1", "a", q1), "order.created"),
+		typed(ev("q2", "a", `{"order":{"id":"o-1","total":120},"paid":true}`), "order.paid"),
+		typed(ev("q3", "b", `{"order":{"id":"o-2","total":"n/a","tags":["gift"]},"note":null}`), "order.created"),
+		typed(ev("q4", "b", ""), "other"),
+		typed(ev("q5", "c", `{"order":{"id":"o-3","total":80.5,"items":[]},"note":"100% done_"}`), "order.created"),
+	)
+	if a, ok := st.(store.EventArchiver); ok {
+		if _, err := a.ArchiveEvents(ctx, "a", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	where := func(path string, op store.PayloadOp, value string) store.EventQuery {
+		return store.EventQuery{Where: []store.PayloadPredicate{{Path: path, Op: op, Value: json.RawMessage(value)}}}
+	}
+	now := time.Now()
+	for _, tc := range []struct {
+		name string
+		q    store.EventQuery
+		want []string
+	}{
+		{"all", store.EventQuery{}, []string{"q1", "q2", "q3", "q4", "q5"}},
+		{"types", store.EventQuery{Types: []string{"order.created", "other"}}, []string{"q1", "q3", "q4", "q5"}},
+		{"run", store.EventQuery{RunID: "b"}, []string{"q3", "q4"}},
+		{"since", store.EventQuery{Since: now.Add(-time.Minute)}, []string{"q1", "q2", "q3", "q4", "q5"}},
+		{"until", store.EventQuery{Until: now.Add(-time.Minute)}, nil},
+		{"eq string", where("order.id", store.PayloadEQ, `"o-1"`), []string{"q1", "q2"}},
+		{"eq bool", where("paid", store.PayloadEQ, `true`), []string{"q2"}},
+		{"eq null", where("note", store.PayloadEQ, `null`), []string{"q3"}},
+		{"ne", where("order.id", store.PayloadNE, `"o-1"`), []string{"q3", "q5"}},
+		{"gt number", where("order.total", store.PayloadGT, `100`), []string{"q1", "q2"}},
+		{"lte number", where("order.total", store.PayloadLTE, `100`), []string{"q5"}},
+		{"gte string", where("order.total", store.PayloadGTE, `"m"`), []string{"q3"}},
+		{"exists", where("note", store.PayloadExists, ``), []string{"q1", "q3", "q5"}},
+		{"contains", where("order.tags", store.PayloadContains, `"gift"`), []string{"q1", "q3"}},
+		{"index", where("order.items[1].sku", store.PayloadEQ, `"y"`), []string{"q1"}},
+		{"text", store.EventQuery{Text: "hello WORLD"}, []string{"q1"}},
+		{"text wildcards", store.EventQuery{Text: "e_"}, []string{"q5"}},
+		{"combined", store.EventQuery{Types: []string{"order.created"}, Where: []store.PayloadPredicate{
+			{Path: "order.total", Op: store.PayloadGT, Value: json.RawMessage(`100`)},
+			{Path: "order.tags", Op: store.PayloadContains, Value: json.RawMessage(`"rush"`)},
+		}}, []string{"q1"}},
+	} {
+		page, err := qr.QueryEvents(ctx, tc.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		wantIDs(t, tc.name, page.Events, tc.want...)
+		if page.NextCursor != "" {
+			t.Fatalf("%s: unexpected cursor %q", tc.name, page.NextCursor)
+		}
+	}
+
+	// Payloads are returned as stored.
+	page, err := qr.QueryEvents(ctx, where("order.id", store.PayloadEQ, `"o-1"`))
+	if err != nil || string(page.Events[0].Payload) != q1 {
+		t.Fatalf("payload=%s err=%v", page.Events[0].Payload, err)
+	}
+
+	// Pages follow the cursor until it is empty.
+	q := store.EventQuery{Types: []string{"order.created"}, Limit: 2}
+	var got []store.EventRecord
+	for range 3 {
+		page, err := qr.QueryEvents(ctx, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page.Events...)
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	wantIDs(t, "pages", got, "q1", "q3", "q5")
+
+	// A numeric key names an object member and an index an array element; neither matches the other.
+	mustAppend(t, st,
+		typed(ev("k1", "d", `{"a":{"0":"x"}}`), "keyed"),
+		typed(ev("k2", "d", `{"a":["x"]}`), "keyed"),
+	)
+	for path, want := range map[string]string{"a.0": "k1", "a[0]": "k2"} {
+		q := where(path, store.PayloadEQ, `"x"`)
+		q.Types = []string{"keyed"}
+		page, err := qr.QueryEvents(ctx, q)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		wantIDs(t, path, page.Events, want)
+	}
+
+	for _, bad := range []store.EventQuery{
+		where("order..id", store.PayloadEQ, `1`),
+		where("order.id'", store.PayloadEQ, `1`),
+		where("order.items[x]", store.PayloadEQ, `1`),
+		where("order.id", "like", `1`),
+		where("order.id", store.PayloadEQ, `{"a":1}`),
+		where("order.id", store.PayloadGT, `true`),
+		{Cursor: "next"},
+	} {
+		if _, err := qr.QueryEvents(ctx, bad); err == nil || errmodel.From(err).Category != errmodel.CategoryValidation {
+			t.Fatalf("query %+v: err=%v want a validation error", bad, err)
+		}
+	}
+}